@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/cmd/influxd/backup_util"
@@ -59,10 +60,22 @@ type Command struct {
 	manifest         backup_util.Manifest
 	portableFileBase string
 	continueOnError  bool
+	skipInternal     bool
+
+	// shardN is the number of shards a backup was attempted for, and
+	// shardErrors the ones that failed with -skip-errors.
+	shardN      int
+	shardErrors []shardError
 
 	BackupFiles []string
 }
 
+// shardError records a shard that failed to back up.
+type shardError struct {
+	db, rp, id string
+	err        error
+}
+
 // NewCommand returns a new instance of Command with default settings.
 func NewCommand() *Command {
 	return &Command{
@@ -112,6 +125,9 @@ func (cmd *Command) Run(args ...string) error {
 		cmd.StdoutLogger.Println("No database, retention policy or shard ID given. Full meta store backed up.")
 		if cmd.portable {
 			cmd.StdoutLogger.Println("Backing up all databases in portable format")
+			if cmd.skipInternal {
+				cmd.StdoutLogger.Println("Skipping the _internal database. Use -skip-internal=false to include it.")
+			}
 			if err := cmd.backupDatabase(); err != nil {
 				cmd.StderrLogger.Printf("backup failed: %v", err)
 				return err
@@ -130,6 +146,12 @@ func (cmd *Command) Run(args ...string) error {
 		cmd.BackupFiles = append(cmd.BackupFiles, filename)
 	}
 
+	// Shards skipped with -skip-errors still fail the backup, after the
+	// remaining shards have been backed up.
+	if err == nil {
+		err = cmd.shardFailures()
+	}
+
 	if err != nil {
 		cmd.StderrLogger.Printf("backup failed: %v", err)
 		return err
@@ -160,6 +182,7 @@ func (cmd *Command) parseFlags(args []string) (err error) {
 	fs.StringVar(&endArg, "end", "", "")
 	fs.BoolVar(&cmd.portable, "portable", false, "")
 	fs.BoolVar(&cmd.continueOnError, "skip-errors", false, "")
+	fs.BoolVar(&cmd.skipInternal, "skip-internal", true, "")
 
 	fs.SetOutput(cmd.Stderr)
 	fs.Usage = cmd.printUsage
@@ -421,18 +444,48 @@ func (cmd *Command) backupResponsePaths(response *snapshotter.Response) error {
 			return err
 		}
 
+		if cmd.skipDatabase(db) {
+			continue
+		}
+
 		// Don't need to verify db and rp, we know they're correct here
 		err = cmd.backupShard(db, rp, id, false)
+		cmd.shardN++
 
-		if err != nil && !cmd.continueOnError {
+		if err != nil {
+			if !cmd.continueOnError {
+				cmd.StderrLogger.Printf("error (%s) when backing up db: %s, rp %s, shard %s", err, db, rp, id)
+				return err
+			}
 			cmd.StderrLogger.Printf("error (%s) when backing up db: %s, rp %s, shard %s. continuing backup on remaining shards", err, db, rp, id)
-			return err
+			cmd.shardErrors = append(cmd.shardErrors, shardError{db: db, rp: rp, id: id, err: err})
 		}
 	}
 
 	return nil
 }
 
+// shardFailures returns an error summarizing the shards that failed to back up,
+// or nil if none failed.
+func (cmd *Command) shardFailures() error {
+	if len(cmd.shardErrors) == 0 {
+		return nil
+	}
+
+	failures := make([]string, len(cmd.shardErrors))
+	for i, e := range cmd.shardErrors {
+		failures[i] = fmt.Sprintf("db=%s rp=%s shard=%s: %v", e.db, e.rp, e.id, e.err)
+	}
+	return fmt.Errorf("%d of %d shards failed: %s", len(cmd.shardErrors), cmd.shardN, strings.Join(failures, "; "))
+}
+
+// skipDatabase returns true if the shards of db should be left out of the backup.
+// The _internal database only holds monitoring data, so it is skipped when backing
+// up all databases unless -skip-internal=false is given.
+func (cmd *Command) skipDatabase(db string) bool {
+	return cmd.skipInternal && cmd.database == "" && db == "_internal"
+}
+
 // backupMetastore will backup the whole metastore on the host to the backup path
 // if useDB is non-empty, it will backup metadata only for the named database.
 func (cmd *Command) backupMetastore() (retErr error) {
@@ -669,6 +722,9 @@ Usage: influxd backup [options] PATH
             Recommend using '-start <timestamp>' instead.
     -skip-errors 
             Optional flag to continue backing up the remaining shards when the current shard fails to backup. 
+    -skip-internal
+            Skip the _internal database when backing up all databases with '-portable'. Defaults to true.
+            Use '-skip-internal=false' to include it.
 `)
 
 }
@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/services/snapshotter"
+)
+
+func TestCommand_SkipInternal(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		args []string
+		db   string
+		exp  bool
+	}{
+		{name: "portable default", args: []string{"-portable"}, db: "_internal", exp: true},
+		{name: "other database", args: []string{"-portable"}, db: "db0", exp: false},
+		{name: "force include", args: []string{"-portable", "-skip-internal=false"}, db: "_internal", exp: false},
+		{name: "explicit database", args: []string{"-portable", "-db", "_internal"}, db: "_internal", exp: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Stderr, cmd.Stdout = io.Discard, io.Discard
+			if err := cmd.parseFlags(append(tt.args, t.TempDir())); err != nil {
+				t.Fatal(err)
+			}
+			if got := cmd.skipDatabase(tt.db); got != tt.exp {
+				t.Fatalf("got %v, expected %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestCommand_ShardFailures(t *testing.T) {
+	cmd := NewCommand()
+	cmd.Stderr, cmd.Stdout = io.Discard, io.Discard
+	if err := cmd.parseFlags([]string{"-portable", "-skip-errors", t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	cmd.StdoutLogger = log.New(io.Discard, "", 0)
+	cmd.StderrLogger = log.New(io.Discard, "", 0)
+
+	// Shard IDs that are not numbers fail before contacting the server.
+	response := &snapshotter.Response{Paths: []string{
+		filepath.Join("db0", "rp0", "a"),
+		filepath.Join("_internal", "monitor", "b"),
+		filepath.Join("db0", "rp0", "c"),
+	}}
+	if err := cmd.backupResponsePaths(response); err != nil {
+		t.Fatalf("unexpected error with -skip-errors: %v", err)
+	}
+
+	err := cmd.shardFailures()
+	if err == nil {
+		t.Fatal("expected an error summarizing failed shards")
+	}
+	for _, s := range []string{"2 of 2 shards failed", "shard=a", "shard=c"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("got %q, expected it to contain %q", err, s)
+		}
+	}
+
+	// Without -skip-errors the first failure stops the backup.
+	cmd = NewCommand()
+	cmd.Stderr, cmd.Stdout = io.Discard, io.Discard
+	if err := cmd.parseFlags([]string{"-portable", t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	cmd.StderrLogger = log.New(io.Discard, "", 0)
+	if err := cmd.backupResponsePaths(response); err == nil {
+		t.Fatal("expected an error")
+	}
+	if cmd.shardN != 1 || cmd.shardFailures() != nil {
+		t.Fatalf("got %d shards attempted and failures %v, expected 1 and none", cmd.shardN, cmd.shardFailures())
+	}
+}
@@ -2,8 +2,10 @@ package backup_util
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -204,6 +206,101 @@ func LoadIncremental(dir string) (*MetaEntry, map[uint64]*Entry, error) {
 	return &metaEntry, shards, nil
 }
 
+// VerifyBackupDir checks that the backup in dir is complete without a running
+// server. It loads the most recent manifest and checks that the meta file and
+// every shard file it lists exist and hold the recorded number of bytes.
+// Compressed shard files are read in full, which also verifies their gzip
+// checksums. It returns the manifest and every inconsistency found.
+func VerifyBackupDir(dir string) (*Manifest, []error) {
+	manifests, err := filepath.Glob(filepath.Join(dir, "*.manifest"))
+	if err != nil {
+		return nil, []error{err}
+	} else if len(manifests) == 0 {
+		return nil, []error{fmt.Errorf("no manifest in %s", dir)}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(manifests)))
+
+	b, err := os.ReadFile(manifests[0])
+	if err != nil {
+		return nil, []error{err}
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, []error{fmt.Errorf("read manifest: %v", err)}
+	}
+
+	var errs []error
+	if manifest.Meta.FileName == "" {
+		errs = append(errs, errors.New("manifest has no meta file"))
+	} else if err := verifyMetaFile(filepath.Join(dir, manifest.Meta.FileName), manifest.Meta.Size); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, e := range manifest.Files {
+		if err := verifyShardFile(filepath.Join(dir, e.FileName), e.Size); err != nil {
+			errs = append(errs, fmt.Errorf("shard %d: %v", e.ShardID, err))
+		}
+	}
+	return &manifest, errs
+}
+
+// verifyMetaFile checks that a meta file holds size bytes of metadata. Both
+// metastore snapshots, which start with the backup magic header, and portable
+// meta files are accepted.
+func verifyMetaFile(fname string, size int64) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	var n int64
+	if len(b) >= 16 && binary.BigEndian.Uint64(b[:8]) == snapshotter.BackupMagicHeader {
+		n = int64(binary.BigEndian.Uint64(b[8:16]))
+		if int64(len(b)-16) < n {
+			return fmt.Errorf("meta file %s: truncated", fname)
+		}
+	} else {
+		var ep PortablePacker
+		if err := ep.UnmarshalBinary(b); err != nil {
+			return fmt.Errorf("meta file %s: invalid metadata file", fname)
+		}
+		n = int64(len(ep.Data))
+	}
+
+	if n != size {
+		return fmt.Errorf("meta file %s: got %d bytes, expected %d", fname, n, size)
+	}
+	return nil
+}
+
+// verifyShardFile checks that a shard file holds size bytes. The size of a
+// gzipped file is that of its decompressed contents.
+func verifyShardFile(fname string, size int64) (retErr error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer errors2.Capture(&retErr, f.Close)()
+
+	var r io.Reader = f
+	if strings.HasSuffix(fname, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fname, err)
+		}
+		defer errors2.Capture(&retErr, zr.Close)()
+		r = zr
+	}
+
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fname, err)
+	} else if n != size {
+		return fmt.Errorf("%s: got %d bytes, expected %d", fname, n, size)
+	}
+	return nil
+}
+
 type CountingWriter struct {
 	io.Writer
 	Total int64 // Total # of bytes transferred
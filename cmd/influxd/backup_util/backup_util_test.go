@@ -0,0 +1,101 @@
+package backup_util_test
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/cmd/influxd/backup_util"
+)
+
+// writeBackup writes a portable backup holding one meta file and one shard
+// file to dir.
+func writeBackup(t *testing.T, dir string) {
+	t.Helper()
+
+	meta := []byte("metadata")
+	b, err := backup_util.PortablePacker{Data: meta}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "20200101T000000Z.meta"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	shard := []byte("shard data shard data shard data")
+	f, err := os.Create(filepath.Join(dir, "20200101T000000Z.s1.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(shard); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := backup_util.Manifest{
+		Meta: backup_util.MetaEntry{FileName: "20200101T000000Z.meta", Size: int64(len(meta))},
+		Files: []backup_util.Entry{
+			{Database: "db", Policy: "rp", ShardID: 1, FileName: "20200101T000000Z.s1.tar.gz", Size: int64(len(shard))},
+		},
+	}
+	if err := manifest.Save(filepath.Join(dir, "20200101T000000Z.manifest")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyBackupDir(t *testing.T) {
+	dir := t.TempDir()
+	writeBackup(t, dir)
+
+	manifest, errs := backup_util.VerifyBackupDir(dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if got, exp := len(manifest.Files), 1; got != exp {
+		t.Fatalf("got %d files, expected %d", got, exp)
+	}
+}
+
+func TestVerifyBackupDir_TruncatedShard(t *testing.T) {
+	dir := t.TempDir()
+	writeBackup(t, dir)
+
+	fname := filepath.Join(dir, "20200101T000000Z.s1.tar.gz")
+	fi, err := os.Stat(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(fname, fi.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, errs := backup_util.VerifyBackupDir(dir); len(errs) != 1 {
+		t.Fatalf("got errors %v, expected one", errs)
+	}
+}
+
+func TestVerifyBackupDir_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeBackup(t, dir)
+
+	for _, name := range []string{"20200101T000000Z.meta", "20200101T000000Z.s1.tar.gz"} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, errs := backup_util.VerifyBackupDir(dir); len(errs) != 2 {
+		t.Fatalf("got errors %v, expected two", errs)
+	}
+
+	if _, errs := backup_util.VerifyBackupDir(t.TempDir()); len(errs) != 1 {
+		t.Fatalf("got errors %v, expected one for a directory without a manifest", errs)
+	}
+}
@@ -175,6 +175,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Coordinator.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.Monitor.Validate(); err != nil {
 		return err
 	}
@@ -198,6 +198,15 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	s.PointsWriter = coordinator.NewPointsWriter()
 	s.PointsWriter.AllowOutOfOrderWrites = c.Coordinator.AllowOutOfOrderWrites
 	s.PointsWriter.WriteTimeout = time.Duration(c.Coordinator.WriteTimeout)
+	s.PointsWriter.RetentionSoftMargin = time.Duration(c.Coordinator.RetentionSoftMargin)
+	s.PointsWriter.MaxDropFraction = c.Coordinator.MaxDropFraction
+	s.PointsWriter.AutoCreateLocalShards = c.Coordinator.AutoCreateLocalShards
+	s.PointsWriter.ShardCreateRetries = c.Coordinator.ShardCreateRetries
+	s.PointsWriter.CompressRemoteWrites = c.Coordinator.CompressRemoteWrites
+	s.PointsWriter.CompressHintedHandoff = c.Coordinator.CompressHintedHandoff
+	s.PointsWriter.IdempotencyKeyTTL = time.Duration(c.Coordinator.IdempotencyKeyTTL)
+	s.PointsWriter.IdempotencyCacheSize = c.Coordinator.IdempotencyCacheSize
+	s.PointsWriter.ShardTouchInterval = time.Duration(c.Coordinator.ShardTouchInterval)
 	s.PointsWriter.TSDBStore = s.TSDBStore
 	s.PointsWriter.ShardWriter = s.ShardWriter
 	s.PointsWriter.HintedHandoff = s.HintedHandoff
@@ -224,6 +233,7 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 			MetaExecutor: s.MetaExecutor,
 		},
 		StrictErrorHandling: s.TSDBStore.EngineOptions.Config.StrictErrorHandling,
+		AllowExcessReplicaN: c.Coordinator.AllowExcessReplicaN,
 		Monitor:             s.Monitor,
 		PointsWriter:        s.PointsWriter,
 		MaxSelectPointN:     c.Coordinator.MaxSelectPointN,
@@ -4,6 +4,7 @@ package coordinator
 
 import (
 	"crypto/tls"
+	"fmt"
 	"time"
 
 	"github.com/influxdata/influxdb/monitor/diagnostics"
@@ -32,6 +33,19 @@ const (
 	// DefaultWriteTimeout is the default timeout for a complete write to succeed.
 	DefaultWriteTimeout = 10 * time.Second
 
+	// DefaultMaxDropFraction is the default fraction of points in a write that may
+	// be dropped for falling outside the retention policy. A value of 1 never fails
+	// the write.
+	DefaultMaxDropFraction = 1.0
+
+	// DefaultShardCreateRetries is the default number of times a missing
+	// local shard is created and the write retried.
+	DefaultShardCreateRetries = 1
+
+	// DefaultIdempotencyCacheSize is the default maximum number of write
+	// idempotency keys remembered.
+	DefaultIdempotencyCacheSize = 10000
+
 	// DefaultMaxConcurrentQueries is the maximum number of running queries.
 	// A value of zero will make the maximum query limit unlimited.
 	DefaultMaxConcurrentQueries = 0
@@ -62,6 +76,16 @@ type Config struct {
 	HTTPSInsecureTLS      bool          `toml:"https-insecure-tls"`
 	ClusterTracing        bool          `toml:"cluster-tracing"`
 	WriteTimeout          toml.Duration `toml:"write-timeout"`
+	RetentionSoftMargin   toml.Duration `toml:"retention-soft-margin"`
+	MaxDropFraction       float64       `toml:"max-drop-fraction"`
+	AutoCreateLocalShards bool          `toml:"auto-create-local-shards"`
+	ShardCreateRetries    int           `toml:"shard-create-retries"`
+	CompressRemoteWrites  bool          `toml:"compress-remote-writes"`
+	CompressHintedHandoff bool          `toml:"compress-hinted-handoff"`
+	IdempotencyKeyTTL     toml.Duration `toml:"idempotency-key-ttl"`
+	IdempotencyCacheSize  int           `toml:"idempotency-cache-size"`
+	ShardTouchInterval    toml.Duration `toml:"shard-touch-interval"`
+	AllowExcessReplicaN   bool          `toml:"allow-excess-replica-n"`
 	MaxConcurrentQueries  int           `toml:"max-concurrent-queries"`
 	QueryTimeout          toml.Duration `toml:"query-timeout"`
 	LogQueriesAfter       toml.Duration `toml:"log-queries-after"`
@@ -78,19 +102,31 @@ type Config struct {
 // NewConfig returns an instance of Config with defaults.
 func NewConfig() Config {
 	return Config{
-		DialTimeout:          toml.Duration(DefaultDialTimeout),
-		PoolMaxIdleStreams:   DefaultPoolMaxIdleStreams,
-		PoolMaxIdleTime:      toml.Duration(DefaultPoolMaxIdleTime),
-		ShardReaderTimeout:   toml.Duration(DefaultShardReaderTimeout),
-		WriteTimeout:         toml.Duration(DefaultWriteTimeout),
-		QueryTimeout:         toml.Duration(query.DefaultQueryTimeout),
-		MaxConcurrentQueries: DefaultMaxConcurrentQueries,
-		LogTimedOutQueries:   false,
-		MaxSelectPointN:      DefaultMaxSelectPointN,
-		MaxSelectSeriesN:     DefaultMaxSelectSeriesN,
-		MaxSelectBucketsN:    DefaultMaxSelectBucketsN,
-		TerminationQueryLog:  false,
+		DialTimeout:           toml.Duration(DefaultDialTimeout),
+		PoolMaxIdleStreams:    DefaultPoolMaxIdleStreams,
+		PoolMaxIdleTime:       toml.Duration(DefaultPoolMaxIdleTime),
+		ShardReaderTimeout:    toml.Duration(DefaultShardReaderTimeout),
+		WriteTimeout:          toml.Duration(DefaultWriteTimeout),
+		MaxDropFraction:       DefaultMaxDropFraction,
+		AutoCreateLocalShards: true,
+		ShardCreateRetries:    DefaultShardCreateRetries,
+		IdempotencyCacheSize:  DefaultIdempotencyCacheSize,
+		QueryTimeout:          toml.Duration(query.DefaultQueryTimeout),
+		MaxConcurrentQueries:  DefaultMaxConcurrentQueries,
+		LogTimedOutQueries:    false,
+		MaxSelectPointN:       DefaultMaxSelectPointN,
+		MaxSelectSeriesN:      DefaultMaxSelectSeriesN,
+		MaxSelectBucketsN:     DefaultMaxSelectBucketsN,
+		TerminationQueryLog:   false,
+	}
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	if c.MaxDropFraction < 0 || c.MaxDropFraction > 1 {
+		return fmt.Errorf("max-drop-fraction must be between 0 and 1, got %v", c.MaxDropFraction)
 	}
+	return nil
 }
 
 // TLSConfig returns a TLS config.
@@ -113,6 +149,16 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"shard-reader-timeout":      c.ShardReaderTimeout,
 		"cluster-tracing":           c.ClusterTracing,
 		"write-timeout":             c.WriteTimeout,
+		"retention-soft-margin":     c.RetentionSoftMargin,
+		"max-drop-fraction":         c.MaxDropFraction,
+		"auto-create-local-shards":  c.AutoCreateLocalShards,
+		"shard-create-retries":      c.ShardCreateRetries,
+		"compress-remote-writes":    c.CompressRemoteWrites,
+		"compress-hinted-handoff":   c.CompressHintedHandoff,
+		"idempotency-key-ttl":       c.IdempotencyKeyTTL,
+		"idempotency-cache-size":    c.IdempotencyCacheSize,
+		"shard-touch-interval":      c.ShardTouchInterval,
+		"allow-excess-replica-n":    c.AllowExcessReplicaN,
 		"max-concurrent-queries":    c.MaxConcurrentQueries,
 		"query-timeout":             c.QueryTimeout,
 		"log-queries-after":         c.LogQueriesAfter,
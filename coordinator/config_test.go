@@ -13,6 +13,10 @@ func TestConfig_Parse(t *testing.T) {
 	var c coordinator.Config
 	if _, err := toml.Decode(`
 write-timeout = "20s"
+max-drop-fraction = 0.5
+shard-create-retries = 3
+compress-remote-writes = true
+compress-hinted-handoff = true
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -21,4 +25,30 @@ write-timeout = "20s"
 	if time.Duration(c.WriteTimeout) != 20*time.Second {
 		t.Fatalf("unexpected write timeout s: %s", c.WriteTimeout)
 	}
+	if c.MaxDropFraction != 0.5 {
+		t.Fatalf("unexpected max drop fraction: %v", c.MaxDropFraction)
+	}
+	if c.ShardCreateRetries != 3 {
+		t.Fatalf("unexpected shard create retries: %d", c.ShardCreateRetries)
+	}
+	if !c.CompressRemoteWrites {
+		t.Fatalf("unexpected compress remote writes: %v", c.CompressRemoteWrites)
+	}
+	if !c.CompressHintedHandoff {
+		t.Fatalf("unexpected compress hinted handoff: %v", c.CompressHintedHandoff)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := coordinator.NewConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error for default config: %v", err)
+	}
+
+	for _, f := range []float64{-0.1, 1.5} {
+		c.MaxDropFraction = f
+		if err := c.Validate(); err == nil {
+			t.Fatalf("expected error for max-drop-fraction %v", f)
+		}
+	}
 }
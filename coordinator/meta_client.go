@@ -30,6 +30,7 @@ type MetaClient interface {
 	MetaNodes() []meta.NodeInfo
 	NodeID() uint64
 	RetentionPolicy(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *meta.RetentionPolicyUpdate) ([]string, error)
 	SetAdminPrivilege(username string, admin bool) error
 	SetPrivilege(username, database string, p influxql.Privilege) error
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
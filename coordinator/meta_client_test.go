@@ -39,6 +39,8 @@ type MetaClient struct {
 	UserPrivilegeFn                     func(username, database string) (*influxql.Privilege, error)
 	UserPrivilegesFn                    func(username string) (map[string]influxql.Privilege, error)
 	UsersFn                             func() []meta.UserInfo
+
+	RetentionPolicyReplicaNUpdateSafetyFn func(database, name string, rpu *meta.RetentionPolicyUpdate) ([]string, error)
 }
 
 func (c *MetaClient) CreateContinuousQuery(database, name, query string) error {
@@ -129,6 +131,10 @@ func (c *MetaClient) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
 
+func (c *MetaClient) RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *meta.RetentionPolicyUpdate) ([]string, error) {
+	return c.RetentionPolicyReplicaNUpdateSafetyFn(database, name, rpu)
+}
+
 func (c *MetaClient) SetPrivilege(username, database string, p influxql.Privilege) error {
 	return c.SetPrivilegeFn(username, database, p)
 }
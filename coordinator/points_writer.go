@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"fmt"
@@ -27,10 +28,15 @@ const (
 	statWriteOK             = "writeOk"
 	statWritePartial        = "writePartial"
 	statWriteDrop           = "writeDrop"
+	statWriteNearExpiry     = "writeNearExpiry"
 	statWriteTimeout        = "writeTimeout"
 	statWriteErr            = "writeError"
 	statSubWriteOK          = "subWriteOk"
 	statSubWriteDrop        = "subWriteDrop"
+	statWriteDuplicate      = "writeDuplicate"
+
+	statShardGroupCreate         = "shardGroupCreate"           // Number of shard groups created by the write path.
+	statShardGroupCreateDuration = "shardGroupCreateDurationNs" // Total (wall) time spent creating shard groups.
 )
 
 var (
@@ -43,18 +49,81 @@ var (
 
 	// ErrWriteFailed is returned when no writes succeeded.
 	ErrWriteFailed = errors.New("write failed")
+
+	// ErrTooManyPointsDropped is returned when the fraction of points beyond
+	// the retention policy exceeds the configured maximum.
+	ErrTooManyPointsDropped = errors.New("too many points dropped")
+
+	// ErrNoDataNodes is returned when writing to a cluster that has no data
+	// nodes to own shards.
+	ErrNoDataNodes = errors.New("no data nodes in cluster")
+)
+
+const (
+	// shardCreateBackoff is the delay before retrying the creation of a
+	// missing local shard. It doubles with each further retry, up to
+	// maxShardCreateBackoff.
+	shardCreateBackoff    = 10 * time.Millisecond
+	maxShardCreateBackoff = time.Second
 )
 
 // PointsWriter handles writes across multiple local and remote data nodes.
 type PointsWriter struct {
+	hhSeq uint64 // sequence of writes; first for 64-bit atomic alignment
+
 	mu                    sync.RWMutex
 	closing               chan struct{}
 	AllowOutOfOrderWrites bool
 	WriteTimeout          time.Duration
 	Logger                *zap.Logger
 
+	// RetentionSoftMargin is the window at the edge of a retention policy's
+	// duration in which points are still accepted but are counted as near
+	// expiry. Zero disables the near expiry accounting.
+	RetentionSoftMargin time.Duration
+
+	// MaxDropFraction is the largest fraction of a write's points that may be
+	// dropped for falling outside the retention policy before the whole write
+	// fails with ErrTooManyPointsDropped. A value of 1, or zero when unset,
+	// never fails the write.
+	MaxDropFraction float64
+
+	// AutoCreateLocalShards creates a shard owned by this node that is missing
+	// from the local store and retries the write. When false, the write fails
+	// with tsdb.ErrShardNotFound instead.
+	AutoCreateLocalShards bool
+
+	// ShardCreateRetries is the number of times a missing local shard is
+	// created and the write retried before the write fails.
+	ShardCreateRetries int
+
+	// CompressRemoteWrites gzips the points written to remote owners when the
+	// ShardWriter implements CompressedShardWriter. Other shard writers are
+	// sent uncompressed points.
+	CompressRemoteWrites bool
+
+	// CompressHintedHandoff queues points for unreachable owners as a
+	// gzipped payload when the HintedHandoff implements
+	// CompressedHintedHandoff. Other services are queued uncompressed points.
+	CompressHintedHandoff bool
+
+	// IdempotencyKeyTTL is how long the IdempotencyKey of a successful write
+	// is remembered. A write repeating a remembered key succeeds without
+	// writing its points again. Zero disables deduplication.
+	IdempotencyKeyTTL time.Duration
+
+	// IdempotencyCacheSize is the maximum number of keys remembered. The
+	// least recently used key is forgotten first.
+	IdempotencyCacheSize int
+
+	// ShardTouchInterval is how often successful writes to shards are
+	// recorded in the metadata, when the MetaClient supports TouchShards.
+	// Zero disables recording.
+	ShardTouchInterval time.Duration
+
 	MetaClient interface {
 		NodeID() uint64
+		DataNodeCount() int
 		Database(name string) (di *meta.DatabaseInfo)
 		RetentionPolicy(database, policy string) (*meta.RetentionPolicyInfo, error)
 		CreateShardGroup(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
@@ -79,9 +148,41 @@ type PointsWriter struct {
 	}
 	subPoints []chan<- *WritePointsRequest
 
+	idempotencyKeys *idempotencyCache
+
+	touchMu        sync.Mutex
+	shardTouches   map[uint64]time.Time // last time each shard was touched
+	pendingTouches []uint64             // shards touched since the last flush
+	wg             sync.WaitGroup
+
 	stats *WriteStatistics
 }
 
+// OrderedHintedHandoff is implemented by hinted handoff services that can replay
+// queued writes in the order they were submitted. seq increases with each write
+// handled by the PointsWriter.
+type OrderedHintedHandoff interface {
+	WriteShardOrdered(seq, shardID, ownerID uint64, points []models.Point) error
+}
+
+var _ OrderedHintedHandoff = (*hh.Service)(nil)
+
+// CompressedShardWriter is implemented by shard writers that can gzip the
+// points they send to remote owners.
+type CompressedShardWriter interface {
+	WriteShardCompressed(shardID, ownerID uint64, points []models.Point) error
+}
+
+var _ CompressedShardWriter = (*ShardWriter)(nil)
+
+// CompressedHintedHandoff is implemented by hinted handoff services that can
+// queue points as a payload created by hh.CompressPoints.
+type CompressedHintedHandoff interface {
+	WriteShardCompressed(shardID, ownerID uint64, payload []byte) error
+}
+
+var _ CompressedHintedHandoff = (*hh.Service)(nil)
+
 // WritePointsRequest represents a request to write point data to the cluster.
 type WritePointsRequest struct {
 	Database        string
@@ -100,13 +201,54 @@ func (w *WritePointsRequest) AddPoint(name string, value interface{}, timestamp
 	w.Points = append(w.Points, pt)
 }
 
+// FilterMeasurements returns a request holding only the points of the named
+// measurements. w itself is returned when measurements is empty.
+func (w *WritePointsRequest) FilterMeasurements(measurements []string) *WritePointsRequest {
+	if len(measurements) == 0 {
+		return w
+	}
+
+	names := make(map[string]struct{}, len(measurements))
+	for _, name := range measurements {
+		names[name] = struct{}{}
+	}
+
+	filtered := &WritePointsRequest{Database: w.Database, RetentionPolicy: w.RetentionPolicy}
+	for _, p := range w.Points {
+		if _, ok := names[string(p.Name())]; ok {
+			filtered.Points = append(filtered.Points, p)
+		}
+	}
+	return filtered
+}
+
+// SplitByRetentionPolicy partitions points by the retention policy name that
+// rpForPoint returns for each one, keeping their relative order. Points mapped
+// to the empty name are meant for the database's default retention policy.
+func SplitByRetentionPolicy(points []models.Point, rpForPoint func(models.Point) string) map[string][]models.Point {
+	batches := make(map[string][]models.Point)
+	for _, p := range points {
+		rp := rpForPoint(p)
+		batches[rp] = append(batches[rp], p)
+	}
+	return batches
+}
+
 // NewPointsWriter returns a new instance of PointsWriter for a node.
 func NewPointsWriter() *PointsWriter {
 	return &PointsWriter{
 		AllowOutOfOrderWrites: false,
 		WriteTimeout:          DefaultWriteTimeout,
+		MaxDropFraction:       DefaultMaxDropFraction,
+		AutoCreateLocalShards: true,
+		ShardCreateRetries:    DefaultShardCreateRetries,
+		IdempotencyCacheSize:  DefaultIdempotencyCacheSize,
 		Logger:                zap.NewNop(),
 		stats:                 &WriteStatistics{},
+
+		// Start from the clock so sequence numbers keep increasing over
+		// restarts while hinted handoff queues are not empty.
+		hhSeq: uint64(time.Now().UnixNano()),
 	}
 }
 
@@ -141,6 +283,11 @@ func (w *PointsWriter) Open() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.closing = make(chan struct{})
+
+	if toucher, ok := w.MetaClient.(shardToucher); ok && w.ShardTouchInterval > 0 {
+		w.wg.Add(1)
+		go w.touchShardsLoop(toucher, w.closing)
+	}
 	return nil
 }
 
@@ -151,6 +298,7 @@ func (w *PointsWriter) Close() error {
 	if w.closing != nil {
 		close(w.closing)
 	}
+	w.wg.Wait()
 	if w.subPoints != nil {
 		// 'nil' channels always block so this makes the
 		// select statement in WritePoints hit its default case
@@ -179,10 +327,15 @@ type WriteStatistics struct {
 	WriteOK             int64
 	WritePartial        int64
 	WriteDropped        int64
+	WriteNearExpiry     int64
 	WriteTimeout        int64
 	WriteErr            int64
 	SubWriteOK          int64
 	SubWriteDrop        int64
+	WriteDuplicate      int64
+
+	ShardGroupsCreated       int64
+	ShardGroupCreateDuration int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -199,14 +352,48 @@ func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
 			statWriteOK:             atomic.LoadInt64(&w.stats.WriteOK),
 			statWritePartial:        atomic.LoadInt64(&w.stats.WritePartial),
 			statWriteDrop:           atomic.LoadInt64(&w.stats.WriteDropped),
+			statWriteNearExpiry:     atomic.LoadInt64(&w.stats.WriteNearExpiry),
 			statWriteTimeout:        atomic.LoadInt64(&w.stats.WriteTimeout),
 			statWriteErr:            atomic.LoadInt64(&w.stats.WriteErr),
 			statSubWriteOK:          atomic.LoadInt64(&w.stats.SubWriteOK),
 			statSubWriteDrop:        atomic.LoadInt64(&w.stats.SubWriteDrop),
+			statWriteDuplicate:      atomic.LoadInt64(&w.stats.WriteDuplicate),
+
+			statShardGroupCreate:         atomic.LoadInt64(&w.stats.ShardGroupsCreated),
+			statShardGroupCreateDuration: atomic.LoadInt64(&w.stats.ShardGroupCreateDuration),
 		},
 	}}
 }
 
+// AggregateWriteStatistics returns the sum of the statistics of writers. Each
+// counter is read atomically, so writers may be in use.
+func AggregateWriteStatistics(writers ...*PointsWriter) WriteStatistics {
+	var sum WriteStatistics
+	for _, w := range writers {
+		if w == nil || w.stats == nil {
+			continue
+		}
+		s := w.stats
+		sum.WriteReq += atomic.LoadInt64(&s.WriteReq)
+		sum.PointWriteReq += atomic.LoadInt64(&s.PointWriteReq)
+		sum.PointWriteReqLocal += atomic.LoadInt64(&s.PointWriteReqLocal)
+		sum.PointWriteReqRemote += atomic.LoadInt64(&s.PointWriteReqRemote)
+		sum.PointWriteReqHH += atomic.LoadInt64(&s.PointWriteReqHH)
+		sum.WriteOK += atomic.LoadInt64(&s.WriteOK)
+		sum.WritePartial += atomic.LoadInt64(&s.WritePartial)
+		sum.WriteDropped += atomic.LoadInt64(&s.WriteDropped)
+		sum.WriteNearExpiry += atomic.LoadInt64(&s.WriteNearExpiry)
+		sum.WriteTimeout += atomic.LoadInt64(&s.WriteTimeout)
+		sum.WriteErr += atomic.LoadInt64(&s.WriteErr)
+		sum.SubWriteOK += atomic.LoadInt64(&s.SubWriteOK)
+		sum.SubWriteDrop += atomic.LoadInt64(&s.SubWriteDrop)
+		sum.WriteDuplicate += atomic.LoadInt64(&s.WriteDuplicate)
+		sum.ShardGroupsCreated += atomic.LoadInt64(&s.ShardGroupsCreated)
+		sum.ShardGroupCreateDuration += atomic.LoadInt64(&s.ShardGroupCreateDuration)
+	}
+	return sum
+}
+
 // MapShards maps the points contained in wp to a ShardMapping.  If a point
 // maps to a shard group or shard that does not currently exist, it will be
 // created before returning the mapping.
@@ -221,8 +408,20 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 	// Holds all the shard groups and shards that are required for writes.
 	list := sgList{items: make(meta.ShardGroupInfos, 0, 8)}
 	min := time.Unix(0, models.MinNanoTime)
+	// Points older than nearExpiry are accepted but are about to fall out
+	// of the retention policy.
+	var nearExpiry time.Time
 	if rp.Duration > 0 {
 		min = time.Now().Add(-rp.Duration)
+		if w.RetentionSoftMargin > 0 {
+			nearExpiry = min.Add(w.RetentionSoftMargin)
+		}
+	}
+
+	// A PointsWriter not created by NewPointsWriter has no statistics.
+	stats := w.stats
+	if stats == nil {
+		stats = &WriteStatistics{}
 	}
 
 	for _, p := range wp.Points {
@@ -234,6 +433,7 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 
 		// No shard groups overlap with the point's time, so we will create
 		// a new shard group for this point.
+		start := time.Now()
 		sg, err := w.MetaClient.CreateShardGroup(wp.Database, wp.RetentionPolicy, p.Time())
 		if err != nil {
 			return nil, err
@@ -243,6 +443,8 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 			return nil, errors.New("nil shard group")
 		}
 		list.Add(*sg)
+		atomic.AddInt64(&stats.ShardGroupCreateDuration, time.Since(start).Nanoseconds())
+		atomic.AddInt64(&stats.ShardGroupsCreated, 1)
 	}
 
 	mapping := NewShardMapping(len(wp.Points))
@@ -252,11 +454,15 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 			// We didn't create a shard group because the point was outside the
 			// scope of the RP.
 			mapping.Dropped = append(mapping.Dropped, p)
-			atomic.AddInt64(&w.stats.WriteDropped, 1)
+			atomic.AddInt64(&stats.WriteDropped, 1)
 			continue
 		}
 
-		sh := sg.ShardFor(p)
+		if p.Time().Before(nearExpiry) {
+			atomic.AddInt64(&stats.WriteNearExpiry, 1)
+		}
+
+		sh := sg.ShardForTag(p, rp.WriteAffinityTag)
 		mapping.MapPoint(&sh, p)
 	}
 	return mapping, nil
@@ -357,6 +563,119 @@ func (w *PointsWriter) WritePointsInto(p *IntoWriteRequest) error {
 	return w.WritePointsPrivileged(p.Database, p.RetentionPolicy, models.ConsistencyLevelOne, p.Points)
 }
 
+// idempotencyKey identifies a batch of points. The client supplied key is
+// scoped by the database, retention policy and user of the write, so clients
+// reusing a key elsewhere cannot suppress each other's writes.
+type idempotencyKey struct {
+	database        string
+	retentionPolicy string
+	user            string
+	key             string
+}
+
+// writeIdempotent writes points with write unless a write with the same
+// idempotency key succeeded within IdempotencyKeyTTL. A write repeating the key
+// of a write still in progress waits for it, and is only written itself if
+// that write fails.
+func (w *PointsWriter) writeIdempotent(ctx context.Context, key idempotencyKey, write func() error) error {
+	w.mu.Lock()
+	if w.idempotencyKeys == nil {
+		w.idempotencyKeys = newIdempotencyCache(w.IdempotencyCacheSize)
+	}
+	keys := w.idempotencyKeys
+	w.mu.Unlock()
+
+	for {
+		e, acquired := keys.Acquire(key, time.Now())
+		if acquired {
+			err := write()
+			keys.Release(e, err, time.Now().Add(w.IdempotencyKeyTTL))
+			return err
+		}
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.closing:
+			return ErrWriteFailed
+		}
+		if e.err == nil {
+			atomic.AddInt64(&w.stats.WriteDuplicate, 1)
+			return nil
+		}
+	}
+}
+
+// idempotencyCache is a least recently used set of idempotency keys, each
+// remembered until it expires.
+type idempotencyCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of *idempotencyEntry, most recently used first
+	keys  map[idempotencyKey]*list.Element
+}
+
+type idempotencyEntry struct {
+	key     idempotencyKey
+	expires time.Time // zero while the write is in progress
+	done    chan struct{}
+	err     error
+}
+
+func newIdempotencyCache(size int) *idempotencyCache {
+	if size < 1 {
+		size = 1
+	}
+	return &idempotencyCache{
+		size:  size,
+		order: list.New(),
+		keys:  make(map[idempotencyKey]*list.Element),
+	}
+}
+
+// Acquire returns the entry for key, adding one if key is not remembered or
+// has expired by now. acquired is true if the entry was added, in which case
+// the caller writes the batch and calls Release. Otherwise the caller waits
+// for the entry to be done.
+func (c *idempotencyCache) Acquire(key idempotencyKey, now time.Time) (e *idempotencyEntry, acquired bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.keys[key]; ok {
+		e := elem.Value.(*idempotencyEntry)
+		if e.expires.IsZero() || !now.After(e.expires) {
+			c.order.MoveToFront(elem)
+			return e, false
+		}
+		c.order.Remove(elem)
+		delete(c.keys, key)
+	}
+
+	e = &idempotencyEntry{key: key, done: make(chan struct{})}
+	c.keys[key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		elem := c.order.Back()
+		c.order.Remove(elem)
+		delete(c.keys, elem.Value.(*idempotencyEntry).key)
+	}
+	return e, true
+}
+
+// Release records the result of the write of an acquired entry. A successful
+// write is remembered until expires, a failed one is forgotten.
+func (c *idempotencyCache) Release(e *idempotencyEntry, err error, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.err, e.expires = err, expires
+	if elem, ok := c.keys[e.key]; err != nil && ok && elem.Value == e {
+		c.order.Remove(elem)
+		delete(c.keys, e.key)
+	}
+	close(e.done)
+}
+
 // A wrapper for WritePointsWithContext()
 func (w *PointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
 	return w.WritePointsWithContext(context.Background(), database, retentionPolicy, consistencyLevel, user, points)
@@ -368,10 +687,32 @@ type ContextKey int
 const (
 	StatPointsWritten = ContextKey(iota)
 	StatValuesWritten
+
+	// IdempotencyKey is the context key of an opaque client supplied string
+	// identifying a batch of points. Retrying a batch with the same key to the
+	// same database and retention policy as the same user does not write its
+	// points twice.
+	//
+	// The key travels in the context rather than in a WritePointsRequest field
+	// so that WritePointsRequest stays convertible to IntoWriteRequest, and so
+	// the existing WritePointsWithContext signature can carry it.
+	IdempotencyKey
 )
 
 // WritePointsWithContext writes data to the underlying storage. consitencyLevel and user are only used for clustered scenarios.
+//
+// If an IdempotencyKey is sent via context values and IdempotencyKeyTTL is set,
+// a batch repeating the key of a recent successful write is not written again.
 func (w *PointsWriter) WritePointsWithContext(ctx context.Context, database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	if key, _ := ctx.Value(IdempotencyKey).(string); key != "" && w.IdempotencyKeyTTL > 0 {
+		ikey := idempotencyKey{database: database, retentionPolicy: retentionPolicy, key: key}
+		if user != nil {
+			ikey.user = user.ID()
+		}
+		return w.writeIdempotent(ctx, ikey, func() error {
+			return w.WritePointsPrivilegedWithContext(ctx, database, retentionPolicy, consistencyLevel, points)
+		})
+	}
 	return w.WritePointsPrivilegedWithContext(ctx, database, retentionPolicy, consistencyLevel, points)
 }
 
@@ -389,6 +730,11 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 	atomic.AddInt64(&w.stats.WriteReq, 1)
 	atomic.AddInt64(&w.stats.PointWriteReq, int64(len(points)))
 
+	// Without data nodes no shard group can be created for the points.
+	if w.MetaClient.DataNodeCount() == 0 {
+		return ErrNoDataNodes
+	}
+
 	if retentionPolicy == "" {
 		db := w.MetaClient.Database(database)
 		if db == nil {
@@ -402,6 +748,42 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 		return err
 	}
 
+	// Drop points for shards that no owner can be found for, rather than
+	// attempting writes that are bound to fail.
+	var noOwners int
+	resolvable := make(map[uint64]bool)
+	for shardID, shard := range shardMappings.Shards {
+		if w.shardOwnersResolvable(shard, resolvable) {
+			continue
+		}
+		w.Logger.Warn("Dropping points for shard with no resolvable owners", zap.Uint64("shard_id", shardID))
+		noOwners += len(shardMappings.Points[shardID])
+		delete(shardMappings.Points, shardID)
+		delete(shardMappings.Shards, shardID)
+	}
+
+	// Points dropped for any reason are reported together in one partial
+	// write error.
+	var partial tsdb.PartialWriteError
+	addDropped := func(e tsdb.PartialWriteError) {
+		if partial.Reason != "" {
+			partial.Reason += "; "
+		}
+		partial.Reason += e.Reason
+		partial.Dropped += e.Dropped
+		partial.DroppedKeys = append(partial.DroppedKeys, e.DroppedKeys...)
+	}
+	if n := len(shardMappings.Dropped); n > 0 {
+		addDropped(tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: n})
+	}
+	if noOwners > 0 {
+		addDropped(tsdb.PartialWriteError{Reason: "shard has no resolvable owners", Dropped: noOwners})
+	}
+
+	if n := partial.Dropped; n > 0 && w.MaxDropFraction > 0 && float64(n) > w.MaxDropFraction*float64(len(points)) {
+		return ErrTooManyPointsDropped
+	}
+
 	// Write each shard in it's own goroutine and return as soon as one fails.
 	ch := make(chan error, len(shardMappings.Points))
 	for shardID, points := range shardMappings.Points {
@@ -411,7 +793,9 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 			ctx = context.WithValue(ctx, tsdb.StatValuesWritten, &numValues)
 
 			err := w.writeToShardWithContext(ctx, shard, database, retentionPolicy, consistencyLevel, points)
-			if err == tsdb.ErrShardDeletion {
+			if err == nil {
+				w.touchShard(shard.ID)
+			} else if err == tsdb.ErrShardDeletion {
 				err = tsdb.PartialWriteError{Reason: fmt.Sprintf("shard %d is pending deletion", shard.ID), Dropped: len(points)}
 			}
 
@@ -450,20 +834,115 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 		atomic.AddInt64(&w.stats.SubWriteDrop, dropped)
 	}
 
-	if err == nil && len(shardMappings.Dropped) > 0 {
-		err = tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: len(shardMappings.Dropped)}
-	}
 	for range shardMappings.Points {
 		select {
 		case <-w.closing:
 			return ErrWriteFailed
 		case err := <-ch:
-			if err != nil {
+			if e, ok := err.(tsdb.PartialWriteError); ok {
+				addDropped(e)
+			} else if err != nil {
 				return err
 			}
 		}
 	}
-	return err
+	if partial.Dropped > 0 {
+		return partial
+	}
+	return nil
+}
+
+// shardOwnersResolvable returns true if at least one owner of shard is this
+// node or a data node known to the meta client. Owners are assumed resolvable
+// when the meta client cannot look up data nodes. Lookups are cached in
+// resolvable by node ID, so each node is only looked up once per write.
+func (w *PointsWriter) shardOwnersResolvable(shard *meta.ShardInfo, resolvable map[uint64]bool) bool {
+	if len(shard.Owners) == 0 {
+		return false
+	}
+
+	resolver, ok := w.MetaClient.(interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+	})
+	if !ok {
+		return true
+	}
+
+	for _, owner := range shard.Owners {
+		if owner.NodeID == w.MetaClient.NodeID() {
+			return true
+		}
+		ok, cached := resolvable[owner.NodeID]
+		if !cached {
+			ni, err := resolver.DataNode(owner.NodeID)
+			ok = err == nil && ni != nil
+			resolvable[owner.NodeID] = ok
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// shardToucher is implemented by meta clients that record the time of the
+// latest write to shards.
+type shardToucher interface {
+	TouchShards(ids []uint64, t time.Time) error
+}
+
+// touchShard queues a successful write to a shard to be recorded in the
+// metadata, at most once per ShardTouchInterval.
+func (w *PointsWriter) touchShard(shardID uint64) {
+	if w.ShardTouchInterval <= 0 {
+		return
+	}
+
+	now := time.Now()
+	w.touchMu.Lock()
+	defer w.touchMu.Unlock()
+	if last, ok := w.shardTouches[shardID]; ok && now.Sub(last) < w.ShardTouchInterval {
+		return
+	}
+	if w.shardTouches == nil {
+		w.shardTouches = make(map[uint64]time.Time)
+	}
+	w.shardTouches[shardID] = now
+	w.pendingTouches = append(w.pendingTouches, shardID)
+}
+
+// touchShardsLoop records the queued shard writes in the metadata in one batch
+// every ShardTouchInterval, until closing is closed.
+func (w *PointsWriter) touchShardsLoop(toucher shardToucher, closing <-chan struct{}) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.ShardTouchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case now := <-ticker.C:
+			w.touchMu.Lock()
+			ids := w.pendingTouches
+			w.pendingTouches = nil
+			// Forget shards not touched within the interval, which no longer
+			// throttle anything, so dropped shards are not kept forever.
+			for id, last := range w.shardTouches {
+				if now.Sub(last) >= w.ShardTouchInterval {
+					delete(w.shardTouches, id)
+				}
+			}
+			w.touchMu.Unlock()
+
+			if len(ids) == 0 {
+				continue
+			}
+			if err := toucher.TouchShards(ids, now); err != nil {
+				w.Logger.Warn("Failed to record shard write times", zap.Int("shards", len(ids)), zap.Error(err))
+			}
+		}
+	}
 }
 
 // writeToShards writes points to a shard and ensures a write consistency level has been met.
@@ -473,7 +952,10 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 }
 
 func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.ShardInfo, database, retentionPolicy string, consistency models.ConsistencyLevel, points []models.Point) error {
-	// The required number of writes to achieve the requested consistency level
+	// The required number of writes to achieve the requested consistency level.
+	// It is based on the shard's owners rather than the retention policy's
+	// replication factor, so shard groups created with their own ReplicaN are
+	// held to their own owner count.
 	required := len(shard.Owners)
 	switch consistency {
 	case models.ConsistencyLevelAny, models.ConsistencyLevelOne:
@@ -501,6 +983,33 @@ func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.
 		return nil
 	}
 
+	// Queues points for an owner via hinted handoff, compressed when enabled
+	// and supported, or else tagged with the write's sequence number when the
+	// service can replay in order.
+	seq := atomic.AddUint64(&w.hhSeq, 1)
+	writeHintedHandoff := func(sid, ownerID uint64, pts []models.Point) error {
+		if chh, ok := w.HintedHandoff.(CompressedHintedHandoff); ok && w.CompressHintedHandoff {
+			payload, err := hh.CompressPoints(pts)
+			if err != nil {
+				return err
+			}
+			return chh.WriteShardCompressed(sid, ownerID, payload)
+		}
+		if ohh, ok := w.HintedHandoff.(OrderedHintedHandoff); ok {
+			return ohh.WriteShardOrdered(seq, sid, ownerID, pts)
+		}
+		return w.HintedHandoff.WriteShard(sid, ownerID, pts)
+	}
+
+	// Sends points to a remote owner, compressed when enabled and supported
+	// by the shard writer.
+	writeRemote := func(sid, ownerID uint64, pts []models.Point) error {
+		if csw, ok := w.ShardWriter.(CompressedShardWriter); ok && w.CompressRemoteWrites {
+			return csw.WriteShardCompressed(sid, ownerID, pts)
+		}
+		return w.ShardWriter.WriteShard(sid, ownerID, pts)
+	}
+
 	// response channel for each shard writer go routine
 	type AsyncWriteResult struct {
 		Owner meta.ShardOwner
@@ -514,19 +1023,41 @@ func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.
 				atomic.AddInt64(&w.stats.PointWriteReqLocal, int64(len(points)))
 				// Except tsdb.ErrShardNotFound no error can be handled here
 				err := writeToShard(shardID, points)
-				if err == tsdb.ErrShardNotFound {
+				if err == tsdb.ErrShardNotFound && w.AutoCreateLocalShards {
 					// Shard doesn't exist -- lets create it and try again..
 					// If we've written to shard that should exist on the current node, but the
 					// store has not actually created this shard, tell it to create it and
-					// retry the write
-					err = w.TSDBStore.CreateShard(database, retentionPolicy, shardID, true)
-					if err != nil {
-						w.Logger.Warn("Write failed with creating shard", zap.Uint64("node_id", owner.NodeID), zap.Uint64("shard_id", shardID), zap.Error(err))
-						ch <- &AsyncWriteResult{owner, err}
-						return
+					// retry the write. Another writer may be creating the same shard, so
+					// a write that still finds no shard is retried with backoff.
+					backoff := shardCreateBackoff
+				retry:
+					for attempt := 1; ; attempt++ {
+						err = w.TSDBStore.CreateShard(database, retentionPolicy, shardID, true)
+						if err != nil {
+							w.Logger.Warn("Write failed with creating shard", zap.Uint64("node_id", owner.NodeID), zap.Uint64("shard_id", shardID), zap.Error(err))
+							break
+						}
+
+						// Now that we've created the shard, try to write to it again.
+						err = writeToShard(shardID, points)
+						if err != tsdb.ErrShardNotFound || attempt >= w.ShardCreateRetries {
+							break
+						}
+
+						timer := time.NewTimer(backoff)
+						select {
+						case <-ctx.Done():
+							timer.Stop()
+							break retry
+						case <-w.closing:
+							timer.Stop()
+							break retry
+						case <-timer.C:
+						}
+						if backoff *= 2; backoff > maxShardCreateBackoff {
+							backoff = maxShardCreateBackoff
+						}
 					}
-					// Now that we've created the shard, try to write to it again.
-					err = writeToShard(shardID, points)
 				}
 				ch <- &AsyncWriteResult{owner, err}
 				return
@@ -534,7 +1065,7 @@ func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.
 
 			if !w.AllowOutOfOrderWrites && !w.HintedHandoff.Empty(shardID, owner.NodeID) {
 				atomic.AddInt64(&w.stats.PointWriteReqHH, int64(len(points)))
-				hherr := w.HintedHandoff.WriteShard(shardID, owner.NodeID, points)
+				hherr := writeHintedHandoff(shardID, owner.NodeID, points)
 				if hherr != nil {
 					w.Logger.Warn("Write shard failed with hinted handoff", zap.Uint64("node_id", owner.NodeID), zap.Uint64("shard_id", shardID), zap.Error(hherr))
 					ch <- &AsyncWriteResult{owner, hherr}
@@ -545,11 +1076,11 @@ func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.
 			}
 
 			atomic.AddInt64(&w.stats.PointWriteReqRemote, int64(len(points)))
-			err := w.ShardWriter.WriteShard(shardID, owner.NodeID, points)
+			err := writeRemote(shardID, owner.NodeID, points)
 			if err != nil && hh.IsRetryable(err) {
 				// The remote write failed so queue it via hinted handoff
 				atomic.AddInt64(&w.stats.PointWriteReqHH, int64(len(points)))
-				hherr := w.HintedHandoff.WriteShard(shardID, owner.NodeID, points)
+				hherr := writeHintedHandoff(shardID, owner.NodeID, points)
 				if hherr != nil {
 					w.Logger.Warn("Write shard failed with both shard writer and hinted handoff", zap.Uint64("node_id", owner.NodeID), zap.Uint64("shard_id", shardID), zap.Error(err))
 					ch <- &AsyncWriteResult{owner, hherr}
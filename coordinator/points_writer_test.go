@@ -1,6 +1,8 @@
 package coordinator_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/hh"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
 )
@@ -243,6 +246,194 @@ func TestPointsWriter_MapShards_Invalid(t *testing.T) {
 	}
 }
 
+// Ensures the points writer counts points written close to the retention
+// policy's edge.
+func TestPointsWriter_MapShards_NearExpiry(t *testing.T) {
+	ms := PointsWriterMetaClient{}
+	rp := NewRetentionPolicy("myp", time.Hour, 3)
+
+	now := time.Now()
+	rp.ShardGroups[0].StartTime = now.Add(-58 * time.Minute)
+	rp.ShardGroups[0].EndTime = now.Add(time.Hour)
+
+	ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+		return rp, nil
+	}
+
+	ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		return &rp.ShardGroups[0], nil
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.RetentionSoftMargin = 10 * time.Minute
+	defer c.Close()
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+
+	// Just inside the margin, just outside the margin and beyond the
+	// retention policy altogether.
+	pr.AddPoint("cpu", 1.0, now.Add(-55*time.Minute), nil)
+	pr.AddPoint("cpu", 2.0, now.Add(-45*time.Minute), nil)
+	pr.AddPoint("cpu", 3.0, now.Add(-2*time.Hour), nil)
+
+	shardMappings, err := c.MapShards(pr)
+	if err != nil {
+		t.Fatalf("unexpected an error: %v", err)
+	}
+
+	if got, exp := len(shardMappings.Dropped), 1; got != exp {
+		t.Fatalf("MapShard() dropped mismatch: got %v, exp %v", got, exp)
+	}
+
+	values := c.Statistics(nil)[0].Values
+	if got, exp := values["writeNearExpiry"], int64(1); got != exp {
+		t.Fatalf("near expiry mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+// Ensures the points writer records shard groups created while mapping.
+func TestPointsWriter_MapShards_ShardGroupCreateStats(t *testing.T) {
+	ms := PointsWriterMetaClient{}
+	rp := NewRetentionPolicy("myp", time.Hour, 3)
+
+	ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+		return rp, nil
+	}
+
+	const delay = 5 * time.Millisecond
+	now := time.Now()
+	var createErr error
+	ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		time.Sleep(delay)
+		if createErr != nil {
+			return nil, createErr
+		}
+		return &meta.ShardGroupInfo{
+			Shards:    make([]meta.ShardInfo, 1),
+			StartTime: now, EndTime: now.Add(time.Minute),
+		}, nil
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	defer c.Close()
+
+	// Both points fit in a single shard group.
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("cpu", 2.0, now.Add(time.Second), nil)
+	if _, err := c.MapShards(pr); err != nil {
+		t.Fatalf("unexpected an error: %v", err)
+	}
+
+	if got, exp := c.Statistics(nil)[0].Values["shardGroupCreate"], int64(1); got != exp {
+		t.Fatalf("shard groups created mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Each request maps its own shard groups, so another write creates again.
+	if _, err := c.MapShards(pr); err != nil {
+		t.Fatalf("unexpected an error: %v", err)
+	}
+
+	values := c.Statistics(nil)[0].Values
+	if got, exp := values["shardGroupCreate"], int64(2); got != exp {
+		t.Fatalf("shard groups created mismatch: got %v, exp %v", got, exp)
+	}
+	duration, ok := values["shardGroupCreateDurationNs"].(int64)
+	if !ok || duration < int64(2*delay) {
+		t.Fatalf("shard group create duration: got %v, exp at least %v", values["shardGroupCreateDurationNs"], int64(2*delay))
+	}
+
+	// A failed creation is neither counted nor timed.
+	createErr = errors.New("meta service unavailable")
+	if _, err := c.MapShards(pr); err != createErr {
+		t.Fatalf("unexpected error: got %v, exp %v", err, createErr)
+	}
+
+	values = c.Statistics(nil)[0].Values
+	if got, exp := values["shardGroupCreate"], int64(2); got != exp {
+		t.Fatalf("shard groups created mismatch: got %v, exp %v", got, exp)
+	}
+	if got := values["shardGroupCreateDurationNs"]; got != duration {
+		t.Fatalf("shard group create duration changed on error: got %v, exp %v", got, duration)
+	}
+}
+
+// Ensures the points writer keeps series sharing the write affinity tag in
+// one shard.
+func TestPointsWriter_MapShards_WriteAffinityTag(t *testing.T) {
+	ms := PointsWriterMetaClient{}
+	rp := NewRetentionPolicy("myp", time.Hour, 1)
+	rp.WriteAffinityTag = "host"
+	for i := 0; i < 7; i++ {
+		rp.ShardGroups[0].Shards = append(rp.ShardGroups[0].Shards, meta.ShardInfo{ID: nextShardID()})
+	}
+
+	ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+		return rp, nil
+	}
+
+	ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		return &rp.ShardGroups[0], nil
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	defer c.Close()
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	now := time.Now()
+	for i := 0; i < 16; i++ {
+		pr.AddPoint(fmt.Sprintf("m%d", i), 1.0, now, map[string]string{"host": "server01", "core": fmt.Sprint(i)})
+	}
+
+	shardMappings, err := c.MapShards(pr)
+	if err != nil {
+		t.Fatalf("unexpected an error: %v", err)
+	}
+
+	if got, exp := len(shardMappings.Points), 1; got != exp {
+		t.Fatalf("MapShards() len mismatch. got %v, exp %v", got, exp)
+	}
+}
+
+func TestSplitByRetentionPolicy(t *testing.T) {
+	now := time.Now()
+	points := []models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"tier": "hot"}), models.Fields{"value": 1.0}, now),
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"tier": "cold"}), models.Fields{"value": 2.0}, now),
+		models.MustNewPoint("mem", models.NewTags(map[string]string{"tier": "hot"}), models.Fields{"value": 3.0}, now),
+		models.MustNewPoint("mem", nil, models.Fields{"value": 4.0}, now),
+	}
+
+	batches := coordinator.SplitByRetentionPolicy(points, func(p models.Point) string {
+		switch string(p.Tags().Get([]byte("tier"))) {
+		case "hot":
+			return "short"
+		case "cold":
+			return "long"
+		}
+		return ""
+	})
+
+	exp := map[string][]models.Point{
+		"short": {points[0], points[2]},
+		"long":  {points[1]},
+		"":      {points[3]},
+	}
+	if !reflect.DeepEqual(batches, exp) {
+		t.Fatalf("got %v, expected %v", batches, exp)
+	}
+}
+
 func TestPointsWriter_WritePoints(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -445,6 +636,779 @@ func TestPointsWriter_WritePoints_Dropped(t *testing.T) {
 	}
 }
 
+// Ensures a write fails outright when too many of its points are dropped.
+func TestPointsWriter_WritePoints_TooManyDropped(t *testing.T) {
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+
+	ms := NewPointsWriterMetaClient()
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	// Two of five points are in range, the rest are beyond the retention policy.
+	now := time.Now()
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("cpu", 2.0, now.Add(time.Second), nil)
+	for i := 0; i < 3; i++ {
+		pr.AddPoint("cpu", 3.0, now.Add(-24*time.Hour), nil)
+	}
+
+	var writes int64
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			atomic.AddInt64(&writes, 1)
+			return nil
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.MaxDropFraction = 0.5
+
+	c.Open()
+	defer c.Close()
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	if err != coordinator.ErrTooManyPointsDropped {
+		t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, coordinator.ErrTooManyPointsDropped)
+	}
+	if got := atomic.LoadInt64(&writes); got != 0 {
+		t.Fatalf("got %d shard writes, expected none", got)
+	}
+
+	// An unset fraction keeps the partial write behaviour, even when every
+	// point is dropped.
+	c.MaxDropFraction = 0
+	err = c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points[2:])
+	if _, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, tsdb.PartialWriteError{})
+	}
+}
+
+// Ensures a request is filtered down to the points of the named measurements.
+func TestWritePointsRequest_FilterMeasurements(t *testing.T) {
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	now := time.Now()
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("mem", 2.0, now, nil)
+	pr.AddPoint("cpu", 3.0, now.Add(time.Second), nil)
+
+	names := func(req *coordinator.WritePointsRequest) []string {
+		var s []string
+		for _, p := range req.Points {
+			s = append(s, string(p.Name()))
+		}
+		return s
+	}
+
+	if got := pr.FilterMeasurements(nil); got != pr {
+		t.Fatal("expected an unfiltered request to be returned as is")
+	}
+	req := pr.FilterMeasurements([]string{"cpu"})
+	if got, exp := names(req), []string{"cpu", "cpu"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if req.Database != "mydb" || req.RetentionPolicy != "myrp" {
+		t.Fatalf("got %s.%s, expected mydb.myrp", req.Database, req.RetentionPolicy)
+	}
+	if got := pr.FilterMeasurements([]string{"disk"}); len(got.Points) != 0 {
+		t.Fatalf("got %v, expected no points", names(got))
+	}
+}
+
+// Ensures points for a shard whose owners are all unknown data nodes are
+// dropped without attempting the write.
+func TestPointsWriter_WritePoints_NoResolvableOwners(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.NodeIDFn = func() uint64 { return 4 }
+	mc := &resolvingMetaClient{
+		PointsWriterMetaClient: *ms,
+		DataNodeFn: func(id uint64) (*meta.NodeInfo, error) {
+			return nil, meta.ErrNodeNotFound
+		},
+	}
+
+	var writes int64
+	sw := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			atomic.AddInt64(&writes, 1)
+			return nil
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = mc
+	c.ShardWriter = sw
+	c.HintedHandoff = &fakeHintedHandoff{
+		EmptyFn: func(shardID, nodeID uint64) bool { return true },
+	}
+
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+	pr.AddPoint("cpu", 2.0, time.Now().Add(time.Second), nil)
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	exp := tsdb.PartialWriteError{Reason: "shard has no resolvable owners", Dropped: 2}
+	if !reflect.DeepEqual(err, exp) {
+		t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, exp)
+	}
+	if got := atomic.LoadInt64(&writes); got != 0 {
+		t.Fatalf("got %d shard writes, expected none", got)
+	}
+
+	// Once an owner is known the write goes ahead.
+	mc.DataNodeFn = func(id uint64) (*meta.NodeInfo, error) {
+		if id == 2 {
+			return &meta.NodeInfo{ID: id}, nil
+		}
+		return nil, meta.ErrNodeNotFound
+	}
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelAll, pr.Points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&writes); got == 0 {
+		t.Fatal("expected shard writes")
+	}
+}
+
+// Ensures points dropped for different reasons are reported together and
+// count towards the maximum drop fraction.
+func TestPointsWriter_WritePoints_DropsSummed(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.NodeIDFn = func() uint64 { return 4 }
+	lookups := make(map[uint64]int)
+	mc := &resolvingMetaClient{
+		PointsWriterMetaClient: *ms,
+		DataNodeFn: func(id uint64) (*meta.NodeInfo, error) {
+			lookups[id]++
+			return nil, meta.ErrNodeNotFound
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = mc
+	c.ShardWriter = &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			t.Fatal("unexpected shard write")
+			return nil
+		},
+	}
+	c.HintedHandoff = &fakeHintedHandoff{
+		EmptyFn: func(shardID, nodeID uint64) bool { return true },
+	}
+
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	now := time.Now()
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("cpu", 2.0, now.Add(time.Second), nil)
+	pr.AddPoint("cpu", 3.0, now.Add(-24*time.Hour), nil)
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	exp := tsdb.PartialWriteError{Reason: "points beyond retention policy; shard has no resolvable owners", Dropped: 3}
+	if !reflect.DeepEqual(err, exp) {
+		t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, exp)
+	}
+	for id, n := range lookups {
+		if n != 1 {
+			t.Fatalf("data node %d looked up %d times, expected once", id, n)
+		}
+	}
+
+	c.MaxDropFraction = 0.5
+	err = c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	if err != coordinator.ErrTooManyPointsDropped {
+		t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, coordinator.ErrTooManyPointsDropped)
+	}
+}
+
+// Ensures the aggregated statistics of several points writers are their sum.
+func TestAggregateWriteStatistics(t *testing.T) {
+	newWriter := func() *coordinator.PointsWriter {
+		ms := PointsWriterMetaClient{}
+		rp := NewRetentionPolicy("myp", time.Hour, 1)
+		ms.NodeIDFn = func() uint64 { return 1 }
+		ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+			return rp, nil
+		}
+		ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+			return &rp.ShardGroups[0], nil
+		}
+
+		c := coordinator.NewPointsWriter()
+		c.MetaClient = ms
+		c.TSDBStore = &fakeStore{
+			WriteFn: func(shardID uint64, points []models.Point) error { return nil },
+		}
+		return c
+	}
+
+	write := func(c *coordinator.PointsWriter, n int) {
+		pr := &coordinator.WritePointsRequest{
+			Database:        "mydb",
+			RetentionPolicy: "myrp",
+		}
+		for i := 0; i < n; i++ {
+			pr.AddPoint("cpu", float64(i), time.Now(), nil)
+		}
+		if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	w1, w2 := newWriter(), newWriter()
+	w1.Open()
+	defer w1.Close()
+	w2.Open()
+	defer w2.Close()
+
+	write(w1, 2)
+	write(w1, 3)
+	write(w2, 4)
+
+	stats := coordinator.AggregateWriteStatistics(w1, w2)
+	if got, exp := stats.WriteReq, int64(3); got != exp {
+		t.Fatalf("got %d write requests, expected %d", got, exp)
+	}
+	if got, exp := stats.PointWriteReq, int64(9); got != exp {
+		t.Fatalf("got %d point write requests, expected %d", got, exp)
+	}
+	if got, exp := stats.PointWriteReqLocal, int64(9); got != exp {
+		t.Fatalf("got %d local point write requests, expected %d", got, exp)
+	}
+}
+
+// Ensures a write repeating the idempotency key of a successful write succeeds
+// without writing its points again, including while the first write is still
+// in progress.
+func TestPointsWriter_WritePoints_IdempotencyKey(t *testing.T) {
+	ms := PointsWriterMetaClient{}
+	rp := NewRetentionPolicy("myp", time.Hour, 1)
+	ms.NodeIDFn = func() uint64 { return 1 }
+	ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+		return rp, nil
+	}
+	ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		return &rp.ShardGroups[0], nil
+	}
+
+	var writes int64
+	unblock := make(chan struct{})
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			atomic.AddInt64(&writes, 1)
+			<-unblock
+			return nil
+		},
+	}
+	c.IdempotencyKeyTTL = time.Minute
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+	writeAs := func(database string, user meta.User, key string) error {
+		ctx := context.WithValue(context.Background(), coordinator.IdempotencyKey, key)
+		return c.WritePointsWithContext(ctx, database, pr.RetentionPolicy, models.ConsistencyLevelOne, user, pr.Points)
+	}
+	write := func(key string) error { return writeAs(pr.Database, nil, key) }
+
+	// Concurrent writes with the same key only write the points once.
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() { errs <- write("batch-1") }()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(unblock)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("write %d: unexpected error: %v", i, err)
+		}
+	}
+	if got := atomic.LoadInt64(&writes); got != 1 {
+		t.Fatalf("got %d writes, expected 1", got)
+	}
+
+	// A retry after the write completed is not written either.
+	if err := write("batch-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&writes); got != 1 {
+		t.Fatalf("got %d writes, expected 1", got)
+	}
+	if got := coordinator.AggregateWriteStatistics(c).WriteDuplicate; got != 3 {
+		t.Fatalf("got %d duplicate writes, expected 3", got)
+	}
+
+	// A different key is written.
+	if err := write("batch-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&writes); got != 2 {
+		t.Fatalf("got %d writes, expected 2", got)
+	}
+
+	// The same key is written again to another database or by another user.
+	if err := writeAs("otherdb", nil, "batch-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := writeAs(pr.Database, &meta.UserInfo{Name: "fred"}, "batch-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&writes); got != 4 {
+		t.Fatalf("got %d writes, expected 4", got)
+	}
+}
+
+// Ensures successful shard writes are recorded in the metadata in one batch per
+// ShardTouchInterval.
+func TestPointsWriter_WritePoints_ShardTouchInterval(t *testing.T) {
+	rp := NewRetentionPolicy("myp", time.Hour, 1)
+	touches := make(chan []uint64, 10)
+	mc := &touchingMetaClient{
+		TouchShardsFn: func(ids []uint64, t time.Time) error {
+			touches <- ids
+			return nil
+		},
+	}
+	mc.NodeIDFn = func() uint64 { return 1 }
+	mc.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+		return rp, nil
+	}
+	mc.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		return &rp.ShardGroups[0], nil
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = mc
+	c.TSDBStore = &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error { return nil },
+	}
+	c.ShardTouchInterval = 50 * time.Millisecond
+	c.Open()
+	defer c.Close()
+
+	write := func() {
+		if err := c.WritePointsPrivileged("mydb", "myrp", models.ConsistencyLevelOne, []models.Point{
+			models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, time.Now()),
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expectTouch := func() {
+		select {
+		case ids := <-touches:
+			if exp := []uint64{rp.ShardGroups[0].Shards[0].ID}; !reflect.DeepEqual(ids, exp) {
+				t.Fatalf("got shards %v touched, expected %v", ids, exp)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for shard touch")
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		write()
+	}
+	expectTouch()
+	select {
+	case ids := <-touches:
+		t.Fatalf("shards %v touched again without a write", ids)
+	case <-time.After(3 * c.ShardTouchInterval):
+	}
+
+	// A write after the interval is recorded again.
+	write()
+	expectTouch()
+}
+
+// Ensures writes to a cluster without data nodes fail with ErrNoDataNodes
+// before any shard group is created.
+func TestPointsWriter_WritePoints_NoDataNodes(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DataNodeCountFn = func() int { return 0 }
+	ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+		t.Fatal("unexpected shard group creation")
+		return nil, nil
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "mydb",
+		RetentionPolicy: "myrp",
+	}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err != coordinator.ErrNoDataNodes {
+		t.Fatalf("got %v, expected %v", err, coordinator.ErrNoDataNodes)
+	}
+}
+
+// Ensures a missing local shard is only created when AutoCreateLocalShards is set.
+func TestPointsWriter_WritePoints_AutoCreateLocalShards(t *testing.T) {
+	for _, autoCreate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("auto-create=%v", autoCreate), func(t *testing.T) {
+			ms := PointsWriterMetaClient{}
+			rp := NewRetentionPolicy("myp", time.Hour, 1)
+			ms.NodeIDFn = func() uint64 { return 1 }
+			ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+				return rp, nil
+			}
+			ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+				return &rp.ShardGroups[0], nil
+			}
+
+			var created int64
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					if atomic.LoadInt64(&created) == 0 {
+						return tsdb.ErrShardNotFound
+					}
+					return nil
+				},
+				CreateShardfn: func(database, retentionPolicy string, shardID uint64, enabled bool) error {
+					atomic.AddInt64(&created, 1)
+					return nil
+				},
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.AutoCreateLocalShards = autoCreate
+
+			c.Open()
+			defer c.Close()
+
+			pr := &coordinator.WritePointsRequest{
+				Database:        "mydb",
+				RetentionPolicy: "myrp",
+			}
+			pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+			err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+			if autoCreate {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := atomic.LoadInt64(&created); got != 1 {
+					t.Fatalf("got %d shards created, expected 1", got)
+				}
+				return
+			}
+
+			if exp := fmt.Errorf("write failed: %v", tsdb.ErrShardNotFound); err == nil || err.Error() != exp.Error() {
+				t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, exp)
+			}
+			if got := atomic.LoadInt64(&created); got != 0 {
+				t.Fatalf("got %d shards created, expected none", got)
+			}
+		})
+	}
+}
+
+// Ensures a local write is retried up to ShardCreateRetries times while
+// writing to a newly created shard still finds no shard, and that other
+// errors aren't retried.
+func TestPointsWriter_WritePoints_ShardCreateRetries(t *testing.T) {
+	errBusy := errors.New("shard busy")
+	for _, tt := range []struct {
+		name       string
+		retries    int
+		retryErr   error // returned by the write after the first creation
+		expErr     error
+		expCreated int64
+	}{
+		{name: "exhausted", retries: 1, retryErr: tsdb.ErrShardNotFound, expErr: tsdb.ErrShardNotFound, expCreated: 1},
+		{name: "retried", retries: 2, retryErr: tsdb.ErrShardNotFound, expCreated: 2},
+		{name: "other error", retries: 2, retryErr: errBusy, expErr: errBusy, expCreated: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := PointsWriterMetaClient{}
+			rp := NewRetentionPolicy("myp", time.Hour, 1)
+			ms.NodeIDFn = func() uint64 { return 1 }
+			ms.RetentionPolicyFn = func(db, retentionPolicy string) (*meta.RetentionPolicyInfo, error) {
+				return rp, nil
+			}
+			ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+				return &rp.ShardGroups[0], nil
+			}
+
+			// The first write finds no shard and the write after the first
+			// creation fails with retryErr, as if another writer was creating it.
+			var writes, created int64
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					switch atomic.AddInt64(&writes, 1) {
+					case 1:
+						return tsdb.ErrShardNotFound
+					case 2:
+						return tt.retryErr
+					}
+					return nil
+				},
+				CreateShardfn: func(database, retentionPolicy string, shardID uint64, enabled bool) error {
+					atomic.AddInt64(&created, 1)
+					return nil
+				},
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.ShardCreateRetries = tt.retries
+
+			c.Open()
+			defer c.Close()
+
+			pr := &coordinator.WritePointsRequest{
+				Database:        "mydb",
+				RetentionPolicy: "myrp",
+			}
+			pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+			err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+			if tt.expErr != nil {
+				if exp := fmt.Errorf("write failed: %v", tt.expErr); err == nil || err.Error() != exp.Error() {
+					t.Fatalf("PointsWriter.WritePoints(): got %v, exp %v", err, exp)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := atomic.LoadInt64(&created); got != tt.expCreated {
+				t.Fatalf("got %d shard creations, expected %d", got, tt.expCreated)
+			}
+		})
+	}
+}
+
+// Ensures writes queued via hinted handoff carry increasing sequence numbers
+// when the service supports ordered replay.
+func TestPointsWriter_WritePoints_OrderedHintedHandoff(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.NodeIDFn = func() uint64 { return 4 }
+
+	sw := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			return fmt.Errorf("node %d unavailable", nodeID)
+		},
+	}
+
+	seqs := make(chan uint64, 16)
+	hh := &fakeOrderedHintedHandoff{
+		fakeHintedHandoff: fakeHintedHandoff{
+			ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+				t.Error("unordered hinted handoff write")
+				return nil
+			},
+			EmptyFn: func(shardID, nodeID uint64) bool {
+				return true
+			},
+		},
+		WriteShardOrderedFn: func(seq, shardID, nodeID uint64, points []models.Point) error {
+			seqs <- seq
+			return nil
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.ShardWriter = sw
+	c.HintedHandoff = hh
+
+	c.Open()
+	defer c.Close()
+
+	var prev uint64
+	for i := 0; i < 3; i++ {
+		pr := &coordinator.WritePointsRequest{
+			Database:        "mydb",
+			RetentionPolicy: "myrp",
+		}
+		pr.AddPoint("cpu", float64(i), time.Now(), nil)
+
+		if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelAny, pr.Points); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Every owner of the shard is queued with the same sequence number.
+		var seq uint64
+		for j := 0; j < 3; j++ {
+			select {
+			case s := <-seqs:
+				if j > 0 && s != seq {
+					t.Fatalf("write %d: got sequence %d, expected %d", i, s, seq)
+				}
+				seq = s
+			case <-time.After(time.Second):
+				t.Fatalf("write %d: timed out waiting for hinted handoff", i)
+			}
+		}
+		if seq <= prev {
+			t.Fatalf("write %d: got sequence %d, expected more than %d", i, seq, prev)
+		}
+		prev = seq
+	}
+}
+
+// Ensures remote writes are compressed when enabled and the shard writer
+// supports compression, and sent uncompressed otherwise.
+func TestPointsWriter_WritePoints_CompressRemoteWrites(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		ms := NewPointsWriterMetaClient()
+		ms.NodeIDFn = func() uint64 { return 4 }
+
+		var plain, compressed int64
+		sw := &fakeCompressedShardWriter{
+			fakeShardWriter: fakeShardWriter{
+				ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+					atomic.AddInt64(&plain, 1)
+					return nil
+				},
+			},
+			WriteShardCompressedFn: func(shardID, nodeID uint64, points []models.Point) error {
+				if len(points) != 2 {
+					return fmt.Errorf("got %d points, expected 2", len(points))
+				}
+				atomic.AddInt64(&compressed, 1)
+				return nil
+			},
+		}
+
+		c := coordinator.NewPointsWriter()
+		c.MetaClient = ms
+		c.ShardWriter = sw
+		c.HintedHandoff = &fakeHintedHandoff{
+			EmptyFn: func(shardID, nodeID uint64) bool { return true },
+		}
+		c.CompressRemoteWrites = compress
+
+		c.Open()
+
+		pr := &coordinator.WritePointsRequest{
+			Database:        "mydb",
+			RetentionPolicy: "myrp",
+		}
+		now := time.Now()
+		pr.AddPoint("cpu", 1.0, now, map[string]string{"host": "a"})
+		pr.AddPoint("cpu", 2.0, now.Add(time.Second), map[string]string{"host": "a"})
+
+		err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelAll, pr.Points)
+		c.Close()
+		if err != nil {
+			t.Fatalf("compress=%v: unexpected error: %v", compress, err)
+		}
+
+		expPlain, expCompressed := int64(3), int64(0)
+		if compress {
+			expPlain, expCompressed = 0, 3
+		}
+		if plain != expPlain || compressed != expCompressed {
+			t.Fatalf("compress=%v: got %d plain and %d compressed writes, expected %d and %d", compress, plain, compressed, expPlain, expCompressed)
+		}
+	}
+}
+
+// Ensures points queued via hinted handoff are gzipped when enabled and the
+// service supports compressed payloads, and are sent uncompressed otherwise.
+func TestPointsWriter_WritePoints_CompressHintedHandoff(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		ms := NewPointsWriterMetaClient()
+		ms.NodeIDFn = func() uint64 { return 4 }
+
+		sw := &fakeShardWriter{
+			ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+				return fmt.Errorf("node %d unavailable", nodeID)
+			},
+		}
+
+		pr := &coordinator.WritePointsRequest{
+			Database:        "mydb",
+			RetentionPolicy: "myrp",
+		}
+		now := time.Now()
+		pr.AddPoint("cpu", 1.0, now, map[string]string{"host": "a"})
+		pr.AddPoint("cpu", 2.0, now.Add(time.Second), map[string]string{"host": "b"})
+
+		// Every owner of the shard is queued, though the write returns after
+		// the first with consistency any.
+		writes := make(chan bool, 3) // true for compressed writes
+		handoff := &fakeCompressedHintedHandoff{
+			fakeHintedHandoff: fakeHintedHandoff{
+				ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+					writes <- false
+					return nil
+				},
+				EmptyFn: func(shardID, nodeID uint64) bool { return true },
+			},
+			WriteShardCompressedFn: func(shardID, nodeID uint64, payload []byte) error {
+				points, err := hh.DecompressPoints(payload)
+				if err != nil {
+					return err
+				}
+				if len(points) != len(pr.Points) {
+					return fmt.Errorf("got %d points, expected %d", len(points), len(pr.Points))
+				}
+				for i := range points {
+					if got, exp := points[i].String(), pr.Points[i].String(); got != exp {
+						return fmt.Errorf("got point %q, expected %q", got, exp)
+					}
+				}
+				writes <- true
+				return nil
+			},
+		}
+
+		c := coordinator.NewPointsWriter()
+		c.MetaClient = ms
+		c.ShardWriter = sw
+		c.HintedHandoff = handoff
+		c.CompressHintedHandoff = compress
+
+		c.Open()
+		err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelAny, pr.Points)
+		c.Close()
+		if err != nil {
+			t.Fatalf("compress=%v: unexpected error: %v", compress, err)
+		}
+
+		for i := 0; i < 3; i++ {
+			select {
+			case got := <-writes:
+				if got != compress {
+					t.Fatalf("compress=%v: got compressed=%v hinted handoff write", compress, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("compress=%v: timed out waiting for hinted handoff write %d", compress, i)
+			}
+		}
+	}
+}
+
 type fakePointsWriter struct {
 	WritePointsIntoFn func(*coordinator.IntoWriteRequest) error
 }
@@ -586,6 +1550,33 @@ func (f *fakeHintedHandoff) Empty(shardID, nodeID uint64) bool {
 	return f.EmptyFn(shardID, nodeID)
 }
 
+type fakeOrderedHintedHandoff struct {
+	fakeHintedHandoff
+	WriteShardOrderedFn func(seq, shardID, nodeID uint64, points []models.Point) error
+}
+
+func (f *fakeOrderedHintedHandoff) WriteShardOrdered(seq, shardID, nodeID uint64, points []models.Point) error {
+	return f.WriteShardOrderedFn(seq, shardID, nodeID, points)
+}
+
+type fakeCompressedShardWriter struct {
+	fakeShardWriter
+	WriteShardCompressedFn func(shardID, nodeID uint64, points []models.Point) error
+}
+
+func (f *fakeCompressedShardWriter) WriteShardCompressed(shardID, nodeID uint64, points []models.Point) error {
+	return f.WriteShardCompressedFn(shardID, nodeID, points)
+}
+
+type fakeCompressedHintedHandoff struct {
+	fakeHintedHandoff
+	WriteShardCompressedFn func(shardID, nodeID uint64, payload []byte) error
+}
+
+func (f *fakeCompressedHintedHandoff) WriteShardCompressed(shardID, nodeID uint64, payload []byte) error {
+	return f.WriteShardCompressedFn(shardID, nodeID, payload)
+}
+
 type fakeStore struct {
 	WriteFn       func(shardID uint64, points []models.Point) error
 	CreateShardfn func(database, retentionPolicy string, shardID uint64, enabled bool) error
@@ -628,8 +1619,27 @@ func NewPointsWriterMetaClient() *PointsWriterMetaClient {
 	return ms
 }
 
+// resolvingMetaClient is a PointsWriterMetaClient that can look up data nodes.
+type resolvingMetaClient struct {
+	PointsWriterMetaClient
+	DataNodeFn func(id uint64) (*meta.NodeInfo, error)
+}
+
+func (m *resolvingMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) { return m.DataNodeFn(id) }
+
+// touchingMetaClient is a PointsWriterMetaClient that records shard writes.
+type touchingMetaClient struct {
+	PointsWriterMetaClient
+	TouchShardsFn func(ids []uint64, t time.Time) error
+}
+
+func (m *touchingMetaClient) TouchShards(ids []uint64, t time.Time) error {
+	return m.TouchShardsFn(ids, t)
+}
+
 type PointsWriterMetaClient struct {
 	NodeIDFn                      func() uint64
+	DataNodeCountFn               func() int
 	RetentionPolicyFn             func(database, name string) (*meta.RetentionPolicyInfo, error)
 	CreateShardGroupIfNotExistsFn func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
 	DatabaseFn                    func(database string) *meta.DatabaseInfo
@@ -638,6 +1648,14 @@ type PointsWriterMetaClient struct {
 
 func (m PointsWriterMetaClient) NodeID() uint64 { return m.NodeIDFn() }
 
+// DataNodeCount returns one data node unless DataNodeCountFn is set.
+func (m PointsWriterMetaClient) DataNodeCount() int {
+	if m.DataNodeCountFn == nil {
+		return 1
+	}
+	return m.DataNodeCountFn()
+}
+
 func (m PointsWriterMetaClient) RetentionPolicy(database, name string) (*meta.RetentionPolicyInfo, error) {
 	return m.RetentionPolicyFn(database, name)
 }
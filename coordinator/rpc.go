@@ -1,9 +1,13 @@
 package coordinator
 
 import (
+	"bytes"
+	"compress/gzip"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net"
 	"time"
@@ -92,6 +96,41 @@ func (w *WriteShardRequest) UnmarshalBinary(buf []byte) error {
 	return nil
 }
 
+// MarshalCompressed encodes the object to a gzipped binary format.
+func (w *WriteShardRequest) MarshalCompressed() ([]byte, error) {
+	buf, err := w.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	if _, err := gz.Write(buf); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalCompressed populates WriteShardRequest from a gzipped binary format.
+func (w *WriteShardRequest) UnmarshalCompressed(buf []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	b, err := io.ReadAll(io.LimitReader(gz, MaxMessageSize))
+	if err != nil {
+		return err
+	} else if len(b) >= MaxMessageSize {
+		return fmt.Errorf("max message size of %d exceeded", MaxMessageSize)
+	}
+	return w.UnmarshalBinary(b)
+}
+
 func (w *WriteShardRequest) unmarshalPoints() []models.Point {
 	points := make([]models.Point, len(w.pb.GetPoints()))
 	for i, p := range w.pb.GetPoints() {
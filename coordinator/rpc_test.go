@@ -2,12 +2,14 @@ package coordinator
 
 import (
 	"bytes"
+	"fmt"
 	"net"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
 )
 
@@ -82,6 +84,80 @@ func TestWriteShardRequestBinary(t *testing.T) {
 	}
 }
 
+func TestWriteShardRequestCompressed(t *testing.T) {
+	sr := &WriteShardRequest{}
+	sr.SetShardID(uint64(1))
+	sr.SetDatabase("db0")
+	for i := 0; i < 100; i++ {
+		sr.AddPoint("cpu", float64(i), time.Unix(0, 0).Add(time.Duration(i)*time.Second), map[string]string{"host": "serverA"})
+	}
+
+	raw, err := sr.MarshalBinary()
+	if err != nil {
+		t.Fatalf("WriteShardRequest.MarshalBinary() failed: %v", err)
+	}
+	b, err := sr.MarshalCompressed()
+	if err != nil {
+		t.Fatalf("WriteShardRequest.MarshalCompressed() failed: %v", err)
+	} else if len(b) >= len(raw) {
+		t.Fatalf("compressed request of %d bytes not smaller than %d bytes", len(b), len(raw))
+	}
+
+	got := &WriteShardRequest{}
+	if err := got.UnmarshalCompressed(b); err != nil {
+		t.Fatalf("WriteShardRequest.UnmarshalCompressed() failed: %v", err)
+	}
+	if got.ShardID() != 1 || got.Database() != "db0" {
+		t.Errorf("request mismatch: got shard %d database %q", got.ShardID(), got.Database())
+	}
+	gotPoints, expPoints := got.Points(), sr.Points()
+	if len(gotPoints) != len(expPoints) {
+		t.Fatalf("Points count mismatch: got %v, exp %v", len(gotPoints), len(expPoints))
+	}
+	for i := range expPoints {
+		if gotPoints[i].String() != expPoints[i].String() {
+			t.Errorf("Point %d mismatch: got %v, exp %v", i, gotPoints[i], expPoints[i])
+		}
+	}
+
+	if err := got.UnmarshalCompressed(raw); err == nil {
+		t.Fatal("expected error unmarshalling an uncompressed request")
+	}
+}
+
+// Measures the size of a large compressed write request relative to the
+// uncompressed request.
+func BenchmarkWriteShardRequest_MarshalCompressed(b *testing.B) {
+	sr := &WriteShardRequest{}
+	sr.SetShardID(uint64(1))
+	now := time.Now()
+	for i := 0; i < 5000; i++ {
+		sr.AddPoints([]models.Point{models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": fmt.Sprintf("server%02d", i%50), "region": "us-west"}),
+			models.Fields{"usage_user": float64(i % 100), "usage_system": float64(i % 7)},
+			now.Add(time.Duration(i)*time.Second),
+		)})
+	}
+
+	raw, err := sr.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		if buf, err = sr.MarshalCompressed(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(raw)), "raw-bytes")
+	b.ReportMetric(float64(len(buf)), "compressed-bytes")
+	b.ReportMetric(float64(len(buf))/float64(len(raw)), "ratio")
+}
+
 func TestWriteShardResponseBinary(t *testing.T) {
 	sr := &WriteShardResponse{}
 	sr.SetCode(10)
@@ -118,6 +118,8 @@ const (
 
 	removeHintedHandoffRequestMessage
 	removeHintedHandoffResponseMessage
+
+	writeShardCompressedRequestMessage
 )
 
 // ShardIDsKey is the shardIDs context key when handling read request.
@@ -339,7 +341,19 @@ func (s *Service) handleConn(conn net.Conn) {
 				return
 			}
 			atomic.AddInt64(&s.stats.WriteShardReq, 1)
-			err = s.processWriteShardRequest(buf)
+			err = s.processWriteShardRequest(buf, false)
+			if err != nil {
+				s.Logger.Error("Process write shard error", zap.Error(err))
+			}
+			s.writeShardResponse(conn, err)
+		case writeShardCompressedRequestMessage:
+			buf, err := ReadLV(conn)
+			if err != nil {
+				s.Logger.Error("Unable to read length-value", zap.Error(err))
+				return
+			}
+			atomic.AddInt64(&s.stats.WriteShardReq, 1)
+			err = s.processWriteShardRequest(buf, true)
 			if err != nil {
 				s.Logger.Error("Process write shard error", zap.Error(err))
 			}
@@ -479,10 +493,14 @@ func (s *Service) executeStatementResponse(w io.Writer, e error) {
 	}
 }
 
-func (s *Service) processWriteShardRequest(buf []byte) error {
+func (s *Service) processWriteShardRequest(buf []byte, compressed bool) error {
 	// Build request
 	var req WriteShardRequest
-	if err := req.UnmarshalBinary(buf); err != nil {
+	unmarshal := req.UnmarshalBinary
+	if compressed {
+		unmarshal = req.UnmarshalCompressed
+	}
+	if err := unmarshal(buf); err != nil {
 		return err
 	}
 
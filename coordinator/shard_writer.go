@@ -40,19 +40,22 @@ func NewShardWriter(timeout, dialTimeout, idleTime time.Duration, maxStreams int
 
 // WriteShard writes time series points to a shard
 func (w *ShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
-	pts := make([][]byte, 0, len(points))
-	for _, p := range points {
-		b, err := p.MarshalBinary()
-		if err != nil {
-			continue
-		}
-		pts = append(pts, b)
-	}
-	return w.WriteShardBinary(shardID, ownerID, pts)
+	return w.WriteShardBinary(shardID, ownerID, marshalPoints(points))
+}
+
+// WriteShardCompressed writes time series points to a shard like WriteShard,
+// but gzips the request. The owner must be running a version that accepts
+// compressed requests.
+func (w *ShardWriter) WriteShardCompressed(shardID, ownerID uint64, points []models.Point) error {
+	return w.writeShardBinary(shardID, ownerID, marshalPoints(points), true)
 }
 
 // WriteShardBinary writes time series binary points to a shard
 func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, points [][]byte) error {
+	return w.writeShardBinary(shardID, ownerID, points, false)
+}
+
+func (w *ShardWriter) writeShardBinary(shardID, ownerID uint64, points [][]byte, compressed bool) error {
 	conn, err := w.dial(ownerID)
 	if err != nil {
 		return err
@@ -76,13 +79,17 @@ func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, points [][]byte)
 	request.SetBinaryPoints(points)
 
 	// Marshal into protocol buffers.
-	buf, err := request.MarshalBinary()
+	typ, marshal := writeShardRequestMessage, request.MarshalBinary
+	if compressed {
+		typ, marshal = writeShardCompressedRequestMessage, request.MarshalCompressed
+	}
+	buf, err := marshal()
 	if err != nil {
 		return err
 	}
 
 	// Write request.
-	if err := WriteTLVT(conn, writeShardRequestMessage, buf, w.timeout); err != nil {
+	if err := WriteTLVT(conn, typ, buf, w.timeout); err != nil {
 		MarkUnusable(conn)
 		return err
 	}
@@ -107,6 +114,20 @@ func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, points [][]byte)
 	return nil
 }
 
+// marshalPoints returns the binary encoding of points, skipping any that
+// cannot be encoded.
+func marshalPoints(points []models.Point) [][]byte {
+	pts := make([][]byte, 0, len(points))
+	for _, p := range points {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			continue
+		}
+		pts = append(pts, b)
+	}
+	return pts
+}
+
 // dial returns a connection to a single node in the cluster.
 func (w *ShardWriter) dial(nodeID uint64) (net.Conn, error) {
 	// If we don't have a connection pool for that addr yet, create one
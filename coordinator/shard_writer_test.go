@@ -114,6 +114,51 @@ func TestShardWriter_WriteShard_Multiple(t *testing.T) {
 	}
 }
 
+// Ensure the shard writer can write a compressed request.
+func TestShardWriter_WriteShardCompressed(t *testing.T) {
+	ts := newTestWriteService(nil)
+	ts.TSDBStore.WriteToShardFn = ts.writeShardSuccess
+	s := coordinator.NewService(coordinator.Config{})
+	s.Listener = ts.muxln
+	s.DefaultListener = ts.defln
+	s.MetaClient = &metaClient{addr: ts.ln.Addr().String()}
+	s.TSDBStore = &ts.TSDBStore
+	s.Server = &server{}
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer ts.Close()
+
+	w := coordinator.NewShardWriter(10*time.Second, time.Second, time.Minute, 1)
+	w.MetaClient = &metaClient{addr: ts.ln.Addr().String()}
+
+	// Build a single point.
+	now := time.Now()
+	var points []models.Point
+	points = append(points, models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now))
+
+	// Write to shard and close.
+	if err := w.WriteShardCompressed(1, 2, points); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate response.
+	responses, err := ts.ResponseN(1)
+	if err != nil {
+		t.Fatal(err)
+	} else if responses[0].shardID != 1 {
+		t.Fatalf("unexpected shard id: %d", responses[0].shardID)
+	}
+
+	// Validate point.
+	if p := responses[0].points[0]; p.String() != points[0].String() {
+		t.Fatalf("unexpected point: %s", p)
+	}
+}
+
 // Ensure the shard writer returns an error when the server fails to accept the write.
 func TestShardWriter_WriteShard_Error(t *testing.T) {
 	ts := newTestWriteService(writeShardFail)
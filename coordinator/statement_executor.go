@@ -57,6 +57,9 @@ type StatementExecutor struct {
 	// Disallow INF values in SELECT INTO and other previously ignored errors
 	StrictErrorHandling bool
 
+	// Allow retention policies to be replicated more times than there are data nodes
+	AllowExcessReplicaN bool
+
 	// Select statement limits
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
@@ -78,7 +81,9 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt i
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeAlterRetentionPolicyStatement(stmt)
+		var notes []*query.Message
+		notes, err = e.executeAlterRetentionPolicyStatement(stmt)
+		messages = append(messages, notes...)
 	case *influxql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -226,15 +231,31 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt i
 	})
 }
 
-func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) error {
+func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) ([]*query.Message, error) {
 	rpu := &meta.RetentionPolicyUpdate{
-		Duration:           stmt.Duration,
-		ReplicaN:           stmt.Replication,
-		ShardGroupDuration: stmt.ShardGroupDuration,
+		Duration:            stmt.Duration,
+		ReplicaN:            stmt.Replication,
+		ShardGroupDuration:  stmt.ShardGroupDuration,
+		AllowExcessReplicaN: e.AllowExcessReplicaN,
+	}
+
+	// Check a new replication factor up front to warn about its effects.
+	var messages []*query.Message
+	if rpu.ReplicaN != nil {
+		notes, err := e.MetaClient.RetentionPolicyReplicaNUpdateSafety(stmt.Database, stmt.Name, rpu)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			messages = append(messages, &query.Message{Level: query.WarningLevel, Text: note})
+		}
 	}
 
 	// Update the retention policy.
-	return e.MetaClient.UpdateRetentionPolicy(stmt.Database, stmt.Name, rpu, stmt.Default)
+	if err := e.MetaClient.UpdateRetentionPolicy(stmt.Database, stmt.Name, rpu, stmt.Default); err != nil {
+		return nil, err
+	}
+	return messages, nil
 }
 
 func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *influxql.CreateContinuousQueryStatement) error {
@@ -304,10 +325,11 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql
 	}
 
 	spec := meta.RetentionPolicySpec{
-		Name:               stmt.Name,
-		Duration:           &stmt.Duration,
-		ReplicaN:           &stmt.Replication,
-		ShardGroupDuration: stmt.ShardGroupDuration,
+		Name:                stmt.Name,
+		Duration:            &stmt.Duration,
+		ReplicaN:            &stmt.Replication,
+		ShardGroupDuration:  stmt.ShardGroupDuration,
+		AllowExcessReplicaN: e.AllowExcessReplicaN,
 	}
 
 	// Create new retention policy.
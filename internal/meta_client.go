@@ -45,7 +45,8 @@ type MetaClientMock struct {
 	PrecreateShardGroupsFn func(from, to time.Time) error
 	PruneShardGroupsFn     func() error
 
-	RetentionPolicyFn func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RetentionPolicyFn                     func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RetentionPolicyReplicaNUpdateSafetyFn func(database, name string, rpu *meta.RetentionPolicyUpdate) ([]string, error)
 
 	AuthenticateFn           func(username, password string) (ui meta.User, err error)
 	AdminUserExistsFn        func() bool
@@ -163,6 +164,10 @@ func (c *MetaClientMock) RetentionPolicy(database, name string) (rpi *meta.Reten
 	return c.RetentionPolicyFn(database, name)
 }
 
+func (c *MetaClientMock) RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *meta.RetentionPolicyUpdate) ([]string, error) {
+	return c.RetentionPolicyReplicaNUpdateSafetyFn(database, name, rpu)
+}
+
 func (c *MetaClientMock) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
@@ -0,0 +1,71 @@
+package hh
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// CompressPoints returns points in the payload format accepted by
+// Service.WriteShardCompressed: the length prefixed binary encoding of each
+// point, gzipped.
+func CompressPoints(points []models.Point) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	nb := make([]byte, 4)
+	for _, p := range points {
+		pb, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(nb, uint32(len(pb)))
+		if _, err := gz.Write(nb); err != nil {
+			return nil, err
+		}
+		if _, err := gz.Write(pb); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressPoints returns the points in a payload created by CompressPoints.
+func DecompressPoints(payload []byte) ([]models.Point, error) {
+	b, err := decompress(payload)
+	if err != nil {
+		return nil, err
+	}
+	var points []models.Point
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return nil, io.ErrShortBuffer
+		}
+		n := int(binary.BigEndian.Uint32(b[:4]))
+		if len(b) < 4+n {
+			return nil, io.ErrShortBuffer
+		}
+		p, err := models.NewPointFromBytes(b[4 : 4+n])
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+		b = b[4+n:]
+	}
+	return points, nil
+}
+
+// decompress gunzips a payload created by CompressPoints.
+func decompress(payload []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
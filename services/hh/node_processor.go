@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -37,6 +38,12 @@ type NodeProcessor struct {
 	meta   metaClient
 	writer shardWriter
 
+	// lateQueue holds ordered writes submitted before a write already in
+	// queue. Replay sends the earlier of the heads of the two queues first.
+	lateQueue *queue
+	seqMu     sync.Mutex
+	lastSeq   uint64 // highest sequence number appended to queue
+
 	stats       *Statistics
 	defaultTags models.StatisticTags
 	Logger      *zap.Logger
@@ -97,6 +104,20 @@ func (n *NodeProcessor) Open() error {
 	}
 	n.queue = queue
 
+	// Create the queue of writes submitted out of order.
+	lateDir := filepath.Join(n.dir, lateQueueDir)
+	if err := os.MkdirAll(lateDir, 0700); err != nil {
+		return fmt.Errorf("mkdir all: %s", err)
+	}
+	lateQueue, err := newQueue(lateDir, n.MaxSize, n.MaxWritesPending)
+	if err != nil {
+		return err
+	}
+	if err := lateQueue.Open(); err != nil {
+		return err
+	}
+	n.lateQueue = lateQueue
+
 	n.wg.Add(1)
 	go n.run()
 
@@ -124,6 +145,9 @@ func (n *NodeProcessor) Close() error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	n.done = nil
+	if err := n.lateQueue.Close(); err != nil {
+		return err
+	}
 	return n.queue.Close()
 }
 
@@ -145,8 +169,8 @@ func (n *NodeProcessor) Statistics(tags map[string]string) []models.Statistic {
 		Values: map[string]interface{}{
 			statBytesRead:           atomic.LoadInt64(&n.stats.BytesRead),
 			statBytesWritten:        atomic.LoadInt64(&n.stats.BytesWritten),
-			statQueueBytes:          n.queue.diskUsage(),
-			statQueueDepth:          int64(len(n.queue.segments)),
+			statQueueBytes:          n.queue.diskUsage() + n.lateQueue.diskUsage(),
+			statQueueDepth:          int64(len(n.queue.segments) + len(n.lateQueue.segments)),
 			statWriteBlocked:        atomic.LoadInt64(&n.stats.WriteBlocked),
 			statWriteDropped:        atomic.LoadInt64(&n.stats.WriteDropped),
 			statWriteShardReq:       atomic.LoadInt64(&n.stats.WriteShardReq),
@@ -184,18 +208,80 @@ func (n *NodeProcessor) WriteShard(points []models.Point) error {
 	atomic.AddInt64(&n.stats.WriteShardReq, 1)
 	atomic.AddInt64(&n.stats.WriteShardReqPoints, int64(len(points)))
 
+	return n.appendWrite(n.queue, 0, points)
+}
+
+// WriteShardOrdered writes hinted-handoff data like WriteShard, recording seq,
+// the position of the write in submission order. A write submitted before one
+// already queued is replayed ahead of it.
+func (n *NodeProcessor) WriteShardOrdered(seq uint64, points []models.Point) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.closed() {
+		return fmt.Errorf("node processor is closed")
+	}
+
+	atomic.AddInt64(&n.stats.WriteShardReq, 1)
+	atomic.AddInt64(&n.stats.WriteShardReqPoints, int64(len(points)))
+
+	n.seqMu.Lock()
+	defer n.seqMu.Unlock()
+
+	if seq < n.lastSeq {
+		return n.appendWrite(n.lateQueue, seq, points)
+	}
+	if err := n.appendWrite(n.queue, seq, points); err != nil {
+		return err
+	}
+	n.lastSeq = seq
+	return nil
+}
+
+// WriteShardCompressed writes a payload created by CompressPoints as a single
+// block. It returns ErrSegmentFull if the payload does not fit in a segment.
+func (n *NodeProcessor) WriteShardCompressed(payload []byte) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.closed() {
+		return fmt.Errorf("node processor is closed")
+	}
+
+	atomic.AddInt64(&n.stats.WriteShardReq, 1)
+
+	b := marshalCompressedWrite(n.shardID, payload)
+	if len(b) > defaultSegmentSize {
+		return ErrSegmentFull
+	}
+	atomic.AddInt64(&n.stats.BytesWritten, int64(len(b)))
+	if err := n.queue.Append(b); err != nil {
+		switch err {
+		case ErrQueueBlocked:
+			atomic.AddInt64(&n.stats.WriteBlocked, 1)
+		case ErrQueueFull:
+			atomic.AddInt64(&n.stats.WriteDropped, 1)
+		}
+		return err
+	}
+	return nil
+}
+
+// appendWrite appends points to q, split into as many blocks as needed to fit
+// in a segment. Blocks of ordered writes carry seq.
+func (n *NodeProcessor) appendWrite(q *queue, seq uint64, points []models.Point) error {
 	i, j := 0, len(points)
 	for i < j {
-		b := marshalWrite(n.shardID, points[i:j])
+		b := marshalOrderedWrite(seq, n.shardID, points[i:j])
 		for len(b) > defaultSegmentSize {
 			if j == i+1 {
 				return ErrSegmentFull
 			}
 			j = (i + j + 1) / 2
-			b = marshalWrite(n.shardID, points[i:j])
+			b = marshalOrderedWrite(seq, n.shardID, points[i:j])
 		}
 		atomic.AddInt64(&n.stats.BytesWritten, int64(len(b)))
-		if err := n.queue.Append(b); err != nil {
+		if err := q.Append(b); err != nil {
 			switch err {
 			case ErrQueueBlocked:
 				atomic.AddInt64(&n.stats.WriteBlocked, 1)
@@ -218,6 +304,11 @@ func (n *NodeProcessor) LastModified() (time.Time, error) {
 	if err != nil {
 		return time.Time{}, err
 	}
+	if lt, err := n.lateQueue.LastModified(); err != nil {
+		return time.Time{}, err
+	} else if lt.After(t) {
+		t = lt
+	}
 	return t.UTC(), nil
 }
 
@@ -237,8 +328,10 @@ func (n *NodeProcessor) run() {
 			return
 
 		case <-time.After(n.PurgeInterval):
-			if err := n.queue.PurgeOlderThan(time.Now().Add(-n.MaxAge)); err != nil {
-				n.Logger.Error("Failed to purge", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
+			for _, q := range []*queue{n.queue, n.lateQueue} {
+				if err := q.PurgeOlderThan(time.Now().Add(-n.MaxAge)); err != nil {
+					n.Logger.Error("Failed to purge", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
+				}
 			}
 
 		case <-time.After(currInterval):
@@ -287,17 +380,17 @@ func (n *NodeProcessor) SendWrite() (int, error) {
 	}
 
 	// Get the current block from the queue
-	buf, err := n.queue.Current()
+	q, buf, err := n.current()
 	if err != nil {
 		if err != io.EOF {
 			n.Logger.Error("Failed to current queue", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 			// Try to truncate it.
-			if err := n.queue.Truncate(); err != nil {
+			if err := q.Truncate(); err != nil {
 				n.Logger.Error("Failed to truncate queue", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 			}
 		} else {
 			// Try to skip it.
-			if err := n.queue.Advance(); err != nil {
+			if err := q.Advance(); err != nil {
 				n.Logger.Error("Failed to advance queue", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 			}
 		}
@@ -309,7 +402,7 @@ func (n *NodeProcessor) SendWrite() (int, error) {
 	if err != nil {
 		n.Logger.Error("Unmarshal write failed", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 		// Try to skip it.
-		if err := n.queue.Advance(); err != nil {
+		if err := q.Advance(); err != nil {
 			n.Logger.Error("Failed to advance queue", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 		}
 		return 0, err
@@ -323,13 +416,41 @@ func (n *NodeProcessor) SendWrite() (int, error) {
 	atomic.AddInt64(&n.stats.WriteNodeReq, 1)
 	atomic.AddInt64(&n.stats.WriteNodeReqPoints, int64(len(points)))
 
-	if err := n.queue.Advance(); err != nil {
+	if err := q.Advance(); err != nil {
 		n.Logger.Error("Failed to advance queue", zap.Uint64("node", n.nodeID), zap.Uint64("shardID", n.shardID), zap.Error(err))
 	}
 
 	return len(buf), nil
 }
 
+// current returns the queue holding the next block to send, and that block.
+// The head of the late queue is sent first if it was submitted before the
+// head of the queue.
+func (n *NodeProcessor) current() (*queue, []byte, error) {
+	buf, err := n.queue.Current()
+	if err == io.EOF {
+		// The head segment may be exhausted with more segments behind it.
+		if err := n.queue.Advance(); err != nil {
+			return n.queue, nil, err
+		}
+		buf, err = n.queue.Current()
+	}
+	lateBuf, lateErr := n.lateQueue.Current()
+	switch {
+	case lateErr == io.EOF:
+		return n.queue, buf, err
+	case lateErr != nil:
+		return n.lateQueue, lateBuf, lateErr
+	case err == io.EOF:
+		return n.lateQueue, lateBuf, nil
+	case err != nil:
+		return n.queue, buf, err
+	case writeSeq(lateBuf) < writeSeq(buf):
+		return n.lateQueue, lateBuf, nil
+	}
+	return n.queue, buf, nil
+}
+
 // Head returns the head of the processor's queue.
 func (n *NodeProcessor) Head() string {
 	qp, err := n.queue.Position()
@@ -359,7 +480,7 @@ func (n *NodeProcessor) Active() (bool, error) {
 
 // Empty returns whether this node processor's queue is empty.
 func (n *NodeProcessor) Empty() bool {
-	return n.queue.Empty()
+	return n.queue.Empty() && n.lateQueue.Empty()
 }
 
 // IsRetryable returns true if this error is temporary and could be retried
@@ -373,9 +494,27 @@ func IsRetryable(err error) bool {
 	return true
 }
 
+// lateQueueDir is the directory, within a node processor's, of the queue of
+// writes submitted out of order.
+const lateQueueDir = "late"
+
+// compressedWriteMarker takes the place of the first point length in a
+// marshalled write whose points are compressed.
+const compressedWriteMarker = 0xFFFFFFFF
+
 func marshalWrite(shardID uint64, points []models.Point) []byte {
+	return marshalOrderedWrite(0, shardID, points)
+}
+
+// marshalOrderedWrite is like marshalWrite, but a non-zero seq is stored after
+// the shard ID behind an empty point, which no unordered write contains.
+func marshalOrderedWrite(seq, shardID uint64, points []models.Point) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, shardID)
+	if seq > 0 {
+		b = append(b, make([]byte, 12)...)
+		binary.BigEndian.PutUint64(b[12:], seq)
+	}
 	nb := make([]byte, 4)
 	for _, p := range points {
 		pb, err := p.MarshalBinary()
@@ -389,11 +528,28 @@ func marshalWrite(shardID uint64, points []models.Point) []byte {
 	return b
 }
 
+// marshalCompressedWrite stores payload after the shard ID behind
+// compressedWriteMarker, which is longer than any point in a segment.
+func marshalCompressedWrite(shardID uint64, payload []byte) []byte {
+	b := make([]byte, 12, 12+len(payload))
+	binary.BigEndian.PutUint64(b, shardID)
+	binary.BigEndian.PutUint32(b[8:], compressedWriteMarker)
+	return append(b, payload...)
+}
+
 func unmarshalWrite(b []byte) (uint64, [][]byte, error) {
 	if len(b) < 8 {
 		return 0, nil, fmt.Errorf("too short: len = %d", len(b))
 	}
 	shardID, b := binary.BigEndian.Uint64(b[:8]), b[8:]
+	if len(b) >= 12 && binary.BigEndian.Uint32(b[:4]) == 0 {
+		b = b[12:]
+	} else if len(b) >= 4 && binary.BigEndian.Uint32(b[:4]) == compressedWriteMarker {
+		var err error
+		if b, err = decompress(b[4:]); err != nil {
+			return shardID, nil, err
+		}
+	}
 	var points [][]byte
 	var n int
 	for len(b) > 0 {
@@ -409,3 +565,12 @@ func unmarshalWrite(b []byte) (uint64, [][]byte, error) {
 	}
 	return shardID, points, nil
 }
+
+// writeSeq returns the sequence number of a marshalled write, or zero if the
+// write is unordered.
+func writeSeq(b []byte) uint64 {
+	if len(b) < 20 || binary.BigEndian.Uint32(b[8:12]) != 0 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b[12:20])
+}
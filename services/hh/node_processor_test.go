@@ -187,3 +187,170 @@ cpu,env=prod,host=serverA,region=us-west,tag1=value1,tag2=value2,tag3=value3,tag
 		t.Fatalf("unexpected points string: %s, exp: %s", pointsStr, expPointsStr)
 	}
 }
+
+func TestNodeProcessorMarshalOrderedWrite(t *testing.T) {
+	points, _ := models.ParsePointsString("cpu value=1 1000000000\ncpu value=2 2000000000")
+	b := marshalOrderedWrite(42, 127, points)
+
+	if seq := writeSeq(b); seq != 42 {
+		t.Fatalf("unexpected seq: %d, exp: 42", seq)
+	}
+	shardID, pts, err := unmarshalWrite(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if shardID != 127 {
+		t.Fatalf("unexpected shardID: %d, exp: 127", shardID)
+	} else if len(pts) != 2 {
+		t.Fatalf("unexpected points: %d, exp: 2", len(pts))
+	}
+
+	if seq := writeSeq(marshalWrite(127, points)); seq != 0 {
+		t.Fatalf("unexpected seq of unordered write: %d", seq)
+	}
+}
+
+// Ensures ordered writes are replayed in the order of their sequence numbers,
+// even when a write arrives after one submitted later.
+func TestNodeProcessorSendWriteOrdered(t *testing.T) {
+	dir, err := os.MkdirTemp("", "node_processor_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var values []string
+	sh := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points [][]byte) error {
+			for _, b := range points {
+				p, err := models.NewPointFromBytes(b)
+				if err != nil {
+					return err
+				}
+				values = append(values, p.String())
+			}
+			return nil
+		},
+	}
+	metastore := &fakeMetaStore{
+		NodeFn: func(nodeID uint64) (*meta.NodeInfo, error) {
+			return &meta.NodeInfo{}, nil
+		},
+	}
+
+	n := NewNodeProcessor(NewConfig(), 200, 100, dir, sh, metastore)
+	if err := n.Open(); err != nil {
+		t.Fatalf("Failed to open node processor: %v", err)
+	}
+	defer n.Close()
+
+	// Writes 2 and 4 arrive after writes submitted later.
+	for _, seq := range []uint64{1, 3, 2, 5, 4} {
+		pt := models.MustNewPoint("cpu", models.NewTags(nil), models.Fields{"value": float64(seq)}, time.Unix(0, 0))
+		if err := n.WriteShardOrdered(seq, []models.Point{pt}); err != nil {
+			t.Fatalf("WriteShardOrdered() failed to write points: %v", err)
+		}
+	}
+
+	for {
+		if _, err := n.SendWrite(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SendWrite() failed to write points: %v", err)
+		}
+	}
+
+	exp := []string{"cpu value=1 0", "cpu value=2 0", "cpu value=3 0", "cpu value=4 0", "cpu value=5 0"}
+	if strings.Join(values, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("unexpected replay order:\n got %v\n exp %v", values, exp)
+	}
+}
+
+func TestNodeProcessorSendWriteCompressed(t *testing.T) {
+	dir, err := os.MkdirTemp("", "node_processor_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var values []string
+	sh := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points [][]byte) error {
+			for _, b := range points {
+				p, err := models.NewPointFromBytes(b)
+				if err != nil {
+					return err
+				}
+				values = append(values, p.String())
+			}
+			return nil
+		},
+	}
+	metastore := &fakeMetaStore{
+		NodeFn: func(nodeID uint64) (*meta.NodeInfo, error) {
+			return &meta.NodeInfo{}, nil
+		},
+	}
+
+	n := NewNodeProcessor(NewConfig(), 200, 100, dir, sh, metastore)
+	if err := n.Open(); err != nil {
+		t.Fatalf("Failed to open node processor: %v", err)
+	}
+	defer n.Close()
+
+	// Compressed writes are queued in order with uncompressed writes.
+	var points []models.Point
+	for i := 1; i <= 3; i++ {
+		points = append(points, models.MustNewPoint("cpu", models.NewTags(nil), models.Fields{"value": float64(i)}, time.Unix(0, 0)))
+	}
+	payload, err := CompressPoints(points[:2])
+	if err != nil {
+		t.Fatalf("CompressPoints() failed: %v", err)
+	}
+	if err := n.WriteShardCompressed(payload); err != nil {
+		t.Fatalf("WriteShardCompressed() failed to write points: %v", err)
+	}
+	if err := n.WriteShard(points[2:]); err != nil {
+		t.Fatalf("WriteShard() failed to write points: %v", err)
+	}
+
+	for {
+		if _, err := n.SendWrite(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SendWrite() failed to write points: %v", err)
+		}
+	}
+
+	exp := []string{"cpu value=1 0", "cpu value=2 0", "cpu value=3 0"}
+	if strings.Join(values, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("unexpected replayed points:\n got %v\n exp %v", values, exp)
+	}
+}
+
+func TestCompressPoints(t *testing.T) {
+	points := []models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), models.Fields{"value": 1.0}, time.Unix(1, 0)),
+		models.MustNewPoint("mem", models.NewTags(map[string]string{"host": "b"}), models.Fields{"used": int64(2)}, time.Unix(2, 0)),
+	}
+
+	payload, err := CompressPoints(points)
+	if err != nil {
+		t.Fatalf("CompressPoints() failed: %v", err)
+	}
+	got, err := DecompressPoints(payload)
+	if err != nil {
+		t.Fatalf("DecompressPoints() failed: %v", err)
+	}
+	if len(got) != len(points) {
+		t.Fatalf("got %d points, expected %d", len(got), len(points))
+	}
+	for i := range points {
+		if got[i].String() != points[i].String() {
+			t.Fatalf("got point %q, expected %q", got[i].String(), points[i].String())
+		}
+	}
+
+	if _, err := DecompressPoints([]byte("not gzipped")); err == nil {
+		t.Fatal("expected error decompressing an uncompressed payload")
+	}
+}
@@ -258,28 +258,9 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 	atomic.AddInt64(&s.stats.WriteShardReq, 1)
 	atomic.AddInt64(&s.stats.WriteShardReqPoints, int64(len(points)))
 
-	s.mu.RLock()
-	processor, ok := s.processor(ownerID, shardID)
-	s.mu.RUnlock()
-	if !ok {
-		if err := func() error {
-			// Check again under write-lock.
-			s.mu.Lock()
-			defer s.mu.Unlock()
-
-			processor, ok = s.processor(ownerID, shardID)
-			if !ok {
-				processor = NewNodeProcessor(s.cfg, ownerID, shardID, s.pathforNodeShard(ownerID, shardID), s.shardWriter, s.MetaClient)
-				processor.WithLogger(s.Logger)
-				if err := processor.Open(); err != nil {
-					return err
-				}
-				s.setProcessor(ownerID, shardID, processor)
-			}
-			return nil
-		}(); err != nil {
-			return err
-		}
+	processor, err := s.openProcessor(ownerID, shardID)
+	if err != nil {
+		return err
 	}
 
 	if err := processor.WriteShard(points); err != nil {
@@ -289,6 +270,67 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 	return nil
 }
 
+// WriteShardOrdered queues the points write like WriteShard. seq orders the
+// write among others for the same shard and node, which are replayed in the
+// order of their sequence numbers.
+func (s *Service) WriteShardOrdered(seq, shardID, ownerID uint64, points []models.Point) error {
+	if !s.cfg.Enabled {
+		return ErrHintedHandoffDisabled
+	}
+	atomic.AddInt64(&s.stats.WriteShardReq, 1)
+	atomic.AddInt64(&s.stats.WriteShardReqPoints, int64(len(points)))
+
+	processor, err := s.openProcessor(ownerID, shardID)
+	if err != nil {
+		return err
+	}
+
+	return processor.WriteShardOrdered(seq, points)
+}
+
+// WriteShardCompressed queues a payload created by CompressPoints for shardID
+// to node ownerID. The payload is stored compressed and decompressed when it
+// is sent to the node.
+func (s *Service) WriteShardCompressed(shardID, ownerID uint64, payload []byte) error {
+	if !s.cfg.Enabled {
+		return ErrHintedHandoffDisabled
+	}
+	atomic.AddInt64(&s.stats.WriteShardReq, 1)
+
+	processor, err := s.openProcessor(ownerID, shardID)
+	if err != nil {
+		return err
+	}
+
+	return processor.WriteShardCompressed(payload)
+}
+
+// openProcessor returns the node processor for nodeID and shardID, creating and
+// opening it if it does not exist.
+func (s *Service) openProcessor(nodeID, shardID uint64) (*NodeProcessor, error) {
+	s.mu.RLock()
+	processor, ok := s.processor(nodeID, shardID)
+	s.mu.RUnlock()
+	if ok {
+		return processor, nil
+	}
+
+	// Check again under write-lock.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	processor, ok = s.processor(nodeID, shardID)
+	if !ok {
+		processor = NewNodeProcessor(s.cfg, nodeID, shardID, s.pathforNodeShard(nodeID, shardID), s.shardWriter, s.MetaClient)
+		processor.WithLogger(s.Logger)
+		if err := processor.Open(); err != nil {
+			return nil, err
+		}
+		s.setProcessor(nodeID, shardID, processor)
+	}
+	return processor, nil
+}
+
 // Empty returns whether this node processor's queue is empty
 func (s *Service) Empty(shardID, ownerID uint64) bool {
 	if !s.cfg.Enabled {
@@ -994,6 +994,9 @@ func (h *Handler) serveWrite(database, retentionPolicy, precision string, w http
 			var npoints, nvalues int64
 			ctx := context.WithValue(context.Background(), coordinator.StatPointsWritten, &npoints)
 			ctx = context.WithValue(ctx, coordinator.StatValuesWritten, &nvalues)
+			if key := r.Header.Get("Idempotency-Key"); key != "" {
+				ctx = context.WithValue(ctx, coordinator.IdempotencyKey, key)
+			}
 
 			// for now, just store the number of values used.
 			err := pw.WritePointsWithContext(ctx, database, retentionPolicy, consistency, user, points)
@@ -1958,6 +1961,7 @@ func cors(inner http.Handler) http.Handler {
 				`Authorization`,
 				`Content-Length`,
 				`Content-Type`,
+				`Idempotency-Key`,
 				`X-CSRF-Token`,
 				`X-HTTP-Method-Override`,
 			}, ", "))
@@ -320,18 +320,33 @@ func (c *Client) DataNodes() []NodeInfo {
 	return c.data().DataNodes
 }
 
+// DataNodeCount returns the number of data nodes in the cluster.
+func (c *Client) DataNodeCount() int {
+	return len(c.data().DataNodes)
+}
+
 // CreateDataNode will create a new data node in the metastore
 func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
+	return c.CreateDataNodeWithZone(httpAddr, tcpAddr, "")
+}
+
+// CreateDataNodeWithZone creates a new data node in the given zone, the
+// failure domain of the node. An empty zone leaves the node without one.
+func (c *Client) CreateDataNodeWithZone(httpAddr, tcpAddr, zone string) (*NodeInfo, error) {
 	cmd := &internal.CreateDataNodeCommand{
 		HTTPAddr: proto.String(httpAddr),
 		TCPAddr:  proto.String(tcpAddr),
 	}
+	if zone != "" {
+		cmd.Zone = proto.String(zone)
+	}
 
-	if err := c.retryUntilExec(internal.Command_CreateDataNodeCommand, internal.E_CreateDataNodeCommand_Command, cmd); err != nil {
+	id, err := c.retryUntilExecWithID(internal.Command_CreateDataNodeCommand, internal.E_CreateDataNodeCommand_Command, cmd)
+	if err != nil {
 		return nil, err
 	}
 
-	n, err := c.DataNodeByTCPAddr(tcpAddr)
+	n, err := c.DataNode(id)
 	if err != nil {
 		return nil, err
 	}
@@ -345,6 +360,22 @@ func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
 	return n, nil
 }
 
+// PromoteToDataNode registers the meta node id as a data node serving on
+// httpAddr and tcpAddr, and returns the new data node.
+func (c *Client) PromoteToDataNode(id uint64, httpAddr, tcpAddr string) (*NodeInfo, error) {
+	cmd := &internal.PromoteToDataNodeCommand{
+		ID:       proto.Uint64(id),
+		HTTPAddr: proto.String(httpAddr),
+		TCPAddr:  proto.String(tcpAddr),
+	}
+
+	if err := c.retryUntilExec(internal.Command_PromoteToDataNodeCommand, internal.E_PromoteToDataNodeCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+
+	return c.DataNode(id)
+}
+
 // DataNodeByHTTPAddr returns the data node with the give http bind address
 func (c *Client) DataNodeByHTTPAddr(httpAddr string) (*NodeInfo, error) {
 	for _, n := range c.DataNodes() {
@@ -376,6 +407,28 @@ func (c *Client) DeleteDataNode(id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteDataNodeCommand, internal.E_DeleteDataNodeCommand_Command, cmd)
 }
 
+// UpdateDataNode changes the addresses of an existing data node.
+func (c *Client) UpdateDataNode(id uint64, httpAddr, tcpAddr string) error {
+	cmd := &internal.UpdateDataNodeCommand{
+		ID:       proto.Uint64(id),
+		HTTPAddr: proto.String(httpAddr),
+		TCPAddr:  proto.String(tcpAddr),
+	}
+
+	return c.retryUntilExec(internal.Command_UpdateDataNodeCommand, internal.E_UpdateDataNodeCommand_Command, cmd)
+}
+
+// SetDataNodeStatus sets the status of a data node to NodeStatusJoined or
+// NodeStatusDisjoined.
+func (c *Client) SetDataNodeStatus(id uint64, status string) error {
+	cmd := &internal.SetDataNodeStatusCommand{
+		ID:     proto.Uint64(id),
+		Status: proto.String(status),
+	}
+
+	return c.retryUntilExec(internal.Command_SetDataNodeStatusCommand, internal.E_SetDataNodeStatusCommand_Command, cmd)
+}
+
 // MetaNodes returns the meta nodes' info.
 func (c *Client) MetaNodes() []NodeInfo {
 	return c.data().MetaNodes
@@ -383,11 +436,20 @@ func (c *Client) MetaNodes() []NodeInfo {
 
 // CreateMetaNode will create a new meta node in the metastore
 func (c *Client) CreateMetaNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
+	return c.CreateMetaNodeWithZone(httpAddr, tcpAddr, "")
+}
+
+// CreateMetaNodeWithZone creates a new meta node in the given zone, the
+// failure domain of the node. An empty zone leaves the node without one.
+func (c *Client) CreateMetaNodeWithZone(httpAddr, tcpAddr, zone string) (*NodeInfo, error) {
 	cmd := &internal.CreateMetaNodeCommand{
 		HTTPAddr: proto.String(httpAddr),
 		TCPAddr:  proto.String(tcpAddr),
 		Rand:     proto.Uint64(uint64(rand.Int63())),
 	}
+	if zone != "" {
+		cmd.Zone = proto.String(zone)
+	}
 
 	if err := c.retryUntilExec(internal.Command_CreateMetaNodeCommand, internal.E_CreateMetaNodeCommand_Command, cmd); err != nil {
 		return nil, err
@@ -440,11 +502,25 @@ func (c *Client) Databases() []DatabaseInfo {
 	return dbs
 }
 
+// checkName returns ErrInvalidName if the name of a new database or retention
+// policy breaks the name rules in the client's config. The rules are checked
+// before a command is proposed so that nodes configured differently apply
+// the same commands.
+func (c *Client) checkName(name string) error {
+	if !c.config.NameRules().allow(name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
 // CreateDatabase creates a database or returns it if it already exists
 func (c *Client) CreateDatabase(name string) (*DatabaseInfo, error) {
 	if db := c.Database(name); db != nil {
 		return db, nil
 	}
+	if err := c.checkName(name); err != nil {
+		return nil, err
+	}
 
 	cmd := &internal.CreateDatabaseCommand{
 		Name: proto.String(name),
@@ -499,6 +575,13 @@ func (c *Client) CreateDatabaseWithRetentionPolicy(name string, spec *RetentionP
 		if db.DefaultRetentionPolicy != rpi.Name {
 			return nil, ErrRetentionPolicyConflict
 		}
+	} else if err := c.checkName(name); err != nil {
+		return nil, err
+	}
+	if db == nil || db.RetentionPolicy(rpi.Name) == nil {
+		if err := c.checkName(rpi.Name); err != nil {
+			return nil, err
+		}
 	}
 
 	cmd := &internal.CreateDatabaseCommand{
@@ -526,17 +609,70 @@ func (c *Client) DropDatabase(name string) error {
 	return c.retryUntilExec(internal.Command_DropDatabaseCommand, internal.E_DropDatabaseCommand_Command, cmd)
 }
 
+// RenameDatabase renames a database that has no shard groups.
+func (c *Client) RenameDatabase(oldName, newName string) error {
+	if err := c.checkName(newName); err != nil {
+		return err
+	}
+	return c.retryUntilExec(internal.Command_RenameDatabaseCommand, internal.E_RenameDatabaseCommand_Command,
+		&internal.RenameDatabaseCommand{
+			OldName: proto.String(oldName),
+			NewName: proto.String(newName),
+		},
+	)
+}
+
+// SetDatabaseLimits sets the maximum number of series in a database and of
+// values per tag key. A zero limit is unlimited.
+func (c *Client) SetDatabaseLimits(name string, maxSeries, maxValues int) error {
+	return c.retryUntilExec(internal.Command_SetDatabaseLimitsCommand, internal.E_SetDatabaseLimitsCommand_Command,
+		&internal.SetDatabaseLimitsCommand{
+			Name:      proto.String(name),
+			MaxSeries: proto.Int64(int64(maxSeries)),
+			MaxValues: proto.Int64(int64(maxValues)),
+		},
+	)
+}
+
+// SetDefaultShardGroupDuration sets the shard group duration of retention
+// policies later created on a database without one. Zero clears the default.
+func (c *Client) SetDefaultShardGroupDuration(database string, d time.Duration) error {
+	return c.retryUntilExec(internal.Command_SetDefaultShardGroupDurationCommand, internal.E_SetDefaultShardGroupDurationCommand_Command,
+		&internal.SetDefaultShardGroupDurationCommand{
+			Database: proto.String(database),
+			Duration: proto.Int64(int64(d)),
+		},
+	)
+}
+
 // CreateRetentionPolicy creates a retention policy on the specified database.
 func (c *Client) CreateRetentionPolicy(database string, spec *RetentionPolicySpec, makeDefault bool) (*RetentionPolicyInfo, error) {
 	if spec.Duration != nil && *spec.Duration < MinRetentionPolicyDuration && *spec.Duration != 0 {
 		return nil, ErrRetentionPolicyDurationTooLow
 	}
 
+	if rpi, _ := c.RetentionPolicy(database, spec.Name); rpi == nil {
+		if err := c.checkName(spec.Name); err != nil {
+			return nil, err
+		}
+	}
+
 	rp := spec.NewRetentionPolicyInfo()
+
+	// Leave an unset shard group duration to the command so the database's
+	// default applies before the one derived from the policy's duration.
+	if spec.ShardGroupDuration == 0 {
+		rp.ShardGroupDuration = 0
+	}
+
 	cmd := &internal.CreateRetentionPolicyCommand{
 		Database:        proto.String(database),
 		RetentionPolicy: rp.marshal(),
 		Default:         proto.Bool(makeDefault),
+		CheckReplicaN:   proto.Bool(!spec.AllowExcessReplicaN),
+	}
+	if spec.StrictShardGroupDuration {
+		cmd.StrictShardGroupDuration = proto.Bool(true)
 	}
 
 	if err := c.retryUntilExec(internal.Command_CreateRetentionPolicyCommand, internal.E_CreateRetentionPolicyCommand_Command, cmd); err != nil {
@@ -546,6 +682,50 @@ func (c *Client) CreateRetentionPolicy(database string, spec *RetentionPolicySpe
 	return c.RetentionPolicy(database, spec.Name)
 }
 
+// SetWriteAffinityTag sets the tag whose value selects the shard points of a
+// retention policy are written to. An empty tag selects by series.
+func (c *Client) SetWriteAffinityTag(database, policy, tag string) error {
+	cmd := &internal.SetWriteAffinityTagCommand{
+		Database: proto.String(database),
+		Policy:   proto.String(policy),
+		Tag:      proto.String(tag),
+	}
+
+	return c.retryUntilExec(internal.Command_SetWriteAffinityTagCommand, internal.E_SetWriteAffinityTagCommand_Command, cmd)
+}
+
+// SetPendingShardCount sets the number of shards in shard groups created for
+// a retention policy from now on. Existing shard groups keep their shards.
+func (c *Client) SetPendingShardCount(database, policy string, n int) error {
+	if n < 1 {
+		return ErrShardCountTooLow
+	}
+
+	cmd := &internal.SetPendingShardCountCommand{
+		Database: proto.String(database),
+		Policy:   proto.String(policy),
+		Count:    proto.Uint32(uint32(n)),
+	}
+
+	return c.retryUntilExec(internal.Command_SetPendingShardCountCommand, internal.E_SetPendingShardCountCommand_Command, cmd)
+}
+
+// SetPreCreateCount sets the number of shard groups after the current one of
+// a retention policy that are created ahead of time. Zero disables it.
+func (c *Client) SetPreCreateCount(database, policy string, n int) error {
+	if n < 0 {
+		return ErrPreCreateCountTooLow
+	}
+
+	cmd := &internal.SetPreCreateCountCommand{
+		Database: proto.String(database),
+		Policy:   proto.String(policy),
+		Count:    proto.Uint32(uint32(n)),
+	}
+
+	return c.retryUntilExec(internal.Command_SetPreCreateCountCommand, internal.E_SetPreCreateCountCommand_Command, cmd)
+}
+
 // RetentionPolicy returns the requested retention policy info.
 func (c *Client) RetentionPolicy(database, name string) (rpi *RetentionPolicyInfo, err error) {
 	db := c.Database(database)
@@ -566,10 +746,29 @@ func (c *Client) DropRetentionPolicy(database, name string) error {
 	return c.retryUntilExec(internal.Command_DropRetentionPolicyCommand, internal.E_DropRetentionPolicyCommand_Command, cmd)
 }
 
+// RenameRetentionPolicy renames a retention policy that has no shard groups.
+func (c *Client) RenameRetentionPolicy(database, oldName, newName string) error {
+	if err := c.checkName(newName); err != nil {
+		return err
+	}
+	return c.retryUntilExec(internal.Command_RenameRetentionPolicyCommand, internal.E_RenameRetentionPolicyCommand_Command,
+		&internal.RenameRetentionPolicyCommand{
+			Database: proto.String(database),
+			OldName:  proto.String(oldName),
+			NewName:  proto.String(newName),
+		},
+	)
+}
+
 // UpdateRetentionPolicy updates a retention policy.
 func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	var newName *string
 	if rpu.Name != nil {
+		if *rpu.Name != name {
+			if err := c.checkName(*rpu.Name); err != nil {
+				return err
+			}
+		}
 		newName = rpu.Name
 	}
 
@@ -599,11 +798,32 @@ func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPoli
 		ReplicaN:           replicaN,
 		ShardGroupDuration: shardDuration,
 		Default:            proto.Bool(makeDefault),
+		CheckReplicaN:      proto.Bool(true),
+	}
+	if rpu.AllowExcessReplicaN {
+		cmd.AllowExcessReplicaN = proto.Bool(true)
+	}
+	if rpu.ReadOnly != nil {
+		cmd.ReadOnly = proto.Bool(*rpu.ReadOnly)
+	}
+	if rpu.StrictExpiry {
+		cmd.StrictExpiry = proto.Bool(true)
+		cmd.Time = proto.Int64(MarshalTime(time.Now()))
+	}
+	if rpu.ForceExpiry {
+		cmd.ForceExpiry = proto.Bool(true)
 	}
 
 	return c.retryUntilExec(internal.Command_UpdateRetentionPolicyCommand, internal.E_UpdateRetentionPolicyCommand_Command, cmd)
 }
 
+// RetentionPolicyReplicaNUpdateSafety checks a change to the replication
+// factor of a retention policy against the current meta data, returning the
+// notes to pass on to the user. See Data.RetentionPolicyReplicaNUpdateSafety.
+func (c *Client) RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *RetentionPolicyUpdate) ([]string, error) {
+	return c.data().RetentionPolicyReplicaNUpdateSafety(database, name, rpu)
+}
+
 // Users returns a slice of UserInfo representing the currently known users.
 func (c *Client) Users() []UserInfo {
 	users := c.data().Users
@@ -721,6 +941,23 @@ func (c *Client) SetPrivilege(username, database string, p influxql.Privilege) e
 	)
 }
 
+// SetPrivileges sets a user's privileges on several databases at once, keyed
+// by database name. No privilege is changed unless every database exists.
+func (c *Client) SetPrivileges(username string, grants map[string]influxql.Privilege) error {
+	cmd := &internal.SetPrivilegesCommand{
+		Username:   proto.String(username),
+		Privileges: make([]*internal.UserPrivilege, 0, len(grants)),
+	}
+	for database, p := range grants {
+		cmd.Privileges = append(cmd.Privileges, &internal.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(p)),
+		})
+	}
+
+	return c.retryUntilExec(internal.Command_SetPrivilegesCommand, internal.E_SetPrivilegesCommand_Command, cmd)
+}
+
 // SetAdminPrivilege sets or unsets admin privilege to the given username.
 func (c *Client) SetAdminPrivilege(username string, admin bool) error {
 	return c.retryUntilExec(internal.Command_SetAdminPrivilegeCommand, internal.E_SetAdminPrivilegeCommand_Command,
@@ -731,6 +968,60 @@ func (c *Client) SetAdminPrivilege(username string, admin bool) error {
 	)
 }
 
+// Roles returns a list of all roles.
+func (c *Client) Roles() []RoleInfo {
+	return c.data().CloneRoles()
+}
+
+// CreateRole creates a new role without users or privileges.
+func (c *Client) CreateRole(name string) error {
+	return c.retryUntilExec(internal.Command_CreateRoleCommand, internal.E_CreateRoleCommand_Command,
+		&internal.CreateRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// DropRole removes a role by name.
+func (c *Client) DropRole(name string) error {
+	return c.retryUntilExec(internal.Command_DropRoleCommand, internal.E_DropRoleCommand_Command,
+		&internal.DropRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// AddUserToRole makes a user a member of a role.
+func (c *Client) AddUserToRole(role, username string) error {
+	return c.retryUntilExec(internal.Command_AddUserToRoleCommand, internal.E_AddUserToRoleCommand_Command,
+		&internal.AddUserToRoleCommand{
+			Role:     proto.String(role),
+			Username: proto.String(username),
+		},
+	)
+}
+
+// RemoveUserFromRole removes a user from a role.
+func (c *Client) RemoveUserFromRole(role, username string) error {
+	return c.retryUntilExec(internal.Command_RemoveUserFromRoleCommand, internal.E_RemoveUserFromRoleCommand_Command,
+		&internal.RemoveUserFromRoleCommand{
+			Role:     proto.String(role),
+			Username: proto.String(username),
+		},
+	)
+}
+
+// SetRolePrivilege sets a privilege for the given role on the given database.
+func (c *Client) SetRolePrivilege(role, database string, p influxql.Privilege) error {
+	return c.retryUntilExec(internal.Command_SetRolePrivilegeCommand, internal.E_SetRolePrivilegeCommand_Command,
+		&internal.SetRolePrivilegeCommand{
+			Role:      proto.String(role),
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(p)),
+		},
+	)
+}
+
 // UserPrivileges returns the privileges for a user mapped by database name.
 func (c *Client) UserPrivileges(username string) (map[string]influxql.Privilege, error) {
 	p, err := c.data().UserPrivileges(username)
@@ -864,6 +1155,79 @@ func (c *Client) DropShard(id uint64) error {
 	return c.retryUntilExec(internal.Command_DropShardCommand, internal.E_DropShardCommand_Command, cmd)
 }
 
+// MoveShard reassigns fromNodeID's replica of a shard to toNodeID. The new
+// owner is ShardStateCopying until its data has been copied.
+func (c *Client) MoveShard(shardID, fromNodeID, toNodeID uint64) error {
+	cmd := &internal.MoveShardCommand{
+		ShardID:    proto.Uint64(shardID),
+		FromNodeID: proto.Uint64(fromNodeID),
+		ToNodeID:   proto.Uint64(toNodeID),
+	}
+
+	return c.retryUntilExec(internal.Command_MoveShardCommand, internal.E_MoveShardCommand_Command, cmd)
+}
+
+// TouchShard records t as the time of the latest write to a shard.
+func (c *Client) TouchShard(id uint64, t time.Time) error {
+	cmd := &internal.TouchShardCommand{
+		ID:   proto.Uint64(id),
+		Time: proto.Int64(MarshalTime(t)),
+	}
+
+	return c.retryUntilExec(internal.Command_TouchShardCommand, internal.E_TouchShardCommand_Command, cmd)
+}
+
+// RebalanceShards evens out the shard replicas held by each data node using
+// strategy, and returns the planned movements. With dryRun set, the movements
+// are only planned.
+func (c *Client) RebalanceShards(strategy string, dryRun bool) ([]ShardMovement, error) {
+	movements, err := c.data().RebalanceShards(strategy, true)
+	if err != nil || dryRun || len(movements) == 0 {
+		return movements, err
+	}
+
+	cmd := &internal.RebalanceShardsCommand{
+		Strategy: proto.String(strategy),
+	}
+	if err := c.retryUntilExec(internal.Command_RebalanceShardsCommand, internal.E_RebalanceShardsCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// SetPlacementStrategy sets the strategy, by name, used to place the shards of
+// new shard groups.
+func (c *Client) SetPlacementStrategy(name string) error {
+	cmd := &internal.SetPlacementStrategyCommand{
+		Name: proto.String(name),
+	}
+
+	return c.retryUntilExec(internal.Command_SetPlacementStrategyCommand, internal.E_SetPlacementStrategyCommand_Command, cmd)
+}
+
+// SetShardOwnerState sets the state of nodeID's replica of a shard to one of
+// ShardStateHot, ShardStateCopying or ShardStateStale.
+func (c *Client) SetShardOwnerState(shardID, nodeID uint64, state string) error {
+	cmd := &internal.SetShardOwnerStateCommand{
+		ShardID: proto.Uint64(shardID),
+		NodeID:  proto.Uint64(nodeID),
+		State:   proto.String(state),
+	}
+
+	return c.retryUntilExec(internal.Command_SetShardOwnerStateCommand, internal.E_SetShardOwnerStateCommand_Command, cmd)
+}
+
+// TouchShards records t as the time of the latest write to each of the shards
+// ids.
+func (c *Client) TouchShards(ids []uint64, t time.Time) error {
+	cmd := &internal.TouchShardsCommand{
+		IDs:  ids,
+		Time: proto.Int64(MarshalTime(t)),
+	}
+
+	return c.retryUntilExec(internal.Command_TouchShardsCommand, internal.E_TouchShardsCommand_Command, cmd)
+}
+
 // TruncateShardGroups truncates any shard group that could contain timestamps beyond t.
 func (c *Client) TruncateShardGroups(t time.Time) error {
 	return c.retryUntilExec(internal.Command_TruncateShardGroupsCommand, internal.E_TruncateShardGroupsCommand_Command,
@@ -882,6 +1246,13 @@ func (c *Client) PruneShardGroups() error {
 
 // CreateShardGroup creates a shard group on a database and policy for a given timestamp.
 func (c *Client) CreateShardGroup(database, policy string, timestamp time.Time) (*ShardGroupInfo, error) {
+	return c.CreateShardGroupWithReplicaN(database, policy, timestamp, 0)
+}
+
+// CreateShardGroupWithReplicaN creates a shard group like CreateShardGroup, with
+// replicaN overriding the policy's replication factor for the new shard group.
+// A replicaN of zero uses the policy's factor.
+func (c *Client) CreateShardGroupWithReplicaN(database, policy string, timestamp time.Time, replicaN int) (*ShardGroupInfo, error) {
 	if sg, _ := c.data().ShardGroupByTimestamp(database, policy, timestamp); sg != nil {
 		return sg, nil
 	}
@@ -891,6 +1262,9 @@ func (c *Client) CreateShardGroup(database, policy string, timestamp time.Time)
 		Policy:    proto.String(policy),
 		Timestamp: proto.Int64(timestamp.UnixNano()),
 	}
+	if replicaN > 0 {
+		cmd.ReplicaN = proto.Uint32(uint32(replicaN))
+	}
 
 	if err := c.retryUntilExec(internal.Command_CreateShardGroupCommand, internal.E_CreateShardGroupCommand_Command, cmd); err != nil {
 		return nil, err
@@ -907,6 +1281,37 @@ func (c *Client) CreateShardGroup(database, policy string, timestamp time.Time)
 	return sgi, nil
 }
 
+// CreateShardGroupWithOwners creates a shard group like CreateShardGroup, with
+// the owners of each shard given by owners instead of the placement strategy.
+func (c *Client) CreateShardGroupWithOwners(database, policy string, timestamp time.Time, owners [][]ShardOwner) (*ShardGroupInfo, error) {
+	cmd := &internal.CreateShardGroupWithOwnersCommand{
+		Database:  proto.String(database),
+		Policy:    proto.String(policy),
+		Timestamp: proto.Int64(timestamp.UnixNano()),
+		Owners:    make([]*internal.ShardOwnerSet, len(owners)),
+	}
+	for i, set := range owners {
+		cmd.Owners[i] = &internal.ShardOwnerSet{Owners: make([]*internal.ShardOwner, len(set))}
+		for j, owner := range set {
+			cmd.Owners[i].Owners[j] = owner.marshal()
+		}
+	}
+
+	if err := c.retryUntilExec(internal.Command_CreateShardGroupWithOwnersCommand, internal.E_CreateShardGroupWithOwnersCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+
+	rpi, err := c.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, errors.New("retention policy deleted after shard group created")
+	}
+
+	sgi := rpi.ShardGroupByTimestamp(timestamp)
+	return sgi, nil
+}
+
 // DeleteShardGroup removes a shard group from a database and retention policy by id.
 func (c *Client) DeleteShardGroup(database, policy string, id uint64) error {
 	cmd := &internal.DeleteShardGroupCommand{
@@ -918,13 +1323,35 @@ func (c *Client) DeleteShardGroup(database, policy string, id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteShardGroupCommand, internal.E_DeleteShardGroupCommand_Command, cmd)
 }
 
+// PreCreateShardGroups creates any of the next PreCreateCount shard groups of a
+// retention policy after the one containing now that do not exist yet.
+func (c *Client) PreCreateShardGroups(database, policy string, now time.Time) error {
+	cmd := &internal.PreCreateShardGroupsCommand{
+		Database:  proto.String(database),
+		Policy:    proto.String(policy),
+		Timestamp: proto.Int64(now.UnixNano()),
+	}
+
+	return c.retryUntilExec(internal.Command_PreCreateShardGroupsCommand, internal.E_PreCreateShardGroupsCommand_Command, cmd)
+}
+
 // PrecreateShardGroups creates shard groups whose endtime is before the 'to' time passed in, but
 // is yet to expire before 'from'. This is to avoid the need for these shards to be created when data
 // for the corresponding time range arrives. Shard creation involves Raft consensus, and precreation
-// avoids taking the hit at write-time.
+// avoids taking the hit at write-time. Retention policies with a PreCreateCount also get that many
+// shard groups after the one containing 'from'.
 func (c *Client) PrecreateShardGroups(from, to time.Time) error {
 	for _, di := range c.data().Databases {
 		for _, rp := range di.RetentionPolicies {
+			if rp.preCreatePending(from) {
+				if err := c.PreCreateShardGroups(di.Name, rp.Name, from); err != nil {
+					c.logger.Info("Failed to pre-create shard groups",
+						logger.Database(di.Name),
+						logger.RetentionPolicy(rp.Name),
+						zap.Error(err))
+				}
+			}
+
 			if len(rp.ShardGroups) == 0 {
 				// No data was ever written to this group, or all groups have been deleted.
 				continue
@@ -945,7 +1372,7 @@ func (c *Client) PrecreateShardGroups(from, to time.Time) error {
 						logger.RetentionPolicy(rp.Name))
 					continue
 				}
-				newGroup, err := c.CreateShardGroup(di.Name, rp.Name, nextShardGroupTime)
+				newGroup, err := c.CreateShardGroupWithReplicaN(di.Name, rp.Name, nextShardGroupTime, g.ReplicaN)
 				if err != nil || newGroup == nil {
 					c.logger.Info("Failed to precreate successive shard group",
 						zap.Uint64("group_id", g.ID), zap.Error(err))
@@ -992,6 +1419,7 @@ func (c *Client) CreateContinuousQuery(database, name, query string) error {
 			Database: proto.String(database),
 			Name:     proto.String(name),
 			Query:    proto.String(query),
+			Validate: proto.Bool(true),
 		},
 	)
 }
@@ -1006,8 +1434,38 @@ func (c *Client) DropContinuousQuery(database, name string) error {
 	)
 }
 
+// UpdateContinuousQuery replaces the query of an existing continuous query,
+// keeping its name and enabled flag.
+func (c *Client) UpdateContinuousQuery(database, name, query string) error {
+	return c.retryUntilExec(internal.Command_UpdateContinuousQueryCommand, internal.E_UpdateContinuousQueryCommand_Command,
+		&internal.UpdateContinuousQueryCommand{
+			Database: proto.String(database),
+			Name:     proto.String(name),
+			Query:    proto.String(query),
+		},
+	)
+}
+
+// SetContinuousQueryEnabled pauses or resumes a continuous query without
+// changing its definition.
+func (c *Client) SetContinuousQueryEnabled(database, name string, enabled bool) error {
+	return c.retryUntilExec(internal.Command_SetContinuousQueryEnabledCommand, internal.E_SetContinuousQueryEnabledCommand_Command,
+		&internal.SetContinuousQueryEnabledCommand{
+			Database: proto.String(database),
+			Name:     proto.String(name),
+			Enabled:  proto.Bool(enabled),
+		},
+	)
+}
+
 // CreateSubscription creates a subscription against the given database and retention policy.
 func (c *Client) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+	return c.CreateSubscriptionWithMeasurements(database, rp, name, mode, destinations, nil)
+}
+
+// CreateSubscriptionWithMeasurements creates a subscription that is only sent
+// the points of the named measurements. An empty list sends every point.
+func (c *Client) CreateSubscriptionWithMeasurements(database, rp, name, mode string, destinations, measurements []string) error {
 	return c.retryUntilExec(internal.Command_CreateSubscriptionCommand, internal.E_CreateSubscriptionCommand_Command,
 		&internal.CreateSubscriptionCommand{
 			Database:        proto.String(database),
@@ -1015,6 +1473,21 @@ func (c *Client) CreateSubscription(database, rp, name, mode string, destination
 			Name:            proto.String(name),
 			Mode:            proto.String(mode),
 			Destinations:    destinations,
+			Measurements:    measurements,
+		},
+	)
+}
+
+// UpdateSubscription replaces the mode and destinations of the named
+// subscription on the given database and retention policy.
+func (c *Client) UpdateSubscription(database, rp, name, mode string, destinations []string) error {
+	return c.retryUntilExec(internal.Command_UpdateSubscriptionCommand, internal.E_UpdateSubscriptionCommand_Command,
+		&internal.UpdateSubscriptionCommand{
+			Database:        proto.String(database),
+			RetentionPolicy: proto.String(rp),
+			Name:            proto.String(name),
+			Mode:            proto.String(mode),
+			Destinations:    destinations,
 		},
 	)
 }
@@ -1096,14 +1569,21 @@ func (c *Client) index() uint64 {
 // retryUntilExec will attempt the command on each of the metaservers until it either succeeds or
 // hits the max number of tries
 func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) error {
+	_, err := c.retryUntilExecWithID(typ, desc, value)
+	return err
+}
+
+// retryUntilExecWithID executes a command like retryUntilExec, and returns the
+// node ID assigned by the command, if any.
+func (c *Client) retryUntilExecWithID(typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (uint64, error) {
 	c.mu.RLock()
 	if len(c.metaServers) == 0 {
 		c.mu.RUnlock()
-		return ErrServiceUnavailable
+		return 0, ErrServiceUnavailable
 	}
 	c.mu.RUnlock()
 	var err error
-	var index uint64
+	var index, id uint64
 	tries := 0
 	currentServer := 0
 	var redirectServer string
@@ -1114,7 +1594,7 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 		select {
 		case <-c.closing:
 			c.mu.RUnlock()
-			return nil
+			return 0, nil
 		default:
 			// we're still open, continue on
 		}
@@ -1141,17 +1621,17 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 			}
 		}
 
-		index, err = c.exec(url, typ, desc, value)
+		index, id, err = c.exec(url, typ, desc, value)
 		tries++
 		currentServer++
 
 		if err == nil {
 			c.waitForIndex(index)
-			return nil
+			return id, nil
 		}
 
 		if tries > maxRetries {
-			return err
+			return 0, err
 		}
 
 		if e, ok := err.(errRedirect); ok {
@@ -1160,14 +1640,14 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 		}
 
 		if _, ok := err.(errCommand); ok {
-			return err
+			return 0, err
 		}
 
 		time.Sleep(errSleep)
 	}
 }
 
-func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (index uint64, err error) {
+func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (index, id uint64, err error) {
 	// Create command.
 	cmd := &internal.Command{Type: &typ}
 	if err := proto.SetExtension(cmd, desc, value); err != nil {
@@ -1176,38 +1656,38 @@ func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.Extensi
 
 	b, err := proto.Marshal(cmd)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	resp, err := c.client.Post(url, "application/octet-stream", bytes.NewBuffer(b))
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer resp.Body.Close()
 
 	// read the response
 	if resp.StatusCode == http.StatusTemporaryRedirect {
-		return 0, errRedirect{host: resp.Header.Get("Location")}
+		return 0, 0, errRedirect{host: resp.Header.Get("Location")}
 	} else if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("meta service returned %s", resp.Status)
+		return 0, 0, fmt.Errorf("meta service returned %s", resp.Status)
 	}
 
 	res := &internal.Response{}
 
 	b, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	if err := proto.Unmarshal(b, res); err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	es := res.GetError()
 	if es != "" {
-		return 0, errCommand{msg: es}
+		return 0, 0, errCommand{msg: es}
 	}
 
-	return res.GetIndex(), nil
+	return res.GetIndex(), res.GetID(), nil
 }
 
 func (c *Client) waitForIndex(idx uint64) {
@@ -410,6 +410,89 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	}
 }
 
+func TestMetaClient_SetDatabaseLimits(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDatabaseLimits("db0", 1000, 100); err != nil {
+		t.Fatal(err)
+	} else if db := c.Database("db0"); db.MaxSeriesPerDatabase != 1000 || db.MaxValuesPerTag != 100 {
+		t.Fatalf("got limits %d and %d, expected 1000 and 100", db.MaxSeriesPerDatabase, db.MaxValuesPerTag)
+	}
+
+	if err := c.SetDatabaseLimits("db0", -1, 0); err == nil || err.Error() != meta.ErrDatabaseLimitNegative.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_CreateRetentionPolicy_StrictShardGroupDuration(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	duration := 10 * 24 * time.Hour
+	spec := &meta.RetentionPolicySpec{
+		Name:                     "rp0",
+		Duration:                 &duration,
+		ShardGroupDuration:       7 * 24 * time.Hour,
+		StrictShardGroupDuration: true,
+	}
+	if _, err := c.CreateRetentionPolicy("db0", spec, false); err == nil || !strings.Contains(err.Error(), meta.ErrShardGroupDurationNotDivisor.Error()) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without strict mode the policy is created.
+	spec.StrictShardGroupDuration = false
+	if _, err := c.CreateRetentionPolicy("db0", spec, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetaClient_SetDefaultShardGroupDuration(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefaultShardGroupDuration("db0", 6*time.Hour); err != nil {
+		t.Fatal(err)
+	} else if got, exp := c.Database("db0").DefaultShardGroupDuration, 6*time.Hour; got != exp {
+		t.Fatalf("got default shard group duration %v, expected %v", got, exp)
+	}
+
+	// A policy without a shard group duration inherits the database default.
+	duration := 7 * 24 * time.Hour
+	rpi, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", Duration: &duration}, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := rpi.ShardGroupDuration, 6*time.Hour; got != exp {
+		t.Fatalf("got shard group duration %v, expected %v", got, exp)
+	}
+
+	if err := c.SetDefaultShardGroupDuration("db1", time.Hour); err == nil || err.Error() != influxdb.ErrDatabaseNotFound("db1").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestMetaClient_DefaultRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -534,6 +617,41 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	}
 }
 
+func TestMetaClient_UpdateRetentionPolicy_StrictExpiry(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8086", "bar:8088"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateShardGroup("db0", "autogen", time.Now().Add(-30*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A strict update refuses to expire the shard group unless forced.
+	duration := 7 * 24 * time.Hour
+	rpu := &meta.RetentionPolicyUpdate{Duration: &duration, StrictExpiry: true}
+	if err := c.UpdateRetentionPolicy("db0", "autogen", rpu, false); err == nil || err.Error() != meta.ErrRetentionPolicyExpiresShardGroups.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpu.ForceExpiry = true
+	if err := c.UpdateRetentionPolicy("db0", "autogen", rpu, false); err != nil {
+		t.Fatal(err)
+	} else if rpi, err := c.RetentionPolicy("db0", "autogen"); err != nil {
+		t.Fatal(err)
+	} else if rpi.Duration != duration {
+		t.Fatalf("got duration %v, expected %v", rpi.Duration, duration)
+	}
+}
+
 func TestMetaClient_DropRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -757,6 +875,43 @@ func TestMetaClient_CreateUser(t *testing.T) {
 	}
 }
 
+func TestMetaClient_SetPrivileges(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	for _, name := range []string{"db0", "db1"} {
+		if _, err := c.CreateDatabase(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CreateUser("wilma", "password", false); err != nil {
+		t.Fatal(err)
+	}
+
+	grants := map[string]influxql.Privilege{"db0": influxql.ReadPrivilege, "db1": influxql.AllPrivileges}
+	if err := c.SetPrivileges("wilma", grants); err != nil {
+		t.Fatal(err)
+	} else if got, err := c.UserPrivileges("wilma"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, grants) {
+		t.Fatalf("got privileges %v, expected %v", got, grants)
+	}
+
+	// No privilege is changed if a database is missing.
+	err := c.SetPrivileges("wilma", map[string]influxql.Privilege{"db0": influxql.WritePrivilege, "db2": influxql.ReadPrivilege})
+	if err == nil || err.Error() != influxdb.ErrDatabaseNotFound("db2").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if p, err := c.UserPrivilege("wilma", "db0"); err != nil {
+		t.Fatal(err)
+	} else if *p != influxql.ReadPrivilege {
+		t.Fatalf("got privilege %v, expected %v", *p, influxql.ReadPrivilege)
+	}
+}
+
 func TestMetaClient_UpdateUser(t *testing.T) {
 	t.Parallel()
 
@@ -771,6 +926,59 @@ func TestMetaClient_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestMetaClient_Roles(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateUser("fred", "supersecure", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateRole("readers"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateRole("readers"); err == nil || err.Error() != meta.ErrRoleExists.Error() {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrRoleExists)
+	}
+	if err := c.SetRolePrivilege("readers", "db0", influxql.ReadPrivilege); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddUserToRole("readers", "fred"); err != nil {
+		t.Fatal(err)
+	}
+
+	if u, err := c.User("fred"); err != nil {
+		t.Fatal(err)
+	} else if !u.(*meta.UserInfo).AuthorizeDatabase(influxql.ReadPrivilege, "db0") {
+		t.Fatal("expected fred to read db0 through the readers role")
+	}
+	if roles := c.Roles(); len(roles) != 1 || roles[0].Name != "readers" {
+		t.Fatalf("unexpected roles: %+v", roles)
+	}
+
+	if err := c.RemoveUserFromRole("readers", "fred"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := c.User("fred"); err != nil {
+		t.Fatal(err)
+	} else if u.(*meta.UserInfo).AuthorizeDatabase(influxql.ReadPrivilege, "db0") {
+		t.Fatal("expected fred to lose read access to db0")
+	}
+
+	if err := c.DropRole("readers"); err != nil {
+		t.Fatal(err)
+	}
+	if roles := c.Roles(); len(roles) != 0 {
+		t.Fatalf("unexpected roles: %+v", roles)
+	}
+}
+
 func TestMetaClient_ContinuousQueries(t *testing.T) {
 	t.Parallel()
 
@@ -791,29 +999,29 @@ func TestMetaClient_ContinuousQueries(t *testing.T) {
 	}
 
 	// Create a CQ
-	if err := c.CreateContinuousQuery("db0", "cq0", `SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m)`); err != nil {
+	if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`); err != nil {
 		t.Fatal(err)
 	}
 
 	// Recreating an existing CQ with the exact same query should not
 	// return an error.
-	if err := c.CreateContinuousQuery("db0", "cq0", `SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m)`); err != nil {
+	if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`); err != nil {
 		t.Fatalf("got error %q, but didn't expect one", err)
 	}
 
 	// Recreating an existing CQ with a different query should return
 	// an error.
-	if err := c.CreateContinuousQuery("db0", "cq0", `SELECT min(value) INTO foo_max FROM foo GROUP BY time(20m)`); err == nil {
+	if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT min(value) INTO foo_max FROM foo GROUP BY time(20m) END`); err == nil {
 		t.Fatal("didn't get and error, but expected one")
 	} else if got, exp := err, meta.ErrContinuousQueryExists; got.Error() != exp.Error() {
 		t.Fatalf("got %v, expected %v", got, exp)
 	}
 
 	// Create a few more CQ's
-	if err := c.CreateContinuousQuery("db0", "cq1", `SELECT max(value) INTO foo_max FROM foo GROUP BY time(10m)`); err != nil {
+	if err := c.CreateContinuousQuery("db0", "cq1", `CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT max(value) INTO foo_max FROM foo GROUP BY time(10m) END`); err != nil {
 		t.Fatal(err)
 	}
-	if err := c.CreateContinuousQuery("db0", "cq2", `SELECT min(value) INTO foo_min FROM foo GROUP BY time(10m)`); err != nil {
+	if err := c.CreateContinuousQuery("db0", "cq2", `CREATE CONTINUOUS QUERY cq2 ON db0 BEGIN SELECT min(value) INTO foo_min FROM foo GROUP BY time(10m) END`); err != nil {
 		t.Fatal(err)
 	}
 
@@ -828,6 +1036,64 @@ func TestMetaClient_ContinuousQueries(t *testing.T) {
 	}
 }
 
+func TestMetaClient_SetContinuousQueryEnabled(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetContinuousQueryEnabled("db0", "cq0", false); err != nil {
+		t.Fatal(err)
+	} else if c.Database("db0").ContinuousQueries[0].Enabled {
+		t.Fatal("expected continuous query to be disabled")
+	}
+	if err := c.SetContinuousQueryEnabled("db0", "cq0", true); err != nil {
+		t.Fatal(err)
+	} else if !c.Database("db0").ContinuousQueries[0].Enabled {
+		t.Fatal("expected continuous query to be enabled")
+	}
+
+	if err := c.SetContinuousQueryEnabled("db0", "cq1", false); err == nil || err.Error() != meta.ErrContinuousQueryNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_UpdateContinuousQuery(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`); err != nil {
+		t.Fatal(err)
+	}
+
+	query := `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO foo_mean FROM foo GROUP BY time(10m) END`
+	if err := c.UpdateContinuousQuery("db0", "cq0", query); err != nil {
+		t.Fatal(err)
+	} else if got := c.Database("db0").ContinuousQueries[0].Query; got != query {
+		t.Fatalf("got query %q, expected %q", got, query)
+	}
+
+	if err := c.UpdateContinuousQuery("db0", "cq1", query); err == nil || err.Error() != meta.ErrContinuousQueryNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestMetaClient_Subscriptions_Create(t *testing.T) {
 	t.Parallel()
 
@@ -884,6 +1150,25 @@ func TestMetaClient_Subscriptions_Create(t *testing.T) {
 	if err := c.CreateSubscription("db0", "autogen", "sub4", "ALL", []string{"https://example.com:9092"}); err != nil {
 		t.Fatal(err)
 	}
+
+	// Update a subscription's destinations.
+	if err := c.UpdateSubscription("db0", "autogen", "sub0", "ANY", []string{"udp://example.com:9093", "udp://example.com:9094"}); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := c.RetentionPolicy("db0", "autogen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	si := rp.Subscriptions[0]
+	if exp := []string{"udp://example.com:9093", "udp://example.com:9094"}; si.Name != "sub0" || si.Mode != "ANY" || !reflect.DeepEqual(si.Destinations, exp) {
+		t.Fatalf("unexpected subscription: %+v", si)
+	}
+
+	// Update a subscription that does not exist.
+	err = c.UpdateSubscription("db0", "autogen", "nosub", "ALL", []string{"udp://example.com:9090"})
+	if err == nil || err.Error() != meta.ErrSubscriptionNotFound.Error() {
+		t.Fatalf("unexpected error: %s", err)
+	}
 }
 
 func TestMetaClient_Subscriptions_Drop(t *testing.T) {
@@ -932,6 +1217,343 @@ func TestMetaClient_Subscriptions_Drop(t *testing.T) {
 	}
 }
 
+func TestMetaClient_CreateNodeWithZone(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	dn, err := c.CreateDataNodeWithZone("data1:8086", "data1:8088", "zone-a")
+	if err != nil {
+		t.Fatal(err)
+	} else if dn.Zone != "zone-a" {
+		t.Fatalf("got data node zone %q, expected %q", dn.Zone, "zone-a")
+	}
+
+	mn, err := c.CreateMetaNodeWithZone("meta2:8091", "meta2:8089", "zone-b")
+	if err != nil {
+		t.Fatal(err)
+	} else if mn.Zone != "zone-b" {
+		t.Fatalf("got meta node zone %q, expected %q", mn.Zone, "zone-b")
+	}
+
+	// Nodes created without a zone have none.
+	if dn, err := c.CreateDataNode("data2:8086", "data2:8088"); err != nil {
+		t.Fatal(err)
+	} else if dn.Zone != "" {
+		t.Fatalf("got data node zone %q, expected none", dn.Zone)
+	}
+}
+
+func TestMetaClient_SetShardOwnerState(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n, err := c.CreateDataNode("foo:8086", "bar:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := c.CreateShardGroup("db0", "autogen", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	shardID := sg.Shards[0].ID
+
+	if err := c.SetShardOwnerState(shardID, n.ID, meta.ShardStateCopying); err != nil {
+		t.Fatal(err)
+	}
+	_, _, sgi := c.ShardOwner(shardID)
+	if sgi == nil {
+		t.Fatal("shard not found")
+	} else if got, exp := sgi.Shards[0].Owners[0].State, meta.ShardStateCopying; got != exp {
+		t.Fatalf("got owner state %q, expected %q", got, exp)
+	}
+
+	if err := c.SetShardOwnerState(shardID, n.ID, "unknown"); err == nil || err.Error() != meta.ErrInvalidShardState.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.SetShardOwnerState(shardID+100, n.ID, meta.ShardStateHot); err == nil || err.Error() != meta.ErrShardNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_SetWriteAffinityTag(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetWriteAffinityTag("db0", "autogen", "host"); err != nil {
+		t.Fatal(err)
+	}
+	if rp, err := c.RetentionPolicy("db0", "autogen"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.WriteAffinityTag, "host"; got != exp {
+		t.Fatalf("got write affinity tag %q, expected %q", got, exp)
+	}
+
+	// An empty tag selects shards by series again.
+	if err := c.SetWriteAffinityTag("db0", "autogen", ""); err != nil {
+		t.Fatal(err)
+	}
+	if rp, err := c.RetentionPolicy("db0", "autogen"); err != nil {
+		t.Fatal(err)
+	} else if rp.WriteAffinityTag != "" {
+		t.Fatalf("unexpected write affinity tag %q", rp.WriteAffinityTag)
+	}
+
+	if err := c.SetWriteAffinityTag("db0", "nope", "host"); err == nil || err.Error() != influxdb.ErrRetentionPolicyNotFound("nope").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_SetPendingShardCount(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	for _, addr := range []string{"foo:8086", "bar:8086"} {
+		if _, err := c.CreateDataNode(addr, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	before, err := c.CreateShardGroup("db0", "autogen", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetPendingShardCount("db0", "autogen", 4); err != nil {
+		t.Fatal(err)
+	}
+	if rp, err := c.RetentionPolicy("db0", "autogen"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.PendingShardCount, 4; got != exp {
+		t.Fatalf("got pending shard count %d, expected %d", got, exp)
+	}
+
+	// Only shard groups created from now on get the new shard count.
+	after, err := c.CreateShardGroup("db0", "autogen", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := len(after.Shards), 4; got != exp {
+		t.Fatalf("got %d shards, expected %d", got, exp)
+	}
+	if sg, err := c.ShardGroupsByTimeRange("db0", "autogen", time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	} else if got, exp := len(sg[0].Shards), len(before.Shards); got != exp {
+		t.Fatalf("got %d shards in existing group, expected %d", got, exp)
+	}
+
+	if err := c.SetPendingShardCount("db0", "autogen", 0); err != meta.ErrShardCountTooLow {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.SetPendingShardCount("db0", "nope", 4); err == nil || err.Error() != influxdb.ErrRetentionPolicyNotFound("nope").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_PreCreateCount(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8086", "foo:8088"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+		Name:               "rp0",
+		ShardGroupDuration: time.Hour,
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetPreCreateCount("db0", "rp0", 2); err != nil {
+		t.Fatal(err)
+	}
+	if rp, err := c.RetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.PreCreateCount, 2; got != exp {
+		t.Fatalf("got pre-create count %d, expected %d", got, exp)
+	}
+
+	// Precreation creates the next 2 shard groups, though no data was written.
+	now := time.Now().UTC()
+	if err := c.PrecreateShardGroups(now, now.Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	start := now.Truncate(time.Hour)
+	groups, err := c.ShardGroupsByTimeRange("db0", "rp0", start, start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var starts []time.Time
+	for _, sgi := range groups {
+		starts = append(starts, sgi.StartTime)
+	}
+	if exp := []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour)}; !reflect.DeepEqual(starts, exp) {
+		t.Fatalf("got shard group starts %v, expected %v", starts, exp)
+	}
+
+	if err := c.SetPreCreateCount("db0", "rp0", -1); err != meta.ErrPreCreateCountTooLow {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.SetPreCreateCount("db0", "nope", 1); err == nil || err.Error() != influxdb.ErrRetentionPolicyNotFound("nope").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_MoveShard(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	from, err := c.CreateDataNode("foo:8086", "foo:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := c.CreateShardGroup("db0", "autogen", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	shardID := sg.Shards[0].ID
+	to, err := c.CreateDataNode("bar:8086", "bar:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.MoveShard(shardID, from.ID, to.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, _, sgi := c.ShardOwner(shardID)
+	if sgi == nil {
+		t.Fatal("shard not found")
+	}
+	exp := []meta.ShardOwner{{NodeID: to.ID, State: meta.ShardStateCopying}}
+	if got := sgi.Shards[0].Owners; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got owners %v, expected %v", got, exp)
+	}
+
+	if err := c.MoveShard(shardID, from.ID, to.ID); err == nil || err.Error() != meta.ErrShardOwnerExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.MoveShard(shardID+100, to.ID, from.ID); err == nil || err.Error() != meta.ErrShardNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_CreateDataNode_ID(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	// The data node reuses the ID of the meta node with its TCP address, as
+	// returned by the command.
+	mn, err := c.CreateMetaNode("host2:8091", "host2:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := c.CreateDataNode("host2:8086", "host2:8088")
+	if err != nil {
+		t.Fatal(err)
+	} else if n.ID != mn.ID || n.TCPAddr != "host2:8088" {
+		t.Fatalf("got data node %+v, expected ID %d", n, mn.ID)
+	}
+
+	if _, err := c.CreateDataNode("host2:8086", "host2:8088"); err == nil || err.Error() != meta.ErrNodeExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_PromoteToDataNode(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	mn, err := c.CreateMetaNode("meta2:8091", "meta2:8089")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.PromoteToDataNode(mn.ID, "meta2:8086", "meta2:8088")
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := (meta.NodeInfo{ID: mn.ID, Addr: "meta2:8086", TCPAddr: "meta2:8088", Status: meta.NodeStatusJoined}); *n != exp {
+		t.Fatalf("got data node %+v, expected %+v", *n, exp)
+	}
+
+	if _, err := c.PromoteToDataNode(mn.ID, "meta2:8086", "meta2:8088"); err == nil || err.Error() != meta.ErrNodeAlreadyDataNode.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaClient_SetDataNodeStatus(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n, err := c.CreateDataNode("host2:8086", "host2:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetDataNodeStatus(n.ID, meta.NodeStatusDisjoined); err != nil {
+		t.Fatal(err)
+	} else if n, err = c.DataNode(n.ID); err != nil {
+		t.Fatal(err)
+	} else if n.Status != meta.NodeStatusDisjoined {
+		t.Fatalf("got status %q, expected %q", n.Status, meta.NodeStatusDisjoined)
+	}
+
+	if err := c.SetDataNodeStatus(n.ID, "bogus"); err == nil || err.Error() != meta.ErrInvalidNodeStatus.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.SetDataNodeStatus(n.ID+100, meta.NodeStatusJoined); err == nil || err.Error() != meta.ErrNodeNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestMetaClient_Shards(t *testing.T) {
 	t.Parallel()
 
@@ -1058,6 +1680,42 @@ func TestMetaClient_CreateShardGroupIdempotent(t *testing.T) {
 	}
 }
 
+func TestMetaClient_CreateShardGroupWithOwners(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n, err := c.CreateDataNode("foo:8086", "bar:8088")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	tmin := time.Now()
+	owners := [][]meta.ShardOwner{{{NodeID: n.ID, State: meta.ShardStateCopying}}}
+	sg, err := c.CreateShardGroupWithOwners("db0", "autogen", tmin, owners)
+	if err != nil {
+		t.Fatal(err)
+	} else if sg == nil || len(sg.Shards) != 1 {
+		t.Fatalf("unexpected shard group: %+v", sg)
+	} else if !reflect.DeepEqual(sg.Shards[0].Owners, owners[0]) {
+		t.Fatalf("got owners %+v, expected %+v", sg.Shards[0].Owners, owners[0])
+	}
+
+	if _, err := c.CreateShardGroupWithOwners("db0", "autogen", tmin, owners); err == nil || err.Error() != meta.ErrShardGroupExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	owners = [][]meta.ShardOwner{{{NodeID: n.ID + 100}}}
+	if _, err := c.CreateShardGroupWithOwners("db0", "autogen", tmin.Add(365*24*time.Hour), owners); err == nil || err.Error() != meta.ErrNodeNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestMetaClient_PruneShardGroups(t *testing.T) {
 	t.Parallel()
 
@@ -1186,6 +1844,50 @@ func TestMetaClient_PersistClusterIDAfterRestart(t *testing.T) {
 	}
 }
 
+func TestMetaClient_NameRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.SingleServer = true
+	cfg.NameForbiddenPrefixes = []string{"_"}
+	defer os.RemoveAll(cfg.Dir)
+	s := newService(cfg)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := newClient(cfg)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("_db0"); err != meta.ErrInvalidName {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "_rp0"}, false); err != meta.ErrInvalidName {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.RenameDatabase("db0", "_db0"); err != meta.ErrInvalidName {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The _internal database is exempt by default.
+	if _, err := c.CreateDatabase("_internal"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rules are checked by the client, so a client without them can
+	// create the same names.
+	otherCfg := *cfg
+	otherCfg.NameForbiddenPrefixes = nil
+	other := newClient(&otherCfg)
+	defer other.Close()
+	if _, err := other.CreateDatabase("_db1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func newClient(cfg *meta.Config) *meta.Client {
 	c := meta.NewClient(cfg)
 	c.SetMetaServers([]string{cfg.HTTPBindAddress})
@@ -109,6 +109,13 @@ type Config struct {
 	PprofEnabled       bool          `toml:"pprof-enabled"`
 	LeaseDuration      toml.Duration `toml:"lease-duration"`
 
+	// NameForbiddenPrefixes, NameAllowInternal and NameMaxRunes restrict the
+	// names of new databases and retention policies created by this node's
+	// meta client. See NameRules.
+	NameForbiddenPrefixes []string `toml:"name-forbidden-prefixes"`
+	NameAllowInternal     bool     `toml:"name-allow-internal"`
+	NameMaxRunes          int      `toml:"name-max-runes"`
+
 	SharedSecret         string `toml:"shared-secret"`
 	InternalSharedSecret string `toml:"internal-shared-secret"`
 }
@@ -129,6 +136,7 @@ func NewConfig() *Config {
 		CommitTimeout:          toml.Duration(DefaultCommitTimeout),
 		PprofEnabled:           true,
 		LeaseDuration:          toml.Duration(DefaultLeaseDuration),
+		NameAllowInternal:      true,
 	}
 }
 
@@ -143,6 +151,15 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// NameRules returns the name rules set by the config.
+func (c *Config) NameRules() NameRules {
+	return NameRules{
+		ForbiddenPrefixes: c.NameForbiddenPrefixes,
+		AllowInternal:     c.NameAllowInternal,
+		MaxRunes:          c.NameMaxRunes,
+	}
+}
+
 // TLSConfig returns a TLS config.
 func (c Config) TLSConfig() (*tls.Config, error) {
 	return tcp.TLSConfig(c.TLS, c.HTTPSEnabled, c.HTTPSCertificate, c.HTTPSPrivateKey)
@@ -1,6 +1,7 @@
 package meta_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -13,6 +14,9 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 dir = "/tmp/foo"
 logging-enabled = false
+name-forbidden-prefixes = ["tmp_"]
+name-allow-internal = false
+name-max-runes = 16
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -22,5 +26,7 @@ logging-enabled = false
 		t.Fatalf("unexpected dir: %s", c.Dir)
 	} else if c.LoggingEnabled {
 		t.Fatalf("unexpected logging enabled: %v", c.LoggingEnabled)
+	} else if exp := (meta.NameRules{ForbiddenPrefixes: []string{"tmp_"}, MaxRunes: 16}); !reflect.DeepEqual(c.NameRules(), exp) {
+		t.Fatalf("unexpected name rules: %+v", c.NameRules())
 	}
 }
@@ -1,16 +1,21 @@
 package meta
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"net"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/influxdata/influxdb"
@@ -42,6 +47,10 @@ const (
 	// MaxNameLen is the maximum length of a database or retention policy name.
 	// InfluxDB uses the name for the directory name on disk.
 	MaxNameLen = 255
+
+	// DataVersion is the version of the metadata format written by
+	// Data.MarshalBinary. Version 0 is metadata written before versioning.
+	DataVersion uint32 = 1
 )
 
 // Data represents the top level collection of all metadata.
@@ -49,10 +58,12 @@ type Data struct {
 	Term      uint64 // associated raft term
 	Index     uint64 // associated raft index
 	ClusterID uint64
+	Version   uint32 // metadata format version, see DataVersion
 	MetaNodes []NodeInfo
 	DataNodes []NodeInfo
 	Databases []DatabaseInfo
 	Users     []UserInfo
+	Roles     []RoleInfo
 
 	// adminUserExists provides a constant time mechanism for determining
 	// if there is at least one admin user.
@@ -61,7 +72,73 @@ type Data struct {
 	MaxNodeID       uint64
 	MaxShardGroupID uint64
 	MaxShardID      uint64
-}
+
+	// PlacementStrategy names the strategy that assigns owners to the shards
+	// of new shard groups. An empty name means PlacementStrategyRoundRobin.
+	PlacementStrategy string
+}
+
+// DataView is the subset of Data's methods that do not modify it. Consumers
+// that only inspect metadata should accept a DataView so they cannot call a
+// mutating method by accident. It is not a read-only copy: the pointers its
+// methods return, such as from Database and RetentionPolicy, still refer to
+// the underlying data and must not be modified.
+type DataView interface {
+	DataNode(id uint64) *NodeInfo
+	DataNodeByTCPAddr(tcpAddr string) *NodeInfo
+	MetaNode(id uint64) *NodeInfo
+	MetaNodeByTCPAddr(tcpAddr string) *NodeInfo
+	Database(name string) *DatabaseInfo
+	CloneDatabases() []DatabaseInfo
+	DatabaseNames() []string
+	DatabaseNamesExcludingInternal() []string
+	DatabaseRetentionPolicyPairs() []RetentionPolicyPair
+	RetentionPoliciesWithAlignedShardGroups(database string, base time.Duration) ([]string, error)
+	RetentionPolicy(database, name string) (*RetentionPolicyInfo, error)
+	ShardGroups(database, policy string) ([]ShardGroupInfo, error)
+	ShardGroupInfosPaginated(database, policy string, offset, limit int) ([]ShardGroupInfo, int, error)
+	ShardGroupMembership(database, policy string) (map[uint64]map[uint64]int, error)
+	ShardGroupReplicaHealth(database, policy string) (ReplicaHealth, error)
+	ShardGroupsByTimeRange(database, policy string, tmin, tmax time.Time) ([]ShardGroupInfo, error)
+	ShardGroupByTimestamp(database, policy string, timestamp time.Time) (*ShardGroupInfo, error)
+	ShardGroupByTimestampDefaultRP(database string, timestamp time.Time) (*ShardGroupInfo, string, error)
+	WouldCreateShardGroup(database, policy string, timestamp time.Time) (bool, error)
+	ShardGroupTimeline(database, policy string) ([]ShardGroupTimelineEntry, error)
+	RetentionPolicyWriteWindow(database, policy string, now time.Time) (min, max time.Time, err error)
+	RetentionPolicyMinAcceptableWriteTime(database, policy string, now time.Time) (time.Time, error)
+	RebalanceRetentionPolicyShards(database, policy string) ([]ShardMovement, error)
+	RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *RetentionPolicyUpdate) ([]string, error)
+	RetentionPolicyUpdateExpiredShardGroups(database, name string, rpu *RetentionPolicyUpdate, now time.Time) (int, error)
+	ClusterShardInfos(database string) ([]ClusterShardInfo, error)
+	ClusterSummary() ClusterSummary
+	DatabaseCount() int
+	ShardGroupCount() int
+	ShardCount() int
+	Topology(httpScheme string) ClusterInfo
+	RetentionPolicyForShardGroup(sgID uint64) (database, policy string, rpi *RetentionPolicyInfo, ok bool)
+	ShardGroupByID(id uint64) (database, policy string, sg *ShardGroupInfo)
+	ShardGroupsPendingDeletion(now time.Time) []PendingShardGroupDeletion
+	IdleShards(before time.Time) []uint64
+	ShardsByNodeID(nodeID uint64) []NodeShard
+	OrphanedShards() []NodeShard
+	ShardOwnerCounts() map[uint64]int
+	ContinuousQueriesReferencingRetentionPolicy(database, policy string) ([]string, error)
+	User(username string) User
+	CloneUsers() []UserInfo
+	AdminUsers() []string
+	RegularUsers() []string
+	AdminUserExists() bool
+	UserPrivileges(name string) (map[string]influxql.Privilege, error)
+	UserPrivilegesSorted(name string) ([]DatabasePrivilege, error)
+	UserPrivilege(name, database string) (*influxql.Privilege, error)
+	UsersWithPrivilege(database string, p influxql.Privilege) []string
+	DatabasesForUser(name string) ([]string, error)
+	Role(name string) *RoleInfo
+	CloneRoles() []RoleInfo
+	Diff(other *Data) DataDiff
+}
+
+var _ DataView = (*Data)(nil)
 
 // DataNode returns a node by id.
 func (data *Data) DataNode(id uint64) *NodeInfo {
@@ -73,23 +150,44 @@ func (data *Data) DataNode(id uint64) *NodeInfo {
 	return nil
 }
 
+// DataNodeByTCPAddr returns the data node with the given TCP address, or nil if
+// there is none. The pointer is into data.DataNodes, so callers must not retain
+// it across changes to the data nodes.
+func (data *Data) DataNodeByTCPAddr(tcpAddr string) *NodeInfo {
+	for i := range data.DataNodes {
+		if data.DataNodes[i].TCPAddr == tcpAddr {
+			return &data.DataNodes[i]
+		}
+	}
+	return nil
+}
+
 // CreateDataNode adds a node to the metadata.
 func (data *Data) CreateDataNode(addr, tcpAddr string) error {
+	return data.CreateDataNodeWithZone(addr, tcpAddr, "")
+}
+
+// CreateDataNodeWithZone adds a node in the given zone, the failure domain of
+// the node, to the metadata. An empty zone leaves the node without one.
+func (data *Data) CreateDataNodeWithZone(addr, tcpAddr, zone string) error {
+	_, err := data.CreateDataNodeWithResult(addr, tcpAddr, zone)
+	return err
+}
+
+// CreateDataNodeWithResult adds a node to the metadata like
+// CreateDataNodeWithZone, and returns the ID assigned to the node. The ID of a
+// meta node with the same TCP address is reused.
+func (data *Data) CreateDataNodeWithResult(addr, tcpAddr, zone string) (uint64, error) {
 	// Ensure a node with the same host doesn't already exist.
-	for _, n := range data.DataNodes {
-		if n.TCPAddr == tcpAddr {
-			return ErrNodeExists
-		}
+	if data.DataNodeByTCPAddr(tcpAddr) != nil {
+		return 0, ErrNodeExists
 	}
 
 	// If an existing meta node exists with the same TCPHost address,
 	// then these nodes are actually the same so re-use the existing ID
 	var existingID uint64
-	for _, n := range data.MetaNodes {
-		if n.TCPAddr == tcpAddr {
-			existingID = n.ID
-			break
-		}
+	if n := data.MetaNodeByTCPAddr(tcpAddr); n != nil {
+		existingID = n.ID
 	}
 
 	// We didn't find an existing node, so assign it a new node ID
@@ -103,10 +201,12 @@ func (data *Data) CreateDataNode(addr, tcpAddr string) error {
 		ID:      existingID,
 		Addr:    addr,
 		TCPAddr: tcpAddr,
+		Zone:    zone,
+		Status:  NodeStatusJoined,
 	})
 	sort.Sort(NodeInfos(data.DataNodes))
 
-	return nil
+	return existingID, nil
 }
 
 // setDataNode adds a data node with a pre-specified nodeID.
@@ -124,65 +224,133 @@ func (data *Data) setDataNode(nodeID uint64, addr, tcpAddr string) error {
 		ID:      nodeID,
 		Addr:    addr,
 		TCPAddr: tcpAddr,
+		Status:  NodeStatusJoined,
 	})
 
 	return nil
 }
 
+// UpdateDataNode changes the addresses of an existing data node. The node keeps
+// its ID and the shards it owns.
+func (data *Data) UpdateDataNode(id uint64, addr, tcpAddr string) error {
+	var node *NodeInfo
+	for i := range data.DataNodes {
+		if data.DataNodes[i].ID == id {
+			node = &data.DataNodes[i]
+		} else if data.DataNodes[i].TCPAddr == tcpAddr {
+			return ErrNodeExists
+		}
+	}
+	if node == nil {
+		return ErrNodeNotFound
+	}
+
+	node.Addr = addr
+	node.TCPAddr = tcpAddr
+	return nil
+}
+
+// SetDataNodeStatus sets the status of a data node to NodeStatusJoined or
+// NodeStatusDisjoined. A disjoined node has been drained but not removed.
+func (data *Data) SetDataNodeStatus(id uint64, status string) error {
+	switch status {
+	case NodeStatusJoined, NodeStatusDisjoined:
+	default:
+		return ErrInvalidNodeStatus
+	}
+
+	for i := range data.DataNodes {
+		if data.DataNodes[i].ID == id {
+			data.DataNodes[i].Status = status
+			return nil
+		}
+	}
+	return ErrNodeNotFound
+}
+
+// PromoteToDataNode registers an existing meta node as a data node with the
+// same ID and zone, so it can start owning shards. Like CreateDataNode, it
+// fails if a data node already has the TCP address.
+func (data *Data) PromoteToDataNode(id uint64, addr, tcpAddr string) error {
+	mn := data.MetaNode(id)
+	if mn == nil {
+		return ErrNodeNotFound
+	} else if data.DataNode(id) != nil {
+		return ErrNodeAlreadyDataNode
+	} else if data.DataNodeByTCPAddr(tcpAddr) != nil {
+		return ErrNodeExists
+	}
+
+	data.DataNodes = append(data.DataNodes, NodeInfo{
+		ID:      mn.ID,
+		Addr:    addr,
+		TCPAddr: tcpAddr,
+		Zone:    mn.Zone,
+		Status:  NodeStatusJoined,
+	})
+	sort.Sort(NodeInfos(data.DataNodes))
+	return nil
+}
+
 // DeleteDataNode removes a node from the Meta store.
 //
 // If necessary, DeleteDataNode reassigns ownership of any shards that
 // would otherwise become orphaned by the removal of the node from the
 // cluster.
 func (data *Data) DeleteDataNode(id uint64) error {
-	var nodes []NodeInfo
+	_, err := data.DeleteDataNodeWithOpts(id, DeleteDataNodeOpts{})
+	return err
+}
 
-	// Remove the data node from the store's list.
-	for _, n := range data.DataNodes {
-		if n.ID != id {
-			nodes = append(nodes, n)
-		}
-	}
+// DeleteDataNodeOpts are the options of DeleteDataNodeWithOpts.
+type DeleteDataNodeOpts struct {
+	// DryRun plans the deletion without changing the metadata.
+	DryRun bool
 
-	if len(nodes) == len(data.DataNodes) {
-		return ErrNodeNotFound
+	// Force marks a shard group deleted when one of its orphaned shards
+	// cannot be given a new owner, instead of failing the deletion.
+	Force bool
+}
+
+// DeleteDataNodePlan describes the changes made by deleting a data node.
+type DeleteDataNodePlan struct {
+	// Reassigned lists the orphaned shards given a new owner, moved from the
+	// deleted node.
+	Reassigned []ShardMovement
+
+	// DeletedShardGroups lists the IDs of the shard groups marked deleted,
+	// either because all of their shards were orphaned or, with Force,
+	// because an orphaned shard could not be reassigned.
+	DeletedShardGroups []uint64
+}
+
+// DeleteDataNodeWithOpts removes a data node like DeleteDataNode and returns
+// the changes it made. The whole plan is computed before the metadata is
+// changed, so data is left unchanged when an error is returned.
+func (data *Data) DeleteDataNodeWithOpts(id uint64, opts DeleteDataNodeOpts) (*DeleteDataNodePlan, error) {
+	if data.DataNode(id) == nil {
+		return nil, ErrNodeNotFound
 	}
-	data.DataNodes = nodes
 
-	// Remove node id from all shard infos
-	for di, d := range data.Databases {
-		for ri, rp := range d.RetentionPolicies {
-			for sgi, sg := range rp.ShardGroups {
-				var (
-					nodeOwnerFreqs = make(map[int]int)
-					orphanedShards []ShardInfo
-				)
+	plan := &DeleteDataNodePlan{}
+	newOwners := make(map[uint64]uint64)   // orphaned shard ID to new owner
+	deletedGroups := make(map[uint64]bool) // shard group IDs to mark deleted
+	for _, d := range data.Databases {
+		for _, rp := range d.RetentionPolicies {
+			for _, sg := range rp.ShardGroups {
+				// Track of how many shards in the group are owned by
+				// each data node in the cluster.
+				nodeOwnerFreqs := shardOwnerFreqs(sg.Shards)
+
 				// Look through all shards in the shard group and
 				// determine (1) if a shard no longer has any owners
-				// (orphaned); (2) if all shards in the shard group
-				// are orphaned; and (3) the number of shards in this
-				// group owned by each data node in the cluster.
-				for si, s := range sg.Shards {
-					// Track of how many shards in the group are
-					// owned by each data node in the cluster.
-					var nodeIdx = -1
-					for i, owner := range s.Owners {
-						if owner.NodeID == id {
-							nodeIdx = i
-						}
-						nodeOwnerFreqs[int(owner.NodeID)]++
-					}
-
-					if nodeIdx > -1 {
-						// Data node owns shard, so relinquish ownership
-						// and set new owners on the shard.
-						s.Owners = append(s.Owners[:nodeIdx], s.Owners[nodeIdx+1:]...)
-						data.Databases[di].RetentionPolicies[ri].ShardGroups[sgi].Shards[si].Owners = s.Owners
-					}
-
-					// Shard no longer owned. Will need reassigning
-					// an owner.
-					if len(s.Owners) == 0 {
+				// (orphaned); and (2) if all shards in the shard group
+				// are orphaned.
+				var orphanedShards []ShardInfo
+				for _, s := range sg.Shards {
+					// Shard no longer owned once the node relinquishes
+					// ownership. Will need reassigning an owner.
+					if n := len(s.Owners); n == 0 || n == 1 && s.OwnedBy(id) {
 						orphanedShards = append(orphanedShards, s)
 					}
 				}
@@ -190,7 +358,8 @@ func (data *Data) DeleteDataNode(id uint64) error {
 				// Mark the shard group as deleted if it has no shards,
 				// or all of its shards are orphaned.
 				if len(sg.Shards) == 0 || len(orphanedShards) == len(sg.Shards) {
-					data.Databases[di].RetentionPolicies[ri].ShardGroups[sgi].DeletedAt = time.Now().UTC()
+					deletedGroups[sg.ID] = true
+					plan.DeletedShardGroups = append(plan.DeletedShardGroups, sg.ID)
 					continue
 				}
 
@@ -198,25 +367,81 @@ func (data *Data) DeleteDataNode(id uint64) error {
 				// dropping from the list of potential new owners.
 				delete(nodeOwnerFreqs, int(id))
 
+				var moves []ShardMovement
+				var err error
 				for _, orphan := range orphanedShards {
-					newOwnerID, err := newShardOwner(orphan, nodeOwnerFreqs)
-					if err != nil {
-						return err
+					var newOwnerID uint64
+					if newOwnerID, err = newShardOwner(orphan, nodeOwnerFreqs); err != nil {
+						break
+					}
+					moves = append(moves, ShardMovement{ShardID: orphan.ID, From: id, To: newOwnerID})
+				}
+				if err != nil {
+					if !opts.Force {
+						return nil, err
 					}
+					deletedGroups[sg.ID] = true
+					plan.DeletedShardGroups = append(plan.DeletedShardGroups, sg.ID)
+					continue
+				}
+				for _, m := range moves {
+					newOwners[m.ShardID] = m.To
+				}
+				plan.Reassigned = append(plan.Reassigned, moves...)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	// Remove the data node from the store's list.
+	var nodes []NodeInfo
+	for _, n := range data.DataNodes {
+		if n.ID != id {
+			nodes = append(nodes, n)
+		}
+	}
+	data.DataNodes = nodes
 
-					for si, s := range sg.Shards {
-						if s.ID == orphan.ID {
-							sg.Shards[si].Owners = append(sg.Shards[si].Owners, ShardOwner{NodeID: newOwnerID})
-							data.Databases[di].RetentionPolicies[ri].ShardGroups[sgi].Shards = sg.Shards
+	// Remove node id from all shard infos, then apply the plan.
+	now := time.Now().UTC()
+	for di := range data.Databases {
+		for ri := range data.Databases[di].RetentionPolicies {
+			rp := &data.Databases[di].RetentionPolicies[ri]
+			for sgi := range rp.ShardGroups {
+				sg := &rp.ShardGroups[sgi]
+				for si := range sg.Shards {
+					s := &sg.Shards[si]
+					for i := len(s.Owners) - 1; i >= 0; i-- {
+						if s.Owners[i].NodeID == id {
+							s.Owners = append(s.Owners[:i], s.Owners[i+1:]...)
 							break
 						}
 					}
-
+					if owner, ok := newOwners[s.ID]; ok {
+						s.Owners = append(s.Owners, ShardOwner{NodeID: owner})
+					}
+				}
+				if deletedGroups[sg.ID] {
+					sg.DeletedAt = now
 				}
 			}
 		}
 	}
-	return nil
+	return plan, nil
+}
+
+// shardOwnerFreqs returns the number of shards in shards owned by each node.
+func shardOwnerFreqs(shards []ShardInfo) map[int]int {
+	nodeOwnerFreqs := make(map[int]int)
+	for _, s := range shards {
+		for _, owner := range s.Owners {
+			nodeOwnerFreqs[int(owner.NodeID)]++
+		}
+	}
+	return nodeOwnerFreqs
 }
 
 // newShardOwner sets the owner of the provided shard to the data node
@@ -245,6 +470,85 @@ func newShardOwner(s ShardInfo, ownerFreqs map[int]int) (uint64, error) {
 	return uint64(minId), nil
 }
 
+// ReassignShardsFromNode replaces deadNodeID as an owner of every shard it owns
+// with the live data node owning the fewest shards that does not already own
+// the shard. Unlike DeleteDataNode, the dead node stays in the metadata. Shards
+// no live node can take keep their dead owner and are reported in the error.
+func (data *Data) ReassignShardsFromNode(deadNodeID uint64) ([]ShardMovement, error) {
+	if data.DataNode(deadNodeID) == nil {
+		return nil, ErrNodeNotFound
+	}
+
+	// Count the shards owned by each live data node.
+	load := make(map[uint64]int, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		if n.ID != deadNodeID {
+			load[n.ID] = 0
+		}
+	}
+	for _, d := range data.Databases {
+		for _, rp := range d.RetentionPolicies {
+			for _, sg := range rp.ShardGroups {
+				for _, s := range sg.Shards {
+					for _, owner := range s.Owners {
+						if _, ok := load[owner.NodeID]; ok {
+							load[owner.NodeID]++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var movements []ShardMovement
+	var stranded []uint64
+	for di := range data.Databases {
+		for ri := range data.Databases[di].RetentionPolicies {
+			rp := &data.Databases[di].RetentionPolicies[ri]
+			for gi := range rp.ShardGroups {
+				sg := &rp.ShardGroups[gi]
+				if sg.Deleted() {
+					continue
+				}
+				for si := range sg.Shards {
+					s := &sg.Shards[si]
+					if !s.OwnedBy(deadNodeID) {
+						continue
+					}
+
+					// Pick the least loaded live node, lowest ID first on ties.
+					var to uint64
+					for id, n := range load {
+						if s.OwnedBy(id) {
+							continue
+						}
+						if to == 0 || n < load[to] || n == load[to] && id < to {
+							to = id
+						}
+					}
+					if to == 0 {
+						stranded = append(stranded, s.ID)
+						continue
+					}
+
+					for i := range s.Owners {
+						if s.Owners[i].NodeID == deadNodeID {
+							s.Owners[i].NodeID = to
+						}
+					}
+					load[to]++
+					movements = append(movements, ShardMovement{ShardID: s.ID, From: deadNodeID, To: to})
+				}
+			}
+		}
+	}
+
+	if len(stranded) > 0 {
+		return movements, fmt.Errorf("no live data node can own shards %v", stranded)
+	}
+	return movements, nil
+}
+
 // MetaNode returns a node by id.
 func (data *Data) MetaNode(id uint64) *NodeInfo {
 	for i := range data.MetaNodes {
@@ -255,8 +559,27 @@ func (data *Data) MetaNode(id uint64) *NodeInfo {
 	return nil
 }
 
+// MetaNodeByTCPAddr returns the meta node with the given TCP address, or nil if
+// there is none. The pointer is into data.MetaNodes, so callers must not retain
+// it across changes to the meta nodes.
+func (data *Data) MetaNodeByTCPAddr(tcpAddr string) *NodeInfo {
+	for i := range data.MetaNodes {
+		if data.MetaNodes[i].TCPAddr == tcpAddr {
+			return &data.MetaNodes[i]
+		}
+	}
+	return nil
+}
+
 // CreateMetaNode will add a new meta node to the metastore
 func (data *Data) CreateMetaNode(httpAddr, tcpAddr string) error {
+	return data.CreateMetaNodeWithZone(httpAddr, tcpAddr, "")
+}
+
+// CreateMetaNodeWithZone adds a meta node in the given zone, the failure
+// domain of the node, to the metastore. An empty zone leaves the node without
+// one.
+func (data *Data) CreateMetaNodeWithZone(httpAddr, tcpAddr, zone string) error {
 	// Ensure a node with the same host doesn't already exist.
 	for _, n := range data.MetaNodes {
 		if n.Addr == httpAddr {
@@ -267,11 +590,8 @@ func (data *Data) CreateMetaNode(httpAddr, tcpAddr string) error {
 	// If an existing data node exists with the same TCPHost address,
 	// then these nodes are actually the same so re-use the existing ID
 	var existingID uint64
-	for _, n := range data.DataNodes {
-		if n.TCPAddr == tcpAddr {
-			existingID = n.ID
-			break
-		}
+	if n := data.DataNodeByTCPAddr(tcpAddr); n != nil {
+		existingID = n.ID
 	}
 
 	// We didn't find and existing data node ID, so assign a new ID
@@ -286,7 +606,43 @@ func (data *Data) CreateMetaNode(httpAddr, tcpAddr string) error {
 		ID:      existingID,
 		Addr:    httpAddr,
 		TCPAddr: tcpAddr,
+		Zone:    zone,
+	})
+
+	sort.Sort(NodeInfos(data.MetaNodes))
+	return nil
+}
+
+// CreateMetaNodeWithID adds a meta node with the given ID, such as when
+// restoring a meta cluster's topology. MaxNodeID is advanced past id if needed.
+func (data *Data) CreateMetaNodeWithID(id uint64, httpAddr, tcpAddr string) error {
+	if id == 0 {
+		return ErrNodeIDRequired
+	}
+
+	// Ensure a node with the same ID or address doesn't already exist.
+	for _, n := range data.MetaNodes {
+		if n.ID == id || n.Addr == httpAddr || n.TCPAddr == tcpAddr {
+			return ErrNodeExists
+		}
+	}
+
+	// A meta node only shares its ID with the data node on the same host,
+	// as in CreateMetaNode.
+	for _, n := range data.DataNodes {
+		if (n.ID == id) != (n.TCPAddr == tcpAddr) {
+			return ErrNodeExists
+		}
+	}
+
+	data.MetaNodes = append(data.MetaNodes, NodeInfo{
+		ID:      id,
+		Addr:    httpAddr,
+		TCPAddr: tcpAddr,
 	})
+	if id > data.MaxNodeID {
+		data.MaxNodeID = id
+	}
 
 	sort.Sort(NodeInfos(data.MetaNodes))
 	return nil
@@ -355,6 +711,81 @@ func (data *Data) CloneDatabases() []DatabaseInfo {
 	return dbs
 }
 
+// DatabaseNames returns the sorted names of all databases.
+func (data *Data) DatabaseNames() []string {
+	return data.databaseNames(false)
+}
+
+// DatabaseNamesExcludingInternal returns the sorted names of all databases
+// except internal ones, such as _internal, whose names start with an
+// underscore.
+func (data *Data) DatabaseNamesExcludingInternal() []string {
+	return data.databaseNames(true)
+}
+
+// databaseNames returns the sorted names of the databases, skipping internal
+// ones if skipInternal is set.
+func (data *Data) databaseNames(skipInternal bool) []string {
+	names := make([]string, 0, len(data.Databases))
+	for _, di := range data.Databases {
+		if skipInternal && strings.HasPrefix(di.Name, "_") {
+			continue
+		}
+		names = append(names, di.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RetentionPolicyPair identifies a retention policy by database and carries
+// its durations.
+type RetentionPolicyPair struct {
+	Database           string
+	Policy             string
+	Duration           time.Duration
+	ShardGroupDuration time.Duration
+}
+
+// DatabaseRetentionPolicyPairs returns every retention policy of every database,
+// in metadata order.
+func (data *Data) DatabaseRetentionPolicyPairs() []RetentionPolicyPair {
+	var pairs []RetentionPolicyPair
+	for _, d := range data.Databases {
+		for _, rp := range d.RetentionPolicies {
+			pairs = append(pairs, RetentionPolicyPair{
+				Database:           d.Name,
+				Policy:             rp.Name,
+				Duration:           rp.Duration,
+				ShardGroupDuration: rp.ShardGroupDuration,
+			})
+		}
+	}
+	return pairs
+}
+
+// RetentionPoliciesWithAlignedShardGroups returns the names of a database's
+// retention policies whose shard group duration is a multiple or a divisor of
+// base, so their shard group boundaries line up with base.
+func (data *Data) RetentionPoliciesWithAlignedShardGroups(database string, base time.Duration) ([]string, error) {
+	if base <= 0 {
+		return nil, ErrAlignmentBaseTooLow
+	}
+
+	di := data.Database(database)
+	if di == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	names := []string{}
+	for _, rp := range di.RetentionPolicies {
+		sgd := normalisedShardDuration(rp.ShardGroupDuration, rp.Duration)
+		if sgd%base == 0 || base%sgd == 0 {
+			names = append(names, rp.Name)
+		}
+	}
+	return names, nil
+}
+
 // CreateDatabase creates a new database.
 // It returns an error if name is blank or if a database with the same name already exists.
 func (data *Data) CreateDatabase(name string) error {
@@ -372,6 +803,40 @@ func (data *Data) CreateDatabase(name string) error {
 	return nil
 }
 
+// CreateDatabaseWithRetentionPolicy creates a database with rpi as its default
+// retention policy in a single change: if the policy can't be created, the
+// database isn't either. ErrRetentionPolicyConflict is returned if the database
+// already exists with a different default policy, or with a policy of the same
+// name but different settings.
+func (data *Data) CreateDatabaseWithRetentionPolicy(name string, rpi *RetentionPolicyInfo) error {
+	if rpi == nil {
+		return ErrRetentionPolicyRequired
+	}
+
+	created := false
+	if di := data.Database(name); di != nil {
+		if di.DefaultRetentionPolicy != rpi.Name && (di.DefaultRetentionPolicy != "" || len(di.RetentionPolicies) > 0) {
+			return ErrRetentionPolicyConflict
+		}
+	} else {
+		if err := data.CreateDatabase(name); err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if err := data.CreateRetentionPolicy(name, rpi, true); err != nil {
+		if created {
+			data.Databases = data.Databases[:len(data.Databases)-1]
+		}
+		if err == ErrRetentionPolicyExists {
+			return ErrRetentionPolicyConflict
+		}
+		return err
+	}
+	return nil
+}
+
 // DropDatabase removes a database by name. It does not return an error
 // if the database cannot be found.
 func (data *Data) DropDatabase(name string) error {
@@ -379,43 +844,152 @@ func (data *Data) DropDatabase(name string) error {
 		if data.Databases[i].Name == name {
 			data.Databases = append(data.Databases[:i], data.Databases[i+1:]...)
 
-			// Remove all user privileges associated with this database.
+			// Remove all user and role privileges associated with this database.
 			for i := range data.Users {
 				delete(data.Users[i].Privileges, name)
 			}
+			for i := range data.Roles {
+				delete(data.Roles[i].Privileges, name)
+			}
+			data.refreshRolePrivileges()
 			break
 		}
 	}
 	return nil
 }
 
-// RetentionPolicy returns a retention policy for a database by name.
-func (data *Data) RetentionPolicy(database, name string) (*RetentionPolicyInfo, error) {
-	di := data.Database(database)
+// RenameDatabase renames a database, keeping its retention policies and default
+// retention policy, and moves the user and role privileges on the database to
+// the new name. The store keeps shard data under the database name, so a
+// database with shard groups cannot be renamed. Continuous queries are left
+// untouched, so queries that name the old database in their text must be
+// recreated.
+func (data *Data) RenameDatabase(oldName, newName string) error {
+	if newName == "" {
+		return ErrDatabaseNameRequired
+	} else if len(newName) > MaxNameLen {
+		return ErrNameTooLong
+	} else if !ValidName(newName) {
+		return ErrInvalidName
+	}
+
+	di := data.Database(oldName)
 	if di == nil {
-		return nil, influxdb.ErrDatabaseNotFound(database)
+		return influxdb.ErrDatabaseNotFound(oldName)
+	} else if data.Database(newName) != nil {
+		return ErrDatabaseExists
+	}
+	for _, rpi := range di.RetentionPolicies {
+		if len(rpi.ShardGroups) > 0 {
+			return ErrDatabaseHasShards
+		}
 	}
+	di.Name = newName
 
-	for i := range di.RetentionPolicies {
-		if di.RetentionPolicies[i].Name == name {
-			return &di.RetentionPolicies[i], nil
+	for i := range data.Users {
+		if p, ok := data.Users[i].Privileges[oldName]; ok {
+			data.Users[i].Privileges[newName] = p
+			delete(data.Users[i].Privileges, oldName)
 		}
 	}
-	return nil, nil
+	for i := range data.Roles {
+		if p, ok := data.Roles[i].Privileges[oldName]; ok {
+			data.Roles[i].Privileges[newName] = p
+			delete(data.Roles[i].Privileges, oldName)
+		}
+	}
+	data.refreshRolePrivileges()
+
+	return nil
 }
 
-// CreateRetentionPolicy creates a new retention policy on a database.
-// It returns an error if name is blank or if the database does not exist.
-func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInfo, makeDefault bool) error {
-	// Validate retention policy.
-	if rpi == nil {
-		return ErrRetentionPolicyRequired
-	} else if rpi.Name == "" {
-		return ErrRetentionPolicyNameRequired
-	} else if len(rpi.Name) > MaxNameLen {
-		return ErrNameTooLong
-	} else if rpi.ReplicaN < 1 {
-		return ErrReplicationFactorTooLow
+// SetDatabaseLimits sets the maximum number of series in a database and of
+// values per tag key. A zero limit is unlimited.
+func (data *Data) SetDatabaseLimits(name string, maxSeries, maxValues int) error {
+	if maxSeries < 0 || maxValues < 0 {
+		return ErrDatabaseLimitNegative
+	}
+
+	di := data.Database(name)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(name)
+	}
+
+	di.MaxSeriesPerDatabase = maxSeries
+	di.MaxValuesPerTag = maxValues
+	return nil
+}
+
+// SetDefaultShardGroupDuration sets the shard group duration of retention
+// policies later created on a database without one. Zero clears the default.
+func (data *Data) SetDefaultShardGroupDuration(name string, d time.Duration) error {
+	if d < 0 {
+		return ErrShardGroupDurationNegative
+	}
+
+	di := data.Database(name)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(name)
+	}
+
+	di.DefaultShardGroupDuration = d
+	return nil
+}
+
+// RetentionPolicy returns a retention policy for a database by name.
+func (data *Data) RetentionPolicy(database, name string) (*RetentionPolicyInfo, error) {
+	di := data.Database(database)
+	if di == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.RetentionPolicies {
+		if di.RetentionPolicies[i].Name == name {
+			return &di.RetentionPolicies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateRetentionPolicy creates a new retention policy on a database.
+// It returns an error if name is blank or if the database does not exist.
+// A policy without a shard group duration uses the database's
+// DefaultShardGroupDuration, when set.
+func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInfo, makeDefault bool) error {
+	return data.CreateRetentionPolicyWithOpts(database, rpi, makeDefault, CreateRetentionPolicyOpts{})
+}
+
+// CreateRetentionPolicyOpts are the options of CreateRetentionPolicyWithOpts.
+type CreateRetentionPolicyOpts struct {
+	// StrictShardGroupDuration rejects a finite duration that isn't a multiple
+	// of the shard group duration with a *ShardGroupDurationError, since the
+	// last shard group would then outlive the duration.
+	StrictShardGroupDuration bool
+
+	// CheckReplicaN rejects a new policy whose ReplicaN exceeds the number of
+	// data nodes with ErrReplicationFactorExceedsNodes. A cluster without data
+	// nodes isn't checked.
+	CheckReplicaN bool
+}
+
+// CreateRetentionPolicyWithOpts creates a new retention policy on a database
+// like CreateRetentionPolicy.
+func (data *Data) CreateRetentionPolicyWithOpts(database string, rpi *RetentionPolicyInfo, makeDefault bool, opts CreateRetentionPolicyOpts) error {
+	// Validate retention policy.
+	if rpi == nil {
+		return ErrRetentionPolicyRequired
+	} else if rpi.Name == "" {
+		return ErrRetentionPolicyNameRequired
+	} else if len(rpi.Name) > MaxNameLen {
+		return ErrNameTooLong
+	} else if rpi.ReplicaN < 1 {
+		return ErrReplicationFactorTooLow
+	}
+
+	if rpi.ShardGroupDuration == 0 {
+		if di := data.Database(database); di != nil {
+			rpi.ShardGroupDuration = di.shardGroupDuration(rpi.Duration)
+		}
 	}
 
 	// Normalise ShardDuration before comparing to any existing
@@ -427,6 +1001,14 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 		return ErrIncompatibleDurations
 	}
 
+	if opts.StrictShardGroupDuration && rpi.Duration > 0 && rpi.Duration%rpi.ShardGroupDuration != 0 {
+		return &ShardGroupDurationError{
+			Duration:           rpi.Duration,
+			ShardGroupDuration: rpi.ShardGroupDuration,
+			Suggested:          ShardGroupDurationDivisor(rpi.Duration, rpi.ShardGroupDuration),
+		}
+	}
+
 	// Find database.
 	di := data.Database(database)
 	if di == nil {
@@ -443,6 +1025,10 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 		return nil
 	}
 
+	if n := len(data.DataNodes); opts.CheckReplicaN && n > 0 && rpi.ReplicaN > n {
+		return ErrReplicationFactorExceedsNodes
+	}
+
 	// Append copy of new policy.
 	di.RetentionPolicies = append(di.RetentionPolicies, *rpi)
 
@@ -480,6 +1066,26 @@ type RetentionPolicyUpdate struct {
 	Duration           *time.Duration
 	ReplicaN           *int
 	ShardGroupDuration *time.Duration
+	ReadOnly           *bool
+
+	// AllowExcessReplicaN permits raising ReplicaN above the number of data
+	// nodes. New shard groups are still capped to the number of data nodes.
+	AllowExcessReplicaN bool
+
+	// StrictExpiry rejects a change to Duration that would expire existing
+	// shard groups with ErrRetentionPolicyExpiresShardGroups, unless
+	// ForceExpiry is also set. Shard groups are checked at the time passed
+	// to UpdateRetentionPolicyWithResult.
+	StrictExpiry bool
+	ForceExpiry  bool
+}
+
+// RetentionPolicyUpdateResult describes the effect of a retention policy
+// update.
+type RetentionPolicyUpdateResult struct {
+	// ExpiredShardGroups is the number of shard groups that were not expired
+	// before the update and are expired under the new duration.
+	ExpiredShardGroups int
 }
 
 // SetName sets the RetentionPolicyUpdate.Name.
@@ -494,28 +1100,100 @@ func (rpu *RetentionPolicyUpdate) SetReplicaN(v int) { rpu.ReplicaN = &v }
 // SetShardGroupDuration sets the RetentionPolicyUpdate.ShardGroupDuration.
 func (rpu *RetentionPolicyUpdate) SetShardGroupDuration(v time.Duration) { rpu.ShardGroupDuration = &v }
 
-// UpdateRetentionPolicy updates an existing retention policy.
+// SetReadOnly sets the RetentionPolicyUpdate.ReadOnly.
+func (rpu *RetentionPolicyUpdate) SetReadOnly(v bool) { rpu.ReadOnly = &v }
+
+// RetentionPolicyReplicaNUpdateSafety checks a change to the replication factor
+// of a retention policy. Raising ReplicaN above the number of data nodes returns
+// ErrReplicationFactorExceedsNodes unless rpu.AllowExcessReplicaN is set; a
+// cluster without data nodes isn't checked. Any notes the caller should pass
+// on to the user are returned on success.
+func (data *Data) RetentionPolicyReplicaNUpdateSafety(database, name string, rpu *RetentionPolicyUpdate) ([]string, error) {
+	rpi, err := data.RetentionPolicy(database, name)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(name)
+	}
+
+	if rpu.ReplicaN == nil || *rpu.ReplicaN <= rpi.ReplicaN {
+		return nil, nil
+	}
+
+	var notes []string
+	if n := len(data.DataNodes); n > 0 && *rpu.ReplicaN > n {
+		if !rpu.AllowExcessReplicaN {
+			return nil, ErrReplicationFactorExceedsNodes
+		}
+		notes = append(notes, fmt.Sprintf("replication factor %d exceeds the %d data node(s); new shard groups will be replicated %d time(s)",
+			*rpu.ReplicaN, n, n))
+	}
+	notes = append(notes, "raising the replication factor does not re-replicate existing shards")
+	return notes, nil
+}
+
+// RetentionPolicyUpdateExpiredShardGroups returns the number of shard groups of
+// a retention policy that are not expired at now, but would be under the
+// duration set by rpu.
+func (data *Data) RetentionPolicyUpdateExpiredShardGroups(database, name string, rpu *RetentionPolicyUpdate, now time.Time) (int, error) {
+	rpi, err := data.RetentionPolicy(database, name)
+	if err != nil {
+		return 0, err
+	} else if rpi == nil {
+		return 0, influxdb.ErrRetentionPolicyNotFound(name)
+	}
+
+	if rpu.Duration == nil || *rpu.Duration == 0 {
+		return 0, nil
+	}
+
+	var n int
+	for i := range rpi.ShardGroups {
+		sgi := &rpi.ShardGroups[i]
+		if sgi.Deleted() {
+			continue
+		}
+		if before := sgi.ExpireTime(rpi.Duration); !before.IsZero() && before.Before(now) {
+			continue
+		}
+		if sgi.ExpireTime(*rpu.Duration).Before(now) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// UpdateRetentionPolicy updates an existing retention policy. No shard groups
+// are counted as expired by the update.
 func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
+	_, err := data.UpdateRetentionPolicyWithResult(database, name, rpu, makeDefault, time.Time{})
+	return err
+}
+
+// UpdateRetentionPolicyWithResult updates an existing retention policy like
+// UpdateRetentionPolicy, and returns the number of shard groups the new
+// duration expires at now.
+func (data *Data) UpdateRetentionPolicyWithResult(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool, now time.Time) (*RetentionPolicyUpdateResult, error) {
 	// Find database.
 	di := data.Database(database)
 	if di == nil {
-		return influxdb.ErrDatabaseNotFound(database)
+		return nil, influxdb.ErrDatabaseNotFound(database)
 	}
 
 	// Find policy.
 	rpi := di.RetentionPolicy(name)
 	if rpi == nil {
-		return influxdb.ErrRetentionPolicyNotFound(name)
+		return nil, influxdb.ErrRetentionPolicyNotFound(name)
 	}
 
 	// Ensure new policy doesn't match an existing policy.
 	if rpu.Name != nil && *rpu.Name != name && di.RetentionPolicy(*rpu.Name) != nil {
-		return ErrRetentionPolicyNameExists
+		return nil, ErrRetentionPolicyNameExists
 	}
 
 	// Enforce duration of at least MinRetentionPolicyDuration
 	if rpu.Duration != nil && *rpu.Duration < MinRetentionPolicyDuration && *rpu.Duration != 0 {
-		return ErrRetentionPolicyDurationTooLow
+		return nil, ErrRetentionPolicyDurationTooLow
 	}
 
 	// Enforce duration is at least the shard duration
@@ -524,7 +1202,22 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 			(rpu.ShardGroupDuration == nil && *rpu.Duration < rpi.ShardGroupDuration))) ||
 		(rpu.Duration == nil && rpi.Duration > 0 &&
 			rpu.ShardGroupDuration != nil && rpi.Duration < *rpu.ShardGroupDuration) {
-		return ErrIncompatibleDurations
+		return nil, ErrIncompatibleDurations
+	}
+
+	// Refuse to raise the replication factor above the data node count
+	// unless explicitly allowed.
+	if _, err := data.RetentionPolicyReplicaNUpdateSafety(database, name, rpu); err != nil {
+		return nil, err
+	}
+
+	// Count the shard groups a shorter duration expires, refusing to expire
+	// any in strict mode unless forced.
+	expired, err := data.RetentionPolicyUpdateExpiredShardGroups(database, name, rpu, now)
+	if err != nil {
+		return nil, err
+	} else if expired > 0 && rpu.StrictExpiry && !rpu.ForceExpiry {
+		return nil, ErrRetentionPolicyExpiresShardGroups
 	}
 
 	// Update fields.
@@ -540,11 +1233,104 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	if rpu.ShardGroupDuration != nil {
 		rpi.ShardGroupDuration = normalisedShardDuration(*rpu.ShardGroupDuration, rpi.Duration)
 	}
+	if rpu.ReadOnly != nil {
+		rpi.ReadOnly = *rpu.ReadOnly
+	}
 
 	if di.DefaultRetentionPolicy != rpi.Name && makeDefault {
 		di.DefaultRetentionPolicy = rpi.Name
 	}
 
+	return &RetentionPolicyUpdateResult{ExpiredShardGroups: expired}, nil
+}
+
+// RenameRetentionPolicy renames a retention policy, keeping its subscriptions.
+// The database's default retention policy follows the rename. The store keeps
+// shard data under the policy name, so a policy with shard groups cannot be
+// renamed. Continuous queries are not updated; use
+// ContinuousQueriesReferencingRetentionPolicy to find the ones that name the
+// old policy and RenameRetentionPolicyInContinuousQueries to rewrite them.
+func (data *Data) RenameRetentionPolicy(database, oldName, newName string) error {
+	if newName == "" {
+		return ErrRetentionPolicyNameRequired
+	} else if len(newName) > MaxNameLen {
+		return ErrNameTooLong
+	} else if !ValidName(newName) {
+		return ErrInvalidName
+	}
+
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	rpi := di.RetentionPolicy(oldName)
+	if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(oldName)
+	} else if newName == oldName {
+		return nil
+	} else if di.RetentionPolicy(newName) != nil {
+		return ErrRetentionPolicyNameExists
+	} else if len(rpi.ShardGroups) > 0 {
+		return ErrRetentionPolicyHasShards
+	}
+
+	rpi.Name = newName
+	if di.DefaultRetentionPolicy == oldName {
+		di.DefaultRetentionPolicy = newName
+	}
+	return nil
+}
+
+// SetWriteAffinityTag sets the tag whose value selects the shard points of a
+// retention policy are written to. An empty tag restores the default of
+// selecting by series.
+func (data *Data) SetWriteAffinityTag(database, policy, tag string) error {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	rpi.WriteAffinityTag = tag
+	return nil
+}
+
+// SetPendingShardCount sets the number of shards in shard groups created for
+// a retention policy from now on. Existing shard groups keep their shards.
+func (data *Data) SetPendingShardCount(database, policy string, n int) error {
+	if n < 1 {
+		return ErrShardCountTooLow
+	}
+
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	rpi.PendingShardCount = n
+	return nil
+}
+
+// SetPreCreateCount sets the number of shard groups after the current one of
+// a retention policy that PreCreateShardGroups creates ahead of time. Zero
+// disables it.
+func (data *Data) SetPreCreateCount(database, policy string, n int) error {
+	if n < 0 {
+		return ErrPreCreateCountTooLow
+	}
+
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	rpi.PreCreateCount = n
 	return nil
 }
 
@@ -580,6 +1366,186 @@ func (data *Data) DropShard(id uint64) {
 	}
 }
 
+// NodeShard is a shard along with the database, retention policy and shard
+// group it belongs to.
+type NodeShard struct {
+	Database        string
+	RetentionPolicy string
+	ShardGroupID    uint64
+	ShardInfo
+}
+
+// ShardsByNodeID returns the shards owned by a node in shard groups that are
+// not deleted, sorted by shard ID.
+func (data *Data) ShardsByNodeID(nodeID uint64) []NodeShard {
+	var shards []NodeShard
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if si.OwnedBy(nodeID) {
+						shards = append(shards, NodeShard{
+							Database:        dbi.Name,
+							RetentionPolicy: rpi.Name,
+							ShardGroupID:    sgi.ID,
+							ShardInfo:       si.clone(),
+						})
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].ID < shards[j].ID })
+	return shards
+}
+
+// OrphanedShards returns the shards without any owners in shard groups that
+// are not deleted, sorted by shard ID. Such shards are left behind when
+// ownership is removed without marking the shard group deleted.
+func (data *Data) OrphanedShards() []NodeShard {
+	var shards []NodeShard
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if len(si.Owners) == 0 {
+						shards = append(shards, NodeShard{
+							Database:        dbi.Name,
+							RetentionPolicy: rpi.Name,
+							ShardGroupID:    sgi.ID,
+							ShardInfo:       si.clone(),
+						})
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].ID < shards[j].ID })
+	return shards
+}
+
+// ShardOwnerCounts returns the number of shard replicas each data node owns in
+// shard groups that are not deleted, keyed by node ID. Data nodes without any
+// replicas are included with a count of zero; owners that are not data nodes
+// are ignored.
+func (data *Data) ShardOwnerCounts() map[uint64]int {
+	counts := make(map[uint64]int, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		counts[n.ID] = 0
+	}
+
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					for _, owner := range si.Owners {
+						if _, ok := counts[owner.NodeID]; ok {
+							counts[owner.NodeID]++
+						}
+					}
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// TouchShard records t as the time of the latest write to a shard. Earlier
+// times than the one already recorded and unknown shards are ignored.
+func (data *Data) TouchShard(id uint64, t time.Time) {
+	data.TouchShards([]uint64{id}, t)
+}
+
+// TouchShards records t as the time of the latest write to each of the shards
+// ids, like TouchShard.
+func (data *Data) TouchShards(ids []uint64, t time.Time) {
+	touched := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		touched[id] = true
+	}
+
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				sg := &rpi.ShardGroups[sgidx]
+				for sidx := range sg.Shards {
+					if s := &sg.Shards[sidx]; touched[s.ID] && t.After(s.LastWrite) {
+						s.LastWrite = t.UTC()
+					}
+				}
+			}
+		}
+	}
+}
+
+// SetShardOwnerState sets the state of nodeID's replica of a shard to one of
+// ShardStateHot, ShardStateCopying or ShardStateStale.
+func (data *Data) SetShardOwnerState(shardID, nodeID uint64, state string) error {
+	switch state {
+	case ShardStateHot, ShardStateCopying, ShardStateStale:
+	default:
+		return ErrInvalidShardState
+	}
+
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				sg := &rpi.ShardGroups[sgidx]
+				for sidx := range sg.Shards {
+					s := &sg.Shards[sidx]
+					if s.ID != shardID {
+						continue
+					}
+					for i := range s.Owners {
+						if s.Owners[i].NodeID == nodeID {
+							s.Owners[i].State = state
+							return nil
+						}
+					}
+					return ErrShardOwnerNotFound
+				}
+			}
+		}
+	}
+	return ErrShardNotFound
+}
+
+// IdleShards returns the sorted IDs of the shards in shard groups that are not
+// deleted whose latest recorded write is before the given time. Shards without
+// a recorded write are idle.
+func (data *Data) IdleShards(before time.Time) []uint64 {
+	var ids []uint64
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if si.LastWrite.Before(before) {
+						ids = append(ids, si.ID)
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 // CopyShardOwner copies a shard owner by ID and NodeID.
 func (data *Data) CopyShardOwner(id, nodeID uint64) {
 	found := -1
@@ -669,29 +1635,364 @@ func (data *Data) RemoveShardOwner(id, nodeID uint64) {
 	}
 }
 
-// ShardGroups returns a list of all shard groups on a database and retention policy.
-func (data *Data) ShardGroups(database, policy string) ([]ShardGroupInfo, error) {
-	// Find retention policy.
-	rpi, err := data.RetentionPolicy(database, policy)
-	if err != nil {
-		return nil, err
-	} else if rpi == nil {
-		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+// MoveShard reassigns fromNodeID's replica of a shard to toNodeID in a single
+// mutation so the shard is never over- or under-replicated in between. The new
+// owner takes the old one's place in the owners and starts out as
+// ShardStateCopying until its data has been copied.
+func (data *Data) MoveShard(shardID, fromNodeID, toNodeID uint64) error {
+	if data.DataNode(toNodeID) == nil {
+		return ErrNodeNotFound
 	}
-	groups := make([]ShardGroupInfo, 0, len(rpi.ShardGroups))
-	for _, g := range rpi.ShardGroups {
-		if g.Deleted() {
-			continue
+
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				sg := &rpi.ShardGroups[sgidx]
+				for sidx := range sg.Shards {
+					s := &sg.Shards[sidx]
+					if s.ID != shardID {
+						continue
+					}
+
+					fromIdx := -1
+					for i, owner := range s.Owners {
+						switch owner.NodeID {
+						case toNodeID:
+							return ErrShardOwnerExists
+						case fromNodeID:
+							fromIdx = i
+						}
+					}
+					if fromIdx == -1 {
+						return ErrShardOwnerNotFound
+					}
+
+					s.Owners[fromIdx] = ShardOwner{NodeID: toNodeID, State: ShardStateCopying}
+					return nil
+				}
+			}
 		}
-		groups = append(groups, g)
 	}
-	return groups, nil
+	return ErrShardNotFound
+}
+
+// DedupeShardOwners removes duplicate owner entries from every shard,
+// keeping the first occurrence of each node, and leaves the remaining
+// owners sorted by node ID. It returns the number of duplicates removed.
+func (data *Data) DedupeShardOwners() int {
+	var n int
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				sg := &rpi.ShardGroups[sgidx]
+				for sidx := range sg.Shards {
+					s := &sg.Shards[sidx]
+					if len(s.Owners) < 2 {
+						continue
+					}
+
+					seen := make(map[uint64]struct{}, len(s.Owners))
+					owners := s.Owners[:0]
+					for _, owner := range s.Owners {
+						if _, ok := seen[owner.NodeID]; ok {
+							n++
+							continue
+						}
+						seen[owner.NodeID] = struct{}{}
+						owners = append(owners, owner)
+					}
+					sort.Slice(owners, func(i, j int) bool { return owners[i].NodeID < owners[j].NodeID })
+					s.Owners = owners
+				}
+			}
+		}
+	}
+	return n
 }
 
-// ShardGroupsByTimeRange returns a list of all shard groups on a database and policy that may contain data
-// for the specified time range. Shard groups are sorted by start time.
-func (data *Data) ShardGroupsByTimeRange(database, policy string, tmin, tmax time.Time) ([]ShardGroupInfo, error) {
-	// Find retention policy.
+// ShardMovement describes moving a shard's replica from one data node to another.
+type ShardMovement struct {
+	ShardID uint64
+	From    uint64
+	To      uint64
+}
+
+// RebalanceRetentionPolicyShards plans the shard movements that even out the
+// number of replicas each data node holds in a retention policy. A replica is
+// never moved to a node that already owns the shard. Deleted shard groups are
+// ignored and the metadata is left unchanged; a movement is applied with
+// CopyShardOwner followed by RemoveShardOwner.
+func (data *Data) RebalanceRetentionPolicyShards(database, policy string) ([]ShardMovement, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	var shards []ShardInfo
+	for _, sgi := range rpi.ShardGroups {
+		if sgi.Deleted() {
+			continue
+		}
+		shards = append(shards, sgi.Shards...)
+	}
+	return data.planShardMovements(shards), nil
+}
+
+// Shard rebalancing strategies accepted by RebalanceShards.
+const (
+	// RebalanceStrategyCluster evens out the replicas each data node holds
+	// across the whole cluster.
+	RebalanceStrategyCluster = "cluster"
+
+	// RebalanceStrategyShardGroup evens out the replicas each data node holds
+	// within every shard group, as DeleteDataNode does when reassigning shards.
+	RebalanceStrategyShardGroup = "shard-group"
+)
+
+// RebalanceShards plans the shard movements that even out the replicas held by
+// each data node, for example after a node joins the cluster. An empty strategy
+// means RebalanceStrategyCluster. Movements never change a shard's replica
+// count. Unless dryRun is set, each movement is applied with CopyShardOwner
+// followed by RemoveShardOwner, so no shard drops below its replica count.
+func (data *Data) RebalanceShards(strategy string, dryRun bool) ([]ShardMovement, error) {
+	var movements []ShardMovement
+	switch strategy {
+	case "", RebalanceStrategyCluster:
+		var shards []ShardInfo
+		for _, dbi := range data.Databases {
+			for _, rpi := range dbi.RetentionPolicies {
+				for _, sgi := range rpi.ShardGroups {
+					if !sgi.Deleted() {
+						shards = append(shards, sgi.Shards...)
+					}
+				}
+			}
+		}
+		movements = data.planShardMovements(shards)
+	case RebalanceStrategyShardGroup:
+		for _, dbi := range data.Databases {
+			for _, rpi := range dbi.RetentionPolicies {
+				for _, sgi := range rpi.ShardGroups {
+					if !sgi.Deleted() {
+						movements = append(movements, data.planShardMovements(sgi.Shards)...)
+					}
+				}
+			}
+		}
+	default:
+		return nil, ErrInvalidRebalanceStrategy
+	}
+
+	if !dryRun {
+		for _, m := range movements {
+			data.CopyShardOwner(m.ShardID, m.To)
+			data.RemoveShardOwner(m.ShardID, m.From)
+		}
+	}
+	return movements, nil
+}
+
+// shardReplicas tracks the owners of a shard while movements are planned.
+type shardReplicas struct {
+	id     uint64
+	owners map[uint64]bool
+}
+
+// planShardMovements plans the movements that even out the replicas of shards
+// held by each data node, starting from the owner frequencies DeleteDataNode
+// uses to pick new owners. A replica is never moved to a node that already
+// owns the shard.
+func (data *Data) planShardMovements(shardInfos []ShardInfo) []ShardMovement {
+	nodeOwnerFreqs := shardOwnerFreqs(shardInfos)
+	load := make(map[uint64]int, len(data.DataNodes))
+	nodes := make([]uint64, 0, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		load[n.ID] = nodeOwnerFreqs[int(n.ID)]
+		nodes = append(nodes, n.ID)
+	}
+
+	shards := make([]shardReplicas, 0, len(shardInfos))
+	for _, si := range shardInfos {
+		s := shardReplicas{id: si.ID, owners: make(map[uint64]bool, len(si.Owners))}
+		for _, owner := range si.Owners {
+			s.owners[owner.NodeID] = true
+		}
+		shards = append(shards, s)
+	}
+
+	// Repeatedly move a replica from the busiest node that can give one up to
+	// the least busy node that can take it, until no node holds two or more
+	// replicas than another that could take one.
+	var movements []ShardMovement
+next:
+	for {
+		sort.Slice(nodes, func(i, j int) bool {
+			if load[nodes[i]] != load[nodes[j]] {
+				return load[nodes[i]] < load[nodes[j]]
+			}
+			return nodes[i] < nodes[j]
+		})
+		for hi := len(nodes) - 1; hi > 0; hi-- {
+			from := nodes[hi]
+			for lo := 0; lo < hi && load[from]-load[nodes[lo]] > 1; lo++ {
+				to := nodes[lo]
+				for _, s := range shards {
+					if !s.owners[from] || s.owners[to] {
+						continue
+					}
+					delete(s.owners, from)
+					s.owners[to] = true
+					load[from]--
+					load[to]++
+					movements = append(movements, ShardMovement{ShardID: s.id, From: from, To: to})
+					continue next
+				}
+			}
+		}
+		return movements
+	}
+}
+
+// ShardGroups returns a list of all shard groups on a database and retention policy.
+func (data *Data) ShardGroups(database, policy string) ([]ShardGroupInfo, error) {
+	// Find retention policy.
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+	groups := make([]ShardGroupInfo, 0, len(rpi.ShardGroups))
+	for _, g := range rpi.ShardGroups {
+		if g.Deleted() {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// ShardGroupInfosPaginated returns up to limit non-deleted shard groups of a
+// retention policy, starting at offset in start time order, along with the
+// total number of non-deleted groups. An offset past the end returns no groups.
+func (data *Data) ShardGroupInfosPaginated(database, policy string, offset, limit int) ([]ShardGroupInfo, int, error) {
+	if offset < 0 {
+		return nil, 0, ErrInvalidOffset
+	} else if limit < 1 {
+		return nil, 0, ErrInvalidLimit
+	}
+
+	groups, err := data.ShardGroups(database, policy)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Sort(ShardGroupInfos(groups))
+
+	total := len(groups)
+	if offset >= total {
+		return []ShardGroupInfo{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return groups[offset:end], total, nil
+}
+
+// ShardGroupMembership returns, for each non-deleted shard group of a retention
+// policy, the number of the group's shards owned by each data node, keyed by
+// shard group ID and then node ID.
+func (data *Data) ShardGroupMembership(database, policy string) (map[uint64]map[uint64]int, error) {
+	groups, err := data.ShardGroups(database, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := make(map[uint64]map[uint64]int, len(groups))
+	for _, sg := range groups {
+		owned := make(map[uint64]int)
+		for _, s := range sg.Shards {
+			for _, owner := range s.Owners {
+				owned[owner.NodeID]++
+			}
+		}
+		membership[sg.ID] = owned
+	}
+	return membership, nil
+}
+
+// ShardHealth describes the replicas of a shard. DesiredN is the shard group's
+// replication factor, ActualN the number of owners and ResolvableN
+// the number of owners that are current data nodes.
+type ShardHealth struct {
+	ShardID     uint64
+	DesiredN    int
+	ActualN     int
+	ResolvableN int
+}
+
+// ReplicaHealth tallies the shards of a retention policy by replica health.
+// A shard is orphaned when none of its owners are data nodes, under-replicated
+// when fewer than DesiredN are, and healthy otherwise.
+type ReplicaHealth struct {
+	Orphaned        int
+	UnderReplicated int
+	Healthy         int
+	Details         []ShardHealth
+}
+
+// ShardGroupReplicaHealth returns the replica health of the shards in every
+// non-deleted shard group of a retention policy.
+func (data *Data) ShardGroupReplicaHealth(database, policy string) (ReplicaHealth, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return ReplicaHealth{}, err
+	} else if rpi == nil {
+		return ReplicaHealth{}, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	nodes := make(map[uint64]struct{}, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		nodes[n.ID] = struct{}{}
+	}
+
+	var health ReplicaHealth
+	for _, sg := range rpi.ShardGroups {
+		if sg.Deleted() {
+			continue
+		}
+		for _, s := range sg.Shards {
+			sh := ShardHealth{ShardID: s.ID, DesiredN: sg.EffectiveReplicaN(rpi.ReplicaN), ActualN: len(s.Owners)}
+			for _, owner := range s.Owners {
+				if _, ok := nodes[owner.NodeID]; ok {
+					sh.ResolvableN++
+				}
+			}
+
+			switch {
+			case sh.ResolvableN == 0:
+				health.Orphaned++
+			case sh.ResolvableN < sh.DesiredN:
+				health.UnderReplicated++
+			default:
+				health.Healthy++
+			}
+			health.Details = append(health.Details, sh)
+		}
+	}
+	return health, nil
+}
+
+// ShardGroupsByTimeRange returns a list of all shard groups on a database and policy that may contain data
+// for the specified time range. Shard groups are sorted by start time.
+func (data *Data) ShardGroupsByTimeRange(database, policy string, tmin, tmax time.Time) ([]ShardGroupInfo, error) {
+	// Find retention policy.
 	rpi, err := data.RetentionPolicy(database, policy)
 	if err != nil {
 		return nil, err
@@ -721,8 +2022,253 @@ func (data *Data) ShardGroupByTimestamp(database, policy string, timestamp time.
 	return rpi.ShardGroupByTimestamp(timestamp), nil
 }
 
+// ShardGroupByTimestampDefaultRP returns the shard group for a given timestamp
+// in the database's default retention policy, along with the policy's name.
+// ErrNoDefaultRetentionPolicy is returned if the database has no default policy.
+func (data *Data) ShardGroupByTimestampDefaultRP(database string, timestamp time.Time) (*ShardGroupInfo, string, error) {
+	di := data.Database(database)
+	if di == nil {
+		return nil, "", influxdb.ErrDatabaseNotFound(database)
+	} else if di.DefaultRetentionPolicy == "" {
+		return nil, "", ErrNoDefaultRetentionPolicy
+	}
+
+	sgi, err := data.ShardGroupByTimestamp(database, di.DefaultRetentionPolicy, timestamp)
+	if err != nil {
+		return nil, "", err
+	}
+	return sgi, di.DefaultRetentionPolicy, nil
+}
+
+// WouldCreateShardGroup returns true if CreateShardGroup would create a new
+// shard group on a database and policy for the given timestamp. No live shard
+// group may contain the timestamp, and the timestamp must fall within the
+// retention policy's duration. The metadata is not modified.
+func (data *Data) WouldCreateShardGroup(database, policy string, timestamp time.Time) (bool, error) {
+	// Find retention policy.
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return false, err
+	} else if rpi == nil {
+		return false, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	// CreateShardGroup is a no-op when there are no nodes to own shards.
+	if len(data.DataNodes) == 0 {
+		return false, nil
+	}
+
+	// Points older than the retention policy are dropped rather than
+	// creating a shard group for them.
+	if rpi.Duration > 0 && timestamp.Before(time.Now().Add(-rpi.Duration)) {
+		return false, nil
+	}
+
+	return rpi.ShardGroupByTimestamp(timestamp) == nil, nil
+}
+
+// ShardGroupTimeline returns the shard group timeline of a retention policy.
+func (data *Data) ShardGroupTimeline(database, policy string) ([]ShardGroupTimelineEntry, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+	return rpi.ShardGroupTimeline(), nil
+}
+
+// RetentionPolicyWriteWindow returns the range of timestamps a retention policy
+// accepts writes for at now. See RetentionPolicyInfo.WriteWindow.
+func (data *Data) RetentionPolicyWriteWindow(database, policy string, now time.Time) (min, max time.Time, err error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	} else if rpi == nil {
+		return time.Time{}, time.Time{}, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+	min, max = rpi.WriteWindow(now)
+	return min, max, nil
+}
+
+// RetentionPolicyMinAcceptableWriteTime returns the oldest timestamp a retention
+// policy accepts writes for at now. See RetentionPolicyInfo.MinAcceptableWriteTime.
+func (data *Data) RetentionPolicyMinAcceptableWriteTime(database, policy string, now time.Time) (time.Time, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return time.Time{}, err
+	} else if rpi == nil {
+		return time.Time{}, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+	return rpi.MinAcceptableWriteTime(now), nil
+}
+
+// RetentionPolicyForShardGroup returns the database and retention policy that
+// own the shard group with the given ID, including deleted shard groups.
+func (data *Data) RetentionPolicyForShardGroup(sgID uint64) (database, policy string, rpi *RetentionPolicyInfo, ok bool) {
+	for i := range data.Databases {
+		di := &data.Databases[i]
+		for j := range di.RetentionPolicies {
+			rp := &di.RetentionPolicies[j]
+			for _, sgi := range rp.ShardGroups {
+				if sgi.ID == sgID {
+					return di.Name, rp.Name, rp, true
+				}
+			}
+		}
+	}
+	return "", "", nil, false
+}
+
+// ShardGroupByID returns the shard group with the given ID, including deleted
+// shard groups, along with the database and retention policy that own it. If
+// no shard group has the ID, empty names and nil are returned.
+func (data *Data) ShardGroupByID(id uint64) (database, policy string, sg *ShardGroupInfo) {
+	for i := range data.Databases {
+		di := &data.Databases[i]
+		for j := range di.RetentionPolicies {
+			rp := &di.RetentionPolicies[j]
+			for k := range rp.ShardGroups {
+				if rp.ShardGroups[k].ID == id {
+					return di.Name, rp.Name, &rp.ShardGroups[k]
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// Shard placement strategies accepted by SetPlacementStrategy.
+const (
+	// PlacementStrategyRoundRobin places shards with RoundRobinPlacement.
+	PlacementStrategyRoundRobin = "round-robin"
+
+	// PlacementStrategyRackAware places shards with RackAwarePlacement.
+	PlacementStrategyRackAware = "rack-aware"
+)
+
+// shardPlacementStrategies maps the name of each placement strategy to it.
+var shardPlacementStrategies = map[string]ShardPlacementStrategy{
+	"":                          RoundRobinPlacement{},
+	PlacementStrategyRoundRobin: RoundRobinPlacement{},
+	PlacementStrategyRackAware:  RackAwarePlacement{},
+}
+
+// SetPlacementStrategy sets the strategy, by name, used to place the shards of
+// new shard groups. An empty name means PlacementStrategyRoundRobin.
+func (data *Data) SetPlacementStrategy(name string) error {
+	if _, ok := shardPlacementStrategies[name]; !ok {
+		return ErrInvalidPlacementStrategy
+	}
+	data.PlacementStrategy = name
+	return nil
+}
+
+// ShardPlacementStrategy chooses the data nodes that own a new shard.
+type ShardPlacementStrategy interface {
+	// NodeRing returns nodes in the order the shards of a shard group are
+	// placed around. The ring is rotated by the replica count for each shard,
+	// so successive shards start from different nodes.
+	NodeRing(nodes []NodeInfo) []NodeInfo
+
+	// AssignOwners returns replicaN owners for shard chosen from the rotated
+	// ring nodes, where replicaN is no more than len(nodes).
+	AssignOwners(shard ShardInfo, nodes []NodeInfo, replicaN int) []ShardOwner
+}
+
+// RoundRobinPlacement assigns a shard to the first replicaN nodes, which spreads
+// the shards of a group across the cluster in turn.
+type RoundRobinPlacement struct{}
+
+// NodeRing implements ShardPlacementStrategy. Nodes are kept in ID order.
+func (RoundRobinPlacement) NodeRing(nodes []NodeInfo) []NodeInfo { return nodes }
+
+// AssignOwners implements ShardPlacementStrategy.
+func (RoundRobinPlacement) AssignOwners(shard ShardInfo, nodes []NodeInfo, replicaN int) []ShardOwner {
+	owners := make([]ShardOwner, 0, replicaN)
+	for _, n := range nodes[:replicaN] {
+		owners = append(owners, ShardOwner{NodeID: n.ID})
+	}
+	return owners
+}
+
+// RackAwarePlacement assigns a shard's owners in ring order like
+// RoundRobinPlacement, but skips nodes in a zone that already holds a replica
+// of the shard while nodes in other zones remain. Nodes without a zone are each
+// treated as a zone of their own.
+type RackAwarePlacement struct{}
+
+// NodeRing implements ShardPlacementStrategy. The nodes of each zone are
+// interleaved, so rotating the ring moves through every node of each zone.
+func (RackAwarePlacement) NodeRing(nodes []NodeInfo) []NodeInfo {
+	type zoneKey struct {
+		zone string
+		id   uint64 // set for nodes without a zone
+	}
+	var zones []zoneKey
+	byZone := make(map[zoneKey][]NodeInfo)
+	for _, n := range nodes {
+		k := zoneKey{zone: n.Zone}
+		if n.Zone == "" {
+			k.id = n.ID
+		}
+		if _, ok := byZone[k]; !ok {
+			zones = append(zones, k)
+		}
+		byZone[k] = append(byZone[k], n)
+	}
+
+	ring := make([]NodeInfo, 0, len(nodes))
+	for i := 0; len(ring) < len(nodes); i++ {
+		for _, k := range zones {
+			if i < len(byZone[k]) {
+				ring = append(ring, byZone[k][i])
+			}
+		}
+	}
+	return ring
+}
+
+// AssignOwners implements ShardPlacementStrategy.
+func (RackAwarePlacement) AssignOwners(shard ShardInfo, nodes []NodeInfo, replicaN int) []ShardOwner {
+	owners := make([]ShardOwner, 0, replicaN)
+	used := make(map[uint64]bool, replicaN)
+	zones := make(map[string]bool, replicaN)
+	for _, n := range nodes {
+		if len(owners) == replicaN {
+			return owners
+		}
+		if n.Zone != "" && zones[n.Zone] {
+			continue
+		}
+		owners = append(owners, ShardOwner{NodeID: n.ID})
+		used[n.ID] = true
+		zones[n.Zone] = true
+	}
+
+	// There are fewer zones than replicas, so share zones.
+	for _, n := range nodes {
+		if len(owners) == replicaN {
+			break
+		}
+		if !used[n.ID] {
+			owners = append(owners, ShardOwner{NodeID: n.ID})
+			used[n.ID] = true
+		}
+	}
+	return owners
+}
+
 // CreateShardGroup creates a shard group on a database and policy for a given timestamp.
 func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time) error {
+	return data.CreateShardGroupWithReplicaN(database, policy, timestamp, 0)
+}
+
+// CreateShardGroupWithReplicaN creates a shard group like CreateShardGroup, with
+// replicaN overriding the policy's replication factor for the new shard group
+// only. The override, limited to the number of data nodes, is recorded in
+// ShardGroupInfo.ReplicaN. A replicaN of zero uses the policy's factor.
+func (data *Data) CreateShardGroupWithReplicaN(database, policy string, timestamp time.Time, replicaN int) error {
 	// Ensure there are nodes in the metadata.
 	if len(data.DataNodes) == 0 {
 		return nil
@@ -741,25 +2287,149 @@ func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time)
 		return nil
 	}
 
+	n, shardN := data.shardGroupLayout(rpi, replicaN)
+	startTime, endTime := rpi.shardGroupBounds(timestamp)
+
+	// Create the shard group.
+	data.MaxShardGroupID++
+	sgi := ShardGroupInfo{}
+	sgi.ID = data.MaxShardGroupID
+	sgi.StartTime = startTime
+	sgi.EndTime = endTime
+	if replicaN > 0 {
+		sgi.ReplicaN = n
+	}
+
+	// Create shards on the group.
+	sgi.Shards = make([]ShardInfo, shardN)
+	for i := range sgi.Shards {
+		data.MaxShardID++
+		sgi.Shards[i] = ShardInfo{ID: data.MaxShardID}
+	}
+
+	// Assign data nodes to shards with the placement strategy. Start from a
+	// repeatably "random" place in the node ring and advance by the replica
+	// count for each shard.
+	placement, ok := shardPlacementStrategies[data.PlacementStrategy]
+	if !ok {
+		placement = RoundRobinPlacement{}
+	}
+	ring := placement.NodeRing(data.DataNodes)
+	nodeIndex := int(data.Index % uint64(len(ring)))
+	nodes := make([]NodeInfo, len(ring))
+	for i := range sgi.Shards {
+		for j := range nodes {
+			nodes[j] = ring[(nodeIndex+j)%len(ring)]
+		}
+		sgi.Shards[i].Owners = placement.AssignOwners(sgi.Shards[i], nodes, n)
+		nodeIndex += n
+	}
+
+	// Retention policy has a new shard group, so update the policy. Shard
+	// Groups must be stored in sorted order, as other parts of the system
+	// assume this to be the case.
+	rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+	sort.Sort(ShardGroupInfos(rpi.ShardGroups))
+
+	return nil
+}
+
+// CreateShardGroupWithOwners creates a shard group on a database and policy for
+// a given timestamp like CreateShardGroup, but with the owners of each shard
+// given by owners instead of the placement strategy. There must be an owner set
+// for each of the shards CreateShardGroup would create, and every owner must be
+// a data node. Data is left unchanged when an error is returned.
+func (data *Data) CreateShardGroupWithOwners(database, policy string, timestamp time.Time, owners [][]ShardOwner) error {
+	if len(data.DataNodes) == 0 {
+		return ErrNodesRequired
+	}
+
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	if rpi.ShardGroupByTimestamp(timestamp) != nil {
+		return ErrShardGroupExists
+	}
+
+	if _, shardN := data.shardGroupLayout(rpi, 0); len(owners) != shardN {
+		return ErrShardOwnerCountMismatch
+	}
+	for _, set := range owners {
+		if len(set) == 0 {
+			return ErrShardOwnerNotFound
+		}
+		seen := make(map[uint64]bool, len(set))
+		for _, owner := range set {
+			if data.DataNode(owner.NodeID) == nil {
+				return ErrNodeNotFound
+			} else if seen[owner.NodeID] {
+				return ErrShardOwnerExists
+			}
+			seen[owner.NodeID] = true
+		}
+	}
+
+	startTime, endTime := rpi.shardGroupBounds(timestamp)
+
+	data.MaxShardGroupID++
+	sgi := ShardGroupInfo{
+		ID:        data.MaxShardGroupID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Shards:    make([]ShardInfo, len(owners)),
+	}
+	for i, set := range owners {
+		data.MaxShardID++
+		sgi.Shards[i] = ShardInfo{ID: data.MaxShardID, Owners: make([]ShardOwner, len(set))}
+		for j := range set {
+			sgi.Shards[i].Owners[j] = set[j].clone()
+		}
+	}
+
+	rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+	sort.Sort(ShardGroupInfos(rpi.ShardGroups))
+
+	return nil
+}
+
+// shardGroupLayout returns the replica count and the number of shards of a new
+// shard group in a retention policy. sgReplicaN overrides the policy's
+// replication factor when positive.
+func (data *Data) shardGroupLayout(rpi *RetentionPolicyInfo, sgReplicaN int) (n, shardN int) {
 	// Require at least one replica but no more replicas than nodes.
-	replicaN := rpi.ReplicaN
-	if replicaN == 0 {
-		replicaN = 1
-	} else if replicaN > len(data.DataNodes) {
-		replicaN = len(data.DataNodes)
+	n = rpi.ReplicaN
+	if sgReplicaN > 0 {
+		n = sgReplicaN
+	}
+	if n == 0 {
+		n = 1
+	} else if n > len(data.DataNodes) {
+		n = len(data.DataNodes)
 	}
 
 	// Determine shard count by the least common multiple of node count and
 	// replication factor divided by node count.
 	// This will ensure nodes will get distributed across nodes evenly and
 	// replicated the correct number of times.
-	shardN := 1
-	for shardN*replicaN%len(data.DataNodes) != 0 {
+	shardN = 1
+	for shardN*n%len(data.DataNodes) != 0 {
 		shardN++
 	}
+	if rpi.PendingShardCount > 0 {
+		shardN = rpi.PendingShardCount
+	}
+	return n, shardN
+}
 
-	startTime := timestamp.Truncate(rpi.ShardGroupDuration).UTC()
-	endTime := startTime.Add(rpi.ShardGroupDuration).UTC()
+// shardGroupBounds returns the time range of a new shard group containing
+// timestamp, shortened so it doesn't overlap the policy's other shard groups.
+func (rpi *RetentionPolicyInfo) shardGroupBounds(timestamp time.Time) (startTime, endTime time.Time) {
+	startTime = timestamp.Truncate(rpi.ShardGroupDuration).UTC()
+	endTime = startTime.Add(rpi.ShardGroupDuration).UTC()
 	if endTime.After(time.Unix(0, models.MaxNanoTime)) {
 		// Shard group range is [start, end) so add one to the max time.
 		endTime = time.Unix(0, models.MaxNanoTime+1)
@@ -793,39 +2463,98 @@ func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time)
 			endTime = startI
 		}
 	}
+	return startTime, endTime
+}
 
-	// Create the shard group.
-	data.MaxShardGroupID++
-	sgi := ShardGroupInfo{}
-	sgi.ID = data.MaxShardGroupID
-	sgi.StartTime = startTime
-	sgi.EndTime = endTime
+// PreCreateShardGroups creates any of the next PreCreateCount shard groups of a
+// retention policy after the one containing now that do not exist yet. It
+// returns the IDs of the shard groups it created.
+func (data *Data) PreCreateShardGroups(database, policy string, now time.Time) ([]uint64, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
 
-	// Create shards on the group.
-	sgi.Shards = make([]ShardInfo, shardN)
-	for i := range sgi.Shards {
-		data.MaxShardID++
-		sgi.Shards[i] = ShardInfo{ID: data.MaxShardID}
+	// New groups keep the replication factor override of the current group.
+	var replicaN int
+	if sgi := rpi.ShardGroupByTimestamp(now); sgi != nil {
+		replicaN = sgi.ReplicaN
 	}
 
-	// Assign data nodes to shards via round robin.
-	// Start from a repeatably "random" place in the node list.
-	nodeIndex := int(data.Index % uint64(len(data.DataNodes)))
-	for i := range sgi.Shards {
-		si := &sgi.Shards[i]
-		for j := 0; j < replicaN; j++ {
-			nodeID := data.DataNodes[nodeIndex%len(data.DataNodes)].ID
-			si.Owners = append(si.Owners, ShardOwner{NodeID: nodeID})
-			nodeIndex++
+	var ids []uint64
+	for _, timestamp := range rpi.preCreateTimestamps(now) {
+		if rpi.ShardGroupByTimestamp(timestamp) != nil {
+			continue
+		}
+
+		maxID := data.MaxShardGroupID
+		if err := data.CreateShardGroupWithReplicaN(database, policy, timestamp, replicaN); err != nil {
+			return ids, err
+		}
+		if data.MaxShardGroupID != maxID {
+			ids = append(ids, data.MaxShardGroupID)
 		}
 	}
+	return ids, nil
+}
 
-	// Retention policy has a new shard group, so update the policy. Shard
-	// Groups must be stored in sorted order, as other parts of the system
-	// assume this to be the case.
-	rpi.ShardGroups = append(rpi.ShardGroups, sgi)
-	sort.Sort(ShardGroupInfos(rpi.ShardGroups))
+// preCreateTimestamps returns a timestamp in each of the next PreCreateCount
+// shard groups after the one containing now.
+func (rpi *RetentionPolicyInfo) preCreateTimestamps(now time.Time) []time.Time {
+	var a []time.Time
+	start := now.Truncate(rpi.ShardGroupDuration)
+	for i := 1; i <= rpi.PreCreateCount; i++ {
+		a = append(a, start.Add(time.Duration(i)*rpi.ShardGroupDuration))
+	}
+	return a
+}
 
+// preCreatePending returns whether any of the shard groups PreCreateShardGroups
+// creates for now is missing.
+func (rpi *RetentionPolicyInfo) preCreatePending(now time.Time) bool {
+	for _, timestamp := range rpi.preCreateTimestamps(now) {
+		if rpi.ShardGroupByTimestamp(timestamp) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PrecreateShardGroupsRange ensures every retention policy has shard groups
+// covering [now, cutoff), so writes at a shard group boundary don't wait for the
+// group to be created. Existing shard groups are kept and deleted ones are
+// ignored; the part of a truncated group after its truncation time gets a new
+// group. Unlike PreCreateShardGroups it is not limited by the policy's
+// PreCreateCount.
+func (data *Data) PrecreateShardGroupsRange(now, cutoff time.Time) error {
+	for i := range data.Databases {
+		di := &data.Databases[i]
+		for j := range di.RetentionPolicies {
+			rpi := &di.RetentionPolicies[j]
+			// New groups keep the replication factor override of the group
+			// before them.
+			var replicaN int
+			for t := now; t.Before(cutoff); {
+				sgi := rpi.ShardGroupByTimestamp(t)
+				if sgi == nil {
+					if err := data.CreateShardGroupWithReplicaN(di.Name, rpi.Name, t, replicaN); err != nil {
+						return err
+					}
+					// No shard group is created without data nodes.
+					if sgi = rpi.ShardGroupByTimestamp(t); sgi == nil {
+						break
+					}
+				}
+				replicaN = sgi.ReplicaN
+				t = sgi.EndTime
+				if sgi.Truncated() {
+					t = sgi.TruncatedAt
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -834,24 +2563,75 @@ func (data *Data) DeleteShardGroup(database, policy string, id uint64) error {
 	// Find retention policy.
 	rpi, err := data.RetentionPolicy(database, policy)
 	if err != nil {
-		return err
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	// Find shard group by ID and set its deletion timestamp.
+	for i := range rpi.ShardGroups {
+		if rpi.ShardGroups[i].ID == id {
+			rpi.ShardGroups[i].DeletedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return ErrShardGroupNotFound
+}
+
+// DeleteAllShardGroups marks every shard group of a retention policy that is not
+// already deleted as deleted, and returns the number of groups marked.
+func (data *Data) DeleteAllShardGroups(database, policy string) (int, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return 0, err
 	} else if rpi == nil {
-		return influxdb.ErrRetentionPolicyNotFound(policy)
+		return 0, influxdb.ErrRetentionPolicyNotFound(policy)
 	}
+	return rpi.deleteAllShardGroups(time.Now().UTC()), nil
+}
 
-	// Find shard group by ID and set its deletion timestamp.
+// DropShardGroupsForDatabase marks every shard group of every retention policy
+// of a database that is not already deleted as deleted, and returns the number
+// of groups marked.
+func (data *Data) DropShardGroupsForDatabase(database string) (int, error) {
+	di := data.Database(database)
+	if di == nil {
+		return 0, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	var n int
+	now := time.Now().UTC()
+	for i := range di.RetentionPolicies {
+		n += di.RetentionPolicies[i].deleteAllShardGroups(now)
+	}
+	return n, nil
+}
+
+// deleteAllShardGroups sets the deletion timestamp of every shard group that is
+// not already deleted to now, and returns the number of groups changed.
+func (rpi *RetentionPolicyInfo) deleteAllShardGroups(now time.Time) int {
+	var n int
 	for i := range rpi.ShardGroups {
-		if rpi.ShardGroups[i].ID == id {
-			rpi.ShardGroups[i].DeletedAt = time.Now().UTC()
-			return nil
+		if rpi.ShardGroups[i].Deleted() {
+			continue
 		}
+		rpi.ShardGroups[i].DeletedAt = now
+		n++
 	}
-
-	return ErrShardGroupNotFound
+	return n
 }
 
-// CreateContinuousQuery adds a named continuous query to a database.
+// CreateContinuousQuery adds a named continuous query to a database. The query
+// must be a CREATE CONTINUOUS QUERY statement of the same name on the same
+// database.
 func (data *Data) CreateContinuousQuery(database, name, query string) error {
+	return data.createContinuousQuery(database, name, query, true)
+}
+
+// createContinuousQuery is CreateContinuousQuery, validating the query only
+// if validate is set.
+func (data *Data) createContinuousQuery(database, name, query string, validate bool) error {
 	di := data.Database(database)
 	if di == nil {
 		return influxdb.ErrDatabaseNotFound(database)
@@ -870,15 +2650,69 @@ func (data *Data) CreateContinuousQuery(database, name, query string) error {
 		}
 	}
 
+	if validate {
+		if err := validateContinuousQuery(database, name, query); err != nil {
+			return err
+		}
+	}
+
 	// Append new query.
 	di.ContinuousQueries = append(di.ContinuousQueries, ContinuousQueryInfo{
-		Name:  name,
-		Query: query,
+		Name:    name,
+		Query:   query,
+		Enabled: true,
 	})
 
 	return nil
 }
 
+// validateContinuousQuery returns an error if query isn't a CREATE CONTINUOUS
+// QUERY statement named name on database.
+func validateContinuousQuery(database, name, query string) error {
+	stmt, err := influxql.ParseStatement(query)
+	if err != nil {
+		return fmt.Errorf("invalid continuous query: %s", err)
+	}
+	cq, ok := stmt.(*influxql.CreateContinuousQueryStatement)
+	if !ok {
+		return fmt.Errorf("invalid continuous query: expected CREATE CONTINUOUS QUERY, got %s", query)
+	}
+	if cq.Name != name {
+		return fmt.Errorf("invalid continuous query: named %s, expected %s", cq.Name, name)
+	}
+	if cq.Database != database {
+		return fmt.Errorf("invalid continuous query: defined on database %s, expected %s", cq.Database, database)
+	}
+	return nil
+}
+
+// UpdateContinuousQuery replaces the query of an existing continuous query,
+// keeping its name and enabled flag. The new query must be valid for
+// CreateContinuousQuery, so it must have the same name; replacing a query with
+// the same string is a no-op.
+func (data *Data) UpdateContinuousQuery(database, name, query string) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		cq := &di.ContinuousQueries[i]
+		if cq.Name != name {
+			continue
+		}
+		if cq.Query == query {
+			return nil
+		}
+		if err := validateContinuousQuery(database, name, query); err != nil {
+			return err
+		}
+		cq.Query = query
+		return nil
+	}
+	return ErrContinuousQueryNotFound
+}
+
 // DropContinuousQuery removes a continuous query.
 func (data *Data) DropContinuousQuery(database, name string) error {
 	di := data.Database(database)
@@ -895,6 +2729,98 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
+// SetContinuousQueryEnabled pauses or resumes a continuous query without
+// changing its definition.
+func (data *Data) SetContinuousQueryEnabled(database, name string, enabled bool) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		if di.ContinuousQueries[i].Name == name {
+			di.ContinuousQueries[i].Enabled = enabled
+			return nil
+		}
+	}
+	return ErrContinuousQueryNotFound
+}
+
+// ContinuousQueriesReferencingRetentionPolicy returns the sorted names of the
+// continuous queries, on any database, that explicitly read from or write into
+// a retention policy of database. Such queries break if the policy is renamed.
+// Queries that cannot be parsed are skipped.
+func (data *Data) ContinuousQueriesReferencingRetentionPolicy(database, policy string) ([]string, error) {
+	if data.Database(database) == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	var names []string
+	for _, di := range data.Databases {
+		for _, cqi := range di.ContinuousQueries {
+			if _, refs := parseContinuousQueryRefs(di.Name, cqi.Query, database, policy); len(refs) > 0 {
+				names = append(names, cqi.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RenameRetentionPolicyInContinuousQueries rewrites the continuous queries that
+// explicitly reference the oldName retention policy of database to reference
+// newName instead, and returns the sorted names of the rewritten queries.
+func (data *Data) RenameRetentionPolicyInContinuousQueries(database, oldName, newName string) ([]string, error) {
+	if data.Database(database) == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	var names []string
+	for i := range data.Databases {
+		di := &data.Databases[i]
+		for j := range di.ContinuousQueries {
+			cqi := &di.ContinuousQueries[j]
+			stmt, refs := parseContinuousQueryRefs(di.Name, cqi.Query, database, oldName)
+			if len(refs) == 0 {
+				continue
+			}
+			for _, m := range refs {
+				m.RetentionPolicy = newName
+			}
+			cqi.Query = stmt.String()
+			names = append(names, cqi.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// parseContinuousQueryRefs parses a continuous query defined on cqDatabase and
+// returns it along with its measurements that explicitly name the policy of
+// database. It returns no measurements if the query cannot be parsed.
+func parseContinuousQueryRefs(cqDatabase, query, database, policy string) (*influxql.CreateContinuousQueryStatement, []*influxql.Measurement) {
+	stmt, err := influxql.ParseStatement(query)
+	if err != nil {
+		return nil, nil
+	}
+	cq, ok := stmt.(*influxql.CreateContinuousQueryStatement)
+	if !ok {
+		return nil, nil
+	}
+
+	var refs []*influxql.Measurement
+	influxql.WalkFunc(cq, func(n influxql.Node) {
+		m, ok := n.(*influxql.Measurement)
+		if !ok || m.RetentionPolicy != policy {
+			return
+		}
+		if db := m.Database; db == database || db == "" && cqDatabase == database {
+			refs = append(refs, m)
+		}
+	})
+	return cq, refs
+}
+
 // validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
@@ -914,8 +2840,25 @@ func validateURL(input string) error {
 	return nil
 }
 
+// normalizeSubscriptionMode returns mode in upper-case, or ErrInvalidSubscriptionMode
+// if it is not ANY or ALL.
+func normalizeSubscriptionMode(mode string) (string, error) {
+	switch m := strings.ToUpper(mode); m {
+	case "ANY", "ALL":
+		return m, nil
+	default:
+		return "", ErrInvalidSubscriptionMode
+	}
+}
+
 // CreateSubscription adds a named subscription to a database and retention policy.
+// The mode must be ANY or ALL, in any case, and is stored in upper-case.
 func (data *Data) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+	mode, err := normalizeSubscriptionMode(mode)
+	if err != nil {
+		return err
+	}
+
 	for _, d := range destinations {
 		if err := validateURL(d); err != nil {
 			return err
@@ -946,6 +2889,56 @@ func (data *Data) CreateSubscription(database, rp, name, mode string, destinatio
 	return nil
 }
 
+// UpdateSubscription replaces the mode and destinations of a subscription in
+// place, so no writes are missed as they would be dropping and recreating it.
+func (data *Data) UpdateSubscription(database, rp, name, mode string, destinations []string) error {
+	mode, err := normalizeSubscriptionMode(mode)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range destinations {
+		if err := validateURL(d); err != nil {
+			return err
+		}
+	}
+
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.Subscriptions {
+		if rpi.Subscriptions[i].Name == name {
+			rpi.Subscriptions[i].Mode = mode
+			rpi.Subscriptions[i].Destinations = append([]string(nil), destinations...)
+			return nil
+		}
+	}
+	return ErrSubscriptionNotFound
+}
+
+// SetSubscriptionMeasurements limits a subscription to the points of the named
+// measurements. An empty list sends the subscription every point.
+func (data *Data) SetSubscriptionMeasurements(database, rp, name string, measurements []string) error {
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.Subscriptions {
+		if rpi.Subscriptions[i].Name == name {
+			rpi.Subscriptions[i].Measurements = append([]string(nil), measurements...)
+			return nil
+		}
+	}
+	return ErrSubscriptionNotFound
+}
+
 // DropSubscription removes a subscription.
 func (data *Data) DropSubscription(database, rp, name string) error {
 	rpi, err := data.RetentionPolicy(database, rp)
@@ -1014,6 +3007,11 @@ func (data *Data) DropUser(name string) error {
 			wasAdmin := data.Users[i].Admin
 			data.Users = append(data.Users[:i], data.Users[i+1:]...)
 
+			// A user created later with the same name must not inherit roles.
+			for j := range data.Roles {
+				data.Roles[j].removeUser(name)
+			}
+
 			// Maybe we dropped the only admin user?
 			if wasAdmin {
 				data.adminUserExists = data.hasAdminUser()
@@ -1049,6 +3047,28 @@ func (data *Data) CloneUsers() []UserInfo {
 	return users
 }
 
+// AdminUsers returns the sorted names of all admin users.
+func (data *Data) AdminUsers() []string {
+	return data.userNames(true)
+}
+
+// RegularUsers returns the sorted names of all non-admin users.
+func (data *Data) RegularUsers() []string {
+	return data.userNames(false)
+}
+
+// userNames returns the sorted names of users whose admin flag matches admin.
+func (data *Data) userNames(admin bool) []string {
+	names := []string{}
+	for _, u := range data.Users {
+		if u.Admin == admin {
+			names = append(names, u.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // SetPrivilege sets a privilege for a user on a database.
 func (data *Data) SetPrivilege(name, database string, p influxql.Privilege) error {
 	ui := data.user(name)
@@ -1068,6 +3088,35 @@ func (data *Data) SetPrivilege(name, database string, p influxql.Privilege) erro
 	return nil
 }
 
+// SetPrivileges sets a user's privileges on several databases at once, keyed
+// by database name. Every database must exist; otherwise the first missing one,
+// in name order, is returned as an error and no privilege is changed.
+func (data *Data) SetPrivileges(name string, grants map[string]influxql.Privilege) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	databases := make([]string, 0, len(grants))
+	for database := range grants {
+		databases = append(databases, database)
+	}
+	sort.Strings(databases)
+	for _, database := range databases {
+		if data.Database(database) == nil {
+			return influxdb.ErrDatabaseNotFound(database)
+		}
+	}
+
+	if ui.Privileges == nil && len(grants) > 0 {
+		ui.Privileges = make(map[string]influxql.Privilege, len(grants))
+	}
+	for database, p := range grants {
+		ui.Privileges[database] = p
+	}
+	return nil
+}
+
 // SetAdminPrivilege sets the admin privilege for a user.
 func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	ui := data.user(name)
@@ -1076,6 +3125,9 @@ func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	}
 
 	ui.Admin = admin
+	if !admin {
+		ui.AdminSince = nil
+	}
 
 	// We could have promoted or revoked the only admin. Check if an admin
 	// user exists.
@@ -1083,6 +3135,29 @@ func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	return nil
 }
 
+// PromoteUserToAdmin makes a user an admin and records at as the time of the
+// promotion. The time of an earlier promotion is kept if the user is already an
+// admin.
+func (data *Data) PromoteUserToAdmin(name string, at time.Time) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	if !ui.Admin || ui.AdminSince == nil {
+		at = at.UTC()
+		ui.AdminSince = &at
+	}
+	ui.Admin = true
+	data.adminUserExists = true
+	return nil
+}
+
+// DemoteAdmin revokes a user's admin privilege and clears its promotion time.
+func (data *Data) DemoteAdmin(name string) error {
+	return data.SetAdminPrivilege(name, false)
+}
+
 // AdminUserExists returns true if an admin user exists.
 func (data Data) AdminUserExists() bool {
 	return data.adminUserExists
@@ -1095,23 +3170,209 @@ func (data *Data) UserPrivileges(name string) (map[string]influxql.Privilege, er
 		return nil, ErrUserNotFound
 	}
 
-	return ui.Privileges, nil
-}
+	return ui.Privileges, nil
+}
+
+// DatabasePrivilege is a privilege granted on a database.
+type DatabasePrivilege struct {
+	Database  string `json:"database"`
+	Privilege string `json:"privilege"`
+}
+
+// UserPrivilegesSorted gets the privileges for a user sorted by database name,
+// with each privilege in its string form.
+func (data *Data) UserPrivilegesSorted(name string) ([]DatabasePrivilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	privileges := make([]DatabasePrivilege, 0, len(ui.Privileges))
+	for db, p := range ui.Privileges {
+		privileges = append(privileges, DatabasePrivilege{Database: db, Privilege: p.String()})
+	}
+	sort.Slice(privileges, func(i, j int) bool { return privileges[i].Database < privileges[j].Database })
+	return privileges, nil
+}
+
+// UserPrivilege gets the privilege for a user on a database.
+func (data *Data) UserPrivilege(name, database string) (*influxql.Privilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	for db, p := range ui.Privileges {
+		if db == database {
+			return &p, nil
+		}
+	}
+
+	return influxql.NewPrivilege(influxql.NoPrivileges), nil
+}
+
+// UsersWithPrivilege returns the sorted names of the users granted p on a
+// database, directly or through their roles. Admins have every privilege.
+func (data *Data) UsersWithPrivilege(database string, p influxql.Privilege) []string {
+	names := []string{}
+	for i := range data.Users {
+		if data.Users[i].AuthorizeDatabase(p, database) {
+			names = append(names, data.Users[i].Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DatabasesForUser returns the sorted names of the databases a user has any
+// privilege on, directly or through its roles. Admins can use every database.
+func (data *Data) DatabasesForUser(name string) ([]string, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	names := []string{}
+	for _, di := range data.Databases {
+		if ui.Admin || unionPrivilege(ui.Privileges[di.Name], ui.rolePrivileges[di.Name]) != influxql.NoPrivileges {
+			names = append(names, di.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// CloneRoles returns a copy of the role infos.
+func (data *Data) CloneRoles() []RoleInfo {
+	if data.Roles == nil {
+		return nil
+	}
+	roles := make([]RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		roles[i] = data.Roles[i].clone()
+	}
+	return roles
+}
+
+// CreateRole creates a new role without users or privileges.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.Role(name) != nil {
+		return ErrRoleExists
+	}
+
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	return nil
+}
+
+// DropRole removes an existing role by name. Its users lose the privileges
+// granted through it.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+			data.refreshRolePrivileges()
+			return nil
+		}
+	}
+	return ErrRoleNotFound
+}
+
+// AddUserToRole makes a user a member of a role, granting it the role's
+// privileges.
+func (data *Data) AddUserToRole(role, username string) error {
+	ri := data.Role(role)
+	if ri == nil {
+		return ErrRoleNotFound
+	} else if data.user(username) == nil {
+		return ErrUserNotFound
+	}
+
+	for _, u := range ri.Users {
+		if u == username {
+			return nil
+		}
+	}
+	ri.Users = append(ri.Users, username)
+	sort.Strings(ri.Users)
+	data.refreshRolePrivileges()
+	return nil
+}
+
+// RemoveUserFromRole removes a user from a role. Removing a user that is not a
+// member of the role does nothing.
+func (data *Data) RemoveUserFromRole(role, username string) error {
+	ri := data.Role(role)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	ri.removeUser(username)
+	data.refreshRolePrivileges()
+	return nil
+}
+
+// SetRolePrivilege sets a privilege for a role on a database.
+func (data *Data) SetRolePrivilege(role, database string, p influxql.Privilege) error {
+	ri := data.Role(role)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	if data.Database(database) == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
 
-// UserPrivilege gets the privilege for a user on a database.
-func (data *Data) UserPrivilege(name, database string) (*influxql.Privilege, error) {
-	ui := data.user(name)
-	if ui == nil {
-		return nil, ErrUserNotFound
+	if ri.Privileges == nil {
+		ri.Privileges = make(map[string]influxql.Privilege)
 	}
+	ri.Privileges[database] = p
+	data.refreshRolePrivileges()
+	return nil
+}
 
-	for db, p := range ui.Privileges {
-		if db == database {
-			return &p, nil
+// refreshRolePrivileges recomputes the privileges each user is granted through
+// its roles. It must be called whenever roles, their users or their privileges
+// change.
+func (data *Data) refreshRolePrivileges() {
+	for i := range data.Users {
+		ui := &data.Users[i]
+		ui.rolePrivileges = nil
+		for _, ri := range data.Roles {
+			if !ri.hasUser(ui.Name) {
+				continue
+			}
+			for database, p := range ri.Privileges {
+				if ui.rolePrivileges == nil {
+					ui.rolePrivileges = make(map[string]influxql.Privilege)
+				}
+				ui.rolePrivileges[database] = unionPrivilege(ui.rolePrivileges[database], p)
+			}
 		}
 	}
+}
 
-	return influxql.NewPrivilege(influxql.NoPrivileges), nil
+// unionPrivilege returns the privilege granting everything a and b grant.
+func unionPrivilege(a, b influxql.Privilege) influxql.Privilege {
+	switch {
+	case a == b || b == influxql.NoPrivileges:
+		return a
+	case a == influxql.NoPrivileges:
+		return b
+	default:
+		return influxql.AllPrivileges
+	}
 }
 
 // Clone returns a copy of data with a new version.
@@ -1120,6 +3381,7 @@ func (data *Data) Clone() *Data {
 
 	other.Databases = data.CloneDatabases()
 	other.Users = data.CloneUsers()
+	other.Roles = data.CloneRoles()
 
 	return &other
 }
@@ -1156,9 +3418,53 @@ func (data *Data) marshal() *internal.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Roles = make([]*internal.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
+	if data.PlacementStrategy != "" {
+		pb.PlacementStrategy = proto.String(data.PlacementStrategy)
+	}
+
+	version := data.Version
+	if version == 0 {
+		version = DataVersion
+	}
+	pb.Version = proto.Uint32(version)
+
 	return pb
 }
 
+// migrate upgrades pb from the version it was written with to DataVersion.
+// Changes to the metadata format that need existing metadata converted
+// should add a step here. Metadata of a newer version is left as is.
+func migrate(pb *internal.Data) {
+	if pb.GetVersion() >= DataVersion {
+		return
+	}
+
+	if pb.GetVersion() < 1 {
+		// Nodes was replaced by DataNodes and MetaNodes in 0.10.0.
+		if len(pb.Nodes) > 0 {
+			pb.DataNodes, pb.Nodes = pb.Nodes, nil
+		}
+
+		// OwnerIDs was replaced by Owners.
+		for _, db := range pb.Databases {
+			for _, rp := range db.RetentionPolicies {
+				for _, sg := range rp.ShardGroups {
+					for _, sh := range sg.Shards {
+						migrateShardOwnerIDs(sh)
+					}
+				}
+			}
+		}
+	}
+
+	pb.Version = proto.Uint32(DataVersion)
+}
+
 // unmarshal deserializes from a protobuf representation.
 func (data *Data) unmarshal(pb *internal.Data) {
 	data.Term = pb.GetTerm()
@@ -1169,16 +3475,16 @@ func (data *Data) unmarshal(pb *internal.Data) {
 	data.MaxShardGroupID = pb.GetMaxShardGroupID()
 	data.MaxShardID = pb.GetMaxShardID()
 
-	// TODO: Nodes is deprecated. This is being left here to make migration from 0.9.x to 0.10.0 possible
-	if len(pb.GetNodes()) > 0 {
-		data.DataNodes = make([]NodeInfo, len(pb.GetNodes()))
-		for i, x := range pb.GetNodes() {
-			data.DataNodes[i].unmarshal(x)
-		}
-	} else {
-		data.DataNodes = make([]NodeInfo, len(pb.GetDataNodes()))
-		for i, x := range pb.GetDataNodes() {
-			data.DataNodes[i].unmarshal(x)
+	migrate(pb)
+	data.Version = pb.GetVersion()
+	data.PlacementStrategy = pb.GetPlacementStrategy()
+
+	data.DataNodes = make([]NodeInfo, len(pb.GetDataNodes()))
+	for i, x := range pb.GetDataNodes() {
+		data.DataNodes[i].unmarshal(x)
+		// Data nodes stored before the status existed are joined.
+		if data.DataNodes[i].Status == "" {
+			data.DataNodes[i].Status = NodeStatusJoined
 		}
 	}
 
@@ -1197,26 +3503,235 @@ func (data *Data) unmarshal(pb *internal.Data) {
 		data.Users[i].unmarshal(x)
 	}
 
+	data.Roles = nil
+	if len(pb.GetRoles()) > 0 {
+		data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+		for i, x := range pb.GetRoles() {
+			data.Roles[i].unmarshal(x)
+		}
+	}
+	data.refreshRolePrivileges()
+
 	// Exhaustively determine if there is an admin user. The marshalled cache
 	// value may not be correct.
 	data.adminUserExists = data.hasAdminUser()
 }
 
+// checksumMagic starts metadata encoded with a checksum. Its first byte is an
+// invalid protobuf tag, so it can't start metadata encoded without one.
+var checksumMagic = []byte{0x07, 'M', 'D', 0x01}
+
+// checksumHeaderSize is the size of the magic and the CRC32 checksum of the
+// protobuf payload that follows them.
+const checksumHeaderSize = 8
+
 // MarshalBinary encodes the metadata to a binary format.
 func (data *Data) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(data.marshal())
 }
 
-// UnmarshalBinary decodes the object from a binary format.
+// ChecksumMetadata prefixes metadata encoded by MarshalBinary with a header
+// holding its CRC32 checksum, as written to backups so a corrupt backup is
+// rejected by UnmarshalBinary.
+func ChecksumMetadata(buf []byte) []byte {
+	other := make([]byte, checksumHeaderSize, checksumHeaderSize+len(buf))
+	copy(other, checksumMagic)
+	binary.BigEndian.PutUint32(other[len(checksumMagic):], crc32.ChecksumIEEE(buf))
+	return append(other, buf...)
+}
+
+// UnmarshalBinary decodes the object from a binary format. Metadata with a
+// checksum header from ChecksumMetadata returns ErrMetadataCorrupt if it
+// doesn't match its checksum, and metadata written in a newer format than
+// DataVersion returns ErrMetadataVersion.
 func (data *Data) UnmarshalBinary(buf []byte) error {
+	if bytes.HasPrefix(buf, checksumMagic) {
+		if len(buf) < checksumHeaderSize {
+			return ErrMetadataCorrupt
+		}
+		checksum := binary.BigEndian.Uint32(buf[len(checksumMagic):])
+		buf = buf[checksumHeaderSize:]
+		if crc32.ChecksumIEEE(buf) != checksum {
+			return ErrMetadataCorrupt
+		}
+	}
+
 	var pb internal.Data
 	if err := proto.Unmarshal(buf, &pb); err != nil {
 		return err
+	} else if pb.GetVersion() > DataVersion {
+		return ErrMetadataVersion
 	}
 	data.unmarshal(&pb)
 	return nil
 }
 
+// MarshalJSONOpts are the options of Data.MarshalJSONWithOpts.
+type MarshalJSONOpts struct {
+	// IncludeHashes includes the users' password hashes.
+	IncludeHashes bool
+}
+
+// MarshalJSON encodes the metadata as human readable JSON, without the users'
+// password hashes.
+func (data *Data) MarshalJSON() ([]byte, error) {
+	return data.MarshalJSONWithOpts(MarshalJSONOpts{})
+}
+
+// MarshalJSONWithOpts encodes the metadata as human readable JSON. Times are
+// formatted as RFC3339 and omitted when zero. Subscriptions are left out, as
+// their destinations may hold credentials.
+func (data *Data) MarshalJSONWithOpts(opts MarshalJSONOpts) ([]byte, error) {
+	out := dataJSON{
+		Term:            data.Term,
+		Index:           data.Index,
+		ClusterID:       data.ClusterID,
+		MaxNodeID:       data.MaxNodeID,
+		MaxShardGroupID: data.MaxShardGroupID,
+		MaxShardID:      data.MaxShardID,
+		MetaNodes:       make([]nodeJSON, len(data.MetaNodes)),
+		DataNodes:       make([]nodeJSON, len(data.DataNodes)),
+		Databases:       make([]databaseJSON, len(data.Databases)),
+		Users:           make([]userJSON, len(data.Users)),
+	}
+
+	for i, n := range data.MetaNodes {
+		out.MetaNodes[i] = nodeJSON{ID: n.ID, Addr: n.Addr, TCPAddr: n.TCPAddr, Zone: n.Zone}
+	}
+	for i, n := range data.DataNodes {
+		out.DataNodes[i] = nodeJSON{ID: n.ID, Addr: n.Addr, TCPAddr: n.TCPAddr, Zone: n.Zone}
+	}
+
+	for i, di := range data.Databases {
+		db := databaseJSON{
+			Name:                   di.Name,
+			DefaultRetentionPolicy: di.DefaultRetentionPolicy,
+			MaxSeriesPerDatabase:   di.MaxSeriesPerDatabase,
+			MaxValuesPerTag:        di.MaxValuesPerTag,
+			RetentionPolicies:      make([]retentionPolicyJSON, len(di.RetentionPolicies)),
+			ContinuousQueries:      make([]continuousQueryJSON, len(di.ContinuousQueries)),
+		}
+		for j, rpi := range di.RetentionPolicies {
+			rp := retentionPolicyJSON{
+				Name:               rpi.Name,
+				ReplicaN:           rpi.ReplicaN,
+				Duration:           rpi.Duration.String(),
+				ShardGroupDuration: rpi.ShardGroupDuration.String(),
+				ShardGroups:        make([]shardGroupJSON, len(rpi.ShardGroups)),
+			}
+			for k, sgi := range rpi.ShardGroups {
+				sg := shardGroupJSON{
+					ID:          sgi.ID,
+					StartTime:   formatJSONTime(sgi.StartTime),
+					EndTime:     formatJSONTime(sgi.EndTime),
+					DeletedAt:   formatJSONTime(sgi.DeletedAt),
+					TruncatedAt: formatJSONTime(sgi.TruncatedAt),
+					Shards:      make([]shardJSON, len(sgi.Shards)),
+				}
+				for l, si := range sgi.Shards {
+					sg.Shards[l].ID = si.ID
+					sg.Shards[l].Owners = make([]uint64, len(si.Owners))
+					for m, o := range si.Owners {
+						sg.Shards[l].Owners[m] = o.NodeID
+					}
+				}
+				rp.ShardGroups[k] = sg
+			}
+			db.RetentionPolicies[j] = rp
+		}
+		for j, cqi := range di.ContinuousQueries {
+			db.ContinuousQueries[j] = continuousQueryJSON{Name: cqi.Name, Query: cqi.Query, Enabled: cqi.Enabled}
+		}
+		out.Databases[i] = db
+	}
+
+	for i, ui := range data.Users {
+		u := userJSON{Name: ui.Name, Admin: ui.Admin, Privileges: make(map[string]string, len(ui.Privileges))}
+		if opts.IncludeHashes {
+			u.Hash = ui.Hash
+		}
+		for db, p := range ui.Privileges {
+			u.Privileges[db] = p.String()
+		}
+		out.Users[i] = u
+	}
+
+	return json.Marshal(out)
+}
+
+// formatJSONTime formats t as RFC3339 in UTC, or returns an empty string if t
+// is zero.
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+type dataJSON struct {
+	Term            uint64         `json:"term"`
+	Index           uint64         `json:"index"`
+	ClusterID       uint64         `json:"cluster-id"`
+	MaxNodeID       uint64         `json:"max-node-id"`
+	MaxShardGroupID uint64         `json:"max-shard-group-id"`
+	MaxShardID      uint64         `json:"max-shard-id"`
+	MetaNodes       []nodeJSON     `json:"meta-nodes"`
+	DataNodes       []nodeJSON     `json:"data-nodes"`
+	Databases       []databaseJSON `json:"databases"`
+	Users           []userJSON     `json:"users"`
+}
+
+type nodeJSON struct {
+	ID      uint64 `json:"id"`
+	Addr    string `json:"addr"`
+	TCPAddr string `json:"tcp-addr"`
+	Zone    string `json:"zone,omitempty"`
+}
+
+type databaseJSON struct {
+	Name                   string                `json:"name"`
+	DefaultRetentionPolicy string                `json:"default-retention-policy"`
+	MaxSeriesPerDatabase   int                   `json:"max-series-per-database,omitempty"`
+	MaxValuesPerTag        int                   `json:"max-values-per-tag,omitempty"`
+	RetentionPolicies      []retentionPolicyJSON `json:"retention-policies"`
+	ContinuousQueries      []continuousQueryJSON `json:"continuous-queries"`
+}
+
+type retentionPolicyJSON struct {
+	Name               string           `json:"name"`
+	ReplicaN           int              `json:"replica-n"`
+	Duration           string           `json:"duration"`
+	ShardGroupDuration string           `json:"shard-group-duration"`
+	ShardGroups        []shardGroupJSON `json:"shard-groups"`
+}
+
+type shardGroupJSON struct {
+	ID          uint64      `json:"id"`
+	StartTime   string      `json:"start-time"`
+	EndTime     string      `json:"end-time"`
+	DeletedAt   string      `json:"deleted-at,omitempty"`
+	TruncatedAt string      `json:"truncated-at,omitempty"`
+	Shards      []shardJSON `json:"shards"`
+}
+
+type shardJSON struct {
+	ID     uint64   `json:"id"`
+	Owners []uint64 `json:"owners"`
+}
+
+type continuousQueryJSON struct {
+	Name    string `json:"name"`
+	Query   string `json:"query"`
+	Enabled bool   `json:"enabled"`
+}
+
+type userJSON struct {
+	Name       string            `json:"name"`
+	Hash       string            `json:"hash,omitempty"`
+	Admin      bool              `json:"admin"`
+	Privileges map[string]string `json:"privileges"`
+}
+
 // TruncateShardGroups truncates any shard group that could contain timestamps beyond t.
 func (data *Data) TruncateShardGroups(t time.Time) {
 	for i := range data.Databases {
@@ -1265,6 +3780,41 @@ func (data *Data) PruneShardGroups() {
 	}
 }
 
+// PendingShardGroupDeletion describes a deleted shard group that has not been
+// pruned yet. PruneAt is when PruneShardGroups will remove it.
+type PendingShardGroupDeletion struct {
+	Database  string
+	Policy    string
+	ID        uint64
+	DeletedAt time.Time
+	PruneAt   time.Time
+}
+
+// ShardGroupsPendingDeletion returns the deleted shard groups that PruneShardGroups
+// would keep at now, sorted by prune time.
+func (data *Data) ShardGroupsPendingDeletion(now time.Time) []PendingShardGroupDeletion {
+	expiration := now.Add(ShardGroupDeletedExpiration)
+	var pending []PendingShardGroupDeletion
+	for _, d := range data.Databases {
+		for _, rp := range d.RetentionPolicies {
+			for _, sgi := range rp.ShardGroups {
+				if sgi.DeletedAt.IsZero() || expiration.After(sgi.DeletedAt) {
+					continue
+				}
+				pending = append(pending, PendingShardGroupDeletion{
+					Database:  d.Name,
+					Policy:    rp.Name,
+					ID:        sgi.ID,
+					DeletedAt: sgi.DeletedAt,
+					PruneAt:   sgi.DeletedAt.Add(-ShardGroupDeletedExpiration),
+				})
+			}
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].PruneAt.Before(pending[j].PruneAt) })
+	return pending
+}
+
 // hasAdminUser exhaustively checks for the presence of at least one admin
 // user.
 func (data *Data) hasAdminUser() bool {
@@ -1282,9 +3832,20 @@ func (data *Data) hasAdminUser() bool {
 // in the new metadata, along with a list of new databases created, both of which can assist in the import of existing
 // shard data during a database restore.
 func (data *Data) ImportData(other Data, backupDBName, restoreDBName, backupRPName, restoreRPName string) (map[uint64]uint64, []string, error) {
+	return data.importData(other, backupDBName, restoreDBName, backupRPName, restoreRPName, false)
+}
+
+// ImportDataMerge imports selected data into the current metadata like ImportData, but retention policies imported
+// into a database that already exists are added to it, and an error is only returned if one of their names is taken.
+func (data *Data) ImportDataMerge(other Data, backupDBName, restoreDBName, backupRPName, restoreRPName string) (map[uint64]uint64, []string, error) {
+	return data.importData(other, backupDBName, restoreDBName, backupRPName, restoreRPName, true)
+}
+
+// importData implements ImportData and ImportDataMerge.
+func (data *Data) importData(other Data, backupDBName, restoreDBName, backupRPName, restoreRPName string, merge bool) (map[uint64]uint64, []string, error) {
 	shardIDMap := make(map[uint64]uint64)
 	if backupDBName != "" {
-		dbName, err := data.importOneDB(other, backupDBName, restoreDBName, backupRPName, restoreRPName, shardIDMap)
+		dbName, err := data.importOneDB(other, backupDBName, restoreDBName, backupRPName, restoreRPName, merge, shardIDMap)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1299,7 +3860,7 @@ func (data *Data) ImportData(other Data, backupDBName, restoreDBName, backupRPNa
 		if dbi.Name == "_internal" {
 			continue
 		}
-		dbName, err := data.importOneDB(other, dbi.Name, "", "", "", shardIDMap)
+		dbName, err := data.importOneDB(other, dbi.Name, "", "", "", merge, shardIDMap)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1309,7 +3870,7 @@ func (data *Data) ImportData(other Data, backupDBName, restoreDBName, backupRPNa
 }
 
 // importOneDB imports a single database/rp from an external metadata object, renaming them if new names are provided.
-func (data *Data) importOneDB(other Data, backupDBName, restoreDBName, backupRPName, restoreRPName string, shardIDMap map[uint64]uint64) (string, error) {
+func (data *Data) importOneDB(other Data, backupDBName, restoreDBName, backupRPName, restoreRPName string, merge bool, shardIDMap map[uint64]uint64) (string, error) {
 
 	dbPtr := other.Database(backupDBName)
 	if dbPtr == nil {
@@ -1320,17 +3881,14 @@ func (data *Data) importOneDB(other Data, backupDBName, restoreDBName, backupRPN
 		restoreDBName = backupDBName
 	}
 
-	if data.Database(restoreDBName) != nil {
+	existing := data.Database(restoreDBName)
+	if existing != nil && !merge {
 		return "", errors.New("database already exists")
 	}
 
 	// change the names if we want/need to
-	err := data.CreateDatabase(restoreDBName)
-	if err != nil {
-		return "", err
-	}
-	dbImport := data.Database(restoreDBName)
-
+	var rpImports []RetentionPolicyInfo
+	var defaultRP string
 	if backupRPName != "" {
 		rpPtr := dbPtr.RetentionPolicy(backupRPName)
 
@@ -1340,25 +3898,33 @@ func (data *Data) importOneDB(other Data, backupDBName, restoreDBName, backupRPN
 				restoreRPName = backupRPName
 			}
 			rpImport.Name = restoreRPName
-			dbImport.RetentionPolicies = []RetentionPolicyInfo{rpImport}
-			dbImport.DefaultRetentionPolicy = restoreRPName
+			rpImports = []RetentionPolicyInfo{rpImport}
+			defaultRP = restoreRPName
 		} else {
 			return "", fmt.Errorf("retention Policy not found in meta backup: %s.%s", backupDBName, backupRPName)
 		}
 
 	} else { // import all RP's without renaming
-		dbImport.DefaultRetentionPolicy = dbPtr.DefaultRetentionPolicy
-		if dbPtr.RetentionPolicies != nil {
-			dbImport.RetentionPolicies = make([]RetentionPolicyInfo, len(dbPtr.RetentionPolicies))
-			for i := range dbPtr.RetentionPolicies {
-				dbImport.RetentionPolicies[i] = dbPtr.RetentionPolicies[i].clone()
-			}
+		defaultRP = dbPtr.DefaultRetentionPolicy
+		for i := range dbPtr.RetentionPolicies {
+			rpImports = append(rpImports, dbPtr.RetentionPolicies[i].clone())
 		}
+	}
 
+	// When merging, check every name before anything is changed.
+	if existing != nil {
+		for _, rpImport := range rpImports {
+			if existing.RetentionPolicy(rpImport.Name) != nil {
+				return "", fmt.Errorf("retention policy already exists: %s.%s", restoreDBName, rpImport.Name)
+			}
+		}
+	} else if err := data.CreateDatabase(restoreDBName); err != nil {
+		return "", err
 	}
+	dbImport := data.Database(restoreDBName)
 
 	// renumber the shard groups and shards for the new retention policy(ies)
-	for _, rpImport := range dbImport.RetentionPolicies {
+	for _, rpImport := range rpImports {
 		for j, sgImport := range rpImport.ShardGroups {
 			data.MaxShardGroupID++
 			rpImport.ShardGroups[j].ID = data.MaxShardGroupID
@@ -1373,14 +3939,107 @@ func (data *Data) importOneDB(other Data, backupDBName, restoreDBName, backupRPN
 		}
 	}
 
+	// A merged database keeps its default retention policy, if it has one.
+	dbImport.RetentionPolicies = append(dbImport.RetentionPolicies, rpImports...)
+	if dbImport.DefaultRetentionPolicy == "" {
+		dbImport.DefaultRetentionPolicy = defaultRP
+	}
+
 	return restoreDBName, nil
 }
 
+// MergeFrom imports every database in other into data under prefix, for
+// building a combined view over several clusters. Data and meta nodes are
+// merged by TCP address, and shard group, shard and owner node IDs are remapped
+// into data's ID space. An error is returned, and data is left unchanged, if
+// any prefixed database name already exists.
+func (data *Data) MergeFrom(other *Data, prefix string) error {
+	for _, dbi := range other.Databases {
+		if data.Database(prefix+dbi.Name) != nil {
+			return fmt.Errorf("merge: database %s already exists", prefix+dbi.Name)
+		}
+	}
+
+	// Map other's node IDs onto existing nodes with the same TCP address,
+	// creating the nodes that are missing.
+	nodeIDMap := make(map[uint64]uint64)
+	for _, n := range other.DataNodes {
+		if err := data.CreateDataNodeWithZone(n.Addr, n.TCPAddr, n.Zone); err != nil && err != ErrNodeExists {
+			return err
+		}
+		if dn := data.DataNodeByTCPAddr(n.TCPAddr); dn != nil {
+			nodeIDMap[n.ID] = dn.ID
+		}
+	}
+	for _, n := range other.MetaNodes {
+		if mn := data.MetaNodeByTCPAddr(n.TCPAddr); mn != nil {
+			nodeIDMap[n.ID] = mn.ID
+			continue
+		}
+		if err := data.CreateMetaNodeWithZone(n.Addr, n.TCPAddr, n.Zone); err != nil {
+			return err
+		}
+		if mn := data.MetaNodeByTCPAddr(n.TCPAddr); mn != nil {
+			nodeIDMap[n.ID] = mn.ID
+		}
+	}
+
+	// Remember the original owners; importOneDB clears them while renumbering.
+	owners := make(map[uint64][]ShardOwner)
+	for _, dbi := range other.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					owners[si.ID] = si.Owners
+				}
+			}
+		}
+	}
+
+	for _, dbi := range other.Databases {
+		shardIDMap := make(map[uint64]uint64)
+		name, err := data.importOneDB(*other, dbi.Name, prefix+dbi.Name, "", "", false, shardIDMap)
+		if err != nil {
+			return err
+		}
+
+		oldShardIDs := make(map[uint64]uint64, len(shardIDMap))
+		for oldID, newID := range shardIDMap {
+			oldShardIDs[newID] = oldID
+		}
+
+		db := data.Database(name)
+		for i := range db.RetentionPolicies {
+			rpi := &db.RetentionPolicies[i]
+			for j := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[j]
+				for k := range sgi.Shards {
+					si := &sgi.Shards[k]
+					for _, o := range owners[oldShardIDs[si.ID]] {
+						owner := o.clone()
+						owner.NodeID = nodeIDMap[o.NodeID]
+						si.Owners = append(si.Owners, owner)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // NodeInfo represents information about a single node in the cluster.
 type NodeInfo struct {
 	ID      uint64
 	Addr    string
 	TCPAddr string
+
+	// Zone is the failure domain, such as a rack, the node runs in.
+	Zone string
+
+	// Status is NodeStatusJoined or NodeStatusDisjoined for data nodes, and
+	// empty for meta nodes.
+	Status string
 }
 
 // clone returns a deep copy of ni.
@@ -1392,6 +4051,12 @@ func (ni NodeInfo) marshal() *internal.NodeInfo {
 	pb.ID = proto.Uint64(ni.ID)
 	pb.Addr = proto.String(ni.Addr)
 	pb.TCPAddr = proto.String(ni.TCPAddr)
+	if ni.Zone != "" {
+		pb.Zone = proto.String(ni.Zone)
+	}
+	if ni.Status != "" {
+		pb.Status = proto.String(ni.Status)
+	}
 	return pb
 }
 
@@ -1400,6 +4065,8 @@ func (ni *NodeInfo) unmarshal(pb *internal.NodeInfo) {
 	ni.ID = pb.GetID()
 	ni.Addr = pb.GetAddr()
 	ni.TCPAddr = pb.GetTCPAddr()
+	ni.Zone = pb.GetZone()
+	ni.Status = pb.GetStatus()
 }
 
 // NodeInfos is a slice of NodeInfo used for sorting
@@ -1420,6 +4087,16 @@ type DatabaseInfo struct {
 	DefaultRetentionPolicy string
 	RetentionPolicies      []RetentionPolicyInfo
 	ContinuousQueries      []ContinuousQueryInfo
+
+	// DefaultShardGroupDuration is the shard group duration of retention
+	// policies created without one, when non-zero. Otherwise the duration is
+	// derived from the retention policy's duration.
+	DefaultShardGroupDuration time.Duration
+
+	// MaxSeriesPerDatabase and MaxValuesPerTag limit the cardinality of the
+	// database on every data node. Zero means unlimited.
+	MaxSeriesPerDatabase int
+	MaxValuesPerTag      int
 }
 
 // RetentionPolicy returns a retention policy by name.
@@ -1439,6 +4116,16 @@ func (di DatabaseInfo) RetentionPolicy(name string) *RetentionPolicyInfo {
 	return nil
 }
 
+// shardGroupDuration returns the database's default shard group duration for a
+// retention policy with duration d. Zero is returned when the database has no
+// default or the default exceeds a finite d.
+func (di DatabaseInfo) shardGroupDuration(d time.Duration) time.Duration {
+	if d > 0 && di.DefaultShardGroupDuration > d {
+		return 0
+	}
+	return di.DefaultShardGroupDuration
+}
+
 // ShardInfos returns a list of all shards' info for the database.
 func (di DatabaseInfo) ShardInfos() []ShardInfo {
 	shards := map[uint64]*ShardInfo{}
@@ -1491,6 +4178,15 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	pb := &internal.DatabaseInfo{}
 	pb.Name = proto.String(di.Name)
 	pb.DefaultRetentionPolicy = proto.String(di.DefaultRetentionPolicy)
+	if di.DefaultShardGroupDuration > 0 {
+		pb.DefaultShardGroupDuration = proto.Int64(int64(di.DefaultShardGroupDuration))
+	}
+	if di.MaxSeriesPerDatabase > 0 {
+		pb.MaxSeriesPerDatabase = proto.Int64(int64(di.MaxSeriesPerDatabase))
+	}
+	if di.MaxValuesPerTag > 0 {
+		pb.MaxValuesPerTag = proto.Int64(int64(di.MaxValuesPerTag))
+	}
 
 	pb.RetentionPolicies = make([]*internal.RetentionPolicyInfo, len(di.RetentionPolicies))
 	for i := range di.RetentionPolicies {
@@ -1508,6 +4204,9 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 	di.Name = pb.GetName()
 	di.DefaultRetentionPolicy = pb.GetDefaultRetentionPolicy()
+	di.DefaultShardGroupDuration = time.Duration(pb.GetDefaultShardGroupDuration())
+	di.MaxSeriesPerDatabase = int(pb.GetMaxSeriesPerDatabase())
+	di.MaxValuesPerTag = int(pb.GetMaxValuesPerTag())
 
 	if len(pb.GetRetentionPolicies()) > 0 {
 		di.RetentionPolicies = make([]RetentionPolicyInfo, len(pb.GetRetentionPolicies()))
@@ -1530,6 +4229,15 @@ type RetentionPolicySpec struct {
 	ReplicaN           *int
 	Duration           *time.Duration
 	ShardGroupDuration time.Duration
+
+	// AllowExcessReplicaN permits a ReplicaN above the number of data nodes
+	// when the policy is created. It isn't stored with the policy.
+	AllowExcessReplicaN bool
+
+	// StrictShardGroupDuration rejects a finite duration that isn't a multiple
+	// of the shard group duration when the policy is created, like
+	// CreateRetentionPolicyOpts. It isn't stored with the policy.
+	StrictShardGroupDuration bool
 }
 
 // NewRetentionPolicyInfo creates a new retention policy info from the specification.
@@ -1616,6 +4324,26 @@ type RetentionPolicyInfo struct {
 	ShardGroupDuration time.Duration
 	ShardGroups        []ShardGroupInfo
 	Subscriptions      []SubscriptionInfo
+
+	// WriteAffinityTag, when set, names the tag whose value alone selects
+	// the shard a point is written to, keeping series that share the value
+	// in one shard.
+	WriteAffinityTag string
+
+	// PendingShardCount, when non-zero, is the number of shards in each
+	// shard group created from now on. Existing groups are unaffected.
+	PendingShardCount int
+
+	// PreCreateCount is the number of shard groups after the current one
+	// that are created ahead of time by PreCreateShardGroups.
+	PreCreateCount int
+
+	// MeasurementRetention overrides Duration for the named measurements.
+	// A zero duration keeps the measurement forever.
+	MeasurementRetention map[string]time.Duration
+
+	// ReadOnly marks an archival policy that rejects all writes.
+	ReadOnly bool
 }
 
 // NewRetentionPolicyInfo returns a new instance of RetentionPolicyInfo
@@ -1693,6 +4421,54 @@ func (rpi *RetentionPolicyInfo) DeletedShardGroups() []*ShardGroupInfo {
 	return groups
 }
 
+// ShardGroupTimelineEntry is a compact description of a shard group's time range
+// and state. Truncated and Deleted are zero unless the group was truncated or
+// deleted.
+type ShardGroupTimelineEntry struct {
+	ID        uint64
+	Start     time.Time
+	End       time.Time
+	Truncated time.Time
+	Deleted   time.Time
+}
+
+// WriteWindow returns the earliest and latest timestamps the policy accepts
+// writes for at now. Points older than the policy's duration are dropped, so
+// min is now less the duration, or the minimum time for an infinite policy.
+// Future writes are not limited, so max is always the maximum time.
+func (rpi *RetentionPolicyInfo) WriteWindow(now time.Time) (min, max time.Time) {
+	return rpi.MinAcceptableWriteTime(now), time.Unix(0, models.MaxNanoTime)
+}
+
+// MinAcceptableWriteTime returns the oldest timestamp the policy accepts writes
+// for at now: now less the policy's duration, or the minimum time for an
+// infinite policy. Older points would be dropped as soon as they are written.
+func (rpi *RetentionPolicyInfo) MinAcceptableWriteTime(now time.Time) time.Time {
+	if rpi.Duration > 0 {
+		return now.Add(-rpi.Duration)
+	}
+	return time.Unix(0, models.MinNanoTime)
+}
+
+// ShardGroupTimeline returns every shard group of the policy, including deleted
+// ones, sorted by start time.
+func (rpi *RetentionPolicyInfo) ShardGroupTimeline() []ShardGroupTimelineEntry {
+	timeline := make([]ShardGroupTimelineEntry, 0, len(rpi.ShardGroups))
+	for _, sgi := range rpi.ShardGroups {
+		timeline = append(timeline, ShardGroupTimelineEntry{
+			ID:        sgi.ID,
+			Start:     sgi.StartTime,
+			End:       sgi.EndTime,
+			Truncated: sgi.TruncatedAt,
+			Deleted:   sgi.DeletedAt,
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Start.Before(timeline[j].Start)
+	})
+	return timeline
+}
+
 // marshal serializes to a protobuf representation.
 func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 	pb := &internal.RetentionPolicyInfo{
@@ -1702,6 +4478,25 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		ShardGroupDuration: proto.Int64(int64(rpi.ShardGroupDuration)),
 	}
 
+	if rpi.WriteAffinityTag != "" {
+		pb.WriteAffinityTag = proto.String(rpi.WriteAffinityTag)
+	}
+	if rpi.PendingShardCount > 0 {
+		pb.PendingShardCount = proto.Uint32(uint32(rpi.PendingShardCount))
+	}
+	if rpi.PreCreateCount > 0 {
+		pb.PreCreateCount = proto.Uint32(uint32(rpi.PreCreateCount))
+	}
+	if rpi.ReadOnly {
+		pb.ReadOnly = proto.Bool(true)
+	}
+	if len(rpi.MeasurementRetention) > 0 {
+		pb.MeasurementRetention = make(map[string]int64, len(rpi.MeasurementRetention))
+		for name, d := range rpi.MeasurementRetention {
+			pb.MeasurementRetention[name] = int64(d)
+		}
+	}
+
 	pb.ShardGroups = make([]*internal.ShardGroupInfo, len(rpi.ShardGroups))
 	for i, sgi := range rpi.ShardGroups {
 		pb.ShardGroups[i] = sgi.marshal()
@@ -1721,6 +4516,18 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 	rpi.ReplicaN = int(pb.GetReplicaN())
 	rpi.Duration = time.Duration(pb.GetDuration())
 	rpi.ShardGroupDuration = time.Duration(pb.GetShardGroupDuration())
+	rpi.WriteAffinityTag = pb.GetWriteAffinityTag()
+	rpi.PendingShardCount = int(pb.GetPendingShardCount())
+	rpi.PreCreateCount = int(pb.GetPreCreateCount())
+	rpi.ReadOnly = pb.GetReadOnly()
+
+	rpi.MeasurementRetention = nil
+	if len(pb.GetMeasurementRetention()) > 0 {
+		rpi.MeasurementRetention = make(map[string]time.Duration, len(pb.GetMeasurementRetention()))
+		for name, d := range pb.GetMeasurementRetention() {
+			rpi.MeasurementRetention[name] = time.Duration(d)
+		}
+	}
 
 	if len(pb.GetShardGroups()) > 0 {
 		rpi.ShardGroups = make([]ShardGroupInfo, len(pb.GetShardGroups()))
@@ -1739,6 +4546,7 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 // clone returns a deep copy of rpi.
 func (rpi RetentionPolicyInfo) clone() RetentionPolicyInfo {
 	other := rpi
+	other.MeasurementRetention = cloneMeasurementRetention(rpi.MeasurementRetention)
 
 	if rpi.ShardGroups != nil {
 		other.ShardGroups = make([]ShardGroupInfo, len(rpi.ShardGroups))
@@ -1750,6 +4558,43 @@ func (rpi RetentionPolicyInfo) clone() RetentionPolicyInfo {
 	return other
 }
 
+// CloneMeta returns a copy of the policy's scalar fields only. ShardGroups and
+// Subscriptions are nil, which makes it much cheaper than a full copy for
+// callers that only need the policy's settings.
+func (rpi RetentionPolicyInfo) CloneMeta() RetentionPolicyInfo {
+	other := rpi
+	other.ShardGroups = nil
+	other.Subscriptions = nil
+	other.MeasurementRetention = cloneMeasurementRetention(rpi.MeasurementRetention)
+	return other
+}
+
+// cloneMeasurementRetention returns a copy of m.
+func cloneMeasurementRetention(m map[string]time.Duration) map[string]time.Duration {
+	if m == nil {
+		return nil
+	}
+	other := make(map[string]time.Duration, len(m))
+	for name, d := range m {
+		other[name] = d
+	}
+	return other
+}
+
+// RetentionFor returns how long data in measurement is retained: its entry in
+// MeasurementRetention, or the policy's Duration if it has none.
+func (rpi *RetentionPolicyInfo) RetentionFor(measurement string) time.Duration {
+	if d, ok := rpi.MeasurementRetention[measurement]; ok {
+		return d
+	}
+	return rpi.Duration
+}
+
+// WritesAllowed returns false if the policy is read-only.
+func (rpi *RetentionPolicyInfo) WritesAllowed() bool {
+	return !rpi.ReadOnly
+}
+
 // MarshalBinary encodes rpi to a binary format.
 func (rpi *RetentionPolicyInfo) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(rpi.marshal())
@@ -1761,6 +4606,11 @@ func (rpi *RetentionPolicyInfo) UnmarshalBinary(data []byte) error {
 	if err := proto.Unmarshal(data, &pb); err != nil {
 		return err
 	}
+	for _, sg := range pb.ShardGroups {
+		for _, sh := range sg.Shards {
+			migrateShardOwnerIDs(sh)
+		}
+	}
 	rpi.unmarshal(&pb)
 	return nil
 }
@@ -1789,6 +4639,60 @@ func normalisedShardDuration(sgd, d time.Duration) time.Duration {
 	return sgd
 }
 
+// ShardGroupDurationDivisor returns the shard group duration nearest to
+// shardGroupDuration that divides a retention duration evenly, preferring the
+// shorter of two equally near durations. Suggestions are whole hours where the
+// retention duration allows, and never less than MinRetentionPolicyDuration.
+// shardGroupDuration is returned if it already divides duration or duration is
+// infinite.
+func ShardGroupDurationDivisor(duration, shardGroupDuration time.Duration) time.Duration {
+	if duration <= 0 || shardGroupDuration <= 0 || duration%shardGroupDuration == 0 {
+		return shardGroupDuration
+	}
+
+	unit := time.Duration(0)
+	for _, u := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if duration%u == 0 {
+			unit = u
+			break
+		}
+	}
+	if unit == 0 {
+		return duration
+	}
+
+	distance := func(d time.Duration) time.Duration {
+		if d < shardGroupDuration {
+			return shardGroupDuration - d
+		}
+		return d - shardGroupDuration
+	}
+
+	best := duration
+	n := int64(duration / unit)
+	for k := int64(1); k*k <= n; k++ {
+		if n%k != 0 {
+			continue
+		}
+		for _, d := range []time.Duration{time.Duration(k) * unit, time.Duration(n/k) * unit} {
+			if d < MinRetentionPolicyDuration {
+				continue
+			}
+			if dist, bestDist := distance(d), distance(best); dist < bestDist || dist == bestDist && d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// NormalizedShardGroupDuration returns the shard group duration the server
+// uses for a retention policy created with the given durations. A zero shard
+// group duration is derived from the retention duration.
+func NormalizedShardGroupDuration(shardGroupDuration, retentionDuration time.Duration) time.Duration {
+	return normalisedShardDuration(shardGroupDuration, retentionDuration)
+}
+
 // ShardGroupInfo represents metadata about a shard group. The DeletedAt field is important
 // because it makes it clear that a ShardGroup has been marked as deleted, and allow the system
 // to be sure that a ShardGroup is not simply missing. If the DeletedAt is set, the system can
@@ -1800,6 +4704,10 @@ type ShardGroupInfo struct {
 	DeletedAt   time.Time
 	Shards      []ShardInfo
 	TruncatedAt time.Time
+
+	// ReplicaN overrides the retention policy's replication factor for this
+	// shard group when non-zero. See EffectiveReplicaN.
+	ReplicaN int
 }
 
 // ShardGroupInfos implements sort.Interface on []ShardGroupInfo, based
@@ -1851,6 +4759,46 @@ func (sgi *ShardGroupInfo) Truncated() bool {
 	return !sgi.TruncatedAt.IsZero()
 }
 
+// ExpireTime returns the time at which the shard group falls out of a retention
+// policy with the given duration. A zero duration never expires, so the zero
+// time is returned.
+func (sgi *ShardGroupInfo) ExpireTime(rpDuration time.Duration) time.Time {
+	if rpDuration == 0 {
+		return time.Time{}
+	}
+	return sgi.EndTime.Add(rpDuration)
+}
+
+// EffectiveReplicaN returns the replication factor of the shard group: its own
+// ReplicaN when set, and otherwise rpReplicaN, the retention policy's.
+func (sgi *ShardGroupInfo) EffectiveReplicaN(rpReplicaN int) int {
+	if sgi.ReplicaN > 0 {
+		return sgi.ReplicaN
+	}
+	return rpReplicaN
+}
+
+// OwnedByAny returns whether nodeID owns any shard in the shard group.
+func (sgi *ShardGroupInfo) OwnedByAny(nodeID uint64) bool {
+	for _, si := range sgi.Shards {
+		if si.OwnedBy(nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShardsOwnedBy returns the shards in the shard group owned by nodeID.
+func (sgi *ShardGroupInfo) ShardsOwnedBy(nodeID uint64) []ShardInfo {
+	var shards []ShardInfo
+	for _, si := range sgi.Shards {
+		if si.OwnedBy(nodeID) {
+			shards = append(shards, si)
+		}
+	}
+	return shards
+}
+
 // clone returns a deep copy of sgi.
 func (sgi ShardGroupInfo) clone() ShardGroupInfo {
 	other := sgi
@@ -1877,6 +4825,28 @@ func (sgi *ShardGroupInfo) ShardFor(p hashIDer) ShardInfo {
 	return sgi.Shards[p.HashID()%uint64(len(sgi.Shards))]
 }
 
+// ShardForTag returns the ShardInfo for p using only the value of tag, so that
+// points sharing that value land in the same shard. If tag is empty or p does
+// not carry it, the shard is selected as in ShardFor.
+func (sgi *ShardGroupInfo) ShardForTag(p hashIDer, tag string) ShardInfo {
+	if tag == "" || len(sgi.Shards) == 1 {
+		return sgi.ShardFor(p)
+	}
+
+	tp, ok := p.(interface{ Tags() models.Tags })
+	if !ok {
+		return sgi.ShardFor(p)
+	}
+	v := tp.Tags().Get([]byte(tag))
+	if len(v) == 0 {
+		return sgi.ShardFor(p)
+	}
+
+	h := models.NewInlineFNV64a()
+	h.Write(v)
+	return sgi.Shards[h.Sum64()%uint64(len(sgi.Shards))]
+}
+
 // marshal serializes to a protobuf representation.
 func (sgi *ShardGroupInfo) marshal() *internal.ShardGroupInfo {
 	pb := &internal.ShardGroupInfo{
@@ -1889,6 +4859,9 @@ func (sgi *ShardGroupInfo) marshal() *internal.ShardGroupInfo {
 	if !sgi.TruncatedAt.IsZero() {
 		pb.TruncatedAt = proto.Int64(MarshalTime(sgi.TruncatedAt))
 	}
+	if sgi.ReplicaN > 0 {
+		pb.ReplicaN = proto.Uint32(uint32(sgi.ReplicaN))
+	}
 
 	pb.Shards = make([]*internal.ShardInfo, len(sgi.Shards))
 	for i := range sgi.Shards {
@@ -1916,6 +4889,7 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 	if pb != nil && pb.TruncatedAt != nil {
 		sgi.TruncatedAt = UnmarshalTime(pb.GetTruncatedAt())
 	}
+	sgi.ReplicaN = int(pb.GetReplicaN())
 
 	if len(pb.GetShards()) > 0 {
 		sgi.Shards = make([]ShardInfo, len(pb.GetShards()))
@@ -1929,6 +4903,10 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 type ShardInfo struct {
 	ID     uint64
 	Owners []ShardOwner
+
+	// LastWrite is the latest time recorded by TouchShard for a successful
+	// write to the shard. It is zero if no write was recorded.
+	LastWrite time.Time
 }
 
 // OwnedBy determines whether the shard's owner IDs includes nodeID.
@@ -1966,6 +4944,10 @@ func (si ShardInfo) marshal() *internal.ShardInfo {
 		pb.Owners[i] = si.Owners[i].marshal()
 	}
 
+	if !si.LastWrite.IsZero() {
+		pb.LastWrite = proto.Int64(MarshalTime(si.LastWrite))
+	}
+
 	return pb
 }
 
@@ -1975,28 +4957,38 @@ func (si *ShardInfo) UnmarshalBinary(buf []byte) error {
 	if err := proto.Unmarshal(buf, &pb); err != nil {
 		return err
 	}
+	migrateShardOwnerIDs(&pb)
 	si.unmarshal(&pb)
 	return nil
 }
 
+// migrateShardOwnerIDs converts the deprecated OwnerIDs of pb to Owners.
+func migrateShardOwnerIDs(pb *internal.ShardInfo) {
+	if len(pb.OwnerIDs) == 0 {
+		return
+	}
+	pb.Owners = make([]*internal.ShardOwner, len(pb.OwnerIDs))
+	for i, x := range pb.OwnerIDs {
+		pb.Owners[i] = &internal.ShardOwner{NodeID: proto.Uint64(x)}
+	}
+	pb.OwnerIDs = nil
+}
+
 // unmarshal deserializes from a protobuf representation.
 func (si *ShardInfo) unmarshal(pb *internal.ShardInfo) {
 	si.ID = pb.GetID()
 
-	// If deprecated "OwnerIDs" exists then convert it to "Owners" format.
-	if len(pb.GetOwnerIDs()) > 0 {
-		si.Owners = make([]ShardOwner, len(pb.GetOwnerIDs()))
-		for i, x := range pb.GetOwnerIDs() {
-			si.Owners[i].unmarshal(&internal.ShardOwner{
-				NodeID: proto.Uint64(x),
-			})
-		}
-	} else if len(pb.GetOwners()) > 0 {
+	if len(pb.GetOwners()) > 0 {
 		si.Owners = make([]ShardOwner, len(pb.GetOwners()))
 		for i, x := range pb.GetOwners() {
 			si.Owners[i].unmarshal(x)
 		}
 	}
+
+	si.LastWrite = time.Time{}
+	if pb.LastWrite != nil {
+		si.LastWrite = UnmarshalTime(pb.GetLastWrite())
+	}
 }
 
 // SubscriptionInfo holds the subscription information.
@@ -2004,6 +4996,10 @@ type SubscriptionInfo struct {
 	Name         string
 	Mode         string
 	Destinations []string
+
+	// Measurements limits the points sent to the subscription to those of
+	// the named measurements. Empty sends every point.
+	Measurements []string
 }
 
 // marshal serializes to a protobuf representation.
@@ -2017,6 +5013,11 @@ func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 	for i := range si.Destinations {
 		pb.Destinations[i] = si.Destinations[i]
 	}
+
+	if len(si.Measurements) > 0 {
+		pb.Measurements = make([]string, len(si.Measurements))
+		copy(pb.Measurements, si.Measurements)
+	}
 	return pb
 }
 
@@ -2029,11 +5030,37 @@ func (si *SubscriptionInfo) unmarshal(pb *internal.SubscriptionInfo) {
 		si.Destinations = make([]string, len(pb.GetDestinations()))
 		copy(si.Destinations, pb.GetDestinations())
 	}
+
+	if len(pb.GetMeasurements()) > 0 {
+		si.Measurements = make([]string, len(pb.GetMeasurements()))
+		copy(si.Measurements, pb.GetMeasurements())
+	}
 }
 
 // ShardOwner represents a node that owns a shard.
 type ShardOwner struct {
 	NodeID uint64
+
+	// State is the state of the node's replica of the shard. An empty state
+	// is ShardStateHot.
+	State string
+}
+
+// Shard owner states.
+const (
+	// ShardStateHot is a complete replica that can serve reads.
+	ShardStateHot = "hot"
+
+	// ShardStateCopying is a replica still being copied to the node.
+	ShardStateCopying = "copying"
+
+	// ShardStateStale is a replica known to be missing writes.
+	ShardStateStale = "stale"
+)
+
+// Hot returns true if the replica can serve reads.
+func (so ShardOwner) Hot() bool {
+	return so.State == "" || so.State == ShardStateHot
 }
 
 // clone returns a deep copy of so.
@@ -2043,20 +5070,29 @@ func (so ShardOwner) clone() ShardOwner {
 
 // marshal serializes to a protobuf representation.
 func (so ShardOwner) marshal() *internal.ShardOwner {
-	return &internal.ShardOwner{
+	pb := &internal.ShardOwner{
 		NodeID: proto.Uint64(so.NodeID),
 	}
+	if so.State != "" {
+		pb.State = proto.String(so.State)
+	}
+	return pb
 }
 
 // unmarshal deserializes from a protobuf representation.
 func (so *ShardOwner) unmarshal(pb *internal.ShardOwner) {
 	so.NodeID = pb.GetNodeID()
+	so.State = pb.GetState()
 }
 
 // ContinuousQueryInfo represents metadata about a continuous query.
 type ContinuousQueryInfo struct {
 	Name  string
 	Query string
+
+	// Enabled is false while the query is paused. Queries stored before the
+	// flag existed are enabled.
+	Enabled bool
 }
 
 // clone returns a deep copy of cqi.
@@ -2065,8 +5101,9 @@ func (cqi ContinuousQueryInfo) clone() ContinuousQueryInfo { return cqi }
 // marshal serializes to a protobuf representation.
 func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 	return &internal.ContinuousQueryInfo{
-		Name:  proto.String(cqi.Name),
-		Query: proto.String(cqi.Query),
+		Name:    proto.String(cqi.Name),
+		Query:   proto.String(cqi.Query),
+		Enabled: proto.Bool(cqi.Enabled),
 	}
 }
 
@@ -2074,6 +5111,7 @@ func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Name = pb.GetName()
 	cqi.Query = pb.GetQuery()
+	cqi.Enabled = pb.Enabled == nil || pb.GetEnabled()
 }
 
 var _ query.FineAuthorizer = (*UserInfo)(nil)
@@ -2089,8 +5127,16 @@ type UserInfo struct {
 	// Whether the user is an admin, i.e. allowed to do everything.
 	Admin bool
 
+	// When the user was last promoted to admin. Nil if the user is not an
+	// admin or was made one without a recorded time.
+	AdminSince *time.Time
+
 	// Map of database name to granted privilege.
 	Privileges map[string]influxql.Privilege
+
+	// Map of database name to the privilege granted through the user's roles.
+	// It is derived from Data.Roles and never modified in place.
+	rolePrivileges map[string]influxql.Privilege
 }
 
 type User interface {
@@ -2108,8 +5154,8 @@ func (ui *UserInfo) AuthorizeDatabase(privilege influxql.Privilege, database str
 	if ui.Admin || privilege == influxql.NoPrivileges {
 		return true
 	}
-	p, ok := ui.Privileges[database]
-	return ok && (p == privilege || p == influxql.AllPrivileges)
+	p := unionPrivilege(ui.Privileges[database], ui.rolePrivileges[database])
+	return p != influxql.NoPrivileges && (p == privilege || p == influxql.AllPrivileges)
 }
 
 // AuthorizeSeriesRead is used to limit access per-series (enterprise only)
@@ -2138,6 +5184,11 @@ func (u *UserInfo) AuthorizeUnrestricted() bool {
 func (ui UserInfo) clone() UserInfo {
 	other := ui
 
+	if ui.AdminSince != nil {
+		t := *ui.AdminSince
+		other.AdminSince = &t
+	}
+
 	if ui.Privileges != nil {
 		other.Privileges = make(map[string]influxql.Privilege)
 		for k, v := range ui.Privileges {
@@ -2156,6 +5207,10 @@ func (ui UserInfo) marshal() *internal.UserInfo {
 		Admin: proto.Bool(ui.Admin),
 	}
 
+	if ui.AdminSince != nil {
+		pb.AdminSince = proto.Int64(MarshalTime(*ui.AdminSince))
+	}
+
 	for database, privilege := range ui.Privileges {
 		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
 			Database:  proto.String(database),
@@ -2172,12 +5227,259 @@ func (ui *UserInfo) unmarshal(pb *internal.UserInfo) {
 	ui.Hash = pb.GetHash()
 	ui.Admin = pb.GetAdmin()
 
+	ui.AdminSince = nil
+	if pb != nil && pb.AdminSince != nil {
+		t := UnmarshalTime(pb.GetAdminSince())
+		ui.AdminSince = &t
+	}
+
 	ui.Privileges = make(map[string]influxql.Privilege)
 	for _, p := range pb.GetPrivileges() {
 		ui.Privileges[p.GetDatabase()] = influxql.Privilege(p.GetPrivilege())
 	}
 }
 
+// RoleInfo represents metadata about a role in the system. Its users are
+// granted its privileges in addition to their own.
+type RoleInfo struct {
+	// Role's name.
+	Name string
+
+	// Sorted names of the role's users.
+	Users []string
+
+	// Map of database name to granted privilege.
+	Privileges map[string]influxql.Privilege
+}
+
+// hasUser returns true if username is a member of the role.
+func (ri *RoleInfo) hasUser(username string) bool {
+	for _, u := range ri.Users {
+		if u == username {
+			return true
+		}
+	}
+	return false
+}
+
+// removeUser removes username from the role's users.
+func (ri *RoleInfo) removeUser(username string) {
+	for i, u := range ri.Users {
+		if u == username {
+			ri.Users = append(ri.Users[:i], ri.Users[i+1:]...)
+			return
+		}
+	}
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+
+	if ri.Users != nil {
+		other.Users = make([]string, len(ri.Users))
+		copy(other.Users, ri.Users)
+	}
+
+	if ri.Privileges != nil {
+		other.Privileges = make(map[string]influxql.Privilege)
+		for k, v := range ri.Privileges {
+			other.Privileges[k] = v
+		}
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *internal.RoleInfo {
+	pb := &internal.RoleInfo{
+		Name:  proto.String(ri.Name),
+		Users: ri.Users,
+	}
+
+	for database, privilege := range ri.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(privilege)),
+		})
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *internal.RoleInfo) {
+	ri.Name = pb.GetName()
+	ri.Users = pb.GetUsers()
+
+	ri.Privileges = make(map[string]influxql.Privilege)
+	for _, p := range pb.GetPrivileges() {
+		ri.Privileges[p.GetDatabase()] = influxql.Privilege(p.GetPrivilege())
+	}
+}
+
+// DiffSet lists the keys of the items added, removed and modified between two
+// metadata snapshots, each sorted.
+type DiffSet struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// Empty returns true if nothing was added, removed or modified.
+func (s DiffSet) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Modified) == 0
+}
+
+// DataDiff describes the changes between two metadata snapshots. Databases and
+// users are keyed by name, retention policies by "database/policy", and shard
+// groups and nodes by ID.
+type DataDiff struct {
+	Databases         DiffSet
+	RetentionPolicies DiffSet
+	ShardGroups       DiffSet
+	DataNodes         DiffSet
+	MetaNodes         DiffSet
+	Users             DiffSet
+}
+
+// Empty returns true if the snapshots are the same.
+func (d DataDiff) Empty() bool {
+	return d.Databases.Empty() && d.RetentionPolicies.Empty() && d.ShardGroups.Empty() &&
+		d.DataNodes.Empty() && d.MetaNodes.Empty() && d.Users.Empty()
+}
+
+// String returns a summary of the changes, one line per kind of item, with
+// added items prefixed by "+", removed by "-" and modified by "~".
+func (d DataDiff) String() string {
+	if d.Empty() {
+		return "no changes"
+	}
+
+	var lines []string
+	for _, x := range []struct {
+		name string
+		set  DiffSet
+	}{
+		{"databases", d.Databases},
+		{"retention policies", d.RetentionPolicies},
+		{"shard groups", d.ShardGroups},
+		{"data nodes", d.DataNodes},
+		{"meta nodes", d.MetaNodes},
+		{"users", d.Users},
+	} {
+		if x.set.Empty() {
+			continue
+		}
+		var items []string
+		for _, k := range x.set.Added {
+			items = append(items, "+"+k)
+		}
+		for _, k := range x.set.Removed {
+			items = append(items, "-"+k)
+		}
+		for _, k := range x.set.Modified {
+			items = append(items, "~"+k)
+		}
+		lines = append(lines, x.name+": "+strings.Join(items, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the changes from data to other. Items are matched by identity
+// rather than position, so the order of the slices holding them is ignored.
+// A database or retention policy is only modified by changes to its own
+// fields; changes to its retention policies or shard groups are listed separately.
+func (data *Data) Diff(other *Data) DataDiff {
+	return DataDiff{
+		Databases:         diffMessages(data.databaseMessages(), other.databaseMessages()),
+		RetentionPolicies: diffMessages(data.retentionPolicyMessages(), other.retentionPolicyMessages()),
+		ShardGroups:       diffMessages(data.shardGroupMessages(), other.shardGroupMessages()),
+		DataNodes:         diffMessages(nodeMessages(data.DataNodes), nodeMessages(other.DataNodes)),
+		MetaNodes:         diffMessages(nodeMessages(data.MetaNodes), nodeMessages(other.MetaNodes)),
+		Users:             diffMessages(data.userMessages(), other.userMessages()),
+	}
+}
+
+// diffMessages compares two sets of items keyed by identity.
+func diffMessages(from, to map[string]proto.Message) DiffSet {
+	var set DiffSet
+	for k, m := range to {
+		if prev, ok := from[k]; !ok {
+			set.Added = append(set.Added, k)
+		} else if !proto.Equal(prev, m) {
+			set.Modified = append(set.Modified, k)
+		}
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			set.Removed = append(set.Removed, k)
+		}
+	}
+	sort.Strings(set.Added)
+	sort.Strings(set.Removed)
+	sort.Strings(set.Modified)
+	return set
+}
+
+// databaseMessages returns the databases without their retention policies.
+func (data *Data) databaseMessages() map[string]proto.Message {
+	m := make(map[string]proto.Message, len(data.Databases))
+	for _, di := range data.Databases {
+		di.RetentionPolicies = nil
+		m[di.Name] = di.marshal()
+	}
+	return m
+}
+
+// retentionPolicyMessages returns the retention policies without their shard groups.
+func (data *Data) retentionPolicyMessages() map[string]proto.Message {
+	m := make(map[string]proto.Message)
+	for _, di := range data.Databases {
+		for _, rpi := range di.RetentionPolicies {
+			rpi.ShardGroups = nil
+			m[di.Name+"/"+rpi.Name] = rpi.marshal()
+		}
+	}
+	return m
+}
+
+func (data *Data) shardGroupMessages() map[string]proto.Message {
+	m := make(map[string]proto.Message)
+	for _, di := range data.Databases {
+		for _, rpi := range di.RetentionPolicies {
+			for i := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[i]
+				m[strconv.FormatUint(sgi.ID, 10)] = sgi.marshal()
+			}
+		}
+	}
+	return m
+}
+
+func nodeMessages(nodes []NodeInfo) map[string]proto.Message {
+	m := make(map[string]proto.Message, len(nodes))
+	for _, n := range nodes {
+		m[strconv.FormatUint(n.ID, 10)] = n.marshal()
+	}
+	return m
+}
+
+// userMessages returns the users with their privileges sorted, as they are
+// marshaled in map order.
+func (data *Data) userMessages() map[string]proto.Message {
+	m := make(map[string]proto.Message, len(data.Users))
+	for _, ui := range data.Users {
+		pb := ui.marshal()
+		sort.Slice(pb.Privileges, func(i, j int) bool {
+			return pb.Privileges[i].GetDatabase() < pb.Privileges[j].GetDatabase()
+		})
+		m[ui.Name] = pb
+	}
+	return m
+}
+
 // Lease represents a lease held on a resource.
 type Lease struct {
 	Name       string    `json:"name"`
@@ -2229,6 +5531,186 @@ func (leases *Leases) Acquire(name string, nodeID uint64) (*Lease, error) {
 	return l, nil
 }
 
+// Renew extends the expiration of a lease nodeID holds. Unlike Acquire, it never
+// takes a lease that is missing, expired or held by another node, so a node
+// that lost its lease finds out instead of silently acquiring it again.
+func (leases *Leases) Renew(name string, nodeID uint64) (*Lease, error) {
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+
+	l := leases.m[name]
+	if l == nil {
+		return nil, ErrLeaseNotFound
+	} else if l.Owner != nodeID || time.Now().After(l.Expiration) {
+		return nil, ErrLeaseNotOwned
+	}
+
+	l.Expiration = time.Now().Add(leases.d)
+	return l, nil
+}
+
+// Release gives up a lease held by nodeID before it expires, so another node
+// can acquire it straight away. A lease held by another node is left alone.
+func (leases *Leases) Release(name string, nodeID uint64) error {
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+
+	l := leases.m[name]
+	if l == nil {
+		return ErrLeaseNotFound
+	} else if l.Owner != nodeID {
+		return ErrLeaseNotOwned
+	}
+
+	delete(leases.m, name)
+	return nil
+}
+
+// List returns copies of the unexpired leases, sorted by name.
+func (leases *Leases) List() []Lease {
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+
+	now := time.Now()
+	a := make([]Lease, 0, len(leases.m))
+	for _, l := range leases.m {
+		if now.After(l.Expiration) {
+			continue
+		}
+		a = append(a, *l)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Name < a[j].Name })
+	return a
+}
+
+// SafeData is a concurrency-safe wrapper around Data. Its methods lock it and
+// return copies that don't share memory with it. Other Data methods are called
+// through View or Update.
+type SafeData struct {
+	mu   sync.RWMutex
+	data *Data
+}
+
+// NewSafeData returns a new instance of SafeData wrapping data.
+func NewSafeData(data *Data) *SafeData {
+	return &SafeData{data: data}
+}
+
+// View calls fn with the data under a read lock. fn must not modify the data
+// or keep references into it after returning.
+func (d *SafeData) View(fn func(data *Data) error) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return fn(d.data)
+}
+
+// Update calls fn with the data under a write lock.
+func (d *SafeData) Update(fn func(data *Data) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return fn(d.data)
+}
+
+// Clone returns a copy of the data.
+func (d *SafeData) Clone() *Data {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.Clone()
+}
+
+// Database returns a copy of the named database, or nil if it doesn't exist.
+func (d *SafeData) Database(name string) *DatabaseInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	di := d.data.Database(name)
+	if di == nil {
+		return nil
+	}
+	other := di.clone()
+	return &other
+}
+
+// RetentionPolicy returns a copy of the named retention policy on a database.
+func (d *SafeData) RetentionPolicy(database, name string) (*RetentionPolicyInfo, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	rpi, err := d.data.RetentionPolicy(database, name)
+	if err != nil || rpi == nil {
+		return nil, err
+	}
+	other := rpi.clone()
+	return &other, nil
+}
+
+// ShardGroupsByTimeRange returns copies of the shard groups on a database and
+// policy that may contain data for the specified time range.
+func (d *SafeData) ShardGroupsByTimeRange(database, policy string, tmin, tmax time.Time) ([]ShardGroupInfo, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	groups, err := d.data.ShardGroupsByTimeRange(database, policy, tmin, tmax)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i] = groups[i].clone()
+	}
+	return groups, nil
+}
+
+// MarshalJSON encodes the data as human readable JSON, like Data.MarshalJSON.
+func (d *SafeData) MarshalJSON() ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.MarshalJSON()
+}
+
+// CreateDatabase creates a new database.
+func (d *SafeData) CreateDatabase(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.CreateDatabase(name)
+}
+
+// DropDatabase removes a database by name.
+func (d *SafeData) DropDatabase(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.DropDatabase(name)
+}
+
+// CreateRetentionPolicy creates a new retention policy on a database.
+func (d *SafeData) CreateRetentionPolicy(database string, rpi *RetentionPolicyInfo, makeDefault bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.CreateRetentionPolicy(database, rpi, makeDefault)
+}
+
+// DropRetentionPolicy removes a retention policy from a database by name.
+func (d *SafeData) DropRetentionPolicy(database, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.DropRetentionPolicy(database, name)
+}
+
+// CreateShardGroup creates a shard group on a database and policy for a given
+// timestamp.
+func (d *SafeData) CreateShardGroup(database, policy string, timestamp time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.CreateShardGroup(database, policy, timestamp)
+}
+
+// DeleteShardGroup removes a shard group from a database and retention policy
+// by id.
+func (d *SafeData) DeleteShardGroup(database, policy string, id uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.data.DeleteShardGroup(database, policy, id)
+}
+
 // MarshalTime converts t to nanoseconds since epoch. A zero time returns 0.
 func MarshalTime(t time.Time) int64 {
 	if t.IsZero() {
@@ -2246,14 +5728,52 @@ func UnmarshalTime(v int64) time.Time {
 	return time.Unix(0, v).UTC()
 }
 
-// ValidName checks to see if the given name can would be valid for DB/RP name
-func ValidName(name string) bool {
+// NameRules are restrictions on database and retention policy names on top of
+// the ones ValidName always applies. The zero value adds no restrictions.
+type NameRules struct {
+	// ForbiddenPrefixes lists prefixes names may not start with, such as "_"
+	// to reserve underscore names for internal databases.
+	ForbiddenPrefixes []string
+
+	// AllowInternal exempts the name of the _internal database, where the
+	// monitor stores statistics, from the rules.
+	AllowInternal bool
+
+	// MaxRunes, when positive, limits the number of unicode characters in a
+	// name, independently of the MaxNameLen limit on its length in bytes.
+	MaxRunes int
+}
+
+// allow returns true if name follows the rules.
+func (r NameRules) allow(name string) bool {
+	if r.AllowInternal && name == "_internal" {
+		return true
+	} else if r.MaxRunes > 0 && utf8.RuneCountInString(name) > r.MaxRunes {
+		return false
+	}
+	for _, prefix := range r.ForbiddenPrefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidName checks to see if the given name can would be valid for DB/RP name.
+// Optional NameRules restrict the name further.
+func ValidName(name string, rules ...NameRules) bool {
 	for _, r := range name {
 		if !unicode.IsPrint(r) {
 			return false
 		}
 	}
 
+	for _, r := range rules {
+		if !r.allow(name) {
+			return false
+		}
+	}
+
 	return name != "" &&
 		name != "." &&
 		name != ".." &&
@@ -2325,6 +5845,7 @@ func NewDataNodeInfo(n *NodeInfo) *DataNodeInfo {
 		ID:       n.ID,
 		TCPAddr:  n.TCPAddr,
 		HTTPAddr: n.Addr,
+		Status:   n.Status,
 	}
 }
 
@@ -2349,6 +5870,80 @@ type ClusterInfo struct {
 	Meta []*MetaNodeInfo `json:"meta"`
 }
 
+// Topology returns the data and meta nodes of the cluster, each sorted by ID,
+// with httpScheme set as their HTTP scheme.
+func (data *Data) Topology(httpScheme string) ClusterInfo {
+	ci := ClusterInfo{
+		Data: make([]*DataNodeInfo, len(data.DataNodes)),
+		Meta: make([]*MetaNodeInfo, len(data.MetaNodes)),
+	}
+	for i := range data.DataNodes {
+		ci.Data[i] = NewDataNodeInfo(&data.DataNodes[i])
+		ci.Data[i].HTTPScheme = httpScheme
+	}
+	for i := range data.MetaNodes {
+		ci.Meta[i] = NewMetaNodeInfo(&data.MetaNodes[i])
+		ci.Meta[i].HTTPScheme = httpScheme
+	}
+	sort.Slice(ci.Data, func(i, j int) bool { return ci.Data[i].ID < ci.Data[j].ID })
+	sort.Slice(ci.Meta, func(i, j int) bool { return ci.Meta[i].ID < ci.Meta[j].ID })
+	return ci
+}
+
+// ClusterSummary counts the nodes and objects held in the metadata.
+type ClusterSummary struct {
+	DataNodes         int `json:"data-nodes"`
+	MetaNodes         int `json:"meta-nodes"`
+	Databases         int `json:"databases"`
+	RetentionPolicies int `json:"retention-policies"`
+	ShardGroups       int `json:"shard-groups"`
+	Shards            int `json:"shards"`
+}
+
+// ClusterSummary returns a count of the nodes, databases, retention policies,
+// shard groups and shards. Deleted shard groups and their shards are not
+// counted, and a shard is counted once however many groups list it.
+func (data *Data) ClusterSummary() ClusterSummary {
+	s := ClusterSummary{
+		DataNodes: len(data.DataNodes),
+		MetaNodes: len(data.MetaNodes),
+		Databases: len(data.Databases),
+	}
+	shardIDs := make(map[uint64]struct{})
+	for _, di := range data.Databases {
+		s.RetentionPolicies += len(di.RetentionPolicies)
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				s.ShardGroups++
+				for _, si := range sgi.Shards {
+					shardIDs[si.ID] = struct{}{}
+				}
+			}
+		}
+	}
+	s.Shards = len(shardIDs)
+	return s
+}
+
+// DatabaseCount returns the number of databases.
+func (data *Data) DatabaseCount() int {
+	return data.ClusterSummary().Databases
+}
+
+// ShardGroupCount returns the number of shard groups that are not deleted.
+func (data *Data) ShardGroupCount() int {
+	return data.ClusterSummary().ShardGroups
+}
+
+// ShardCount returns the number of distinct shards in shard groups that are
+// not deleted.
+func (data *Data) ShardCount() int {
+	return data.ClusterSummary().Shards
+}
+
 type ClusterShardInfo struct {
 	ID              uint64            `json:"id"`
 	Database        string            `json:"database"`
@@ -2371,6 +5966,56 @@ type ShardOwnerInfo struct {
 	Err          string    `json:"err"`
 }
 
+// ClusterShardInfos returns the shards of database, or of all databases if
+// database is empty. Shards in deleted shard groups are skipped. The size,
+// state and error of each owner are left for the data nodes to fill in.
+func (data *Data) ClusterShardInfos(database string) ([]ClusterShardInfo, error) {
+	if database != "" && data.Database(database) == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+
+	var infos []ClusterShardInfo
+	for _, di := range data.Databases {
+		if database != "" && di.Name != database {
+			continue
+		}
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					infos = append(infos, data.clusterShardInfo(di.Name, rpi, sgi, si))
+				}
+			}
+		}
+	}
+	return infos, nil
+}
+
+// clusterShardInfo describes a single shard.
+func (data *Data) clusterShardInfo(database string, rpi RetentionPolicyInfo, sgi ShardGroupInfo, si ShardInfo) ClusterShardInfo {
+	owners := make([]*ShardOwnerInfo, len(si.Owners))
+	for i, owner := range si.Owners {
+		owners[i] = &ShardOwnerInfo{ID: owner.NodeID}
+		if n := data.DataNode(owner.NodeID); n != nil {
+			owners[i].TCPAddr = n.TCPAddr
+		}
+	}
+	return ClusterShardInfo{
+		ID:              si.ID,
+		Database:        database,
+		RetentionPolicy: rpi.Name,
+		ReplicaN:        sgi.EffectiveReplicaN(rpi.ReplicaN),
+		ShardGroupID:    sgi.ID,
+		StartTime:       sgi.StartTime,
+		EndTime:         sgi.EndTime,
+		ExpireTime:      sgi.ExpireTime(rpi.Duration),
+		TruncatedAt:     sgi.TruncatedAt,
+		Owners:          owners,
+	}
+}
+
 type UserPrivilege struct {
 	Name     string `json:"name"`
 	Hash     string `json:"hash,omitempty"`
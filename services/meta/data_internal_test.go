@@ -1,10 +1,15 @@
 package meta
 
 import (
+	"reflect"
 	"sort"
 	"time"
 
 	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/raft"
+	internal "github.com/influxdata/influxdb/services/meta/internal"
 )
 
 func TestShardGroupSort(t *testing.T) {
@@ -62,3 +67,252 @@ func Test_Data_RetentionPolicy_MarshalBinary(t *testing.T) {
 		t.Errorf("unexpected DeletedAt time.  got: %s, exp: %s", got, exp)
 	}
 }
+
+func TestData_unmarshal_Migrate(t *testing.T) {
+	// Metadata written before versioning, using the deprecated Nodes and
+	// OwnerIDs fields.
+	pb := &internal.Data{
+		Term:      proto.Uint64(1),
+		Index:     proto.Uint64(1),
+		ClusterID: proto.Uint64(1),
+		Nodes:     []*internal.NodeInfo{{ID: proto.Uint64(1), TCPAddr: proto.String("host0:8088")}},
+		Databases: []*internal.DatabaseInfo{{
+			Name:                   proto.String("db0"),
+			DefaultRetentionPolicy: proto.String("rp0"),
+			RetentionPolicies: []*internal.RetentionPolicyInfo{{
+				Name:     proto.String("rp0"),
+				Duration: proto.Int64(0),
+				ReplicaN: proto.Uint32(1),
+				ShardGroups: []*internal.ShardGroupInfo{{
+					ID:        proto.Uint64(1),
+					StartTime: proto.Int64(0),
+					EndTime:   proto.Int64(1),
+					DeletedAt: proto.Int64(0),
+					Shards:    []*internal.ShardInfo{{ID: proto.Uint64(1), OwnerIDs: []uint64{1}}},
+				}},
+			}},
+		}},
+		MaxNodeID:       proto.Uint64(1),
+		MaxShardGroupID: proto.Uint64(1),
+		MaxShardID:      proto.Uint64(1),
+	}
+
+	var data Data
+	data.unmarshal(pb)
+
+	if got, exp := data.Version, DataVersion; got != exp {
+		t.Fatalf("got version %d, expected %d", got, exp)
+	}
+	if exp := []NodeInfo{{ID: 1, TCPAddr: "host0:8088", Status: NodeStatusJoined}}; !reflect.DeepEqual(data.DataNodes, exp) {
+		t.Fatalf("got data nodes %+v, expected %+v", data.DataNodes, exp)
+	}
+	owners := data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].Owners
+	if exp := []ShardOwner{{NodeID: 1}}; !reflect.DeepEqual(owners, exp) {
+		t.Fatalf("got owners %+v, expected %+v", owners, exp)
+	}
+
+	// The current version is written back.
+	if got, exp := (&Data{}).marshal().GetVersion(), DataVersion; got != exp {
+		t.Fatalf("got marshalled version %d, expected %d", got, exp)
+	}
+}
+
+func TestData_UnmarshalBinary_Version(t *testing.T) {
+	buf, err := proto.Marshal(&internal.Data{
+		Term:            proto.Uint64(1),
+		Index:           proto.Uint64(1),
+		ClusterID:       proto.Uint64(1),
+		MaxNodeID:       proto.Uint64(0),
+		MaxShardGroupID: proto.Uint64(0),
+		MaxShardID:      proto.Uint64(0),
+		Version:         proto.Uint32(DataVersion + 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata written by a newer version isn't decoded as the current one.
+	if err := (&Data{}).UnmarshalBinary(buf); err != ErrMetadataVersion {
+		t.Fatalf("got error %v, expected %v", err, ErrMetadataVersion)
+	}
+}
+
+func TestRetentionPolicyInfo_UnmarshalBinary_Migrate(t *testing.T) {
+	buf, err := proto.Marshal(&internal.RetentionPolicyInfo{
+		Name:               proto.String("rp0"),
+		Duration:           proto.Int64(0),
+		ShardGroupDuration: proto.Int64(0),
+		ReplicaN:           proto.Uint32(1),
+		ShardGroups: []*internal.ShardGroupInfo{{
+			ID:        proto.Uint64(1),
+			StartTime: proto.Int64(0),
+			EndTime:   proto.Int64(1),
+			DeletedAt: proto.Int64(0),
+			Shards:    []*internal.ShardInfo{{ID: proto.Uint64(1), OwnerIDs: []uint64{1, 2}}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rpi RetentionPolicyInfo
+	if err := rpi.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	owners := rpi.ShardGroups[0].Shards[0].Owners
+	if exp := []ShardOwner{{NodeID: 1}, {NodeID: 2}}; !reflect.DeepEqual(owners, exp) {
+		t.Fatalf("got owners %+v, expected %+v", owners, exp)
+	}
+}
+
+// newLargeRetentionPolicy returns a retention policy with n shard groups of
+// one shard each.
+func newLargeRetentionPolicy(n int) RetentionPolicyInfo {
+	rpi := RetentionPolicyInfo{Name: "rp", ReplicaN: 1, Duration: time.Hour, ShardGroupDuration: time.Hour}
+	rpi.ShardGroups = make([]ShardGroupInfo, n)
+	for i := range rpi.ShardGroups {
+		rpi.ShardGroups[i] = ShardGroupInfo{
+			ID:     uint64(i + 1),
+			Shards: []ShardInfo{{ID: uint64(i + 1), Owners: []ShardOwner{{NodeID: 1}}}},
+		}
+	}
+	return rpi
+}
+
+func BenchmarkRetentionPolicyInfo_clone(b *testing.B) {
+	rpi := newLargeRetentionPolicy(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rpi.clone()
+	}
+}
+
+func BenchmarkRetentionPolicyInfo_CloneMeta(b *testing.B) {
+	rpi := newLargeRetentionPolicy(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rpi.CloneMeta()
+	}
+}
+
+func TestContinuousQueryInfo_unmarshal_Enabled(t *testing.T) {
+	// Queries stored before the enabled flag existed are enabled.
+	var cqi ContinuousQueryInfo
+	cqi.unmarshal(&internal.ContinuousQueryInfo{Name: proto.String("cq0"), Query: proto.String("query")})
+	if !cqi.Enabled {
+		t.Fatal("expected continuous query without a stored flag to be enabled")
+	}
+
+	cqi.unmarshal(&internal.ContinuousQueryInfo{Name: proto.String("cq0"), Query: proto.String("query"), Enabled: proto.Bool(false)})
+	if cqi.Enabled {
+		t.Fatal("expected continuous query to be disabled")
+	}
+}
+
+// applyCommand applies a command of type typ with extension ext set to v to a
+// store FSM holding data. It returns the FSM's resulting data and the result.
+func applyCommand(data *Data, typ internal.Command_Type, ext *proto.ExtensionDesc, v interface{}) (*Data, interface{}) {
+	cmd := &internal.Command{Type: &typ}
+	if err := proto.SetExtension(cmd, ext, v); err != nil {
+		panic(err)
+	}
+	b, err := proto.Marshal(cmd)
+	if err != nil {
+		panic(err)
+	}
+	fsm := (*storeFSM)(&store{data: data, dataChanged: make(chan struct{})})
+	res := fsm.Apply(&raft.Log{Data: b})
+	return fsm.data, res
+}
+
+func TestStoreFSM_CreateRetentionPolicy_WriteAffinityTag(t *testing.T) {
+	data := &Data{Databases: []DatabaseInfo{{Name: "db0"}}}
+	rpi := &RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: time.Hour, WriteAffinityTag: "host"}
+	data, err := applyCommand(data, internal.Command_CreateRetentionPolicyCommand, internal.E_CreateRetentionPolicyCommand_Command,
+		&internal.CreateRetentionPolicyCommand{
+			Database:        proto.String("db0"),
+			RetentionPolicy: rpi.marshal(),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rp, err := data.RetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.WriteAffinityTag, "host"; got != exp {
+		t.Fatalf("got write affinity tag %q, expected %q", got, exp)
+	}
+}
+
+func TestStoreFSM_CreateRetentionPolicy_PendingShardCount(t *testing.T) {
+	data := &Data{Databases: []DatabaseInfo{{Name: "db0"}}}
+	rpi := &RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: time.Hour, PendingShardCount: 4}
+	data, err := applyCommand(data, internal.Command_CreateRetentionPolicyCommand, internal.E_CreateRetentionPolicyCommand_Command,
+		&internal.CreateRetentionPolicyCommand{
+			Database:        proto.String("db0"),
+			RetentionPolicy: rpi.marshal(),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rp, err := data.RetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.PendingShardCount, 4; got != exp {
+		t.Fatalf("got pending shard count %d, expected %d", got, exp)
+	}
+}
+
+func TestStoreFSM_CreateRetentionPolicy_PreCreateCount(t *testing.T) {
+	data := &Data{Databases: []DatabaseInfo{{Name: "db0"}}}
+	rpi := &RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: time.Hour, PreCreateCount: 3}
+	data, err := applyCommand(data, internal.Command_CreateRetentionPolicyCommand, internal.E_CreateRetentionPolicyCommand_Command,
+		&internal.CreateRetentionPolicyCommand{
+			Database:        proto.String("db0"),
+			RetentionPolicy: rpi.marshal(),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rp, err := data.RetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.PreCreateCount, 3; got != exp {
+		t.Fatalf("got pre-create count %d, expected %d", got, exp)
+	}
+}
+
+func TestStoreFSM_CreateContinuousQuery_Validate(t *testing.T) {
+	data := &Data{Databases: []DatabaseInfo{{Name: "db0"}}}
+
+	// Commands written before queries were validated still apply.
+	query := `SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m)`
+	data, err := applyCommand(data, internal.Command_CreateContinuousQueryCommand, internal.E_CreateContinuousQueryCommand_Command,
+		&internal.CreateContinuousQueryCommand{
+			Database: proto.String("db0"),
+			Name:     proto.String("cq0"),
+			Query:    proto.String(query),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := len(data.Database("db0").ContinuousQueries), 1; got != exp {
+		t.Fatalf("got %d continuous queries, expected %d", got, exp)
+	}
+
+	data, err = applyCommand(data, internal.Command_CreateContinuousQueryCommand, internal.E_CreateContinuousQueryCommand_Command,
+		&internal.CreateContinuousQueryCommand{
+			Database: proto.String("db0"),
+			Name:     proto.String("cq1"),
+			Query:    proto.String(query),
+			Validate: proto.Bool(true),
+		})
+	if err == nil {
+		t.Fatal("expected an error for an invalid query")
+	}
+	if got, exp := len(data.Database("db0").ContinuousQueries), 1; got != exp {
+		t.Fatalf("got %d continuous queries, expected %d", got, exp)
+	}
+}
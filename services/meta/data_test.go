@@ -1,13 +1,19 @@
 package meta_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/testing/assert"
 	"github.com/influxdata/influxql"
 
@@ -143,6 +149,147 @@ func Test_Data_CreateRetentionPolicy(t *testing.T) {
 	}
 }
 
+func TestData_UpdateRetentionPolicy_ReadOnly(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true))
+
+	rpi, _ := data.RetentionPolicy("db", "rp")
+	if !rpi.WritesAllowed() {
+		t.Fatal("expected new retention policy to allow writes")
+	}
+
+	var rpu meta.RetentionPolicyUpdate
+	rpu.SetReadOnly(true)
+	must(data.UpdateRetentionPolicy("db", "rp", &rpu, false))
+
+	// The flag survives a marshal round trip, and updates that don't set it
+	// leave it alone.
+	buf, err := data.MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+	must(other.UpdateRetentionPolicy("db", "rp", &meta.RetentionPolicyUpdate{}, false))
+	if rpi, _ := other.RetentionPolicy("db", "rp"); !rpi.ReadOnly || rpi.WritesAllowed() {
+		t.Fatalf("got %+v, expected a read-only retention policy", rpi)
+	}
+
+	rpu.SetReadOnly(false)
+	must(other.UpdateRetentionPolicy("db", "rp", &rpu, false))
+	if rpi, _ := other.RetentionPolicy("db", "rp"); !rpi.WritesAllowed() {
+		t.Fatal("expected retention policy to allow writes")
+	}
+}
+
+func TestData_UpdateRetentionPolicy_ExpiredShardGroups(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db"))
+	rpi := meta.NewRetentionPolicyInfo("rp")
+	rpi.Duration = 0
+	must(data.CreateRetentionPolicy("db", rpi, true))
+	must(data.CreateShardGroup("db", "rp", now.Add(-30*24*time.Hour)))
+	must(data.CreateShardGroup("db", "rp", now))
+
+	duration := 7 * 24 * time.Hour
+	rpu := &meta.RetentionPolicyUpdate{Duration: &duration}
+	n, err := data.RetentionPolicyUpdateExpiredShardGroups("db", "rp", rpu, now)
+	must(err)
+	if n != 1 {
+		t.Fatalf("got %d expired shard groups, expected 1", n)
+	}
+
+	// Strict mode refuses the update unless forced.
+	rpu.StrictExpiry = true
+	if _, err := data.UpdateRetentionPolicyWithResult("db", "rp", rpu, false, now); err != meta.ErrRetentionPolicyExpiresShardGroups {
+		t.Fatalf("got %v, expected %v", err, meta.ErrRetentionPolicyExpiresShardGroups)
+	}
+	if rpi, _ := data.RetentionPolicy("db", "rp"); rpi.Duration != 0 {
+		t.Fatalf("got duration %v, expected it unchanged", rpi.Duration)
+	}
+
+	rpu.ForceExpiry = true
+	result, err := data.UpdateRetentionPolicyWithResult("db", "rp", rpu, false, now)
+	must(err)
+	if got, exp := result.ExpiredShardGroups, 1; got != exp {
+		t.Fatalf("got %d expired shard groups, expected %d", got, exp)
+	}
+
+	// Groups already expired aren't counted again.
+	duration = 2 * 24 * time.Hour
+	n, err = data.RetentionPolicyUpdateExpiredShardGroups("db", "rp", rpu, now)
+	must(err)
+	if n != 0 {
+		t.Fatalf("got %d expired shard groups, expected 0", n)
+	}
+}
+
+func TestData_UpdateRetentionPolicy_ReplicaNExceedsNodes(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDataNode("bar:8086", "bar:8088"))
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true))
+
+	replicaN := 3
+	rpu := &meta.RetentionPolicyUpdate{ReplicaN: &replicaN}
+	if err := data.UpdateRetentionPolicy("db", "rp", rpu, false); err != meta.ErrReplicationFactorExceedsNodes {
+		t.Fatalf("got %v, expected %v", err, meta.ErrReplicationFactorExceedsNodes)
+	}
+	rpi, err := data.RetentionPolicy("db", "rp")
+	must(err)
+	if got, exp := rpi.ReplicaN, 1; got != exp {
+		t.Fatalf("got replica n %d, expected %d", got, exp)
+	}
+
+	// Raising within the node count only warns about existing shards.
+	replicaN = 2
+	notes, err := data.RetentionPolicyReplicaNUpdateSafety("db", "rp", rpu)
+	must(err)
+	if got, exp := len(notes), 1; got != exp {
+		t.Fatalf("got %d notes, expected %d: %v", got, exp, notes)
+	}
+
+	replicaN = 3
+	rpu.AllowExcessReplicaN = true
+	notes, err = data.RetentionPolicyReplicaNUpdateSafety("db", "rp", rpu)
+	must(err)
+	if got, exp := len(notes), 2; got != exp {
+		t.Fatalf("got %d notes, expected %d: %v", got, exp, notes)
+	}
+	must(data.UpdateRetentionPolicy("db", "rp", rpu, false))
+	rpi, err = data.RetentionPolicy("db", "rp")
+	must(err)
+	if got, exp := rpi.ReplicaN, 3; got != exp {
+		t.Fatalf("got replica n %d, expected %d", got, exp)
+	}
+
+	// Lowering the replication factor is always allowed.
+	replicaN = 1
+	rpu.AllowExcessReplicaN = false
+	must(data.UpdateRetentionPolicy("db", "rp", rpu, false))
+}
+
 func TestData_AdminUserExists(t *testing.T) {
 	data := meta.Data{}
 
@@ -221,6 +368,106 @@ func TestData_AdminUserExists(t *testing.T) {
 	}
 }
 
+func TestData_AdminUsers(t *testing.T) {
+	data := meta.Data{}
+
+	if got, exp := data.AdminUsers(), []string{}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	for _, u := range []struct {
+		name  string
+		admin bool
+	}{
+		{"zed", true},
+		{"bob", false},
+		{"alice", true},
+		{"carol", false},
+	} {
+		if err := data.CreateUser(u.name, "a", u.admin); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, exp := data.AdminUsers(), []string{"alice", "zed"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.RegularUsers(), []string{"bob", "carol"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// Modifying the result must not affect the users.
+	data.AdminUsers()[0] = "mallory"
+	if got, exp := data.AdminUsers(), []string{"alice", "zed"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	if err := data.SetAdminPrivilege("zed", false); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := data.AdminUsers(), []string{"alice"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.RegularUsers(), []string{"bob", "carol", "zed"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_PromoteUserToAdmin(t *testing.T) {
+	data := meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateUser("user1", "a", false))
+	if err := data.PromoteUserToAdmin("unknown", time.Now()); err != meta.ErrUserNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrUserNotFound)
+	}
+
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	must(data.PromoteUserToAdmin("user1", at))
+	if !data.Users[0].Admin || !data.AdminUserExists() {
+		t.Fatal("expected user1 to be an admin")
+	}
+	if got := data.Users[0].AdminSince; got == nil || !got.Equal(at) {
+		t.Fatalf("got admin since %v, expected %v", got, at)
+	}
+
+	// Promoting an admin again keeps the original promotion time.
+	must(data.PromoteUserToAdmin("user1", at.Add(time.Hour)))
+	if got := data.Users[0].AdminSince; got == nil || !got.Equal(at) {
+		t.Fatalf("got admin since %v, expected %v", got, at)
+	}
+
+	// The promotion time survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	if got := other.Users[0].AdminSince; got == nil || !got.Equal(at) {
+		t.Fatalf("got admin since %v after round trip, expected %v", got, at)
+	}
+
+	must(data.DemoteAdmin("user1"))
+	if data.Users[0].Admin || data.AdminUserExists() {
+		t.Fatal("expected user1 not to be an admin")
+	}
+	if got := data.Users[0].AdminSince; got != nil {
+		t.Fatalf("got admin since %v, expected nil", got)
+	}
+
+	buf, err = data.MarshalBinary()
+	must(err)
+	other = meta.Data{}
+	must(other.UnmarshalBinary(buf))
+	if got := other.Users[0].AdminSince; got != nil {
+		t.Fatalf("got admin since %v after round trip, expected nil", got)
+	}
+}
+
 func TestData_SetPrivilege(t *testing.T) {
 	data := meta.Data{}
 	if err := data.CreateDatabase("db0"); err != nil {
@@ -247,204 +494,3747 @@ func TestData_SetPrivilege(t *testing.T) {
 	}
 }
 
-func TestData_TruncateShardGroups(t *testing.T) {
+func TestData_SetPrivileges(t *testing.T) {
 	data := &meta.Data{}
-
 	must := func(err error) {
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateUser("user1", "", false))
+	must(data.SetPrivilege("user1", "db0", influxql.ReadPrivilege))
 
-	must(data.CreateDataNode("foo:8086", "bar:8088"))
-	must(data.CreateDatabase("db"))
-	rp := meta.NewRetentionPolicyInfo("rp")
-	rp.ShardGroupDuration = 24 * time.Hour
-	must(data.CreateRetentionPolicy("db", rp, true))
-
-	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+	// A missing database leaves every privilege unchanged.
+	err := data.SetPrivileges("user1", map[string]influxql.Privilege{
+		"db0": influxql.AllPrivileges,
+		"db1": influxql.WritePrivilege,
+		"db3": influxql.ReadPrivilege,
+		"db2": influxql.ReadPrivilege,
+	})
+	if exp := influxdb.ErrDatabaseNotFound("db2"); err == nil || err.Error() != exp.Error() {
+		t.Fatalf("got error %v, expected %v", err, exp)
+	}
+	exp := map[string]influxql.Privilege{"db0": influxql.ReadPrivilege}
+	if got, _ := data.UserPrivileges("user1"); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
 
-	sg0, err := data.ShardGroupByTimestamp("db", "rp", time.Unix(0, 0))
-	if err != nil {
-		t.Fatal("Failed to find shard group:", err)
+	must(data.SetPrivileges("user1", map[string]influxql.Privilege{
+		"db0": influxql.AllPrivileges,
+		"db1": influxql.WritePrivilege,
+	}))
+	exp = map[string]influxql.Privilege{"db0": influxql.AllPrivileges, "db1": influxql.WritePrivilege}
+	if got, _ := data.UserPrivileges("user1"); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
 	}
 
-	if sg0.Truncated() {
-		t.Fatal("shard group already truncated")
+	if err := data.SetPrivileges("nope", nil); err != meta.ErrUserNotFound {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrUserNotFound)
 	}
+}
 
-	sgEnd, err := data.ShardGroupByTimestamp("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration-1))
-	if err != nil {
-		t.Fatal("Failed to find shard group for end range:", err)
+func TestData_UserPrivilegesSorted(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, db := range []string{"db2", "db0", "db1"} {
+		must(data.CreateDatabase(db))
 	}
+	must(data.CreateUser("user1", "", false))
+	must(data.SetPrivilege("user1", "db2", influxql.AllPrivileges))
+	must(data.SetPrivilege("user1", "db0", influxql.ReadPrivilege))
+	must(data.SetPrivilege("user1", "db1", influxql.WritePrivilege))
 
-	if sgEnd == nil || sgEnd.ID != sg0.ID {
-		t.Fatalf("Retention policy mis-match: Expected %v, Got %v", sg0, sgEnd)
+	privileges, err := data.UserPrivilegesSorted("user1")
+	must(err)
+	exp := []meta.DatabasePrivilege{
+		{Database: "db0", Privilege: "READ"},
+		{Database: "db1", Privilege: "WRITE"},
+		{Database: "db2", Privilege: "ALL PRIVILEGES"},
+	}
+	if !reflect.DeepEqual(privileges, exp) {
+		t.Fatalf("got %v, expected %v", privileges, exp)
 	}
 
-	must(data.CreateShardGroup("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration)))
+	if _, err := data.UserPrivilegesSorted("nope"); err != meta.ErrUserNotFound {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrUserNotFound)
+	}
+}
 
-	sg1, err := data.ShardGroupByTimestamp("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration+time.Minute))
-	if err != nil {
-		t.Fatal("Failed to find second shard group:", err)
+func TestData_UsersWithPrivilege(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
 	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateUser("admin", "", true))
+	for _, name := range []string{"reader", "writer", "all", "member", "other"} {
+		must(data.CreateUser(name, "", false))
+	}
+	must(data.SetPrivilege("reader", "db0", influxql.ReadPrivilege))
+	must(data.SetPrivilege("writer", "db0", influxql.WritePrivilege))
+	must(data.SetPrivilege("all", "db0", influxql.AllPrivileges))
+	must(data.SetPrivilege("other", "db1", influxql.AllPrivileges))
+	must(data.CreateRole("readers"))
+	must(data.SetRolePrivilege("readers", "db0", influxql.ReadPrivilege))
+	must(data.AddUserToRole("readers", "member"))
 
-	if sg1.Truncated() {
-		t.Fatal("second shard group already truncated")
+	for _, tt := range []struct {
+		p   influxql.Privilege
+		exp []string
+	}{
+		{p: influxql.ReadPrivilege, exp: []string{"admin", "all", "member", "reader"}},
+		{p: influxql.WritePrivilege, exp: []string{"admin", "all", "writer"}},
+		{p: influxql.AllPrivileges, exp: []string{"admin", "all"}},
+	} {
+		if got := data.UsersWithPrivilege("db0", tt.p); !reflect.DeepEqual(got, tt.exp) {
+			t.Fatalf("got %v with %v, expected %v", got, tt.p, tt.exp)
+		}
 	}
+}
 
-	// shouldn't do anything
-	must(data.CreateShardGroup("db", "rp", sg0.EndTime.Add(-time.Minute)))
+func TestData_DatabasesForUser(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, db := range []string{"db2", "db0", "db1", "db3"} {
+		must(data.CreateDatabase(db))
+	}
+	must(data.CreateUser("admin", "", true))
+	must(data.CreateUser("user1", "", false))
+	must(data.SetPrivilege("user1", "db2", influxql.ReadPrivilege))
+	must(data.SetPrivilege("user1", "db1", influxql.WritePrivilege))
+	must(data.SetPrivilege("user1", "db3", influxql.NoPrivileges))
+	must(data.CreateRole("readers"))
+	must(data.SetRolePrivilege("readers", "db0", influxql.ReadPrivilege))
+	must(data.AddUserToRole("readers", "user1"))
 
-	sgs, err := data.ShardGroupsByTimeRange("db", "rp", time.Unix(0, 0), sg1.EndTime.Add(time.Minute))
-	if err != nil {
-		t.Fatal("Failed to find shard groups:", err)
+	names, err := data.DatabasesForUser("user1")
+	must(err)
+	if exp := []string{"db0", "db1", "db2"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
 	}
 
-	if len(sgs) != 2 {
-		t.Fatalf("Expected %d shard groups, found %d", 2, len(sgs))
+	names, err = data.DatabasesForUser("admin")
+	must(err)
+	if exp := []string{"db0", "db1", "db2", "db3"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
 	}
 
-	truncateTime := sg0.EndTime.Add(-time.Minute)
-	data.TruncateShardGroups(truncateTime)
+	if _, err := data.DatabasesForUser("nope"); err != meta.ErrUserNotFound {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrUserNotFound)
+	}
+}
 
-	// at this point, we should get nil shard groups for times after truncateTime
-	for _, tc := range []struct {
-		t      time.Time
-		exists bool
-	}{
-		{sg0.StartTime, true},
-		{sg0.EndTime.Add(-1), false},
-		{truncateTime.Add(-1), true},
-		{truncateTime, false},
-		{sg1.StartTime, false},
-	} {
-		sg, err := data.ShardGroupByTimestamp("db", "rp", tc.t)
+func TestData_Roles(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
 		if err != nil {
-			t.Fatalf("Failed to find shardgroup for %v: %v", tc.t, err)
-		}
-		if tc.exists && sg == nil {
-			t.Fatalf("Shard group for timestamp '%v' should exist, got nil", tc.t)
+			t.Fatal(err)
 		}
 	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateUser("user1", "", false))
+	must(data.CreateUser("user2", "", false))
 
-	for _, x := range data.Databases[0].RetentionPolicies[0].ShardGroups {
-		switch x.ID {
-		case sg0.ID:
-			*sg0 = x
-		case sg1.ID:
-			*sg1 = x
-		}
+	if got, exp := data.CreateRole(""), meta.ErrRoleNameRequired; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	must(data.CreateRole("readers"))
+	must(data.CreateRole("writers"))
+	if got, exp := data.CreateRole("readers"), meta.ErrRoleExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
 	}
 
-	if sg0.TruncatedAt != truncateTime {
-		t.Fatalf("Incorrect truncation of current shard group. Expected %v, got %v", truncateTime, sg0.TruncatedAt)
+	if got, exp := data.AddUserToRole("nope", "user1"), meta.ErrRoleNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.AddUserToRole("readers", "nope"), meta.ErrUserNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.SetRolePrivilege("readers", "db1", influxql.ReadPrivilege), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
 	}
 
-	if sg1.TruncatedAt != sg1.StartTime {
-		t.Fatalf("Incorrect truncation of future shard group. Expected %v, got %v", sg1.StartTime, sg1.TruncatedAt)
+	must(data.SetRolePrivilege("readers", "db0", influxql.ReadPrivilege))
+	must(data.SetRolePrivilege("writers", "db0", influxql.WritePrivilege))
+	must(data.AddUserToRole("readers", "user1"))
+	must(data.AddUserToRole("writers", "user1"))
+	must(data.AddUserToRole("readers", "user2"))
+
+	authorized := func(data *meta.Data, username string, p influxql.Privilege) bool {
+		return data.User(username).(*meta.UserInfo).AuthorizeDatabase(p, "db0")
 	}
 
-	groups := data.Databases[0].RetentionPolicies[0].ShardGroups
-	assert.Equal(t, 2, len(groups))
-	assert.Equal(t, "1970-01-01 00:00:00 +0000 UTC", groups[0].StartTime.String())
-	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[0].EndTime.String())
-	assert.Equal(t, "1970-01-01 23:59:00 +0000 UTC", groups[0].TruncatedAt.String())
+	// user1 holds the union of both roles, user2 only reads.
+	for _, p := range []influxql.Privilege{influxql.ReadPrivilege, influxql.WritePrivilege, influxql.AllPrivileges} {
+		if !authorized(data, "user1", p) {
+			t.Fatalf("user1 not authorized for %v", p)
+		}
+	}
+	if !authorized(data, "user2", influxql.ReadPrivilege) || authorized(data, "user2", influxql.WritePrivilege) {
+		t.Fatal("expected user2 to only be authorized to read")
+	}
 
-	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[1].StartTime.String())
-	assert.Equal(t, "1970-01-03 00:00:00 +0000 UTC", groups[1].EndTime.String())
-	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[1].TruncatedAt.String())
+	// Role privileges add to the user's own.
+	must(data.SetPrivilege("user2", "db0", influxql.WritePrivilege))
+	if !authorized(data, "user2", influxql.AllPrivileges) {
+		t.Fatal("expected user2 to be authorized for all privileges")
+	}
 
-	// Create some more shard groups and validate there is no overlap
-	// Add a shard starting at sg0's truncation time, until 01/02
-	must(data.CreateShardGroup("db", "rp", sg0.EndTime.Add(-time.Second)))
-	// Add a shard 01/02 - 01/03 (since sg1 is fully truncated)
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(-time.Second)))
-	// Add a shard 01/06 - 01/07
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(3*rp.ShardGroupDuration)))
-	newDuration := 10 * rp.ShardGroupDuration
-	data.UpdateRetentionPolicy("db", "rp", &meta.RetentionPolicyUpdate{
-		Name:               nil,
-		Duration:           nil,
-		ReplicaN:           nil,
-		ShardGroupDuration: &newDuration,
-	}, true)
-	// Add a shard 01/03 - 01/06
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(1*rp.ShardGroupDuration)))
-	// Add a shard 01/07 - 01/09
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(4*rp.ShardGroupDuration)))
-	// Add a shard 01/09 - 01/19
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(10*rp.ShardGroupDuration)))
-	// No additional shard added
-	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(11*rp.ShardGroupDuration)))
+	// Role privileges survive cloning and a marshal round trip.
+	buf, err := data.Clone().MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	if !reflect.DeepEqual(other.Roles, data.Roles) {
+		t.Fatalf("got roles %v, expected %v", other.Roles, data.Roles)
+	}
+	if !authorized(&other, "user1", influxql.AllPrivileges) {
+		t.Fatal("user1 not authorized after unmarshal")
+	}
 
-	groups = data.Databases[0].RetentionPolicies[0].ShardGroups
-	assert.Equal(t, 8, len(groups))
+	must(data.RemoveUserFromRole("writers", "user1"))
+	if authorized(data, "user1", influxql.WritePrivilege) {
+		t.Fatal("user1 authorized to write after leaving writers")
+	}
+	must(data.DropRole("readers"))
+	if authorized(data, "user1", influxql.ReadPrivilege) {
+		t.Fatal("user1 authorized to read after readers was dropped")
+	}
+	if got, exp := data.DropRole("readers"), meta.ErrRoleNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
 
-	expectTimes := []struct {
-		start, end, truncated string
+	// A dropped user is removed from its roles.
+	must(data.AddUserToRole("writers", "user1"))
+	must(data.DropUser("user1"))
+	if got := data.Role("writers").Users; len(got) != 0 {
+		t.Fatalf("got users %v, expected none", got)
+	}
+}
+
+func TestData_UpdateDataNode(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDataNode("node1:8086", "node1:8088"))
+	must(data.CreateDataNode("node2:8086", "node2:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ReplicaN = 2
+	must(data.CreateRetentionPolicy("db", rp, true))
+	must(data.CreateShardGroup("db", "rp", time.Now()))
+	before := data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].Owners
+
+	if got, exp := data.UpdateDataNode(3, "node3:8086", "node3:8088"), meta.ErrNodeNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.UpdateDataNode(1, "node1:8086", "node2:8088"), meta.ErrNodeExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// Keeping the current TCP address is not a collision.
+	must(data.UpdateDataNode(1, "host1:9086", "node1:8088"))
+	must(data.UpdateDataNode(2, "host2:9086", "host2:9088"))
+	exp := []meta.NodeInfo{
+		{ID: 1, Addr: "host1:9086", TCPAddr: "node1:8088", Status: meta.NodeStatusJoined},
+		{ID: 2, Addr: "host2:9086", TCPAddr: "host2:9088", Status: meta.NodeStatusJoined},
+	}
+	if !reflect.DeepEqual(data.DataNodes, exp) {
+		t.Fatalf("got %v, expected %v", data.DataNodes, exp)
+	}
+	if got := data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].Owners; !reflect.DeepEqual(got, before) {
+		t.Fatalf("got owners %v, expected %v", got, before)
+	}
+}
+
+func TestData_CreateDataNodeWithResult(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateMetaNode("meta1:8091", "node2:8088"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateMetaNode("meta2:8091", "meta2:8089"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		tcpAddr string
+		exp     uint64
 	}{
-		{"1970-01-01 00:00:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC", "1970-01-01 23:59:00 +0000 UTC"},
-		{"1970-01-01 23:59:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
-		{"1970-01-02 00:00:00 +0000 UTC", "1970-01-03 00:00:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC"},
-		{"1970-01-02 00:00:00 +0000 UTC", "1970-01-03 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
-		{"1970-01-03 00:00:00 +0000 UTC", "1970-01-06 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
-		{"1970-01-06 00:00:00 +0000 UTC", "1970-01-07 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
-		{"1970-01-07 00:00:00 +0000 UTC", "1970-01-09 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
-		{"1970-01-09 00:00:00 +0000 UTC", "1970-01-19 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{tcpAddr: "node3:8088", exp: 3},
+		// The meta node's ID is reused.
+		{tcpAddr: "node2:8088", exp: 1},
+		{tcpAddr: "node4:8088", exp: 4},
+	} {
+		id, err := data.CreateDataNodeWithResult("host:8086", tt.tcpAddr, "")
+		if err != nil {
+			t.Fatal(err)
+		} else if id != tt.exp {
+			t.Fatalf("%s: got id %d, expected %d", tt.tcpAddr, id, tt.exp)
+		} else if n := data.DataNode(id); n == nil || n.TCPAddr != tt.tcpAddr {
+			t.Fatalf("%s: got node %+v", tt.tcpAddr, n)
+		}
 	}
 
-	for i := range expectTimes {
-		assert.Equal(t, expectTimes[i].start, groups[i].StartTime.String(), "start time %d", i)
-		assert.Equal(t, expectTimes[i].end, groups[i].EndTime.String(), "end time %d", i)
-		assert.Equal(t, expectTimes[i].truncated, groups[i].TruncatedAt.String(), "truncate time %d", i)
+	if id, err := data.CreateDataNodeWithResult("host:8086", "node3:8088", ""); err != meta.ErrNodeExists || id != 0 {
+		t.Fatalf("got id %d and error %v, expected %v", id, err, meta.ErrNodeExists)
 	}
 }
 
-func TestUserInfo_AuthorizeDatabase(t *testing.T) {
-	emptyUser := &meta.UserInfo{}
-	if !emptyUser.AuthorizeDatabase(influxql.NoPrivileges, "anydb") {
-		t.Fatal("expected NoPrivileges to be authorized but it wasn't")
+func TestData_SetDataNodeStatus(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
 	}
-	if emptyUser.AuthorizeDatabase(influxql.ReadPrivilege, "anydb") {
-		t.Fatal("expected ReadPrivilege to prevent authorization, but it was authorized")
+	must(data.CreateDataNode("node1:8086", "node1:8088"))
+	must(data.CreateMetaNode("meta2:8091", "meta2:8089"))
+	if got, exp := data.DataNode(1).Status, meta.NodeStatusJoined; got != exp {
+		t.Fatalf("got status %q, expected %q", got, exp)
 	}
 
-	adminUser := &meta.UserInfo{Admin: true}
-	if !adminUser.AuthorizeDatabase(influxql.AllPrivileges, "anydb") {
-		t.Fatalf("expected admin to be authorized but it wasn't")
+	if got, exp := data.SetDataNodeStatus(1, "draining"), meta.ErrInvalidNodeStatus; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.SetDataNodeStatus(2, meta.NodeStatusDisjoined), meta.ErrNodeNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	must(data.SetDataNodeStatus(1, meta.NodeStatusDisjoined))
+
+	// The status survives a marshal round trip and is reported by the data
+	// node's info.
+	buf, err := data.MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+	if got, exp := meta.NewDataNodeInfo(other.DataNode(1)).Status, meta.NodeStatusDisjoined; got != exp {
+		t.Fatalf("got status %q, expected %q", got, exp)
+	}
+	if got := other.MetaNode(2).Status; got != "" {
+		t.Fatalf("got meta node status %q, expected none", got)
 	}
 }
 
-func TestShardGroupInfo_Contains(t *testing.T) {
-	sgi := &meta.ShardGroupInfo{StartTime: time.Unix(10, 0), EndTime: time.Unix(20, 0)}
+func TestData_PromoteToDataNode(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateMetaNodeWithZone("meta1:8091", "meta1:8089", "zone-a"))
+	must(data.CreateDataNode("node2:8086", "node2:8088"))
+	must(data.CreateMetaNode("meta3:8091", "node2:8088"))
 
-	tests := []struct {
-		ts  time.Time
-		exp bool
+	if got, exp := data.PromoteToDataNode(4, "meta4:8086", "meta4:8088"), meta.ErrNodeNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.PromoteToDataNode(2, "meta3:8086", "meta3:8088"), meta.ErrNodeAlreadyDataNode; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.PromoteToDataNode(1, "meta1:8086", "node2:8088"), meta.ErrNodeExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	must(data.PromoteToDataNode(1, "meta1:8086", "meta1:8088"))
+	exp := []meta.NodeInfo{
+		{ID: 1, Addr: "meta1:8086", TCPAddr: "meta1:8088", Zone: "zone-a", Status: meta.NodeStatusJoined},
+		{ID: 2, Addr: "node2:8086", TCPAddr: "node2:8088", Status: meta.NodeStatusJoined},
+	}
+	if !reflect.DeepEqual(data.DataNodes, exp) {
+		t.Fatalf("got %v, expected %v", data.DataNodes, exp)
+	}
+	if got, exp := data.MaxNodeID, uint64(2); got != exp {
+		t.Fatalf("got max node id %d, expected %d", got, exp)
+	}
+	if got, exp := data.PromoteToDataNode(1, "meta1:8086", "meta1:8088"), meta.ErrNodeAlreadyDataNode; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_IdleShards(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 1, Shards: []meta.ShardInfo{{ID: 1}, {ID: 2}, {ID: 3}}},
+							{ID: 2, Shards: []meta.ShardInfo{{ID: 4}}, DeletedAt: time.Now()},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	data.TouchShard(1, ts)
+	data.TouchShard(2, ts.Add(time.Hour))
+	data.TouchShard(2, ts) // earlier times are ignored
+	data.TouchShard(100, ts)
+
+	if got, exp := data.IdleShards(ts.Add(time.Minute)), []uint64{1, 3}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.IdleShards(ts.Add(2*time.Hour)), []uint64{1, 2, 3}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// The latest write time survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	shards := other.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards
+	if got, exp := shards[1].LastWrite, ts.Add(time.Hour); !got.Equal(exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if !shards[2].LastWrite.IsZero() {
+		t.Fatalf("got %v, expected zero time", shards[2].LastWrite)
+	}
+}
+
+func TestData_CreateContinuousQuery(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	query := `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`
+	if err := data.CreateContinuousQuery("db0", "cq0", query); err != nil {
+		t.Fatal(err)
+	}
+
+	// Recreating the same query is a no-op regardless of case.
+	if err := data.CreateContinuousQuery("db0", "cq0", strings.ToLower(query)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{
+		`CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY`,
+		`SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m)`,
+		`CREATE CONTINUOUS QUERY cq1 ON db1 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`,
+		`CREATE CONTINUOUS QUERY cq2 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`,
+	} {
+		if err := data.CreateContinuousQuery("db0", "cq1", query); err == nil {
+			t.Fatalf("expected an error for %q", query)
+		}
+	}
+	if got, exp := len(data.Database("db0").ContinuousQueries), 1; got != exp {
+		t.Fatalf("got %d continuous queries, expected %d", got, exp)
+	}
+}
+
+func TestData_UpdateContinuousQuery(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`))
+	must(data.SetContinuousQueryEnabled("db0", "cq0", false))
+
+	// A query differing only in case replaces the old one.
+	query := `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO foo_mean FROM foo GROUP BY time(10m) END`
+	must(data.UpdateContinuousQuery("db0", "cq0", strings.ToLower(query)))
+	if got, exp := data.Database("db0").ContinuousQueries[0].Query, strings.ToLower(query); got != exp {
+		t.Fatalf("got query %q, expected %q", got, exp)
+	}
+	must(data.UpdateContinuousQuery("db0", "cq0", query))
+	must(data.UpdateContinuousQuery("db0", "cq0", query))
+	if cqi := data.Database("db0").ContinuousQueries[0]; cqi.Query != query || cqi.Enabled {
+		t.Fatalf("got %+v, expected the new query to stay disabled", cqi)
+	}
+
+	for _, q := range []string{
+		`SELECT mean(value) INTO foo_mean FROM foo GROUP BY time(10m)`,
+		`CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT mean(value) INTO foo_mean FROM foo GROUP BY time(10m) END`,
+	} {
+		if err := data.UpdateContinuousQuery("db0", "cq0", q); err == nil {
+			t.Fatalf("expected an error for %q", q)
+		}
+	}
+	if got, exp := data.UpdateContinuousQuery("db0", "cq1", query), meta.ErrContinuousQueryNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got := data.Database("db0").ContinuousQueries[0].Query; got != query {
+		t.Fatalf("got query %q, expected %q", got, query)
+	}
+}
+
+func TestData_SetContinuousQueryEnabled(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m) END`))
+	if !data.Database("db0").ContinuousQueries[0].Enabled {
+		t.Fatal("expected new continuous query to be enabled")
+	}
+
+	must(data.SetContinuousQueryEnabled("db0", "cq0", false))
+	if got, exp := data.SetContinuousQueryEnabled("db0", "cq1", false), meta.ErrContinuousQueryNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if err := data.SetContinuousQueryEnabled("db1", "cq0", false); err == nil {
+		t.Fatal("expected an error for an unknown database")
+	}
+
+	// The flag survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+	if other.Database("db0").ContinuousQueries[0].Enabled {
+		t.Fatal("expected continuous query to stay disabled")
+	}
+}
+
+func TestData_ContinuousQueriesReferencingRetentionPolicy(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateContinuousQuery("db0", "into", `CREATE CONTINUOUS QUERY "into" ON "db0" BEGIN SELECT mean(value) INTO "db0"."old"."cpu_1h" FROM "cpu" GROUP BY time(1h) END`))
+	must(data.CreateContinuousQuery("db0", "from", `CREATE CONTINUOUS QUERY "from" ON "db0" BEGIN SELECT mean(value) INTO "cpu_1h" FROM "old"."cpu" GROUP BY time(1h) END`))
+	must(data.CreateContinuousQuery("db0", "unrelated", `CREATE CONTINUOUS QUERY "unrelated" ON "db0" BEGIN SELECT mean(value) INTO "cpu_1h" FROM "cpu" GROUP BY time(1h) END`))
+	must(data.CreateContinuousQuery("db1", "other", `CREATE CONTINUOUS QUERY "other" ON "db1" BEGIN SELECT mean(value) INTO "cpu_1h" FROM "db0"."old"."cpu" GROUP BY time(1h) END`))
+	must(data.CreateContinuousQuery("db1", "samename", `CREATE CONTINUOUS QUERY "samename" ON "db1" BEGIN SELECT mean(value) INTO "cpu_1h" FROM "old"."cpu" GROUP BY time(1h) END`))
+
+	names, err := data.ContinuousQueriesReferencingRetentionPolicy("db0", "old")
+	must(err)
+	if exp := []string{"from", "into", "other"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
+	}
+
+	names, err = data.RenameRetentionPolicyInContinuousQueries("db0", "old", "new")
+	must(err)
+	if exp := []string{"from", "into", "other"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
+	}
+	if names, err := data.ContinuousQueriesReferencingRetentionPolicy("db0", "old"); err != nil {
+		t.Fatal(err)
+	} else if len(names) != 0 {
+		t.Fatalf("got %v, expected no queries", names)
+	}
+	if names, err := data.ContinuousQueriesReferencingRetentionPolicy("db0", "new"); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"from", "into", "other"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
+	}
+
+	if _, err := data.ContinuousQueriesReferencingRetentionPolicy("nope", "old"); err == nil {
+		t.Fatal("expected error for unknown database")
+	}
+}
+
+func TestData_ShardsByNodeID(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{
+								ID: 1,
+								Shards: []meta.ShardInfo{
+									{ID: 3, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+									{ID: 1, Owners: []meta.ShardOwner{{NodeID: 2}}},
+								},
+							},
+							{
+								ID:        2,
+								Shards:    []meta.ShardInfo{{ID: 4, Owners: []meta.ShardOwner{{NodeID: 1}}}},
+								DeletedAt: time.Now(),
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "db1",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp1",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 3, Shards: []meta.ShardInfo{{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	exp := []meta.NodeShard{
+		{Database: "db1", RetentionPolicy: "rp1", ShardGroupID: 3, ShardInfo: meta.ShardInfo{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}}}},
+		{Database: "db0", RetentionPolicy: "rp0", ShardGroupID: 1, ShardInfo: meta.ShardInfo{ID: 3, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}}},
+	}
+	if got := data.ShardsByNodeID(1); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got := data.ShardsByNodeID(3); len(got) != 0 {
+		t.Fatalf("got %v, expected no shards", got)
+	}
+}
+
+func TestData_OrphanedShards(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{
+								ID: 1,
+								Shards: []meta.ShardInfo{
+									{ID: 3},
+									{ID: 1, Owners: []meta.ShardOwner{{NodeID: 2}}},
+								},
+							},
+							{
+								ID:        2,
+								Shards:    []meta.ShardInfo{{ID: 4}},
+								DeletedAt: time.Now(),
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "db1",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp1",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 3, Shards: []meta.ShardInfo{{ID: 2, Owners: []meta.ShardOwner{}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	exp := []meta.NodeShard{
+		{Database: "db1", RetentionPolicy: "rp1", ShardGroupID: 3, ShardInfo: meta.ShardInfo{ID: 2, Owners: []meta.ShardOwner{}}},
+		{Database: "db0", RetentionPolicy: "rp0", ShardGroupID: 1, ShardInfo: meta.ShardInfo{ID: 3}},
+	}
+	if got := data.OrphanedShards(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_ShardOwnerCounts(t *testing.T) {
+	data := &meta.Data{
+		DataNodes: []meta.NodeInfo{{ID: 1}, {ID: 2}, {ID: 3}},
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{
+								ID: 1,
+								Shards: []meta.ShardInfo{
+									{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+									{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 4}}},
+								},
+							},
+							{
+								ID:        2,
+								Shards:    []meta.ShardInfo{{ID: 3, Owners: []meta.ShardOwner{{NodeID: 3}}}},
+								DeletedAt: time.Now(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	exp := map[uint64]int{1: 2, 2: 1, 3: 0}
+	if got := data.ShardOwnerCounts(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	strict := meta.NameRules{ForbiddenPrefixes: []string{"tmp_", "_"}, AllowInternal: true, MaxRunes: 4}
+	for _, tt := range []struct {
+		name         string
+		valid, rules bool
 	}{
-		{time.Unix(0, 0), false},
-		{time.Unix(9, 0), false},
-		{time.Unix(10, 0), true},
-		{time.Unix(11, 0), true},
-		{time.Unix(15, 0), true},
-		{time.Unix(19, 0), true},
-		{time.Unix(20, 0), false},
-		{time.Unix(21, 0), false},
+		{name: "db", valid: true, rules: true},
+		{name: "", valid: false, rules: false},
+		{name: "..", valid: false, rules: false},
+		{name: "a/b", valid: false, rules: false},
+		{name: "_db", valid: true, rules: false},
+		// The _internal database is exempt from the prefixes and rune limit.
+		{name: "_internal", valid: true, rules: true},
+		{name: "tmp_", valid: true, rules: false},
+		{name: "dbdb", valid: true, rules: true},
+		{name: "dbdbd", valid: true, rules: false},
+		// The rune limit counts characters, not bytes.
+		{name: "日本語", valid: true, rules: true},
+	} {
+		if got := meta.ValidName(tt.name); got != tt.valid {
+			t.Errorf("ValidName(%q): got %v, expected %v", tt.name, got, tt.valid)
+		}
+		if got := meta.ValidName(tt.name, meta.NameRules{}); got != tt.valid {
+			t.Errorf("ValidName(%q) with zero rules: got %v, expected %v", tt.name, got, tt.valid)
+		}
+		if got := meta.ValidName(tt.name, strict); got != tt.rules {
+			t.Errorf("ValidName(%q) with rules: got %v, expected %v", tt.name, got, tt.rules)
+		}
 	}
-	for _, test := range tests {
-		t.Run(fmt.Sprintf("ts=%d", test.ts.Unix()), func(t *testing.T) {
-			got := sgi.Contains(test.ts)
-			assert.Equal(t, got, test.exp)
-		})
+}
+
+func TestData_CreateDatabaseWithRetentionPolicy(t *testing.T) {
+	data := &meta.Data{}
+
+	rpi := meta.NewRetentionPolicyInfo("rp0")
+	if err := data.CreateDatabaseWithRetentionPolicy("db0", rpi); err != nil {
+		t.Fatal(err)
+	}
+	di := data.Database("db0")
+	if di == nil || di.DefaultRetentionPolicy != "rp0" || di.RetentionPolicy("rp0") == nil {
+		t.Fatalf("unexpected database %+v", di)
+	}
+
+	// Creating the same database and policy again is a no-op.
+	if err := data.CreateDatabaseWithRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A different default policy, or different settings, conflict.
+	if err := data.CreateDatabaseWithRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp1")); err != meta.ErrRetentionPolicyConflict {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrRetentionPolicyConflict)
+	}
+	other := meta.NewRetentionPolicyInfo("rp0")
+	other.ReplicaN = 3
+	if err := data.CreateDatabaseWithRetentionPolicy("db0", other); err != meta.ErrRetentionPolicyConflict {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrRetentionPolicyConflict)
+	}
+
+	// An invalid policy doesn't leave a database behind.
+	invalid := meta.NewRetentionPolicyInfo("rp0")
+	invalid.ReplicaN = 0
+	if err := data.CreateDatabaseWithRetentionPolicy("db1", invalid); err != meta.ErrReplicationFactorTooLow {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrReplicationFactorTooLow)
+	}
+	if data.Database("db1") != nil {
+		t.Fatal("database created without its retention policy")
 	}
 }
 
-func randString(n int) string {
-	var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	b := make([]rune, n)
-	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+func TestData_SetDatabaseLimits(t *testing.T) {
+	data := &meta.Data{Index: 1}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateDatabase("db1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := data.SetDatabaseLimits("db0", 1000000, 100000); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.SetDatabaseLimits("db0", -1, 0); err != meta.ErrDatabaseLimitNegative {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrDatabaseLimitNegative)
+	}
+	if err := data.SetDatabaseLimits("nope", 1, 1); err == nil {
+		t.Fatal("expected error for missing database")
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &meta.Data{}
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if di := other.Database("db0"); di.MaxSeriesPerDatabase != 1000000 || di.MaxValuesPerTag != 100000 {
+		t.Fatalf("got limits %d and %d, expected 1000000 and 100000", di.MaxSeriesPerDatabase, di.MaxValuesPerTag)
+	}
+	// Databases without limits stay unlimited.
+	if di := other.Database("db1"); di.MaxSeriesPerDatabase != 0 || di.MaxValuesPerTag != 0 {
+		t.Fatalf("got limits %d and %d, expected unlimited", di.MaxSeriesPerDatabase, di.MaxValuesPerTag)
+	}
+}
+
+func TestData_CreateRetentionPolicy_DefaultShardGroupDuration(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	if err := data.SetDefaultShardGroupDuration("db", -time.Hour); err != meta.ErrShardGroupDurationNegative {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	must(data.SetDefaultShardGroupDuration("db", 2*time.Hour))
+
+	// The database default survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	if got, exp := other.Database("db").DefaultShardGroupDuration, 2*time.Hour; got != exp {
+		t.Fatalf("got default shard group duration %v, expected %v", got, exp)
+	}
+
+	must(other.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "inherit", ReplicaN: 1, Duration: 7 * 24 * time.Hour}, true))
+	must(other.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "explicit", ReplicaN: 1, Duration: 7 * 24 * time.Hour, ShardGroupDuration: 24 * time.Hour}, false))
+	must(other.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "short", ReplicaN: 1, Duration: time.Hour}, false))
+
+	for _, tt := range []struct {
+		rp  string
+		exp time.Duration
+	}{
+		{rp: "inherit", exp: 2 * time.Hour},
+		{rp: "explicit", exp: 24 * time.Hour},
+		// The default is longer than the policy, so the derived duration is used.
+		{rp: "short", exp: time.Hour},
+	} {
+		rpi, err := other.RetentionPolicy("db", tt.rp)
+		must(err)
+		if rpi.ShardGroupDuration != tt.exp {
+			t.Fatalf("%s: got shard group duration %v, expected %v", tt.rp, rpi.ShardGroupDuration, tt.exp)
+		}
+	}
+}
+
+func TestData_NodeByTCPAddr(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateMetaNode("host0:8091", "host0:8089"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateDataNode("host1:8086", "host1:8088"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateDataNode("host0:8086", "host0:8089"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := data.DataNodeByTCPAddr("host1:8088"); n == nil || n.ID != 2 || n.Addr != "host1:8086" {
+		t.Fatalf("unexpected data node: %v", n)
+	}
+	// A data node sharing a meta node's TCP address shares its ID.
+	if n := data.DataNodeByTCPAddr("host0:8089"); n == nil || n.ID != 1 {
+		t.Fatalf("unexpected data node: %v", n)
+	}
+	if n := data.MetaNodeByTCPAddr("host0:8089"); n == nil || n.ID != 1 || n.Addr != "host0:8091" {
+		t.Fatalf("unexpected meta node: %v", n)
+	}
+	if n := data.DataNodeByTCPAddr("host2:8088"); n != nil {
+		t.Fatalf("got %v, expected no data node", n)
+	}
+	if n := data.MetaNodeByTCPAddr("host1:8088"); n != nil {
+		t.Fatalf("got %v, expected no meta node", n)
+	}
+}
+
+func TestData_RenameDatabase(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateUser("susy", "pass", false))
+	must(data.SetPrivilege("susy", "db0", influxql.WritePrivilege))
+	must(data.CreateRole("writers"))
+	must(data.SetRolePrivilege("writers", "db0", influxql.ReadPrivilege))
+
+	if err := data.RenameDatabase("nope", "db2"); err == nil || err.Error() != influxdb.ErrDatabaseNotFound("nope").Error() {
+		t.Fatalf("got %v, expected %v", err, influxdb.ErrDatabaseNotFound("nope"))
+	}
+	if err := data.RenameDatabase("db0", "db1"); err != meta.ErrDatabaseExists {
+		t.Fatalf("got %v, expected %v", err, meta.ErrDatabaseExists)
+	}
+	if err := data.RenameDatabase("db0", "db/2"); err != meta.ErrInvalidName {
+		t.Fatalf("got %v, expected %v", err, meta.ErrInvalidName)
+	}
+
+	// A database with shard groups is not renamed.
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateRetentionPolicy("db1", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateShardGroup("db1", "rp0", time.Unix(0, 0)))
+	if err := data.RenameDatabase("db1", "db3"); err != meta.ErrDatabaseHasShards {
+		t.Fatalf("got %v, expected %v", err, meta.ErrDatabaseHasShards)
+	}
+
+	must(data.RenameDatabase("db0", "db2"))
+
+	if data.Database("db0") != nil {
+		t.Fatal("expected db0 to be gone")
+	}
+	di := data.Database("db2")
+	if di == nil {
+		t.Fatal("expected db2 to exist")
+	} else if di.DefaultRetentionPolicy != "rp0" || di.RetentionPolicy("rp0") == nil {
+		t.Fatalf("got default retention policy %q, expected rp0", di.DefaultRetentionPolicy)
+	}
+
+	privs, err := data.UserPrivileges("susy")
+	must(err)
+	if _, ok := privs["db0"]; ok {
+		t.Fatal("expected no privilege on db0")
+	} else if privs["db2"] != influxql.WritePrivilege {
+		t.Fatalf("got privilege %v on db2, expected %v", privs["db2"], influxql.WritePrivilege)
+	}
+	if p := data.Role("writers").Privileges["db2"]; p != influxql.ReadPrivilege {
+		t.Fatalf("got role privilege %v on db2, expected %v", p, influxql.ReadPrivilege)
+	}
+}
+
+func TestData_RenameRetentionPolicy(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp1"), false))
+	must(data.CreateSubscription("db", "rp0", "sub", "ALL", []string{"udp://h1:9093"}))
+
+	if err := data.RenameRetentionPolicy("db", "nope", "rp2"); err == nil || err.Error() != influxdb.ErrRetentionPolicyNotFound("nope").Error() {
+		t.Fatalf("got %v, expected %v", err, influxdb.ErrRetentionPolicyNotFound("nope"))
+	}
+	if err := data.RenameRetentionPolicy("db", "rp0", "rp1"); err != meta.ErrRetentionPolicyNameExists {
+		t.Fatalf("got %v, expected %v", err, meta.ErrRetentionPolicyNameExists)
+	}
+
+	// A policy with shard groups is not renamed.
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp4"), false))
+	must(data.CreateShardGroup("db", "rp4", time.Unix(0, 0)))
+	if err := data.RenameRetentionPolicy("db", "rp4", "rp5"); err != meta.ErrRetentionPolicyHasShards {
+		t.Fatalf("got %v, expected %v", err, meta.ErrRetentionPolicyHasShards)
+	}
+
+	must(data.RenameRetentionPolicy("db", "rp0", "rp2"))
+
+	di := data.Database("db")
+	if di.RetentionPolicy("rp0") != nil {
+		t.Fatal("expected rp0 to be gone")
+	} else if di.DefaultRetentionPolicy != "rp2" {
+		t.Fatalf("got default retention policy %q, expected rp2", di.DefaultRetentionPolicy)
+	}
+	if rpi := di.RetentionPolicy("rp2"); rpi == nil || len(rpi.Subscriptions) != 1 {
+		t.Fatalf("expected rp2 to keep its subscription: %v", rpi)
+	}
+
+	// Renaming a policy that isn't the default leaves the default alone.
+	must(data.RenameRetentionPolicy("db", "rp1", "rp3"))
+	if di.DefaultRetentionPolicy != "rp2" {
+		t.Fatalf("got default retention policy %q, expected rp2", di.DefaultRetentionPolicy)
+	}
+}
+
+func TestData_ShardGroupByTimestampDefaultRP(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, _, err := data.ShardGroupByTimestampDefaultRP("db", time.Unix(0, 0)); err == nil || err.Error() != influxdb.ErrDatabaseNotFound("db").Error() {
+		t.Fatalf("got %v, expected %v", err, influxdb.ErrDatabaseNotFound("db"))
+	}
+
+	must(data.CreateDataNode("foo:8086", "bar:8088"))
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp0"), false))
+
+	if _, _, err := data.ShardGroupByTimestampDefaultRP("db", time.Unix(0, 0)); err != meta.ErrNoDefaultRetentionPolicy {
+		t.Fatalf("got %v, expected %v", err, meta.ErrNoDefaultRetentionPolicy)
+	}
+
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp1"), true))
+	must(data.CreateShardGroup("db", "rp0", time.Unix(0, 0)))
+	must(data.CreateShardGroup("db", "rp1", time.Unix(0, 0)))
+
+	sgi, rp, err := data.ShardGroupByTimestampDefaultRP("db", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if rp != "rp1" {
+		t.Fatalf("got retention policy %q, expected rp1", rp)
+	}
+	exp, err := data.ShardGroupByTimestamp("db", "rp1", time.Unix(0, 0))
+	must(err)
+	if sgi == nil || sgi.ID != exp.ID {
+		t.Fatalf("got shard group %v, expected %d", sgi, exp.ID)
+	}
+}
+
+func TestData_CreateShardGroup_ReplicaN(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		must(data.CreateDataNode(fmt.Sprintf("host%d:8086", i), fmt.Sprintf("host%d:8088", i)))
+	}
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ReplicaN = 1
+	rp.ShardGroupDuration = time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+	must(data.CreateShardGroupWithReplicaN("db", "rp", time.Unix(0, 0).Add(time.Hour), 3))
+	// An override beyond the number of data nodes is limited to it.
+	must(data.CreateShardGroupWithReplicaN("db", "rp", time.Unix(0, 0).Add(2*time.Hour), 5))
+
+	// The override survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+
+	groups, err := other.ShardGroups("db", "rp")
+	must(err)
+	if len(groups) != 3 {
+		t.Fatalf("got %d shard groups, expected 3", len(groups))
+	}
+	for i, exp := range []struct{ replicaN, effective int }{{0, 1}, {3, 3}, {3, 3}} {
+		sg := groups[i]
+		if sg.ReplicaN != exp.replicaN || sg.EffectiveReplicaN(rp.ReplicaN) != exp.effective {
+			t.Fatalf("group %d: got ReplicaN %d (effective %d), expected %d (effective %d)", sg.ID, sg.ReplicaN, sg.EffectiveReplicaN(rp.ReplicaN), exp.replicaN, exp.effective)
+		}
+		for _, s := range sg.Shards {
+			if len(s.Owners) != exp.effective {
+				t.Fatalf("group %d shard %d: got %d owners, expected %d", sg.ID, s.ID, len(s.Owners), exp.effective)
+			}
+		}
+	}
+
+	// Precreated shard groups keep the override of the group before them.
+	must(data.PrecreateShardGroupsRange(time.Unix(0, 0).Add(2*time.Hour), time.Unix(0, 0).Add(4*time.Hour)))
+	sgi, err := data.ShardGroupByTimestamp("db", "rp", time.Unix(0, 0).Add(3*time.Hour))
+	must(err)
+	if sgi == nil || sgi.ReplicaN != 3 {
+		t.Fatalf("unexpected precreated shard group: %+v", sgi)
+	}
+}
+
+func TestData_TruncateShardGroups(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "bar:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+
+	sg0, err := data.ShardGroupByTimestamp("db", "rp", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal("Failed to find shard group:", err)
+	}
+
+	if sg0.Truncated() {
+		t.Fatal("shard group already truncated")
+	}
+
+	sgEnd, err := data.ShardGroupByTimestamp("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration-1))
+	if err != nil {
+		t.Fatal("Failed to find shard group for end range:", err)
+	}
+
+	if sgEnd == nil || sgEnd.ID != sg0.ID {
+		t.Fatalf("Retention policy mis-match: Expected %v, Got %v", sg0, sgEnd)
+	}
+
+	must(data.CreateShardGroup("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration)))
+
+	sg1, err := data.ShardGroupByTimestamp("db", "rp", sg0.StartTime.Add(rp.ShardGroupDuration+time.Minute))
+	if err != nil {
+		t.Fatal("Failed to find second shard group:", err)
+	}
+
+	if sg1.Truncated() {
+		t.Fatal("second shard group already truncated")
+	}
+
+	// shouldn't do anything
+	must(data.CreateShardGroup("db", "rp", sg0.EndTime.Add(-time.Minute)))
+
+	sgs, err := data.ShardGroupsByTimeRange("db", "rp", time.Unix(0, 0), sg1.EndTime.Add(time.Minute))
+	if err != nil {
+		t.Fatal("Failed to find shard groups:", err)
+	}
+
+	if len(sgs) != 2 {
+		t.Fatalf("Expected %d shard groups, found %d", 2, len(sgs))
+	}
+
+	truncateTime := sg0.EndTime.Add(-time.Minute)
+	data.TruncateShardGroups(truncateTime)
+
+	// at this point, we should get nil shard groups for times after truncateTime
+	for _, tc := range []struct {
+		t      time.Time
+		exists bool
+	}{
+		{sg0.StartTime, true},
+		{sg0.EndTime.Add(-1), false},
+		{truncateTime.Add(-1), true},
+		{truncateTime, false},
+		{sg1.StartTime, false},
+	} {
+		sg, err := data.ShardGroupByTimestamp("db", "rp", tc.t)
+		if err != nil {
+			t.Fatalf("Failed to find shardgroup for %v: %v", tc.t, err)
+		}
+		if tc.exists && sg == nil {
+			t.Fatalf("Shard group for timestamp '%v' should exist, got nil", tc.t)
+		}
+	}
+
+	for _, x := range data.Databases[0].RetentionPolicies[0].ShardGroups {
+		switch x.ID {
+		case sg0.ID:
+			*sg0 = x
+		case sg1.ID:
+			*sg1 = x
+		}
+	}
+
+	if sg0.TruncatedAt != truncateTime {
+		t.Fatalf("Incorrect truncation of current shard group. Expected %v, got %v", truncateTime, sg0.TruncatedAt)
+	}
+
+	if sg1.TruncatedAt != sg1.StartTime {
+		t.Fatalf("Incorrect truncation of future shard group. Expected %v, got %v", sg1.StartTime, sg1.TruncatedAt)
+	}
+
+	groups := data.Databases[0].RetentionPolicies[0].ShardGroups
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, "1970-01-01 00:00:00 +0000 UTC", groups[0].StartTime.String())
+	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[0].EndTime.String())
+	assert.Equal(t, "1970-01-01 23:59:00 +0000 UTC", groups[0].TruncatedAt.String())
+
+	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[1].StartTime.String())
+	assert.Equal(t, "1970-01-03 00:00:00 +0000 UTC", groups[1].EndTime.String())
+	assert.Equal(t, "1970-01-02 00:00:00 +0000 UTC", groups[1].TruncatedAt.String())
+
+	// Create some more shard groups and validate there is no overlap
+	// Add a shard starting at sg0's truncation time, until 01/02
+	must(data.CreateShardGroup("db", "rp", sg0.EndTime.Add(-time.Second)))
+	// Add a shard 01/02 - 01/03 (since sg1 is fully truncated)
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(-time.Second)))
+	// Add a shard 01/06 - 01/07
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(3*rp.ShardGroupDuration)))
+	newDuration := 10 * rp.ShardGroupDuration
+	data.UpdateRetentionPolicy("db", "rp", &meta.RetentionPolicyUpdate{
+		Name:               nil,
+		Duration:           nil,
+		ReplicaN:           nil,
+		ShardGroupDuration: &newDuration,
+	}, true)
+	// Add a shard 01/03 - 01/06
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(1*rp.ShardGroupDuration)))
+	// Add a shard 01/07 - 01/09
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(4*rp.ShardGroupDuration)))
+	// Add a shard 01/09 - 01/19
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(10*rp.ShardGroupDuration)))
+	// No additional shard added
+	must(data.CreateShardGroup("db", "rp", sg1.EndTime.Add(11*rp.ShardGroupDuration)))
+
+	groups = data.Databases[0].RetentionPolicies[0].ShardGroups
+	assert.Equal(t, 8, len(groups))
+
+	expectTimes := []struct {
+		start, end, truncated string
+	}{
+		{"1970-01-01 00:00:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC", "1970-01-01 23:59:00 +0000 UTC"},
+		{"1970-01-01 23:59:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{"1970-01-02 00:00:00 +0000 UTC", "1970-01-03 00:00:00 +0000 UTC", "1970-01-02 00:00:00 +0000 UTC"},
+		{"1970-01-02 00:00:00 +0000 UTC", "1970-01-03 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{"1970-01-03 00:00:00 +0000 UTC", "1970-01-06 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{"1970-01-06 00:00:00 +0000 UTC", "1970-01-07 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{"1970-01-07 00:00:00 +0000 UTC", "1970-01-09 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+		{"1970-01-09 00:00:00 +0000 UTC", "1970-01-19 00:00:00 +0000 UTC", "0001-01-01 00:00:00 +0000 UTC"},
+	}
+
+	for i := range expectTimes {
+		assert.Equal(t, expectTimes[i].start, groups[i].StartTime.String(), "start time %d", i)
+		assert.Equal(t, expectTimes[i].end, groups[i].EndTime.String(), "end time %d", i)
+		assert.Equal(t, expectTimes[i].truncated, groups[i].TruncatedAt.String(), "truncate time %d", i)
+	}
+}
+
+func TestData_WouldCreateShardGroup(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "bar:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.Duration = 7 * 24 * time.Hour
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	now := time.Now().UTC()
+	must(data.CreateShardGroup("db", "rp", now))
+
+	for _, tc := range []struct {
+		name string
+		t    time.Time
+		exp  bool
+	}{
+		{"in range, missing", now.Add(-3 * 24 * time.Hour), true},
+		{"in range, existing", now, false},
+		{"out of range", now.Add(-8 * 24 * time.Hour), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := data.WouldCreateShardGroup("db", "rp", tc.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, got)
+		})
+	}
+
+	// Asking must not create anything.
+	groups, err := data.ShardGroups("db", "rp")
+	must(err)
+	assert.Equal(t, 1, len(groups))
+
+	if _, err := data.WouldCreateShardGroup("db", "nope", now); err == nil {
+		t.Fatal("expected error for missing retention policy")
+	}
+}
+
+func TestData_SetPendingShardCount(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.Duration = 7 * 24 * time.Hour
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	must(data.CreateShardGroup("db", "rp", day))
+
+	if err := data.SetPendingShardCount("db", "rp", 0); err != meta.ErrShardCountTooLow {
+		t.Fatalf("got %v, expected %v", err, meta.ErrShardCountTooLow)
+	}
+	if err := data.SetPendingShardCount("db", "nope", 4); err == nil {
+		t.Fatal("expected error for missing retention policy")
+	}
+	must(data.SetPendingShardCount("db", "rp", 4))
+	must(data.CreateShardGroup("db", "rp", day.Add(24*time.Hour)))
+
+	groups, err := data.ShardGroups("db", "rp")
+	must(err)
+	if got, exp := len(groups), 2; got != exp {
+		t.Fatalf("got %d shard groups, expected %d", got, exp)
+	}
+	if got, exp := len(groups[0].Shards), 1; got != exp {
+		t.Fatalf("got %d shards in existing group, expected %d", got, exp)
+	}
+	if got, exp := len(groups[1].Shards), 4; got != exp {
+		t.Fatalf("got %d shards in new group, expected %d", got, exp)
+	}
+
+	// The pending count survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	rpi, err := other.RetentionPolicy("db", "rp")
+	must(err)
+	if got, exp := rpi.PendingShardCount, 4; got != exp {
+		t.Fatalf("got pending shard count %d, expected %d", got, exp)
+	}
+}
+
+func TestData_PreCreateShardGroups(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ShardGroupDuration = time.Hour
+	rp.PreCreateCount = 3
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	now := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	must(data.CreateShardGroup("db", "rp", now))
+	// The second future group already exists.
+	must(data.CreateShardGroup("db", "rp", now.Add(2*time.Hour)))
+
+	ids, err := data.PreCreateShardGroups("db", "rp", now)
+	must(err)
+	if got, exp := len(ids), 2; got != exp {
+		t.Fatalf("got %d shard groups created, expected %d", got, exp)
+	}
+
+	groups, err := data.ShardGroups("db", "rp")
+	must(err)
+	var starts []time.Time
+	for _, sgi := range groups {
+		starts = append(starts, sgi.StartTime)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	start := now.Truncate(time.Hour)
+	exp := []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour), start.Add(3 * time.Hour)}
+	if !reflect.DeepEqual(starts, exp) {
+		t.Fatalf("got shard group starts %v, expected %v", starts, exp)
+	}
+
+	// Running it again creates nothing.
+	ids, err = data.PreCreateShardGroups("db", "rp", now)
+	must(err)
+	if len(ids) != 0 {
+		t.Fatalf("got %v created on second run, expected none", ids)
+	}
+
+	if _, err := data.PreCreateShardGroups("db", "nope", now); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+
+	// The count survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	rpi, err := other.RetentionPolicy("db", "rp")
+	must(err)
+	if got, exp := rpi.PreCreateCount, 3; got != exp {
+		t.Fatalf("got pre-create count %d, expected %d", got, exp)
+	}
+}
+
+func TestData_PrecreateShardGroupsRange(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	for _, db := range []string{"db0", "db1"} {
+		rp := meta.NewRetentionPolicyInfo("rp")
+		rp.ShardGroupDuration = time.Hour
+		must(data.CreateRetentionPolicy(db, rp, true))
+	}
+
+	now := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	start := now.Truncate(time.Hour)
+	must(data.CreateShardGroup("db0", "rp", now))
+	// A deleted group is replaced and a truncated group is followed by a new one.
+	must(data.CreateShardGroup("db0", "rp", now.Add(time.Hour)))
+	must(data.CreateShardGroup("db0", "rp", now.Add(2*time.Hour)))
+	groups, err := data.ShardGroups("db0", "rp")
+	must(err)
+	must(data.DeleteShardGroup("db0", "rp", groups[1].ID))
+	data.Database("db0").RetentionPolicy("rp").ShardGroupByTimestamp(now.Add(2 * time.Hour)).TruncatedAt = start.Add(2*time.Hour + 30*time.Minute)
+
+	cutoff := now.Add(3 * time.Hour)
+	must(data.PrecreateShardGroupsRange(now, cutoff))
+
+	covered := func(db string) {
+		t.Helper()
+		rpi, err := data.RetentionPolicy(db, "rp")
+		must(err)
+		for ts := now; ts.Before(cutoff); ts = ts.Add(15 * time.Minute) {
+			if rpi.ShardGroupByTimestamp(ts) == nil {
+				t.Fatalf("%s: no shard group for %v", db, ts)
+			}
+		}
+	}
+	covered("db0")
+	covered("db1")
+
+	// Running it again creates nothing.
+	maxID := data.MaxShardGroupID
+	must(data.PrecreateShardGroupsRange(now, cutoff))
+	if data.MaxShardGroupID != maxID {
+		t.Fatalf("got max shard group ID %d on second run, expected %d", data.MaxShardGroupID, maxID)
+	}
+}
+
+func TestData_CreateShardGroupWithOwners(t *testing.T) {
+	data := &meta.Data{}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDataNode("node1:8086", "node1:8088"))
+	must(data.CreateDataNode("node2:8086", "node2:8088"))
+	must(data.CreateDatabase("db"))
+	rpi := meta.NewRetentionPolicyInfo("rp")
+	rpi.ReplicaN = 1
+	must(data.CreateRetentionPolicy("db", rpi, true))
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		owners [][]meta.ShardOwner
+		err    error
+	}{
+		{owners: [][]meta.ShardOwner{{{NodeID: 1}}}, err: meta.ErrShardOwnerCountMismatch},
+		{owners: [][]meta.ShardOwner{{{NodeID: 1}}, {{NodeID: 3}}}, err: meta.ErrNodeNotFound},
+		{owners: [][]meta.ShardOwner{{{NodeID: 1}}, {}}, err: meta.ErrShardOwnerNotFound},
+		{owners: [][]meta.ShardOwner{{{NodeID: 1}}, {{NodeID: 2}, {NodeID: 2}}}, err: meta.ErrShardOwnerExists},
+	} {
+		if err := data.CreateShardGroupWithOwners("db", "rp", ts, tt.owners); err != tt.err {
+			t.Fatalf("got error %v with owners %v, expected %v", err, tt.owners, tt.err)
+		}
+	}
+	if data.MaxShardGroupID != 0 || data.MaxShardID != 0 {
+		t.Fatalf("got max shard group id %d and shard id %d, expected data unchanged", data.MaxShardGroupID, data.MaxShardID)
+	}
+
+	// Both nodes own the second shard, more than the policy's replica count.
+	must(data.CreateShardGroupWithOwners("db", "rp", ts, [][]meta.ShardOwner{{{NodeID: 2}}, {{NodeID: 1}, {NodeID: 2}}}))
+	sgi, err := data.ShardGroupByTimestamp("db", "rp", ts)
+	must(err)
+	exp := []meta.ShardInfo{
+		{ID: 1, Owners: []meta.ShardOwner{{NodeID: 2}}},
+		{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+	}
+	if !reflect.DeepEqual(sgi.Shards, exp) {
+		t.Fatalf("got shards %+v, expected %+v", sgi.Shards, exp)
+	}
+	if !sgi.Contains(ts) || sgi.EndTime.Sub(sgi.StartTime) != rpi.ShardGroupDuration {
+		t.Fatalf("got shard group [%v, %v), expected it to contain %v", sgi.StartTime, sgi.EndTime, ts)
+	}
+
+	if got, exp := data.CreateShardGroupWithOwners("db", "rp", ts, [][]meta.ShardOwner{{{NodeID: 1}}, {{NodeID: 2}}}), meta.ErrShardGroupExists; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+}
+
+func TestData_CreateShardGroup_PlacementStrategy(t *testing.T) {
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// owners creates a shard group with two replicas, and a shard for each
+	// node, on nodes in zones, and returns the owners of each of its shards.
+	owners := func(placement string, zones []string) [][]uint64 {
+		data := &meta.Data{}
+		must(data.SetPlacementStrategy(placement))
+		for i, zone := range zones {
+			must(data.CreateDataNode(fmt.Sprintf("node%d:8086", i), fmt.Sprintf("node%d:8088", i)))
+			data.DataNodes[i].Zone = zone
+		}
+		must(data.CreateDatabase("db"))
+		rp := meta.NewRetentionPolicyInfo("rp")
+		rp.ReplicaN = 2
+		rp.ShardGroupDuration = time.Hour
+		must(data.CreateRetentionPolicy("db", rp, true))
+		must(data.CreateShardGroup("db", "rp", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		var owners [][]uint64
+		for _, si := range data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards {
+			var ids []uint64
+			for _, owner := range si.Owners {
+				ids = append(ids, owner.NodeID)
+			}
+			owners = append(owners, ids)
+		}
+		return owners
+	}
+
+	zones := []string{"a", "a", "b", "b"}
+	if got, exp := owners("", zones), [][]uint64{{1, 2}, {3, 4}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := owners(meta.PlacementStrategyRoundRobin, zones), [][]uint64{{1, 2}, {3, 4}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := owners(meta.PlacementStrategyRackAware, zones), [][]uint64{{1, 3}, {2, 4}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	zones = []string{"a", "a", "a", "b", "b", "b"}
+	if got, exp := owners(meta.PlacementStrategyRackAware, zones), [][]uint64{{1, 4}, {2, 5}, {3, 6}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	if err := (&meta.Data{}).SetPlacementStrategy("random"); err != meta.ErrInvalidPlacementStrategy {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrInvalidPlacementStrategy)
+	}
+
+	// The strategy is persisted.
+	data := &meta.Data{}
+	must(data.SetPlacementStrategy(meta.PlacementStrategyRackAware))
+	buf, err := data.MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+	if other.PlacementStrategy != meta.PlacementStrategyRackAware {
+		t.Fatalf("got placement strategy %q, expected %q", other.PlacementStrategy, meta.PlacementStrategyRackAware)
+	}
+
+	// Zones are shared once every zone holds a replica.
+	nodes := []meta.NodeInfo{{ID: 1, Zone: "a"}, {ID: 2, Zone: "a"}, {ID: 3, Zone: "b"}}
+	got := meta.RackAwarePlacement{}.AssignOwners(meta.ShardInfo{ID: 1}, nodes, 3)
+	if exp := []meta.ShardOwner{{NodeID: 1}, {NodeID: 3}, {NodeID: 2}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_RebalanceRetentionPolicyShards(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Create the shard groups while only two nodes exist, so every shard has
+	// a replica on both, then add two empty nodes.
+	must(data.CreateDataNode("node1:8086", "node1:8088"))
+	must(data.CreateDataNode("node2:8086", "node2:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ReplicaN = 2
+	rp.ShardGroupDuration = time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		must(data.CreateShardGroup("db", "rp", ts.Add(time.Duration(i)*time.Hour)))
+	}
+	must(data.CreateDataNode("node3:8086", "node3:8088"))
+	must(data.CreateDataNode("node4:8086", "node4:8088"))
+
+	before := data.Clone()
+	movements, err := data.RebalanceRetentionPolicyShards("db", "rp")
+	must(err)
+	if len(movements) == 0 {
+		t.Fatal("expected movements")
+	}
+	if !reflect.DeepEqual(data.Clone(), before) {
+		t.Fatal("planning movements modified the metadata")
+	}
+
+	for _, m := range movements {
+		data.CopyShardOwner(m.ShardID, m.To)
+		data.RemoveShardOwner(m.ShardID, m.From)
+	}
+
+	load := make(map[uint64]int)
+	groups, err := data.ShardGroups("db", "rp")
+	must(err)
+	for _, sgi := range groups {
+		for _, si := range sgi.Shards {
+			seen := make(map[uint64]bool)
+			for _, owner := range si.Owners {
+				if seen[owner.NodeID] {
+					t.Fatalf("shard %d has two replicas on node %d", si.ID, owner.NodeID)
+				}
+				seen[owner.NodeID] = true
+				load[owner.NodeID]++
+			}
+			if got, exp := len(si.Owners), 2; got != exp {
+				t.Fatalf("shard %d: got %d owners, expected %d", si.ID, got, exp)
+			}
+		}
+	}
+	for _, n := range data.DataNodes {
+		if got, exp := load[n.ID], 2; got != exp {
+			t.Fatalf("node %d: got %d replicas, expected %d", n.ID, got, exp)
+		}
+	}
+
+	// A balanced policy needs no movements.
+	movements, err = data.RebalanceRetentionPolicyShards("db", "rp")
+	must(err)
+	if len(movements) != 0 {
+		t.Fatalf("got %v, expected no movements", movements)
+	}
+
+	if _, err := data.RebalanceRetentionPolicyShards("db", "nope"); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_RebalanceShards(t *testing.T) {
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Create shard groups in two databases while only two nodes exist, then
+	// add an empty third node.
+	newData := func() *meta.Data {
+		data := &meta.Data{}
+		must(data.CreateDataNode("node1:8086", "node1:8088"))
+		must(data.CreateDataNode("node2:8086", "node2:8088"))
+		ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		for _, db := range []string{"db0", "db1"} {
+			must(data.CreateDatabase(db))
+			rp := meta.NewRetentionPolicyInfo("rp")
+			rp.ReplicaN = 2
+			rp.ShardGroupDuration = time.Hour
+			must(data.CreateRetentionPolicy(db, rp, true))
+			for i := 0; i < 3; i++ {
+				must(data.CreateShardGroup(db, "rp", ts.Add(time.Duration(i)*time.Hour)))
+			}
+		}
+		must(data.CreateDataNode("node3:8086", "node3:8088"))
+		return data
+	}
+
+	// replicas returns the replicas held by each node, overall and per shard
+	// group, and checks every shard still has two distinct owners.
+	replicas := func(data *meta.Data) (map[uint64]int, []map[uint64]int) {
+		load := make(map[uint64]int)
+		var groups []map[uint64]int
+		for _, dbi := range data.Databases {
+			for _, sgi := range dbi.RetentionPolicies[0].ShardGroups {
+				group := make(map[uint64]int)
+				for _, si := range sgi.Shards {
+					seen := make(map[uint64]bool)
+					for _, owner := range si.Owners {
+						if seen[owner.NodeID] {
+							t.Fatalf("shard %d has two replicas on node %d", si.ID, owner.NodeID)
+						}
+						seen[owner.NodeID] = true
+						load[owner.NodeID]++
+						group[owner.NodeID]++
+					}
+					if got, exp := len(si.Owners), 2; got != exp {
+						t.Fatalf("shard %d: got %d owners, expected %d", si.ID, got, exp)
+					}
+				}
+				groups = append(groups, group)
+			}
+		}
+		return load, groups
+	}
+
+	balanced := func(load map[uint64]int, nodes []meta.NodeInfo) bool {
+		min, max := -1, 0
+		for _, n := range nodes {
+			if min == -1 || load[n.ID] < min {
+				min = load[n.ID]
+			}
+			if load[n.ID] > max {
+				max = load[n.ID]
+			}
+		}
+		return max-min <= 1
+	}
+
+	// A dry run leaves the metadata unchanged.
+	data := newData()
+	before := data.Clone()
+	movements, err := data.RebalanceShards(meta.RebalanceStrategyCluster, true)
+	must(err)
+	if len(movements) == 0 {
+		t.Fatal("expected movements")
+	}
+	if !reflect.DeepEqual(data.Clone(), before) {
+		t.Fatal("dry run modified the metadata")
+	}
+
+	movements, err = data.RebalanceShards("", false)
+	must(err)
+	if len(movements) == 0 {
+		t.Fatal("expected movements")
+	}
+	if load, _ := replicas(data); !balanced(load, data.DataNodes) {
+		t.Fatalf("got unbalanced replicas %v", load)
+	}
+	if movements, err := data.RebalanceShards(meta.RebalanceStrategyCluster, false); err != nil {
+		t.Fatal(err)
+	} else if len(movements) != 0 {
+		t.Fatalf("got %v, expected no movements", movements)
+	}
+
+	data = newData()
+	_, err = data.RebalanceShards(meta.RebalanceStrategyShardGroup, false)
+	must(err)
+	_, groups := replicas(data)
+	for i, group := range groups {
+		if !balanced(group, data.DataNodes) {
+			t.Fatalf("shard group %d: got unbalanced replicas %v", i, group)
+		}
+	}
+
+	if _, err := data.RebalanceShards("random", true); err != meta.ErrInvalidRebalanceStrategy {
+		t.Fatalf("got %v, expected %v", err, meta.ErrInvalidRebalanceStrategy)
+	}
+}
+
+func TestData_ShardGroupInfosPaginated(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ShardGroupDuration = time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	// Create the groups out of order and delete one of them.
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, h := range []int{4, 0, 2, 1, 3, 5} {
+		must(data.CreateShardGroup("db", "rp", ts.Add(time.Duration(h)*time.Hour)))
+	}
+	sgi, err := data.ShardGroupByTimestamp("db", "rp", ts.Add(5*time.Hour))
+	must(err)
+	must(data.DeleteShardGroup("db", "rp", sgi.ID))
+
+	starts := func(groups []meta.ShardGroupInfo) []time.Time {
+		s := []time.Time{}
+		for _, g := range groups {
+			s = append(s, g.StartTime)
+		}
+		return s
+	}
+
+	for _, tt := range []struct {
+		name          string
+		offset, limit int
+		exp           []time.Time
+	}{
+		{"first page", 0, 2, []time.Time{ts, ts.Add(time.Hour)}},
+		{"last partial page", 4, 2, []time.Time{ts.Add(4 * time.Hour)}},
+		{"offset out of range", 5, 2, []time.Time{}},
+	} {
+		groups, total, err := data.ShardGroupInfosPaginated("db", "rp", tt.offset, tt.limit)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if total != 5 {
+			t.Fatalf("%s: got total %d, expected 5", tt.name, total)
+		}
+		if got := starts(groups); !reflect.DeepEqual(got, tt.exp) {
+			t.Fatalf("%s: got %v, expected %v", tt.name, got, tt.exp)
+		}
+	}
+
+	if _, _, err := data.ShardGroupInfosPaginated("db", "rp", -1, 2); err != meta.ErrInvalidOffset {
+		t.Fatalf("got %v, expected %v", err, meta.ErrInvalidOffset)
+	}
+	if _, _, err := data.ShardGroupInfosPaginated("db", "rp", 0, 0); err != meta.ErrInvalidLimit {
+		t.Fatalf("got %v, expected %v", err, meta.ErrInvalidLimit)
+	}
+	if _, _, err := data.ShardGroupInfosPaginated("db", "nope", 0, 2); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_CreateSubscription_Mode(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []string{"", "ALLL", "some"} {
+		if err := data.CreateSubscription("db", "rp", "sub", mode, []string{"udp://h1:9093"}); err != meta.ErrInvalidSubscriptionMode {
+			t.Fatalf("mode %q: got %v, expected %v", mode, err, meta.ErrInvalidSubscriptionMode)
+		}
+	}
+
+	if err := data.CreateSubscription("db", "rp", "sub0", "any", []string{"udp://h1:9093"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateSubscription("db", "rp", "sub1", "All", []string{"udp://h1:9093"}); err != nil {
+		t.Fatal(err)
+	}
+
+	subs := data.Database("db").RetentionPolicy("rp").Subscriptions
+	if len(subs) != 2 {
+		t.Fatalf("got %d subscriptions, expected 2", len(subs))
+	} else if subs[0].Mode != "ANY" || subs[1].Mode != "ALL" {
+		t.Fatalf("got modes %q and %q, expected ANY and ALL", subs[0].Mode, subs[1].Mode)
+	}
+}
+
+func TestData_UpdateSubscription(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true))
+	must(data.CreateSubscription("db", "rp", "sub", "ALL", []string{"udp://h1:9093"}))
+
+	if err := data.UpdateSubscription("db", "rp", "nope", "ANY", []string{"udp://h2:9093"}); err != meta.ErrSubscriptionNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrSubscriptionNotFound)
+	}
+	if err := data.UpdateSubscription("db", "rp", "sub", "ANY", []string{"bad://h2:9093"}); err == nil {
+		t.Fatal("expected an error for an invalid destination")
+	}
+
+	destinations := []string{"http://h3:9092", "udp://h2:9093"}
+	must(data.UpdateSubscription("db", "rp", "sub", "any", destinations))
+
+	sub := data.Database("db").RetentionPolicy("rp").Subscriptions[0]
+	if sub.Mode != "ANY" {
+		t.Fatalf("got mode %q, expected ANY", sub.Mode)
+	} else if !reflect.DeepEqual(sub.Destinations, destinations) {
+		t.Fatalf("got destinations %v, expected %v", sub.Destinations, destinations)
+	}
+}
+
+func TestData_SetSubscriptionMeasurements(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true))
+	must(data.CreateSubscription("db", "rp", "sub", "ALL", []string{"udp://h1:9093"}))
+
+	if err := data.SetSubscriptionMeasurements("db", "rp", "nope", []string{"cpu"}); err != meta.ErrSubscriptionNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrSubscriptionNotFound)
+	}
+	must(data.SetSubscriptionMeasurements("db", "rp", "sub", []string{"cpu", "mem"}))
+
+	// The filter survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	var other meta.Data
+	must(other.UnmarshalBinary(buf))
+	rpi, err := other.RetentionPolicy("db", "rp")
+	must(err)
+	if got, exp := rpi.Subscriptions[0].Measurements, []string{"cpu", "mem"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_CreateMetaNodeWithID(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateMetaNodeWithID(5, "meta5:8091", "meta5:8089"))
+	if got, exp := data.MaxNodeID, uint64(5); got != exp {
+		t.Fatalf("got max node id %d, expected %d", got, exp)
+	}
+
+	// A lower ID leaves MaxNodeID alone.
+	must(data.CreateMetaNodeWithID(2, "meta2:8091", "meta2:8089"))
+	if got, exp := data.MaxNodeID, uint64(5); got != exp {
+		t.Fatalf("got max node id %d, expected %d", got, exp)
+	}
+	if got, exp := []uint64{data.MetaNodes[0].ID, data.MetaNodes[1].ID}, []uint64{2, 5}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got meta node ids %v, expected %v", got, exp)
+	}
+
+	// Nodes created afterwards get IDs past the restored ones.
+	must(data.CreateMetaNode("meta6:8091", "meta6:8089"))
+	if got, exp := data.MetaNode(6), "meta6:8091"; got == nil || got.Addr != exp {
+		t.Fatalf("got %v, expected meta node 6 at %s", got, exp)
+	}
+
+	for _, tt := range []struct {
+		id                uint64
+		httpAddr, tcpAddr string
+		err               error
+	}{
+		{5, "other:8091", "other:8089", meta.ErrNodeExists},
+		{7, "meta5:8091", "other:8089", meta.ErrNodeExists},
+		{7, "other:8091", "meta5:8089", meta.ErrNodeExists},
+		{0, "other:8091", "other:8089", meta.ErrNodeIDRequired},
+	} {
+		if err := data.CreateMetaNodeWithID(tt.id, tt.httpAddr, tt.tcpAddr); err != tt.err {
+			t.Fatalf("id %d: got %v, expected %v", tt.id, err, tt.err)
+		}
+	}
+	if got, exp := len(data.MetaNodes), 3; got != exp {
+		t.Fatalf("got %d meta nodes, expected %d", got, exp)
+	}
+
+	// The ID of a data node is only shared with a meta node on its host.
+	must(data.CreateDataNode("data:8086", "data:8088"))
+	dn := data.DataNodeByTCPAddr("data:8088")
+	if err := data.CreateMetaNodeWithID(dn.ID, "other:8091", "other:8089"); err != meta.ErrNodeExists {
+		t.Fatalf("got %v, expected %v", err, meta.ErrNodeExists)
+	}
+	if err := data.CreateMetaNodeWithID(dn.ID+1, "data:8091", "data:8088"); err != meta.ErrNodeExists {
+		t.Fatalf("got %v, expected %v", err, meta.ErrNodeExists)
+	}
+	must(data.CreateMetaNodeWithID(dn.ID, "data:8091", "data:8088"))
+}
+
+func TestData_RetentionPoliciesWithAlignedShardGroups(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	for _, rp := range []struct {
+		name string
+		sgd  time.Duration
+	}{
+		{"hourly", time.Hour},
+		{"daily", 24 * time.Hour},
+		{"odd", 36 * time.Hour},
+		{"weekly", 7 * 24 * time.Hour},
+	} {
+		must(data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: rp.name, ReplicaN: 1, ShardGroupDuration: rp.sgd}, false))
+	}
+
+	names, err := data.RetentionPoliciesWithAlignedShardGroups("db", 24*time.Hour)
+	must(err)
+	if exp := []string{"hourly", "daily", "weekly"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
+	}
+
+	if _, err := data.RetentionPoliciesWithAlignedShardGroups("db", 0); err != meta.ErrAlignmentBaseTooLow {
+		t.Fatalf("got %v, expected %v", err, meta.ErrAlignmentBaseTooLow)
+	}
+	if _, err := data.RetentionPoliciesWithAlignedShardGroups("nope", time.Hour); err == nil {
+		t.Fatal("expected error for unknown database")
+	}
+}
+
+func TestNormalizedShardGroupDuration(t *testing.T) {
+	day := 24 * time.Hour
+	for _, tt := range []struct {
+		sgd, d time.Duration
+		exp    time.Duration
+	}{
+		{0, 0, 7 * day},
+		{0, 365 * day, 7 * day},
+		{0, 180 * day, 7 * day},
+		{0, 179 * day, day},
+		{0, 2 * day, day},
+		{0, 47 * time.Hour, time.Hour},
+		{0, time.Hour, time.Hour},
+		{time.Minute, 0, time.Hour},
+		{30 * time.Minute, 365 * day, time.Hour},
+		{2 * time.Hour, 0, 2 * time.Hour},
+		{3 * day, 30 * day, 3 * day},
+	} {
+		if got := meta.NormalizedShardGroupDuration(tt.sgd, tt.d); got != tt.exp {
+			t.Errorf("NormalizedShardGroupDuration(%v, %v): got %v, expected %v", tt.sgd, tt.d, got, tt.exp)
+		}
+	}
+}
+
+func TestShardGroupDurationDivisor(t *testing.T) {
+	day := 24 * time.Hour
+	for _, tt := range []struct {
+		d, sgd time.Duration
+		exp    time.Duration
+	}{
+		{10 * day, 7 * day, 5 * day},
+		{30 * day, 7 * day, 180 * time.Hour},
+		{7 * day, 7 * day, 7 * day},
+		{0, 7 * day, 7 * day},
+		{25 * time.Hour, day, 25 * time.Hour},
+		{90 * time.Minute, time.Hour, 90 * time.Minute},
+		// Equally near durations prefer the shorter one.
+		{4 * time.Hour, 3 * time.Hour, 2 * time.Hour},
+	} {
+		if got := meta.ShardGroupDurationDivisor(tt.d, tt.sgd); got != tt.exp {
+			t.Errorf("ShardGroupDurationDivisor(%v, %v): got %v, expected %v", tt.d, tt.sgd, got, tt.exp)
+		}
+	}
+}
+
+func TestData_CreateRetentionPolicyWithOpts_StrictShardGroupDuration(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db"); err != nil {
+		t.Fatal(err)
+	}
+
+	day := 24 * time.Hour
+	strict := meta.CreateRetentionPolicyOpts{StrictShardGroupDuration: true}
+	rpi := &meta.RetentionPolicyInfo{Name: "rp", ReplicaN: 1, Duration: 10 * day, ShardGroupDuration: 7 * day}
+	err := data.CreateRetentionPolicyWithOpts("db", rpi, false, strict)
+	if !errors.Is(err, meta.ErrShardGroupDurationNotDivisor) {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrShardGroupDurationNotDivisor)
+	}
+	var sgdErr *meta.ShardGroupDurationError
+	if !errors.As(err, &sgdErr) || sgdErr.Suggested != 5*day {
+		t.Fatalf("got error %#v, expected a suggestion of %v", err, 5*day)
+	}
+	if rpi, _ := data.RetentionPolicy("db", "rp"); rpi != nil {
+		t.Fatal("expected retention policy not to be created")
+	}
+
+	// Durations that divide cleanly and infinite durations are accepted, and
+	// the check is off by default.
+	rpi.ShardGroupDuration = 5 * day
+	if err := data.CreateRetentionPolicyWithOpts("db", rpi, false, strict); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicyWithOpts("db", &meta.RetentionPolicyInfo{Name: "inf", ReplicaN: 1, ShardGroupDuration: 7 * day}, false, strict); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "lax", ReplicaN: 1, Duration: 10 * day, ShardGroupDuration: 7 * day}, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestData_CreateRetentionPolicyWithOpts_CheckReplicaN(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A cluster without data nodes isn't checked.
+	check := meta.CreateRetentionPolicyOpts{CheckReplicaN: true}
+	if err := data.CreateRetentionPolicyWithOpts("db", &meta.RetentionPolicyInfo{Name: "empty", ReplicaN: 3}, false, check); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := data.CreateDataNode("foo:8086", "foo:8088"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicyWithOpts("db", &meta.RetentionPolicyInfo{Name: "rp", ReplicaN: 2}, false, check); err != meta.ErrReplicationFactorExceedsNodes {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrReplicationFactorExceedsNodes)
+	}
+	if rpi, _ := data.RetentionPolicy("db", "rp"); rpi != nil {
+		t.Fatal("expected retention policy not to be created")
+	}
+
+	// An existing identical policy is still accepted, and the check is off
+	// by default.
+	if err := data.CreateRetentionPolicyWithOpts("db", &meta.RetentionPolicyInfo{Name: "empty", ReplicaN: 3}, false, check); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "rp", ReplicaN: 2}, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestData_ReassignShardsFromNode(t *testing.T) {
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newData := func(nodes int) *meta.Data {
+		data := &meta.Data{}
+		for i := 1; i <= nodes; i++ {
+			must(data.CreateDataNode(fmt.Sprintf("node%d:8086", i), fmt.Sprintf("node%d:8088", i)))
+		}
+		must(data.CreateDatabase("db"))
+		rp := meta.NewRetentionPolicyInfo("rp")
+		rp.ReplicaN = 2
+		rp.ShardGroupDuration = time.Hour
+		must(data.CreateRetentionPolicy("db", rp, true))
+		ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			must(data.CreateShardGroup("db", "rp", ts.Add(time.Duration(i)*time.Hour)))
+		}
+		return data
+	}
+
+	t.Run("reassigned", func(t *testing.T) {
+		data := newData(3)
+		movements, err := data.ReassignShardsFromNode(1)
+		must(err)
+		if len(movements) == 0 {
+			t.Fatal("expected movements")
+		}
+
+		groups, err := data.ShardGroups("db", "rp")
+		must(err)
+		for _, sg := range groups {
+			for _, s := range sg.Shards {
+				if s.OwnedBy(1) {
+					t.Fatalf("shard %d still owned by node 1", s.ID)
+				}
+				if len(s.Owners) != 2 || s.Owners[0].NodeID == s.Owners[1].NodeID {
+					t.Fatalf("shard %d: got owners %v, expected two distinct owners", s.ID, s.Owners)
+				}
+			}
+		}
+		for _, m := range movements {
+			if m.From != 1 || m.To == 1 {
+				t.Fatalf("unexpected movement %+v", m)
+			}
+		}
+
+		// The dead node is kept for audit.
+		if data.DataNode(1) == nil {
+			t.Fatal("expected node 1 to remain in the metadata")
+		}
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		// With two nodes and two replicas the surviving node already owns
+		// every shard.
+		data := newData(2)
+		before := data.Clone()
+		movements, err := data.ReassignShardsFromNode(1)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if len(movements) != 0 {
+			t.Fatalf("got %v, expected no movements", movements)
+		}
+		if !reflect.DeepEqual(data.Clone(), before) {
+			t.Fatal("expected shard owners to be unchanged")
+		}
+	})
+
+	if _, err := newData(2).ReassignShardsFromNode(9); err != meta.ErrNodeNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrNodeNotFound)
+	}
+}
+
+func TestData_RetentionPolicyWriteWindow(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "finite", ReplicaN: 1, Duration: 48 * time.Hour}, true))
+	must(data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "infinite", ReplicaN: 1}, false))
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	maxTime := time.Unix(0, models.MaxNanoTime)
+
+	min, max, err := data.RetentionPolicyWriteWindow("db", "finite", now)
+	must(err)
+	if exp := now.Add(-48 * time.Hour); !min.Equal(exp) {
+		t.Fatalf("got min %v, expected %v", min, exp)
+	}
+	if !max.Equal(maxTime) {
+		t.Fatalf("got max %v, expected %v", max, maxTime)
+	}
+
+	min, max, err = data.RetentionPolicyWriteWindow("db", "infinite", now)
+	must(err)
+	if exp := time.Unix(0, models.MinNanoTime); !min.Equal(exp) {
+		t.Fatalf("got min %v, expected %v", min, exp)
+	}
+	if !max.Equal(maxTime) {
+		t.Fatalf("got max %v, expected %v", max, maxTime)
+	}
+
+	if _, _, err := data.RetentionPolicyWriteWindow("db", "nope", now); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_RetentionPolicyMinAcceptableWriteTime(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "finite", ReplicaN: 1, Duration: 7 * 24 * time.Hour}, true))
+	must(data.CreateRetentionPolicy("db", &meta.RetentionPolicyInfo{Name: "infinite", ReplicaN: 1}, false))
+
+	now := time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC)
+
+	min, err := data.RetentionPolicyMinAcceptableWriteTime("db", "finite", now)
+	must(err)
+	if exp := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC); !min.Equal(exp) {
+		t.Fatalf("got %v, expected %v", min, exp)
+	}
+
+	min, err = data.RetentionPolicyMinAcceptableWriteTime("db", "infinite", now)
+	must(err)
+	if exp := time.Unix(0, models.MinNanoTime); !min.Equal(exp) {
+		t.Fatalf("got %v, expected %v", min, exp)
+	}
+
+	if _, err := data.RetentionPolicyMinAcceptableWriteTime("db", "nope", now); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_ShardGroupMembership(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp",
+						ShardGroups: []meta.ShardGroupInfo{
+							{
+								ID: 1,
+								Shards: []meta.ShardInfo{
+									{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+									{ID: 2, Owners: []meta.ShardOwner{{NodeID: 2}, {NodeID: 3}}},
+								},
+							},
+							{
+								ID:     2,
+								Shards: []meta.ShardInfo{{ID: 3, Owners: []meta.ShardOwner{{NodeID: 3}}}},
+							},
+							{
+								ID:        3,
+								Shards:    []meta.ShardInfo{{ID: 4, Owners: []meta.ShardOwner{{NodeID: 1}}}},
+								DeletedAt: time.Now(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	membership, err := data.ShardGroupMembership("db", "rp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[uint64]map[uint64]int{
+		1: {1: 1, 2: 2, 3: 1},
+		2: {3: 1},
+	}
+	if !reflect.DeepEqual(membership, exp) {
+		t.Fatalf("got %v, expected %v", membership, exp)
+	}
+
+	if _, err := data.ShardGroupMembership("db", "nope"); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_ShardGroupReplicaHealth(t *testing.T) {
+	data := &meta.Data{
+		DataNodes: []meta.NodeInfo{{ID: 1}, {ID: 2}},
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name:     "rp",
+						ReplicaN: 2,
+						ShardGroups: []meta.ShardGroupInfo{
+							{
+								ID: 1,
+								Shards: []meta.ShardInfo{
+									{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+									{ID: 2, Owners: []meta.ShardOwner{{NodeID: 2}, {NodeID: 3}}},
+								},
+							},
+							{
+								ID:     2,
+								Shards: []meta.ShardInfo{{ID: 3, Owners: []meta.ShardOwner{{NodeID: 3}, {NodeID: 4}}}},
+							},
+							{
+								ID:        3,
+								Shards:    []meta.ShardInfo{{ID: 4}},
+								DeletedAt: time.Now(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	health, err := data.ShardGroupReplicaHealth("db", "rp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Orphaned != 1 || health.UnderReplicated != 1 || health.Healthy != 1 {
+		t.Fatalf("got %d orphaned, %d under-replicated and %d healthy, expected one each", health.Orphaned, health.UnderReplicated, health.Healthy)
+	}
+	exp := []meta.ShardHealth{
+		{ShardID: 1, DesiredN: 2, ActualN: 2, ResolvableN: 2},
+		{ShardID: 2, DesiredN: 2, ActualN: 2, ResolvableN: 1},
+		{ShardID: 3, DesiredN: 2, ActualN: 2, ResolvableN: 0},
+	}
+	if !reflect.DeepEqual(health.Details, exp) {
+		t.Fatalf("got %v, expected %v", health.Details, exp)
+	}
+
+	if _, err := data.ShardGroupReplicaHealth("db", "nope"); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+}
+
+func TestData_DeleteAllShardGroups(t *testing.T) {
+	deletedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newData := func() *meta.Data {
+		return &meta.Data{
+			Databases: []meta.DatabaseInfo{
+				{
+					Name: "db",
+					RetentionPolicies: []meta.RetentionPolicyInfo{
+						{
+							Name: "rp0",
+							ShardGroups: []meta.ShardGroupInfo{
+								{ID: 1},
+								{ID: 2, DeletedAt: deletedAt},
+								{ID: 3},
+							},
+						},
+						{
+							Name:        "rp1",
+							ShardGroups: []meta.ShardGroupInfo{{ID: 4}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	data := newData()
+	n, err := data.DeleteAllShardGroups("db", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("got %d, expected 2", n)
+	}
+	for _, sgi := range data.Databases[0].RetentionPolicies[0].ShardGroups {
+		if !sgi.Deleted() {
+			t.Fatalf("shard group %d not deleted", sgi.ID)
+		} else if sgi.ID == 2 && !sgi.DeletedAt.Equal(deletedAt) {
+			t.Fatalf("got DeletedAt %v for already deleted shard group, expected %v", sgi.DeletedAt, deletedAt)
+		}
+	}
+	if data.Databases[0].RetentionPolicies[1].ShardGroups[0].Deleted() {
+		t.Fatal("shard group of another retention policy deleted")
+	}
+	if _, err := data.DeleteAllShardGroups("db", "nope"); err == nil {
+		t.Fatal("expected error for unknown retention policy")
+	}
+
+	data = newData()
+	n, err = data.DropShardGroupsForDatabase("db")
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 3 {
+		t.Fatalf("got %d, expected 3", n)
+	}
+	for _, rpi := range data.Databases[0].RetentionPolicies {
+		for _, sgi := range rpi.ShardGroups {
+			if !sgi.Deleted() {
+				t.Fatalf("shard group %d not deleted", sgi.ID)
+			}
+		}
+	}
+	if _, err := data.DropShardGroupsForDatabase("nope"); err == nil {
+		t.Fatal("expected error for unknown database")
+	}
+}
+
+func TestData_ShardGroupTimeline(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.Duration = 0
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, i := range []int{2, 0, 1} {
+		must(data.CreateShardGroup("db", "rp", day.Add(time.Duration(i)*24*time.Hour)))
+	}
+
+	groups, err := data.ShardGroups("db", "rp")
+	must(err)
+	must(data.DeleteShardGroup("db", "rp", groups[0].ID))
+	truncateAt := day.Add(36 * time.Hour)
+	data.TruncateShardGroups(truncateAt)
+
+	timeline, err := data.ShardGroupTimeline("db", "rp")
+	must(err)
+	if got, exp := len(timeline), 3; got != exp {
+		t.Fatalf("got %d entries, expected %d", got, exp)
+	}
+
+	for i, e := range timeline {
+		if exp := day.Add(time.Duration(i) * 24 * time.Hour); !e.Start.Equal(exp) {
+			t.Fatalf("entry %d: got start %v, expected %v", i, e.Start, exp)
+		}
+		if exp := e.Start.Add(24 * time.Hour); !e.End.Equal(exp) {
+			t.Fatalf("entry %d: got end %v, expected %v", i, e.End, exp)
+		}
+	}
+
+	if timeline[0].Deleted.IsZero() {
+		t.Fatal("expected first shard group to be marked deleted")
+	}
+	if got := timeline[1].Truncated; !got.Equal(truncateAt) {
+		t.Fatalf("got truncated %v, expected %v", got, truncateAt)
+	}
+	if !timeline[1].Deleted.IsZero() || !timeline[2].Deleted.IsZero() {
+		t.Fatal("expected remaining shard groups not to be deleted")
+	}
+
+	if _, err := data.ShardGroupTimeline("db", "nope"); err == nil {
+		t.Fatal("expected error for missing retention policy")
+	}
+}
+
+func TestData_ClusterShardInfos(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDataNode("bar:8086", "bar:8088"))
+	for _, db := range []string{"db0", "db1"} {
+		must(data.CreateDatabase(db))
+		rp := meta.NewRetentionPolicyInfo("rp")
+		rp.ReplicaN = 2
+		rp.Duration = 48 * time.Hour
+		rp.ShardGroupDuration = 24 * time.Hour
+		must(data.CreateRetentionPolicy(db, rp, true))
+		must(data.CreateShardGroup(db, "rp", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	}
+
+	infos, err := data.ClusterShardInfos("db0")
+	must(err)
+	if got, exp := len(infos), 1; got != exp {
+		t.Fatalf("got %d shards, expected %d", got, exp)
+	}
+
+	info := infos[0]
+	if got, exp := info.Database, "db0"; got != exp {
+		t.Fatalf("got database %s, expected %s", got, exp)
+	}
+	if got, exp := info.ReplicaN, 2; got != exp {
+		t.Fatalf("got replica n %d, expected %d", got, exp)
+	}
+	if got, exp := info.ExpireTime, time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC); !got.Equal(exp) {
+		t.Fatalf("got expire time %v, expected %v", got, exp)
+	}
+
+	var addrs []string
+	for _, o := range info.Owners {
+		addrs = append(addrs, o.TCPAddr)
+	}
+	sort.Strings(addrs)
+	if exp := []string{"bar:8088", "foo:8088"}; !reflect.DeepEqual(addrs, exp) {
+		t.Fatalf("got %v, expected %v", addrs, exp)
+	}
+
+	// An empty database name returns the shards of every database.
+	infos, err = data.ClusterShardInfos("")
+	must(err)
+	if got, exp := len(infos), 2; got != exp {
+		t.Fatalf("got %d shards, expected %d", got, exp)
+	}
+
+	// Infinite retention never expires.
+	duration := time.Duration(0)
+	must(data.UpdateRetentionPolicy("db1", "rp", &meta.RetentionPolicyUpdate{Duration: &duration}, false))
+	infos, err = data.ClusterShardInfos("db1")
+	must(err)
+	if !infos[0].ExpireTime.IsZero() {
+		t.Fatalf("got expire time %v, expected zero", infos[0].ExpireTime)
+	}
+
+	// A shard group replication factor overrides the retention policy's.
+	must(data.CreateShardGroupWithReplicaN("db1", "rp", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), 1))
+	infos, err = data.ClusterShardInfos("db1")
+	must(err)
+	if got, exp := infos[1].ReplicaN, 1; got != exp {
+		t.Fatalf("got replica n %d, expected %d", got, exp)
+	}
+
+	if _, err := data.ClusterShardInfos("nope"); err == nil {
+		t.Fatal("expected error for missing database")
+	}
+}
+
+func TestData_ClusterSummary(t *testing.T) {
+	now := time.Now()
+	data := &meta.Data{
+		MetaNodes: []meta.NodeInfo{{ID: 1}},
+		DataNodes: []meta.NodeInfo{{ID: 2}, {ID: 3}},
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 1, Shards: []meta.ShardInfo{{ID: 1}, {ID: 2}}},
+							{ID: 2, Shards: []meta.ShardInfo{{ID: 3}}, DeletedAt: now},
+						},
+					},
+					{
+						Name: "rp1",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 3, Shards: []meta.ShardInfo{{ID: 4}, {ID: 5}, {ID: 6}}},
+						},
+					},
+				},
+			},
+			{Name: "db1", RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}}},
+			{Name: "db2"},
+		},
+	}
+
+	exp := meta.ClusterSummary{
+		DataNodes:         2,
+		MetaNodes:         1,
+		Databases:         3,
+		RetentionPolicies: 3,
+		ShardGroups:       2,
+		Shards:            5,
+	}
+	if got := data.ClusterSummary(); got != exp {
+		t.Fatalf("got %+v, expected %+v", got, exp)
+	}
+}
+
+func TestData_Counts(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 1, Shards: []meta.ShardInfo{{ID: 1}, {ID: 2}}},
+							{ID: 2, Shards: []meta.ShardInfo{{ID: 3}}, DeletedAt: time.Now()},
+						},
+					},
+				},
+			},
+			{
+				Name: "db1",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						// A shard listed in more than one shard group is counted once.
+						ShardGroups: []meta.ShardGroupInfo{{ID: 3, Shards: []meta.ShardInfo{{ID: 1}, {ID: 4}}}},
+					},
+				},
+			},
+			{Name: "db2"},
+		},
+	}
+
+	if got, exp := data.DatabaseCount(), 3; got != exp {
+		t.Fatalf("got %d databases, expected %d", got, exp)
+	}
+	if got, exp := data.ShardGroupCount(), 2; got != exp {
+		t.Fatalf("got %d shard groups, expected %d", got, exp)
+	}
+	if got, exp := data.ShardCount(), 3; got != exp {
+		t.Fatalf("got %d shards, expected %d", got, exp)
+	}
+	if s := data.ClusterSummary(); s.Databases != 3 || s.ShardGroups != 2 || s.Shards != 3 {
+		t.Fatalf("got summary %+v, expected the counts", s)
+	}
+}
+
+func TestData_Topology(t *testing.T) {
+	data := &meta.Data{
+		MetaNodes: []meta.NodeInfo{
+			{ID: 3, Addr: "meta3:8091", TCPAddr: "meta3:8089"},
+			{ID: 1, Addr: "meta1:8091", TCPAddr: "meta1:8089"},
+		},
+		DataNodes: []meta.NodeInfo{
+			{ID: 4, Addr: "data4:8086", TCPAddr: "data4:8088"},
+			{ID: 2, Addr: "data2:8086", TCPAddr: "data2:8088"},
+		},
+	}
+
+	exp := meta.ClusterInfo{
+		Data: []*meta.DataNodeInfo{
+			{ID: 2, TCPAddr: "data2:8088", HTTPAddr: "data2:8086", HTTPScheme: "https"},
+			{ID: 4, TCPAddr: "data4:8088", HTTPAddr: "data4:8086", HTTPScheme: "https"},
+		},
+		Meta: []*meta.MetaNodeInfo{
+			{ID: 1, Addr: "meta1:8091", TCPAddr: "meta1:8089", HTTPScheme: "https"},
+			{ID: 3, Addr: "meta3:8091", TCPAddr: "meta3:8089", HTTPScheme: "https"},
+		},
+	}
+	if got := data.Topology("https"); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %+v, expected %+v", got, exp)
+	}
+}
+
+func TestData_RetentionPolicyForShardGroup(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDataNode("bar:8086", "bar:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	rp := meta.NewRetentionPolicyInfo("rp1")
+	rp.ReplicaN = 2
+	rp.Duration = 72 * time.Hour
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db1", rp, true))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	must(data.CreateShardGroup("db0", "rp0", ts))
+	must(data.CreateShardGroup("db1", "rp1", ts))
+	must(data.CreateShardGroup("db1", "rp1", ts.Add(24*time.Hour)))
+
+	groups, err := data.ShardGroups("db1", "rp1")
+	must(err)
+	sgID := groups[0].ID
+	must(data.DeleteShardGroup("db1", "rp1", sgID))
+
+	db, policy, rpi, ok := data.RetentionPolicyForShardGroup(sgID)
+	if !ok {
+		t.Fatalf("shard group %d not found", sgID)
+	}
+	if db != "db1" || policy != "rp1" {
+		t.Fatalf("got %s.%s, expected db1.rp1", db, policy)
+	}
+	if got, exp := rpi.ReplicaN, 2; got != exp {
+		t.Fatalf("got replica n %d, expected %d", got, exp)
+	}
+	if got, exp := rpi.Duration, 72*time.Hour; got != exp {
+		t.Fatalf("got duration %v, expected %v", got, exp)
+	}
+
+	if _, _, _, ok := data.RetentionPolicyForShardGroup(1000); ok {
+		t.Fatal("expected unknown shard group not to be found")
+	}
+}
+
+func TestData_ShardGroupByID(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("foo:8086", "foo:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateRetentionPolicy("db1", meta.NewRetentionPolicyInfo("rp1"), true))
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	must(data.CreateShardGroup("db0", "rp0", ts))
+	must(data.CreateShardGroup("db1", "rp1", ts))
+
+	groups, err := data.ShardGroups("db1", "rp1")
+	must(err)
+	sgID := groups[0].ID
+
+	db, policy, sgi := data.ShardGroupByID(sgID)
+	if db != "db1" || policy != "rp1" {
+		t.Fatalf("got %s.%s, expected db1.rp1", db, policy)
+	} else if sgi == nil || sgi.ID != sgID {
+		t.Fatalf("got shard group %+v, expected %d", sgi, sgID)
+	}
+
+	// The returned group is the one stored in the metadata.
+	must(data.DeleteShardGroup(db, policy, sgi.ID))
+	if !sgi.Deleted() {
+		t.Fatal("expected shard group to be deleted")
+	}
+
+	if db, policy, sgi := data.ShardGroupByID(1000); db != "" || policy != "" || sgi != nil {
+		t.Fatalf("got %s.%s %+v, expected unknown shard group not to be found", db, policy, sgi)
+	}
+}
+
+func TestData_ShardGroupsPendingDeletion(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	older := now.Add(-7 * 24 * time.Hour)
+	longAgo := now.Add(-30 * 24 * time.Hour)
+
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							{ID: 1},
+							{ID: 2, DeletedAt: recent},
+							{ID: 3, DeletedAt: longAgo},
+						},
+					},
+				},
+			},
+			{
+				Name: "db1",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name:        "rp1",
+						ShardGroups: []meta.ShardGroupInfo{{ID: 4, DeletedAt: older}},
+					},
+				},
+			},
+		},
+	}
+
+	exp := []meta.PendingShardGroupDeletion{
+		{Database: "db1", Policy: "rp1", ID: 4, DeletedAt: older, PruneAt: older.Add(-meta.ShardGroupDeletedExpiration)},
+		{Database: "db0", Policy: "rp0", ID: 2, DeletedAt: recent, PruneAt: recent.Add(-meta.ShardGroupDeletedExpiration)},
+	}
+	if got := data.ShardGroupsPendingDeletion(now); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := exp[1].PruneAt, recent.Add(14*24*time.Hour); !got.Equal(exp) {
+		t.Fatalf("got prune time %v, expected %v", got, exp)
+	}
+
+	if got := data.ShardGroupsPendingDeletion(now.Add(30 * 24 * time.Hour)); len(got) != 0 {
+		t.Fatalf("got %v, expected no pending deletions", got)
+	}
+}
+
+func TestData_DatabaseNames(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{{Name: "db1"}, {Name: "_internal"}, {Name: "db0"}},
+	}
+
+	if got, exp := data.DatabaseNames(), []string{"_internal", "db0", "db1"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.DatabaseNamesExcludingInternal(), []string{"db0", "db1"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got := (&meta.Data{}).DatabaseNames(); len(got) != 0 {
+		t.Fatalf("got %v, expected no names", got)
+	}
+}
+
+func TestData_DatabaseRetentionPolicyPairs(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateDatabase("db2"))
+	must(data.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: 24 * time.Hour, ShardGroupDuration: time.Hour}, true))
+	must(data.CreateRetentionPolicy("db1", &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, ShardGroupDuration: 7 * 24 * time.Hour}, true))
+	must(data.CreateRetentionPolicy("db1", &meta.RetentionPolicyInfo{Name: "rp1", ReplicaN: 1, Duration: 48 * time.Hour, ShardGroupDuration: 24 * time.Hour}, false))
+
+	exp := []meta.RetentionPolicyPair{
+		{Database: "db0", Policy: "rp0", Duration: 24 * time.Hour, ShardGroupDuration: time.Hour},
+		{Database: "db1", Policy: "rp0", ShardGroupDuration: 7 * 24 * time.Hour},
+		{Database: "db1", Policy: "rp1", Duration: 48 * time.Hour, ShardGroupDuration: 24 * time.Hour},
+	}
+	if got := data.DatabaseRetentionPolicyPairs(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_DedupeShardOwners(t *testing.T) {
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{{
+			Name: "db0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{
+				Name: "rp0",
+				ShardGroups: []meta.ShardGroupInfo{{
+					ID: 1,
+					Shards: []meta.ShardInfo{
+						{ID: 1, Owners: []meta.ShardOwner{{NodeID: 3}, {NodeID: 1}, {NodeID: 1}, {NodeID: 2}, {NodeID: 3}}},
+						{ID: 2, Owners: []meta.ShardOwner{{NodeID: 3}, {NodeID: 2}}},
+					},
+				}},
+			}},
+		}},
+	}
+
+	if got, exp := data.DedupeShardOwners(), 2; got != exp {
+		t.Fatalf("got %d duplicates removed, expected %d", got, exp)
+	}
+
+	shards := data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards
+	if got, exp := shards[0].Owners, []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}, {NodeID: 3}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := shards[1].Owners, []meta.ShardOwner{{NodeID: 2}, {NodeID: 3}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// A second pass has nothing left to repair.
+	if got := data.DedupeShardOwners(); got != 0 {
+		t.Fatalf("got %d duplicates removed on second pass, expected 0", got)
+	}
+}
+
+func TestData_MergeFrom(t *testing.T) {
+	newCluster := func(nodes ...string) *meta.Data {
+		data := &meta.Data{}
+		for _, n := range nodes {
+			if err := data.CreateDataNode(n+":8086", n+":8088"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := data.CreateDatabase("db0"); err != nil {
+			t.Fatal(err)
+		}
+		rp := meta.NewRetentionPolicyInfo("rp0")
+		rp.ReplicaN = 2
+		if err := data.CreateRetentionPolicy("db0", rp, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := data.CreateShardGroup("db0", "rp0", time.Unix(0, 0)); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := newCluster("a", "b")
+	other := newCluster("b", "c")
+	other.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].Owners[1].State = meta.ShardStateCopying
+
+	if err := data.MergeFrom(other, "east_"); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, db := range data.Databases {
+		names = append(names, db.Name)
+	}
+	if exp := []string{"db0", "east_db0"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got %v, expected %v", names, exp)
+	}
+
+	// Node "b" is shared between the clusters.
+	if got, exp := len(data.DataNodes), 3; got != exp {
+		t.Fatalf("got %d data nodes, expected %d", got, exp)
+	}
+	nodeAddrs := make(map[uint64]string)
+	for _, n := range data.DataNodes {
+		nodeAddrs[n.ID] = n.TCPAddr
+	}
+
+	groupIDs := make(map[uint64]bool)
+	shardIDs := make(map[uint64]bool)
+	for _, db := range data.Databases {
+		for _, sg := range db.RetentionPolicies[0].ShardGroups {
+			if groupIDs[sg.ID] {
+				t.Fatalf("duplicate shard group id %d", sg.ID)
+			}
+			groupIDs[sg.ID] = true
+			for _, sh := range sg.Shards {
+				if shardIDs[sh.ID] {
+					t.Fatalf("duplicate shard id %d", sh.ID)
+				}
+				shardIDs[sh.ID] = true
+			}
+		}
+	}
+
+	// Owners must point at the merged nodes with the original addresses.
+	merged := data.Database("east_db0").RetentionPolicies[0].ShardGroups[0].Shards[0]
+	var owners []string
+	for _, o := range merged.Owners {
+		owners = append(owners, nodeAddrs[o.NodeID])
+	}
+	sort.Strings(owners)
+	if exp := []string{"b:8088", "c:8088"}; !reflect.DeepEqual(owners, exp) {
+		t.Fatalf("got %v, expected %v", owners, exp)
+	}
+
+	// Owner state is carried over with the owner.
+	if got, exp := merged.Owners[1].State, meta.ShardStateCopying; got != exp {
+		t.Fatalf("got owner state %q, expected %q", got, exp)
+	}
+
+	// Merging again under the same prefix collides.
+	if err := data.MergeFrom(other, "east_"); err == nil {
+		t.Fatal("expected error for prefix collision")
+	}
+}
+
+func TestUserInfo_AuthorizeDatabase(t *testing.T) {
+	emptyUser := &meta.UserInfo{}
+	if !emptyUser.AuthorizeDatabase(influxql.NoPrivileges, "anydb") {
+		t.Fatal("expected NoPrivileges to be authorized but it wasn't")
+	}
+	if emptyUser.AuthorizeDatabase(influxql.ReadPrivilege, "anydb") {
+		t.Fatal("expected ReadPrivilege to prevent authorization, but it was authorized")
+	}
+
+	adminUser := &meta.UserInfo{Admin: true}
+	if !adminUser.AuthorizeDatabase(influxql.AllPrivileges, "anydb") {
+		t.Fatalf("expected admin to be authorized but it wasn't")
+	}
+}
+
+func TestShardGroupInfo_Contains(t *testing.T) {
+	sgi := &meta.ShardGroupInfo{StartTime: time.Unix(10, 0), EndTime: time.Unix(20, 0)}
+
+	tests := []struct {
+		ts  time.Time
+		exp bool
+	}{
+		{time.Unix(0, 0), false},
+		{time.Unix(9, 0), false},
+		{time.Unix(10, 0), true},
+		{time.Unix(11, 0), true},
+		{time.Unix(15, 0), true},
+		{time.Unix(19, 0), true},
+		{time.Unix(20, 0), false},
+		{time.Unix(21, 0), false},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("ts=%d", test.ts.Unix()), func(t *testing.T) {
+			got := sgi.Contains(test.ts)
+			assert.Equal(t, got, test.exp)
+		})
+	}
+}
+
+func randString(n int) string {
+	var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func TestShardGroupInfo_ShardForTag(t *testing.T) {
+	sgi := &meta.ShardGroupInfo{Shards: make([]meta.ShardInfo, 16)}
+	for i := range sgi.Shards {
+		sgi.Shards[i].ID = uint64(i + 1)
+	}
+
+	now := time.Now()
+	shardFor := func(tag string, tags map[string]string, measurement string) uint64 {
+		p := models.MustNewPoint(measurement, models.NewTags(tags), models.Fields{"v": 1.0}, now)
+		return sgi.ShardForTag(p, tag).ID
+	}
+
+	// Every series for host a lands in the same shard.
+	exp := shardFor("host", map[string]string{"host": "a"}, "cpu")
+	for i := 0; i < 32; i++ {
+		tags := map[string]string{"host": "a", "core": fmt.Sprint(i)}
+		if got := shardFor("host", tags, fmt.Sprintf("m%d", i)); got != exp {
+			t.Fatalf("series %d: got shard %d, expected %d", i, got, exp)
+		}
+	}
+
+	// Without the tag the full series key is hashed.
+	p := models.MustNewPoint("cpu", models.NewTags(map[string]string{"region": "west"}), models.Fields{"v": 1.0}, now)
+	if got, exp := sgi.ShardForTag(p, "host"), sgi.ShardFor(p); got.ID != exp.ID {
+		t.Fatalf("got shard %d, expected %d", got.ID, exp.ID)
+	}
+	if got, exp := sgi.ShardForTag(p, ""), sgi.ShardFor(p); got.ID != exp.ID {
+		t.Fatalf("got shard %d, expected %d", got.ID, exp.ID)
+	}
+}
+
+func TestRetentionPolicyInfo_CloneMeta(t *testing.T) {
+	rpi := meta.RetentionPolicyInfo{
+		Name:               "rp",
+		ReplicaN:           2,
+		Duration:           time.Hour,
+		ShardGroupDuration: time.Minute,
+		ShardGroups:        []meta.ShardGroupInfo{{ID: 1}},
+		Subscriptions:      []meta.SubscriptionInfo{{Name: "sub"}},
+	}
+
+	other := rpi.CloneMeta()
+	if other.ShardGroups != nil || other.Subscriptions != nil {
+		t.Fatalf("got shard groups %v and subscriptions %v, expected nil", other.ShardGroups, other.Subscriptions)
+	}
+	exp := rpi
+	exp.ShardGroups, exp.Subscriptions = nil, nil
+	if !reflect.DeepEqual(other, exp) {
+		t.Fatalf("got %+v, expected %+v", other, exp)
+	}
+}
+
+func TestRetentionPolicyInfo_MarshalBinary_WriteAffinityTag(t *testing.T) {
+	rpi := meta.NewRetentionPolicyInfo("rp0")
+	rpi.WriteAffinityTag = "host"
+
+	buf, err := rpi.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var other meta.RetentionPolicyInfo
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := other.WriteAffinityTag, "host"; got != exp {
+		t.Fatalf("got %q, expected %q", got, exp)
+	}
+}
+
+func TestRetentionPolicyInfo_RetentionFor(t *testing.T) {
+	rpi := meta.NewRetentionPolicyInfo("rp0")
+	rpi.Duration = 7 * 24 * time.Hour
+	rpi.MeasurementRetention = map[string]time.Duration{"events": 0, "cpu": time.Hour}
+
+	buf, err := rpi.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.RetentionPolicyInfo
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for measurement, exp := range map[string]time.Duration{
+		"events": 0,
+		"cpu":    time.Hour,
+		"mem":    7 * 24 * time.Hour,
+	} {
+		if got := other.RetentionFor(measurement); got != exp {
+			t.Fatalf("got retention %v for %s, expected %v", got, measurement, exp)
+		}
+	}
+
+	// Copies don't share the overrides.
+	clone := other.CloneMeta()
+	clone.MeasurementRetention["cpu"] = 2 * time.Hour
+	if got, exp := other.RetentionFor("cpu"), time.Hour; got != exp {
+		t.Fatalf("got retention %v after changing a copy, expected %v", got, exp)
+	}
+}
+
+func TestDataView_NoMutatingMethods(t *testing.T) {
+	var _ meta.DataView = &meta.Data{}
+
+	mutating := []string{"Create", "Drop", "Delete", "Update", "Set", "Remove", "Copy", "Truncate", "Prune", "Import", "Merge", "Unmarshal", "Dedupe"}
+	typ := reflect.TypeOf((*meta.DataView)(nil)).Elem()
+	for i := 0; i < typ.NumMethod(); i++ {
+		name := typ.Method(i).Name
+		for _, prefix := range mutating {
+			if strings.HasPrefix(name, prefix) {
+				t.Errorf("DataView exposes mutating method %s", name)
+			}
+		}
+	}
+}
+
+func TestShardGroupInfo_ShardsOwnedBy(t *testing.T) {
+	sgi := meta.ShardGroupInfo{
+		ID: 1,
+		Shards: []meta.ShardInfo{
+			{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+			{ID: 2, Owners: []meta.ShardOwner{{NodeID: 2}, {NodeID: 3}}},
+			{ID: 3, Owners: []meta.ShardOwner{{NodeID: 3}, {NodeID: 1}}},
+		},
+	}
+
+	if !sgi.OwnedByAny(1) {
+		t.Fatal("expected node 1 to own a shard in the group")
+	}
+	if sgi.OwnedByAny(4) {
+		t.Fatal("expected node 4 to own no shard in the group")
+	}
+
+	shards := sgi.ShardsOwnedBy(1)
+	if got, exp := len(shards), 2; got != exp {
+		t.Fatalf("got %d shards, expected %d", got, exp)
+	}
+	if shards[0].ID != 1 || shards[1].ID != 3 {
+		t.Fatalf("got shards %d and %d, expected 1 and 3", shards[0].ID, shards[1].ID)
+	}
+	if shards := sgi.ShardsOwnedBy(4); len(shards) != 0 {
+		t.Fatalf("got %d shards for node 4, expected none", len(shards))
+	}
+}
+
+func TestShardGroupInfo_ExpireTime(t *testing.T) {
+	sgi := &meta.ShardGroupInfo{StartTime: time.Unix(0, 0), EndTime: time.Unix(3600, 0)}
+
+	if got, exp := sgi.ExpireTime(24*time.Hour), time.Unix(3600+86400, 0); !got.Equal(exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// An infinite retention policy never expires.
+	if got := sgi.ExpireTime(0); !got.IsZero() {
+		t.Fatalf("got %v, expected zero time", got)
+	}
+}
+
+func TestLeases_RenewRelease(t *testing.T) {
+	leases := meta.NewLeases(time.Minute)
+
+	if _, err := leases.Renew("retention", 1); err != meta.ErrLeaseNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrLeaseNotFound)
+	}
+	if err := leases.Release("retention", 1); err != meta.ErrLeaseNotFound {
+		t.Fatalf("got %v, expected %v", err, meta.ErrLeaseNotFound)
+	}
+
+	l, err := leases.Acquire("retention", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiration := l.Expiration
+
+	// Only the owner can renew or release the lease.
+	if _, err := leases.Renew("retention", 2); err != meta.ErrLeaseNotOwned {
+		t.Fatalf("got %v, expected %v", err, meta.ErrLeaseNotOwned)
+	}
+	if err := leases.Release("retention", 2); err != meta.ErrLeaseNotOwned {
+		t.Fatalf("got %v, expected %v", err, meta.ErrLeaseNotOwned)
+	}
+	if l, err := leases.Renew("retention", 1); err != nil {
+		t.Fatal(err)
+	} else if l.Expiration.Before(expiration) {
+		t.Fatalf("got expiration %v, expected no earlier than %v", l.Expiration, expiration)
+	}
+
+	// Once released, another node acquires the lease.
+	if err := leases.Release("retention", 1); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := leases.Acquire("retention", 2); err != nil {
+		t.Fatal(err)
+	} else if l.Owner != 2 {
+		t.Fatalf("got owner %d, expected 2", l.Owner)
+	}
+}
+
+func TestLeases_List(t *testing.T) {
+	leases := meta.NewLeases(time.Minute)
+	if got := leases.List(); len(got) != 0 {
+		t.Fatalf("got %d leases, expected none", len(got))
+	}
+
+	for i, name := range []string{"retention", "cq", "precreation"} {
+		if _, err := leases.Acquire(name, uint64(i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Expire the precreation lease.
+	l, err := leases.Acquire("precreation", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Expiration = time.Now().Add(-time.Second)
+
+	got := leases.List()
+	if len(got) != 2 {
+		t.Fatalf("got %d leases, expected 2", len(got))
+	}
+	if got[0].Name != "cq" || got[0].Owner != 2 || got[1].Name != "retention" || got[1].Owner != 1 {
+		t.Fatalf("unexpected leases: %+v", got)
+	}
+
+	// Changing the returned copies doesn't change the held leases.
+	got[0].Owner = 10
+	if l := leases.List()[0]; l.Owner != 2 {
+		t.Fatalf("got owner %d, expected 2", l.Owner)
+	}
+}
+
+func TestData_Diff(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDataNode("host1:8086", "host1:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateDatabase("db1"))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp1"), false))
+	must(data.CreateShardGroup("db0", "rp0", time.Unix(0, 0)))
+	must(data.CreateUser("susy", "pass", false))
+	must(data.SetPrivilege("susy", "db0", influxql.ReadPrivilege))
+	must(data.SetPrivilege("susy", "db1", influxql.WritePrivilege))
+
+	// Reordering slices is not a change.
+	other := data.Clone()
+	other.DataNodes[0], other.DataNodes[1] = other.DataNodes[1], other.DataNodes[0]
+	other.Databases[0], other.Databases[1] = other.Databases[1], other.Databases[0]
+	if diff := data.Diff(other); !diff.Empty() {
+		t.Fatalf("unexpected changes: %s", diff)
+	}
+
+	other = data.Clone()
+	must(other.CreateDataNode("host2:8086", "host2:8088"))
+	must(other.CreateDatabase("db2"))
+	must(other.DropRetentionPolicy("db0", "rp1"))
+	must(other.CreateShardGroup("db0", "rp0", time.Unix(0, 0).Add(7*24*time.Hour)))
+	must(other.SetPrivilege("susy", "db1", influxql.AllPrivileges))
+	must(other.UpdateDataNode(1, "host0:9086", "host0:8088"))
+
+	diff := data.Diff(other)
+	exp := meta.DataDiff{
+		Databases:         meta.DiffSet{Added: []string{"db2"}},
+		RetentionPolicies: meta.DiffSet{Removed: []string{"db0/rp1"}},
+		ShardGroups:       meta.DiffSet{Added: []string{"2"}},
+		DataNodes:         meta.DiffSet{Added: []string{"3"}, Modified: []string{"1"}},
+		Users:             meta.DiffSet{Modified: []string{"susy"}},
+	}
+	if !reflect.DeepEqual(diff, exp) {
+		t.Fatalf("got %+v, expected %+v", diff, exp)
+	}
+
+	expStr := "databases: +db2\nretention policies: -db0/rp1\nshard groups: +2\ndata nodes: +3 ~1\nusers: ~susy"
+	if got := diff.String(); got != expStr {
+		t.Fatalf("got:\n%s\nexpected:\n%s", got, expStr)
+	}
+}
+
+func TestData_DeleteDataNodeWithOpts(t *testing.T) {
+	newData := func() *meta.Data {
+		return &meta.Data{
+			DataNodes: []meta.NodeInfo{{ID: 1}, {ID: 2}},
+			Databases: []meta.DatabaseInfo{{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{{
+					Name:     "rp0",
+					ReplicaN: 1,
+					ShardGroups: []meta.ShardGroupInfo{
+						{ID: 1, Shards: []meta.ShardInfo{
+							{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}}},
+							{ID: 2, Owners: []meta.ShardOwner{{NodeID: 2}}},
+						}},
+						{ID: 2, Shards: []meta.ShardInfo{
+							{ID: 3, Owners: []meta.ShardOwner{{NodeID: 1}}},
+						}},
+					},
+				}},
+			}},
+		}
+	}
+
+	// A dry run returns the plan without changing the data.
+	data := newData()
+	plan, err := data.DeleteDataNodeWithOpts(1, meta.DeleteDataNodeOpts{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &meta.DeleteDataNodePlan{
+		Reassigned:         []meta.ShardMovement{{ShardID: 1, From: 1, To: 2}},
+		DeletedShardGroups: []uint64{2},
+	}
+	if !reflect.DeepEqual(plan, exp) {
+		t.Fatalf("got plan %+v, expected %+v", plan, exp)
+	}
+	if diff := data.Diff(newData()); !diff.Empty() {
+		t.Fatalf("dry run changed the data: %s", diff)
+	}
+
+	// Without the dry run the plan is applied.
+	if plan, err = data.DeleteDataNodeWithOpts(1, meta.DeleteDataNodeOpts{}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(plan, exp) {
+		t.Fatalf("got plan %+v, expected %+v", plan, exp)
+	}
+	if data.DataNode(1) != nil {
+		t.Fatal("data node 1 not deleted")
+	}
+	sgs := data.Databases[0].RetentionPolicies[0].ShardGroups
+	if got, exp := sgs[0].Shards[0].Owners, []meta.ShardOwner{{NodeID: 2}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got owners %v, expected %v", got, exp)
+	}
+	if sgs[0].Deleted() || !sgs[1].Deleted() {
+		t.Fatal("unexpected shard groups marked deleted")
+	}
+
+	if _, err := data.DeleteDataNodeWithOpts(1, meta.DeleteDataNodeOpts{}); err != meta.ErrNodeNotFound {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrNodeNotFound)
+	}
+
+	// An orphaned shard with no other node to take it fails the deletion
+	// unless forced.
+	orphaning := func() *meta.Data {
+		data := newData()
+		data.DataNodes = data.DataNodes[:1]
+		data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards = []meta.ShardInfo{
+			{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 1}}},
+			{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}}},
+		}
+		return data
+	}
+	data = orphaning()
+	if _, err := data.DeleteDataNodeWithOpts(1, meta.DeleteDataNodeOpts{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if diff := data.Diff(orphaning()); !diff.Empty() {
+		t.Fatalf("failed deletion changed the data: %s", diff)
+	}
+
+	plan, err = data.DeleteDataNodeWithOpts(1, meta.DeleteDataNodeOpts{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := plan.DeletedShardGroups, []uint64{1, 2}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got deleted shard groups %v, expected %v", got, exp)
+	}
+	if !data.Databases[0].RetentionPolicies[0].ShardGroups[0].Deleted() {
+		t.Fatal("shard group 1 not marked deleted")
+	}
+}
+
+func TestData_NodeZone(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNodeWithZone("host1:8086", "host1:8088", "rack1"))
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateMetaNodeWithZone("host2:8091", "host2:8089", "rack2"))
+
+	// The zone survives cloning and a marshal round trip.
+	buf, err := data.Clone().MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+
+	exp := []meta.NodeInfo{
+		{ID: 1, Addr: "host1:8086", TCPAddr: "host1:8088", Zone: "rack1", Status: meta.NodeStatusJoined},
+		{ID: 2, Addr: "host0:8086", TCPAddr: "host0:8088", Status: meta.NodeStatusJoined},
+	}
+	if !reflect.DeepEqual(other.DataNodes, exp) {
+		t.Fatalf("got data nodes %+v, expected %+v", other.DataNodes, exp)
+	}
+	if got, exp := other.MetaNodes[0].Zone, "rack2"; got != exp {
+		t.Fatalf("got meta node zone %q, expected %q", got, exp)
+	}
+}
+
+func TestData_ImportData_Merge(t *testing.T) {
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backup := meta.Data{}
+	must(backup.CreateDataNode("host0:8086", "host0:8088"))
+	must(backup.CreateDatabase("db0"))
+	must(backup.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp1"), true))
+	must(backup.CreateShardGroup("db0", "rp1", time.Unix(0, 0)))
+	backupShardID := backup.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].ID
+
+	data := &meta.Data{}
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateShardGroup("db0", "rp0", time.Unix(0, 0)))
+
+	// Without merging, an existing database can't be imported into.
+	if _, _, err := data.ImportData(backup, "db0", "", "rp1", ""); err == nil {
+		t.Fatal("expected an error importing into an existing database")
+	}
+
+	shardIDMap, newDBs, err := data.ImportDataMerge(backup, "db0", "", "rp1", "")
+	must(err)
+	if exp := []string{"db0"}; !reflect.DeepEqual(newDBs, exp) {
+		t.Fatalf("got databases %v, expected %v", newDBs, exp)
+	}
+
+	dbi := data.Database("db0")
+	if got, exp := len(dbi.RetentionPolicies), 2; got != exp {
+		t.Fatalf("got %d retention policies, expected %d", got, exp)
+	}
+	if got, exp := dbi.DefaultRetentionPolicy, "rp0"; got != exp {
+		t.Fatalf("got default retention policy %s, expected %s", got, exp)
+	}
+
+	// The imported shard is renumbered after the existing one.
+	rpi := dbi.RetentionPolicy("rp1")
+	if got, exp := rpi.ShardGroups[0].ID, uint64(2); got != exp {
+		t.Fatalf("got shard group ID %d, expected %d", got, exp)
+	}
+	sh := rpi.ShardGroups[0].Shards[0]
+	if got, exp := shardIDMap[backupShardID], sh.ID; got != exp || exp != 2 {
+		t.Fatalf("got shard ID map %v, expected %d to map to 2", shardIDMap, backupShardID)
+	}
+
+	// Merging a retention policy whose name is taken fails and changes
+	// nothing.
+	before := data.Clone()
+	if _, _, err := data.ImportDataMerge(backup, "db0", "", "rp1", ""); err == nil {
+		t.Fatal("expected an error for a retention policy name collision")
+	}
+	if diff := data.Diff(before); !diff.Empty() || data.MaxShardID != before.MaxShardID {
+		t.Fatalf("failed merge changed the data: %s", diff)
+	}
+}
+
+func TestData_UnmarshalBinary_Checksum(t *testing.T) {
+	data := &meta.Data{Index: 1, ClusterID: 2}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksummed := meta.ChecksumMetadata(buf)
+
+	// A flipped bit in the payload is detected.
+	corrupt := append([]byte{}, checksummed...)
+	corrupt[len(corrupt)-1] ^= 0x01
+	if err := (&meta.Data{}).UnmarshalBinary(corrupt); err != meta.ErrMetadataCorrupt {
+		t.Fatalf("got error %v, expected %v", err, meta.ErrMetadataCorrupt)
+	}
+
+	// Metadata with and without the checksum header decodes.
+	for _, b := range [][]byte{checksummed, buf} {
+		other := &meta.Data{}
+		if err := other.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		} else if other.Database("db0") == nil || other.ClusterID != 2 {
+			t.Fatalf("unexpected data decoded: %+v", other)
+		}
+	}
+}
+
+func TestSafeData(t *testing.T) {
+	data := meta.NewSafeData(&meta.Data{})
+	if err := data.Update(func(data *meta.Data) error {
+		return data.CreateDataNode("host0:8086", "host0:8088")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			rpi := meta.NewRetentionPolicyInfo(fmt.Sprintf("rp%d", i))
+			if err := data.CreateRetentionPolicy("db0", rpi, false); err != nil {
+				t.Error(err)
+			}
+			if err := data.CreateShardGroup("db0", rpi.Name, time.Unix(0, 0)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			data.Database("db0")
+			data.ShardGroupsByTimeRange("db0", "rp0", time.Unix(0, 0), time.Unix(1, 0))
+		}()
+	}
+	wg.Wait()
+
+	// Returned values are copies.
+	groups, err := data.ShardGroupsByTimeRange("db0", "rp0", time.Unix(0, 0), time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(groups) != 1 {
+		t.Fatalf("got %d shard groups, expected 1", len(groups))
+	}
+	groups[0].Shards[0].ID = 100
+	data.Database("db0").RetentionPolicies[0].Name = "changed"
+
+	if err := data.View(func(data *meta.Data) error {
+		rpi := data.Database("db0").RetentionPolicy("rp0")
+		if rpi == nil {
+			t.Fatal("retention policy changed through a copy")
+		} else if rpi.ShardGroups[0].Shards[0].ID == 100 {
+			t.Fatal("shard changed through a copy")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestData_MarshalJSON(t *testing.T) {
+	data := &meta.Data{Index: 1, ClusterID: 2}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDatabase("db0"))
+	must(data.CreateRetentionPolicy("db0", meta.NewRetentionPolicyInfo("rp0"), true))
+	must(data.CreateShardGroup("db0", "rp0", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	must(data.CreateUser("susy", "pass", true))
+	must(data.SetPrivilege("susy", "db0", influxql.ReadPrivilege))
+
+	buf, err := data.MarshalJSON()
+	must(err)
+
+	var out struct {
+		DataNodes []struct {
+			TCPAddr string `json:"tcp-addr"`
+		} `json:"data-nodes"`
+		Databases []struct {
+			RetentionPolicies []struct {
+				ShardGroups []struct {
+					StartTime string `json:"start-time"`
+					DeletedAt string `json:"deleted-at"`
+					Shards    []struct {
+						Owners []uint64 `json:"owners"`
+					} `json:"shards"`
+				} `json:"shard-groups"`
+			} `json:"retention-policies"`
+		} `json:"databases"`
+		Users []struct {
+			Name       string            `json:"name"`
+			Hash       string            `json:"hash"`
+			Privileges map[string]string `json:"privileges"`
+		} `json:"users"`
+	}
+	must(json.Unmarshal(buf, &out))
+
+	if got, exp := out.DataNodes[0].TCPAddr, "host0:8088"; got != exp {
+		t.Fatalf("got tcp addr %s, expected %s", got, exp)
+	}
+	sg := out.Databases[0].RetentionPolicies[0].ShardGroups[0]
+	if got, exp := sg.StartTime, "2019-12-30T00:00:00Z"; got != exp {
+		t.Fatalf("got start time %s, expected %s", got, exp)
+	} else if sg.DeletedAt != "" {
+		t.Fatalf("got deleted at %s, expected none", sg.DeletedAt)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(sg.Shards[0].Owners, exp) {
+		t.Fatalf("got owners %v, expected %v", sg.Shards[0].Owners, exp)
+	}
+	if u := out.Users[0]; u.Hash != "" {
+		t.Fatal("password hash exported by default")
+	} else if got, exp := u.Privileges["db0"], influxql.ReadPrivilege.String(); got != exp {
+		t.Fatalf("got privilege %s, expected %s", got, exp)
+	}
+
+	buf, err = data.MarshalJSONWithOpts(meta.MarshalJSONOpts{IncludeHashes: true})
+	must(err)
+	must(json.Unmarshal(buf, &out))
+	if out.Users[0].Hash == "" {
+		t.Fatal("password hash not exported")
+	}
+}
+
+func TestData_SetShardOwnerState(t *testing.T) {
+	data := &meta.Data{Index: 1}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDataNode("host1:8086", "host1:8088"))
+	must(data.CreateDatabase("db0"))
+	rpi := meta.NewRetentionPolicyInfo("rp0")
+	rpi.ReplicaN = 2
+	must(data.CreateRetentionPolicy("db0", rpi, true))
+	must(data.CreateShardGroup("db0", "rp0", time.Unix(0, 0)))
+
+	must(data.SetShardOwnerState(1, 2, meta.ShardStateCopying))
+	if got, exp := data.SetShardOwnerState(1, 2, "lukewarm"), meta.ErrInvalidShardState; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got, exp := data.SetShardOwnerState(1, 3, meta.ShardStateHot), meta.ErrShardOwnerNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got, exp := data.SetShardOwnerState(100, 1, meta.ShardStateHot), meta.ErrShardNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+
+	// The state survives a marshal round trip.
+	buf, err := data.MarshalBinary()
+	must(err)
+	other := &meta.Data{}
+	must(other.UnmarshalBinary(buf))
+
+	owners := other.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0].Owners
+	for _, o := range owners {
+		if got, exp := o.Hot(), o.NodeID == 1; got != exp {
+			t.Fatalf("got hot %v for owner %+v, expected %v", got, o, exp)
+		}
+	}
+	if len(owners) != 2 {
+		t.Fatalf("got owners %+v, expected two", owners)
+	}
+}
+
+func TestData_MoveShard(t *testing.T) {
+	data := &meta.Data{Index: 1}
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDataNode("host1:8086", "host1:8088"))
+	must(data.CreateDataNode("host2:8086", "host2:8088"))
+	must(data.CreateDatabase("db0"))
+	rpi := meta.NewRetentionPolicyInfo("rp0")
+	rpi.ReplicaN = 2
+	must(data.CreateRetentionPolicy("db0", rpi, true))
+	must(data.CreateShardGroup("db0", "rp0", time.Unix(0, 0)))
+
+	si := &data.Databases[0].RetentionPolicies[0].ShardGroups[0].Shards[0]
+	from, other := si.Owners[0].NodeID, si.Owners[1]
+	to := 6 - from - other.NodeID
+	must(data.SetShardOwnerState(si.ID, from, meta.ShardStateStale))
+
+	if got, exp := data.MoveShard(si.ID, to, from), meta.ErrShardOwnerExists; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got, exp := data.MoveShard(si.ID, to, 100), meta.ErrNodeNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got, exp := data.MoveShard(100, from, to), meta.ErrShardNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+
+	// The new owner takes the old one's place and copies its data.
+	must(data.MoveShard(si.ID, from, to))
+	if exp := []meta.ShardOwner{{NodeID: to, State: meta.ShardStateCopying}, other}; !reflect.DeepEqual(si.Owners, exp) {
+		t.Fatalf("got owners %+v, expected %+v", si.Owners, exp)
+	}
+	if got, exp := data.MoveShard(si.ID, from, to), meta.ErrShardOwnerExists; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
+	}
+	if got, exp := data.MoveShard(si.ID, from, from), meta.ErrShardOwnerNotFound; got != exp {
+		t.Fatalf("got error %v, expected %v", got, exp)
 	}
-	return string(b)
 }
@@ -3,6 +3,7 @@ package meta
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -11,6 +12,14 @@ var (
 
 	// ErrStoreClosed is returned when closing an already closed store.
 	ErrStoreClosed = errors.New("raft store already closed")
+
+	// ErrMetadataCorrupt is returned when decoding metadata that doesn't
+	// match its checksum.
+	ErrMetadataCorrupt = errors.New("metadata corrupt: checksum mismatch")
+
+	// ErrMetadataVersion is returned when decoding metadata written in a
+	// newer format than this version supports.
+	ErrMetadataVersion = errors.New("metadata format version is not supported")
 )
 
 var (
@@ -20,6 +29,14 @@ var (
 	// ErrNodeNotFound is returned when mutating a node that doesn't exist.
 	ErrNodeNotFound = errors.New("node not found")
 
+	// ErrInvalidNodeStatus is returned when setting a node to an unknown
+	// status.
+	ErrInvalidNodeStatus = errors.New("invalid node status")
+
+	// ErrNodeAlreadyDataNode is returned when promoting a meta node that is
+	// already a data node.
+	ErrNodeAlreadyDataNode = errors.New("node is already a data node")
+
 	// ErrNodesRequired is returned when at least one node is required for an operation.
 	// This occurs when creating a shard group.
 	ErrNodesRequired = errors.New("at least one node required")
@@ -45,6 +62,18 @@ var (
 	// ErrDatabaseNameRequired is returned when creating a database without a name.
 	ErrDatabaseNameRequired = errors.New("database name required")
 
+	// ErrDatabaseHasShards is returned when renaming a database that has shard
+	// groups, whose data the store keeps under the database name.
+	ErrDatabaseHasShards = errors.New("database has shard groups")
+
+	// ErrDatabaseLimitNegative is returned when setting a negative database
+	// cardinality limit.
+	ErrDatabaseLimitNegative = errors.New("database limit must not be negative")
+
+	// ErrShardGroupDurationNegative is returned when setting a negative
+	// default shard group duration on a database.
+	ErrShardGroupDurationNegative = errors.New("shard group duration must not be negative")
+
 	// ErrNameTooLong is returned when attempting to create a database or
 	// retention policy with a name that is too long.
 	ErrNameTooLong = errors.New("name too long")
@@ -71,6 +100,15 @@ var (
 	// ErrRetentionPolicyNameRequired is returned when creating a policy without a name.
 	ErrRetentionPolicyNameRequired = errors.New("retention policy name required")
 
+	// ErrNoDefaultRetentionPolicy is returned when an operation needs the
+	// default retention policy of a database that has none.
+	ErrNoDefaultRetentionPolicy = errors.New("database has no default retention policy")
+
+	// ErrRetentionPolicyHasShards is returned when renaming a retention
+	// policy that has shard groups, whose data the store keeps under the
+	// policy name.
+	ErrRetentionPolicyHasShards = errors.New("retention policy has shard groups")
+
 	// ErrRetentionPolicyNameExists is returned when renaming a policy to
 	// the same name as another existing policy.
 	ErrRetentionPolicyNameExists = errors.New("retention policy name already exists")
@@ -83,6 +121,15 @@ var (
 	// with an existing policy.
 	ErrRetentionPolicyConflict = errors.New("retention policy conflicts with an existing policy")
 
+	// ErrRetentionPolicyExpiresShardGroups is returned when a strict update
+	// to a retention policy's duration would expire existing shard groups.
+	ErrRetentionPolicyExpiresShardGroups = errors.New("retention policy update would expire existing shard groups")
+
+	// ErrShardGroupDurationNotDivisor is returned by a strict retention policy
+	// creation when the duration is not a multiple of the shard group
+	// duration. It is wrapped in a *ShardGroupDurationError.
+	ErrShardGroupDurationNotDivisor = errors.New("retention policy duration must be a multiple of the shard group duration")
+
 	// ErrIncompatibleDurations is returned when creating or updating a
 	// retention policy that has a duration lower than the current shard
 	// duration.
@@ -91,6 +138,22 @@ var (
 	// ErrReplicationFactorTooLow is returned when the replication factor is not in an
 	// acceptable range.
 	ErrReplicationFactorTooLow = errors.New("replication factor must be greater than 0")
+
+	// ErrReplicationFactorExceedsNodes is returned when the replication factor
+	// of a retention policy is raised above the number of data nodes.
+	ErrReplicationFactorExceedsNodes = errors.New("replication factor exceeds the number of data nodes")
+
+	// ErrShardCountTooLow is returned when the shard count of a retention
+	// policy is not in an acceptable range.
+	ErrShardCountTooLow = errors.New("shard count must be greater than 0")
+
+	// ErrPreCreateCountTooLow is returned when the pre-create count of a
+	// retention policy is negative.
+	ErrPreCreateCountTooLow = errors.New("pre-create count must not be negative")
+
+	// ErrAlignmentBaseTooLow is returned when checking shard group alignment
+	// against a duration that is not positive.
+	ErrAlignmentBaseTooLow = errors.New("alignment base must be greater than 0")
 )
 
 var (
@@ -100,9 +163,44 @@ var (
 	// ErrShardGroupNotFound is returned when mutating a shard group that doesn't exist.
 	ErrShardGroupNotFound = errors.New("shard group not found")
 
+	// ErrShardNotFound is returned when mutating a shard that doesn't exist.
+	ErrShardNotFound = errors.New("shard not found")
+
+	// ErrShardOwnerNotFound is returned when mutating the owner of a shard
+	// that the node doesn't own.
+	ErrShardOwnerNotFound = errors.New("shard owner not found")
+
+	// ErrShardOwnerExists is returned when assigning a shard to a node that
+	// already owns it.
+	ErrShardOwnerExists = errors.New("shard owner already exists")
+
+	// ErrShardOwnerCountMismatch is returned when creating a shard group with
+	// a number of owner sets that differs from its number of shards.
+	ErrShardOwnerCountMismatch = errors.New("number of shard owner sets does not match shard count")
+
+	// ErrInvalidShardState is returned when setting a shard owner to an
+	// unknown state.
+	ErrInvalidShardState = errors.New("invalid shard state")
+
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
+
+	// ErrInvalidOffset is returned when a page of shard groups is requested
+	// with a negative offset.
+	ErrInvalidOffset = errors.New("offset must not be negative")
+
+	// ErrInvalidLimit is returned when a page of shard groups is requested
+	// with a limit that is not positive.
+	ErrInvalidLimit = errors.New("limit must be greater than 0")
+
+	// ErrInvalidRebalanceStrategy is returned when shards are rebalanced
+	// with an unknown strategy.
+	ErrInvalidRebalanceStrategy = errors.New("invalid rebalance strategy")
+
+	// ErrInvalidPlacementStrategy is returned when an unknown shard placement
+	// strategy is set.
+	ErrInvalidPlacementStrategy = errors.New("invalid shard placement strategy")
 )
 
 var (
@@ -119,6 +217,9 @@ var (
 
 	// ErrSubscriptionNotFound is returned when removing a subscription that doesn't exist.
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrInvalidSubscriptionMode is returned when a subscription's mode is not ANY or ALL.
+	ErrInvalidSubscriptionMode = errors.New("invalid subscription mode, must be ANY or ALL")
 )
 
 // ErrInvalidSubscriptionURL is returned when the subscription's destination URL is invalid.
@@ -153,3 +254,31 @@ var (
 	// ErrRoleNameRequired is returned when creating a role without a role name.
 	ErrRoleNameRequired = errors.New("role name required")
 )
+
+var (
+	// ErrLeaseNotFound is returned when releasing or renewing a lease that
+	// isn't held.
+	ErrLeaseNotFound = errors.New("lease not found")
+
+	// ErrLeaseNotOwned is returned when releasing or renewing a lease held by
+	// another node, or one that has expired.
+	ErrLeaseNotOwned = errors.New("lease not owned by node")
+)
+
+// ShardGroupDurationError is returned when a retention policy's duration is not
+// a multiple of its shard group duration. Suggested is the nearest shard group
+// duration that divides the retention duration evenly.
+type ShardGroupDurationError struct {
+	Duration           time.Duration
+	ShardGroupDuration time.Duration
+	Suggested          time.Duration
+}
+
+// Error returns the error message, including the suggested shard group duration.
+func (e *ShardGroupDurationError) Error() string {
+	return fmt.Sprintf("%s: duration %s, shard group duration %s, try shard group duration %s",
+		ErrShardGroupDurationNotDivisor, e.Duration, e.ShardGroupDuration, e.Suggested)
+}
+
+// Unwrap returns ErrShardGroupDurationNotDivisor.
+func (e *ShardGroupDurationError) Unwrap() error { return ErrShardGroupDurationNotDivisor }
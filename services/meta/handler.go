@@ -66,7 +66,7 @@ type handler struct {
 		leader() string
 		leaderHTTP() string
 		snapshot() (*Data, error)
-		apply(b []byte) error
+		applyWithID(b []byte) (uint64, error)
 		join(addr, raftAddr string) (*NodeInfo, error)
 		leave(raftAddr string) error
 		remove(addr string) error
@@ -272,7 +272,7 @@ func (h *handler) serveExec(w http.ResponseWriter, r *http.Request) {
 
 	// Apply the command to the store.
 	var resp *internal.Response
-	if err := h.store.apply(body); err != nil {
+	if id, err := h.store.applyWithID(body); err != nil {
 		// If we aren't the leader, redirect client to the leader.
 		if err == raft.ErrNotLeader {
 			l := h.store.leaderHTTP()
@@ -292,11 +292,15 @@ func (h *handler) serveExec(w http.ResponseWriter, r *http.Request) {
 			Error: proto.String(err.Error()),
 		}
 	} else {
-		// Apply was successful. Return the new store index to the client.
+		// Apply was successful. Return the new store index to the client,
+		// and the node ID the command assigned.
 		resp = &internal.Response{
 			OK:    proto.Bool(false),
 			Index: proto.Uint64(h.store.index()),
 		}
+		if id != 0 {
+			resp.ID = proto.Uint64(id)
+		}
 	}
 
 	// Marshal the response.
@@ -23,39 +23,65 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type Command_Type int32
 
 const (
-	Command_CreateNodeCommand                Command_Type = 1
-	Command_DeleteNodeCommand                Command_Type = 2
-	Command_CreateDatabaseCommand            Command_Type = 3
-	Command_DropDatabaseCommand              Command_Type = 4
-	Command_CreateRetentionPolicyCommand     Command_Type = 5
-	Command_DropRetentionPolicyCommand       Command_Type = 6
-	Command_SetDefaultRetentionPolicyCommand Command_Type = 7
-	Command_UpdateRetentionPolicyCommand     Command_Type = 8
-	Command_CreateShardGroupCommand          Command_Type = 9
-	Command_DeleteShardGroupCommand          Command_Type = 10
-	Command_CreateContinuousQueryCommand     Command_Type = 11
-	Command_DropContinuousQueryCommand       Command_Type = 12
-	Command_CreateUserCommand                Command_Type = 13
-	Command_DropUserCommand                  Command_Type = 14
-	Command_UpdateUserCommand                Command_Type = 15
-	Command_SetPrivilegeCommand              Command_Type = 16
-	Command_SetDataCommand                   Command_Type = 17
-	Command_SetAdminPrivilegeCommand         Command_Type = 18
-	Command_UpdateNodeCommand                Command_Type = 19
-	Command_CreateSubscriptionCommand        Command_Type = 21
-	Command_DropSubscriptionCommand          Command_Type = 22
-	Command_RemovePeerCommand                Command_Type = 23
-	Command_CreateMetaNodeCommand            Command_Type = 24
-	Command_CreateDataNodeCommand            Command_Type = 25
-	Command_UpdateDataNodeCommand            Command_Type = 26
-	Command_DeleteMetaNodeCommand            Command_Type = 27
-	Command_DeleteDataNodeCommand            Command_Type = 28
-	Command_SetMetaNodeCommand               Command_Type = 29
-	Command_DropShardCommand                 Command_Type = 30
-	Command_TruncateShardGroupsCommand       Command_Type = 31
-	Command_PruneShardGroupsCommand          Command_Type = 32
-	Command_CopyShardOwnerCommand            Command_Type = 33
-	Command_RemoveShardOwnerCommand          Command_Type = 34
+	Command_CreateNodeCommand                   Command_Type = 1
+	Command_DeleteNodeCommand                   Command_Type = 2
+	Command_CreateDatabaseCommand               Command_Type = 3
+	Command_DropDatabaseCommand                 Command_Type = 4
+	Command_CreateRetentionPolicyCommand        Command_Type = 5
+	Command_DropRetentionPolicyCommand          Command_Type = 6
+	Command_SetDefaultRetentionPolicyCommand    Command_Type = 7
+	Command_UpdateRetentionPolicyCommand        Command_Type = 8
+	Command_CreateShardGroupCommand             Command_Type = 9
+	Command_DeleteShardGroupCommand             Command_Type = 10
+	Command_CreateContinuousQueryCommand        Command_Type = 11
+	Command_DropContinuousQueryCommand          Command_Type = 12
+	Command_CreateUserCommand                   Command_Type = 13
+	Command_DropUserCommand                     Command_Type = 14
+	Command_UpdateUserCommand                   Command_Type = 15
+	Command_SetPrivilegeCommand                 Command_Type = 16
+	Command_SetDataCommand                      Command_Type = 17
+	Command_SetAdminPrivilegeCommand            Command_Type = 18
+	Command_UpdateNodeCommand                   Command_Type = 19
+	Command_CreateSubscriptionCommand           Command_Type = 21
+	Command_DropSubscriptionCommand             Command_Type = 22
+	Command_RemovePeerCommand                   Command_Type = 23
+	Command_CreateMetaNodeCommand               Command_Type = 24
+	Command_CreateDataNodeCommand               Command_Type = 25
+	Command_UpdateDataNodeCommand               Command_Type = 26
+	Command_DeleteMetaNodeCommand               Command_Type = 27
+	Command_DeleteDataNodeCommand               Command_Type = 28
+	Command_SetMetaNodeCommand                  Command_Type = 29
+	Command_DropShardCommand                    Command_Type = 30
+	Command_TruncateShardGroupsCommand          Command_Type = 31
+	Command_PruneShardGroupsCommand             Command_Type = 32
+	Command_CopyShardOwnerCommand               Command_Type = 33
+	Command_RemoveShardOwnerCommand             Command_Type = 34
+	Command_TouchShardCommand                   Command_Type = 35
+	Command_RebalanceShardsCommand              Command_Type = 36
+	Command_SetPlacementStrategyCommand         Command_Type = 37
+	Command_CreateRoleCommand                   Command_Type = 38
+	Command_DropRoleCommand                     Command_Type = 39
+	Command_AddUserToRoleCommand                Command_Type = 40
+	Command_RemoveUserFromRoleCommand           Command_Type = 41
+	Command_SetRolePrivilegeCommand             Command_Type = 42
+	Command_TouchShardsCommand                  Command_Type = 43
+	Command_UpdateSubscriptionCommand           Command_Type = 44
+	Command_RenameDatabaseCommand               Command_Type = 45
+	Command_RenameRetentionPolicyCommand        Command_Type = 46
+	Command_SetDefaultShardGroupDurationCommand Command_Type = 47
+	Command_SetDatabaseLimitsCommand            Command_Type = 48
+	Command_SetShardOwnerStateCommand           Command_Type = 49
+	Command_SetContinuousQueryEnabledCommand    Command_Type = 50
+	Command_UpdateContinuousQueryCommand        Command_Type = 51
+	Command_PromoteToDataNodeCommand            Command_Type = 52
+	Command_SetPrivilegesCommand                Command_Type = 53
+	Command_SetDataNodeStatusCommand            Command_Type = 54
+	Command_CreateShardGroupWithOwnersCommand   Command_Type = 55
+	Command_SetWriteAffinityTagCommand          Command_Type = 56
+	Command_SetPendingShardCountCommand         Command_Type = 57
+	Command_SetPreCreateCountCommand            Command_Type = 58
+	Command_PreCreateShardGroupsCommand         Command_Type = 59
+	Command_MoveShardCommand                    Command_Type = 60
 )
 
 var Command_Type_name = map[int32]string{
@@ -92,42 +118,94 @@ var Command_Type_name = map[int32]string{
 	32: "PruneShardGroupsCommand",
 	33: "CopyShardOwnerCommand",
 	34: "RemoveShardOwnerCommand",
+	35: "TouchShardCommand",
+	36: "RebalanceShardsCommand",
+	37: "SetPlacementStrategyCommand",
+	38: "CreateRoleCommand",
+	39: "DropRoleCommand",
+	40: "AddUserToRoleCommand",
+	41: "RemoveUserFromRoleCommand",
+	42: "SetRolePrivilegeCommand",
+	43: "TouchShardsCommand",
+	44: "UpdateSubscriptionCommand",
+	45: "RenameDatabaseCommand",
+	46: "RenameRetentionPolicyCommand",
+	47: "SetDefaultShardGroupDurationCommand",
+	48: "SetDatabaseLimitsCommand",
+	49: "SetShardOwnerStateCommand",
+	50: "SetContinuousQueryEnabledCommand",
+	51: "UpdateContinuousQueryCommand",
+	52: "PromoteToDataNodeCommand",
+	53: "SetPrivilegesCommand",
+	54: "SetDataNodeStatusCommand",
+	55: "CreateShardGroupWithOwnersCommand",
+	56: "SetWriteAffinityTagCommand",
+	57: "SetPendingShardCountCommand",
+	58: "SetPreCreateCountCommand",
+	59: "PreCreateShardGroupsCommand",
+	60: "MoveShardCommand",
 }
 
 var Command_Type_value = map[string]int32{
-	"CreateNodeCommand":                1,
-	"DeleteNodeCommand":                2,
-	"CreateDatabaseCommand":            3,
-	"DropDatabaseCommand":              4,
-	"CreateRetentionPolicyCommand":     5,
-	"DropRetentionPolicyCommand":       6,
-	"SetDefaultRetentionPolicyCommand": 7,
-	"UpdateRetentionPolicyCommand":     8,
-	"CreateShardGroupCommand":          9,
-	"DeleteShardGroupCommand":          10,
-	"CreateContinuousQueryCommand":     11,
-	"DropContinuousQueryCommand":       12,
-	"CreateUserCommand":                13,
-	"DropUserCommand":                  14,
-	"UpdateUserCommand":                15,
-	"SetPrivilegeCommand":              16,
-	"SetDataCommand":                   17,
-	"SetAdminPrivilegeCommand":         18,
-	"UpdateNodeCommand":                19,
-	"CreateSubscriptionCommand":        21,
-	"DropSubscriptionCommand":          22,
-	"RemovePeerCommand":                23,
-	"CreateMetaNodeCommand":            24,
-	"CreateDataNodeCommand":            25,
-	"UpdateDataNodeCommand":            26,
-	"DeleteMetaNodeCommand":            27,
-	"DeleteDataNodeCommand":            28,
-	"SetMetaNodeCommand":               29,
-	"DropShardCommand":                 30,
-	"TruncateShardGroupsCommand":       31,
-	"PruneShardGroupsCommand":          32,
-	"CopyShardOwnerCommand":            33,
-	"RemoveShardOwnerCommand":          34,
+	"CreateNodeCommand":                   1,
+	"DeleteNodeCommand":                   2,
+	"CreateDatabaseCommand":               3,
+	"DropDatabaseCommand":                 4,
+	"CreateRetentionPolicyCommand":        5,
+	"DropRetentionPolicyCommand":          6,
+	"SetDefaultRetentionPolicyCommand":    7,
+	"UpdateRetentionPolicyCommand":        8,
+	"CreateShardGroupCommand":             9,
+	"DeleteShardGroupCommand":             10,
+	"CreateContinuousQueryCommand":        11,
+	"DropContinuousQueryCommand":          12,
+	"CreateUserCommand":                   13,
+	"DropUserCommand":                     14,
+	"UpdateUserCommand":                   15,
+	"SetPrivilegeCommand":                 16,
+	"SetDataCommand":                      17,
+	"SetAdminPrivilegeCommand":            18,
+	"UpdateNodeCommand":                   19,
+	"CreateSubscriptionCommand":           21,
+	"DropSubscriptionCommand":             22,
+	"RemovePeerCommand":                   23,
+	"CreateMetaNodeCommand":               24,
+	"CreateDataNodeCommand":               25,
+	"UpdateDataNodeCommand":               26,
+	"DeleteMetaNodeCommand":               27,
+	"DeleteDataNodeCommand":               28,
+	"SetMetaNodeCommand":                  29,
+	"DropShardCommand":                    30,
+	"TruncateShardGroupsCommand":          31,
+	"PruneShardGroupsCommand":             32,
+	"CopyShardOwnerCommand":               33,
+	"RemoveShardOwnerCommand":             34,
+	"TouchShardCommand":                   35,
+	"RebalanceShardsCommand":              36,
+	"SetPlacementStrategyCommand":         37,
+	"CreateRoleCommand":                   38,
+	"DropRoleCommand":                     39,
+	"AddUserToRoleCommand":                40,
+	"RemoveUserFromRoleCommand":           41,
+	"SetRolePrivilegeCommand":             42,
+	"TouchShardsCommand":                  43,
+	"UpdateSubscriptionCommand":           44,
+	"RenameDatabaseCommand":               45,
+	"RenameRetentionPolicyCommand":        46,
+	"SetDefaultShardGroupDurationCommand": 47,
+	"SetDatabaseLimitsCommand":            48,
+	"SetShardOwnerStateCommand":           49,
+	"SetContinuousQueryEnabledCommand":    50,
+	"UpdateContinuousQueryCommand":        51,
+	"PromoteToDataNodeCommand":            52,
+	"SetPrivilegesCommand":                53,
+	"SetDataNodeStatusCommand":            54,
+	"CreateShardGroupWithOwnersCommand":   55,
+	"SetWriteAffinityTagCommand":          56,
+	"SetPendingShardCountCommand":         57,
+	"SetPreCreateCountCommand":            58,
+	"PreCreateShardGroupsCommand":         59,
+	"MoveShardCommand":                    60,
 }
 
 func (x Command_Type) Enum() *Command_Type {
@@ -150,7 +228,7 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 }
 
 func (Command_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{12, 0}
+	return fileDescriptor_59b0956366e72083, []int{14, 0}
 }
 
 type Data struct {
@@ -166,6 +244,9 @@ type Data struct {
 	// added for 0.10.0
 	DataNodes            []*NodeInfo `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
 	MetaNodes            []*NodeInfo `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Roles                []*RoleInfo `protobuf:"bytes,12,rep,name=Roles" json:"Roles,omitempty"`
+	Version              *uint32     `protobuf:"varint,13,opt,name=Version" json:"Version,omitempty"`
+	PlacementStrategy    *string     `protobuf:"bytes,14,opt,name=PlacementStrategy" json:"PlacementStrategy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -272,10 +353,33 @@ func (m *Data) GetMetaNodes() []*NodeInfo {
 	return nil
 }
 
+func (m *Data) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Data) GetVersion() uint32 {
+	if m != nil && m.Version != nil {
+		return *m.Version
+	}
+	return 0
+}
+
+func (m *Data) GetPlacementStrategy() string {
+	if m != nil && m.PlacementStrategy != nil {
+		return *m.PlacementStrategy
+	}
+	return ""
+}
+
 type NodeInfo struct {
 	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	Addr                 *string  `protobuf:"bytes,2,opt,name=Addr" json:"Addr,omitempty"`
 	TCPAddr              *string  `protobuf:"bytes,3,opt,name=TCPAddr" json:"TCPAddr,omitempty"`
+	Zone                 *string  `protobuf:"bytes,4,opt,name=Zone" json:"Zone,omitempty"`
+	Status               *string  `protobuf:"bytes,5,opt,name=Status" json:"Status,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -326,14 +430,31 @@ func (m *NodeInfo) GetTCPAddr() string {
 	return ""
 }
 
+func (m *NodeInfo) GetZone() string {
+	if m != nil && m.Zone != nil {
+		return *m.Zone
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
 type DatabaseInfo struct {
-	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
-	RetentionPolicies      []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
-	ContinuousQueries      []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{}               `json:"-"`
-	XXX_unrecognized       []byte                 `json:"-"`
-	XXX_sizecache          int32                  `json:"-"`
+	Name                      *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	DefaultRetentionPolicy    *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
+	RetentionPolicies         []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
+	ContinuousQueries         []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	DefaultShardGroupDuration *int64                 `protobuf:"varint,5,opt,name=DefaultShardGroupDuration" json:"DefaultShardGroupDuration,omitempty"`
+	MaxSeriesPerDatabase      *int64                 `protobuf:"varint,6,opt,name=MaxSeriesPerDatabase" json:"MaxSeriesPerDatabase,omitempty"`
+	MaxValuesPerTag           *int64                 `protobuf:"varint,7,opt,name=MaxValuesPerTag" json:"MaxValuesPerTag,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{}               `json:"-"`
+	XXX_unrecognized          []byte                 `json:"-"`
+	XXX_sizecache             int32                  `json:"-"`
 }
 
 func (m *DatabaseInfo) Reset()         { *m = DatabaseInfo{} }
@@ -388,6 +509,27 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetDefaultShardGroupDuration() int64 {
+	if m != nil && m.DefaultShardGroupDuration != nil {
+		return *m.DefaultShardGroupDuration
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetMaxSeriesPerDatabase() int64 {
+	if m != nil && m.MaxSeriesPerDatabase != nil {
+		return *m.MaxSeriesPerDatabase
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetMaxValuesPerTag() int64 {
+	if m != nil && m.MaxValuesPerTag != nil {
+		return *m.MaxValuesPerTag
+	}
+	return 0
+}
+
 type RetentionPolicySpec struct {
 	Name                 *string  `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration             *int64   `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
@@ -457,6 +599,11 @@ type RetentionPolicyInfo struct {
 	ReplicaN             *uint32             `protobuf:"varint,4,req,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroups          []*ShardGroupInfo   `protobuf:"bytes,5,rep,name=ShardGroups" json:"ShardGroups,omitempty"`
 	Subscriptions        []*SubscriptionInfo `protobuf:"bytes,6,rep,name=Subscriptions" json:"Subscriptions,omitempty"`
+	WriteAffinityTag     *string             `protobuf:"bytes,7,opt,name=WriteAffinityTag" json:"WriteAffinityTag,omitempty"`
+	PendingShardCount    *uint32             `protobuf:"varint,8,opt,name=PendingShardCount" json:"PendingShardCount,omitempty"`
+	PreCreateCount       *uint32             `protobuf:"varint,9,opt,name=PreCreateCount" json:"PreCreateCount,omitempty"`
+	MeasurementRetention map[string]int64    `protobuf:"bytes,10,rep,name=MeasurementRetention" json:"MeasurementRetention,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ReadOnly             *bool               `protobuf:"varint,11,opt,name=ReadOnly" json:"ReadOnly,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
 	XXX_sizecache        int32               `json:"-"`
@@ -528,6 +675,41 @@ func (m *RetentionPolicyInfo) GetSubscriptions() []*SubscriptionInfo {
 	return nil
 }
 
+func (m *RetentionPolicyInfo) GetWriteAffinityTag() string {
+	if m != nil && m.WriteAffinityTag != nil {
+		return *m.WriteAffinityTag
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetPendingShardCount() uint32 {
+	if m != nil && m.PendingShardCount != nil {
+		return *m.PendingShardCount
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetPreCreateCount() uint32 {
+	if m != nil && m.PreCreateCount != nil {
+		return *m.PreCreateCount
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetMeasurementRetention() map[string]int64 {
+	if m != nil {
+		return m.MeasurementRetention
+	}
+	return nil
+}
+
+func (m *RetentionPolicyInfo) GetReadOnly() bool {
+	if m != nil && m.ReadOnly != nil {
+		return *m.ReadOnly
+	}
+	return false
+}
+
 type ShardGroupInfo struct {
 	ID                   *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	StartTime            *int64       `protobuf:"varint,2,req,name=StartTime" json:"StartTime,omitempty"`
@@ -535,6 +717,7 @@ type ShardGroupInfo struct {
 	DeletedAt            *int64       `protobuf:"varint,4,req,name=DeletedAt" json:"DeletedAt,omitempty"`
 	Shards               []*ShardInfo `protobuf:"bytes,5,rep,name=Shards" json:"Shards,omitempty"`
 	TruncatedAt          *int64       `protobuf:"varint,6,opt,name=TruncatedAt" json:"TruncatedAt,omitempty"`
+	ReplicaN             *uint32      `protobuf:"varint,7,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -606,10 +789,18 @@ func (m *ShardGroupInfo) GetTruncatedAt() int64 {
 	return 0
 }
 
+func (m *ShardGroupInfo) GetReplicaN() uint32 {
+	if m != nil && m.ReplicaN != nil {
+		return *m.ReplicaN
+	}
+	return 0
+}
+
 type ShardInfo struct {
 	ID                   *uint64       `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	OwnerIDs             []uint64      `protobuf:"varint,2,rep,name=OwnerIDs" json:"OwnerIDs,omitempty"` // Deprecated: Do not use.
 	Owners               []*ShardOwner `protobuf:"bytes,3,rep,name=Owners" json:"Owners,omitempty"`
+	LastWrite            *int64        `protobuf:"varint,4,opt,name=LastWrite" json:"LastWrite,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
@@ -661,10 +852,18 @@ func (m *ShardInfo) GetOwners() []*ShardOwner {
 	return nil
 }
 
+func (m *ShardInfo) GetLastWrite() int64 {
+	if m != nil && m.LastWrite != nil {
+		return *m.LastWrite
+	}
+	return 0
+}
+
 type SubscriptionInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode                 *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
 	Destinations         []string `protobuf:"bytes,3,rep,name=Destinations" json:"Destinations,omitempty"`
+	Measurements         []string `protobuf:"bytes,4,rep,name=Measurements" json:"Measurements,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -715,8 +914,16 @@ func (m *SubscriptionInfo) GetDestinations() []string {
 	return nil
 }
 
+func (m *SubscriptionInfo) GetMeasurements() []string {
+	if m != nil {
+		return m.Measurements
+	}
+	return nil
+}
+
 type ShardOwner struct {
 	NodeID               *uint64  `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
+	State                *string  `protobuf:"bytes,2,opt,name=State" json:"State,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -753,9 +960,55 @@ func (m *ShardOwner) GetNodeID() uint64 {
 	return 0
 }
 
+func (m *ShardOwner) GetState() string {
+	if m != nil && m.State != nil {
+		return *m.State
+	}
+	return ""
+}
+
+type ShardOwnerSet struct {
+	Owners               []*ShardOwner `protobuf:"bytes,1,rep,name=Owners" json:"Owners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ShardOwnerSet) Reset()         { *m = ShardOwnerSet{} }
+func (m *ShardOwnerSet) String() string { return proto.CompactTextString(m) }
+func (*ShardOwnerSet) ProtoMessage()    {}
+func (*ShardOwnerSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{9}
+}
+func (m *ShardOwnerSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShardOwnerSet.Unmarshal(m, b)
+}
+func (m *ShardOwnerSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShardOwnerSet.Marshal(b, m, deterministic)
+}
+func (m *ShardOwnerSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShardOwnerSet.Merge(m, src)
+}
+func (m *ShardOwnerSet) XXX_Size() int {
+	return xxx_messageInfo_ShardOwnerSet.Size(m)
+}
+func (m *ShardOwnerSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShardOwnerSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShardOwnerSet proto.InternalMessageInfo
+
+func (m *ShardOwnerSet) GetOwners() []*ShardOwner {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
 type ContinuousQueryInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Query                *string  `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	Enabled              *bool    `protobuf:"varint,3,opt,name=Enabled" json:"Enabled,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -765,7 +1018,7 @@ func (m *ContinuousQueryInfo) Reset()         { *m = ContinuousQueryInfo{} }
 func (m *ContinuousQueryInfo) String() string { return proto.CompactTextString(m) }
 func (*ContinuousQueryInfo) ProtoMessage()    {}
 func (*ContinuousQueryInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{9}
+	return fileDescriptor_59b0956366e72083, []int{10}
 }
 func (m *ContinuousQueryInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ContinuousQueryInfo.Unmarshal(m, b)
@@ -799,11 +1052,19 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+func (m *ContinuousQueryInfo) GetEnabled() bool {
+	if m != nil && m.Enabled != nil {
+		return *m.Enabled
+	}
+	return false
+}
+
 type UserInfo struct {
 	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Hash                 *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
 	Admin                *bool            `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
 	Privileges           []*UserPrivilege `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
+	AdminSince           *int64           `protobuf:"varint,5,opt,name=AdminSince" json:"AdminSince,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -813,7 +1074,7 @@ func (m *UserInfo) Reset()         { *m = UserInfo{} }
 func (m *UserInfo) String() string { return proto.CompactTextString(m) }
 func (*UserInfo) ProtoMessage()    {}
 func (*UserInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{10}
+	return fileDescriptor_59b0956366e72083, []int{11}
 }
 func (m *UserInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserInfo.Unmarshal(m, b)
@@ -861,6 +1122,13 @@ func (m *UserInfo) GetPrivileges() []*UserPrivilege {
 	return nil
 }
 
+func (m *UserInfo) GetAdminSince() int64 {
+	if m != nil && m.AdminSince != nil {
+		return *m.AdminSince
+	}
+	return 0
+}
+
 type UserPrivilege struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Privilege            *int32   `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
@@ -873,7 +1141,7 @@ func (m *UserPrivilege) Reset()         { *m = UserPrivilege{} }
 func (m *UserPrivilege) String() string { return proto.CompactTextString(m) }
 func (*UserPrivilege) ProtoMessage()    {}
 func (*UserPrivilege) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{11}
+	return fileDescriptor_59b0956366e72083, []int{12}
 }
 func (m *UserPrivilege) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserPrivilege.Unmarshal(m, b)
@@ -907,6 +1175,60 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 	return 0
 }
 
+type RoleInfo struct {
+	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Users                []string         `protobuf:"bytes,2,rep,name=Users" json:"Users,omitempty"`
+	Privileges           []*UserPrivilege `protobuf:"bytes,3,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RoleInfo) Reset()         { *m = RoleInfo{} }
+func (m *RoleInfo) String() string { return proto.CompactTextString(m) }
+func (*RoleInfo) ProtoMessage()    {}
+func (*RoleInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{13}
+}
+func (m *RoleInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleInfo.Unmarshal(m, b)
+}
+func (m *RoleInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleInfo.Marshal(b, m, deterministic)
+}
+func (m *RoleInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleInfo.Merge(m, src)
+}
+func (m *RoleInfo) XXX_Size() int {
+	return xxx_messageInfo_RoleInfo.Size(m)
+}
+func (m *RoleInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleInfo proto.InternalMessageInfo
+
+func (m *RoleInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RoleInfo) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *RoleInfo) GetPrivileges() []*UserPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
 type Command struct {
 	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=meta.Command_Type" json:"type,omitempty"`
 	XXX_NoUnkeyedLiteral         struct{}      `json:"-"`
@@ -919,7 +1241,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{12}
+	return fileDescriptor_59b0956366e72083, []int{14}
 }
 
 var extRange_Command = []proto.ExtensionRange{
@@ -969,7 +1291,7 @@ func (m *CreateNodeCommand) Reset()         { *m = CreateNodeCommand{} }
 func (m *CreateNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateNodeCommand) ProtoMessage()    {}
 func (*CreateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{13}
+	return fileDescriptor_59b0956366e72083, []int{15}
 }
 func (m *CreateNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateNodeCommand.Unmarshal(m, b)
@@ -1024,7 +1346,7 @@ func (m *DeleteNodeCommand) Reset()         { *m = DeleteNodeCommand{} }
 func (m *DeleteNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteNodeCommand) ProtoMessage()    {}
 func (*DeleteNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{14}
+	return fileDescriptor_59b0956366e72083, []int{16}
 }
 func (m *DeleteNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteNodeCommand.Unmarshal(m, b)
@@ -1079,7 +1401,7 @@ func (m *CreateDatabaseCommand) Reset()         { *m = CreateDatabaseCommand{} }
 func (m *CreateDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()    {}
 func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{15}
+	return fileDescriptor_59b0956366e72083, []int{17}
 }
 func (m *CreateDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDatabaseCommand.Unmarshal(m, b)
@@ -1133,7 +1455,7 @@ func (m *DropDatabaseCommand) Reset()         { *m = DropDatabaseCommand{} }
 func (m *DropDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*DropDatabaseCommand) ProtoMessage()    {}
 func (*DropDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{16}
+	return fileDescriptor_59b0956366e72083, []int{18}
 }
 func (m *DropDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropDatabaseCommand.Unmarshal(m, b)
@@ -1170,19 +1492,21 @@ var E_DropDatabaseCommand_Command = &proto.ExtensionDesc{
 }
 
 type CreateRetentionPolicyCommand struct {
-	Database             *string              `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	RetentionPolicy      *RetentionPolicyInfo `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
-	Default              *bool                `protobuf:"varint,3,opt,name=Default" json:"Default,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Database                 *string              `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy          *RetentionPolicyInfo `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Default                  *bool                `protobuf:"varint,3,opt,name=Default" json:"Default,omitempty"`
+	CheckReplicaN            *bool                `protobuf:"varint,4,opt,name=CheckReplicaN" json:"CheckReplicaN,omitempty"`
+	StrictShardGroupDuration *bool                `protobuf:"varint,5,opt,name=StrictShardGroupDuration" json:"StrictShardGroupDuration,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{}             `json:"-"`
+	XXX_unrecognized         []byte               `json:"-"`
+	XXX_sizecache            int32                `json:"-"`
 }
 
 func (m *CreateRetentionPolicyCommand) Reset()         { *m = CreateRetentionPolicyCommand{} }
 func (m *CreateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateRetentionPolicyCommand) ProtoMessage()    {}
 func (*CreateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{17}
+	return fileDescriptor_59b0956366e72083, []int{19}
 }
 func (m *CreateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1223,6 +1547,20 @@ func (m *CreateRetentionPolicyCommand) GetDefault() bool {
 	return false
 }
 
+func (m *CreateRetentionPolicyCommand) GetCheckReplicaN() bool {
+	if m != nil && m.CheckReplicaN != nil {
+		return *m.CheckReplicaN
+	}
+	return false
+}
+
+func (m *CreateRetentionPolicyCommand) GetStrictShardGroupDuration() bool {
+	if m != nil && m.StrictShardGroupDuration != nil {
+		return *m.StrictShardGroupDuration
+	}
+	return false
+}
+
 var E_CreateRetentionPolicyCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateRetentionPolicyCommand)(nil),
@@ -1244,7 +1582,7 @@ func (m *DropRetentionPolicyCommand) Reset()         { *m = DropRetentionPolicyC
 func (m *DropRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*DropRetentionPolicyCommand) ProtoMessage()    {}
 func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{18}
+	return fileDescriptor_59b0956366e72083, []int{20}
 }
 func (m *DropRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropRetentionPolicyCommand.Unmarshal(m, b)
@@ -1299,7 +1637,7 @@ func (m *SetDefaultRetentionPolicyCommand) Reset()         { *m = SetDefaultRete
 func (m *SetDefaultRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDefaultRetentionPolicyCommand) ProtoMessage()    {}
 func (*SetDefaultRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{19}
+	return fileDescriptor_59b0956366e72083, []int{21}
 }
 func (m *SetDefaultRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDefaultRetentionPolicyCommand.Unmarshal(m, b)
@@ -1350,6 +1688,12 @@ type UpdateRetentionPolicyCommand struct {
 	ReplicaN             *uint32  `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroupDuration   *int64   `protobuf:"varint,6,opt,name=ShardGroupDuration" json:"ShardGroupDuration,omitempty"`
 	Default              *bool    `protobuf:"varint,7,opt,name=Default" json:"Default,omitempty"`
+	AllowExcessReplicaN  *bool    `protobuf:"varint,8,opt,name=AllowExcessReplicaN" json:"AllowExcessReplicaN,omitempty"`
+	ReadOnly             *bool    `protobuf:"varint,9,opt,name=ReadOnly" json:"ReadOnly,omitempty"`
+	CheckReplicaN        *bool    `protobuf:"varint,10,opt,name=CheckReplicaN" json:"CheckReplicaN,omitempty"`
+	StrictExpiry         *bool    `protobuf:"varint,11,opt,name=StrictExpiry" json:"StrictExpiry,omitempty"`
+	ForceExpiry          *bool    `protobuf:"varint,12,opt,name=ForceExpiry" json:"ForceExpiry,omitempty"`
+	Time                 *int64   `protobuf:"varint,13,opt,name=Time" json:"Time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1359,7 +1703,7 @@ func (m *UpdateRetentionPolicyCommand) Reset()         { *m = UpdateRetentionPol
 func (m *UpdateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateRetentionPolicyCommand) ProtoMessage()    {}
 func (*UpdateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{20}
+	return fileDescriptor_59b0956366e72083, []int{22}
 }
 func (m *UpdateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1428,6 +1772,48 @@ func (m *UpdateRetentionPolicyCommand) GetDefault() bool {
 	return false
 }
 
+func (m *UpdateRetentionPolicyCommand) GetAllowExcessReplicaN() bool {
+	if m != nil && m.AllowExcessReplicaN != nil {
+		return *m.AllowExcessReplicaN
+	}
+	return false
+}
+
+func (m *UpdateRetentionPolicyCommand) GetReadOnly() bool {
+	if m != nil && m.ReadOnly != nil {
+		return *m.ReadOnly
+	}
+	return false
+}
+
+func (m *UpdateRetentionPolicyCommand) GetCheckReplicaN() bool {
+	if m != nil && m.CheckReplicaN != nil {
+		return *m.CheckReplicaN
+	}
+	return false
+}
+
+func (m *UpdateRetentionPolicyCommand) GetStrictExpiry() bool {
+	if m != nil && m.StrictExpiry != nil {
+		return *m.StrictExpiry
+	}
+	return false
+}
+
+func (m *UpdateRetentionPolicyCommand) GetForceExpiry() bool {
+	if m != nil && m.ForceExpiry != nil {
+		return *m.ForceExpiry
+	}
+	return false
+}
+
+func (m *UpdateRetentionPolicyCommand) GetTime() int64 {
+	if m != nil && m.Time != nil {
+		return *m.Time
+	}
+	return 0
+}
+
 var E_UpdateRetentionPolicyCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*UpdateRetentionPolicyCommand)(nil),
@@ -1441,6 +1827,7 @@ type CreateShardGroupCommand struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Policy               *string  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
 	Timestamp            *int64   `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
+	ReplicaN             *uint32  `protobuf:"varint,4,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1450,7 +1837,7 @@ func (m *CreateShardGroupCommand) Reset()         { *m = CreateShardGroupCommand
 func (m *CreateShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateShardGroupCommand) ProtoMessage()    {}
 func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{21}
+	return fileDescriptor_59b0956366e72083, []int{23}
 }
 func (m *CreateShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateShardGroupCommand.Unmarshal(m, b)
@@ -1491,6 +1878,13 @@ func (m *CreateShardGroupCommand) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *CreateShardGroupCommand) GetReplicaN() uint32 {
+	if m != nil && m.ReplicaN != nil {
+		return *m.ReplicaN
+	}
+	return 0
+}
+
 var E_CreateShardGroupCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateShardGroupCommand)(nil),
@@ -1513,7 +1907,7 @@ func (m *DeleteShardGroupCommand) Reset()         { *m = DeleteShardGroupCommand
 func (m *DeleteShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteShardGroupCommand) ProtoMessage()    {}
 func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{22}
+	return fileDescriptor_59b0956366e72083, []int{24}
 }
 func (m *DeleteShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteShardGroupCommand.Unmarshal(m, b)
@@ -1567,6 +1961,7 @@ type CreateContinuousQueryCommand struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
 	Query                *string  `protobuf:"bytes,3,req,name=Query" json:"Query,omitempty"`
+	Validate             *bool    `protobuf:"varint,4,opt,name=Validate" json:"Validate,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1576,7 +1971,7 @@ func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQu
 func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateContinuousQueryCommand) ProtoMessage()    {}
 func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{23}
+	return fileDescriptor_59b0956366e72083, []int{25}
 }
 func (m *CreateContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateContinuousQueryCommand.Unmarshal(m, b)
@@ -1617,6 +2012,13 @@ func (m *CreateContinuousQueryCommand) GetQuery() string {
 	return ""
 }
 
+func (m *CreateContinuousQueryCommand) GetValidate() bool {
+	if m != nil && m.Validate != nil {
+		return *m.Validate
+	}
+	return false
+}
+
 var E_CreateContinuousQueryCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateContinuousQueryCommand)(nil),
@@ -1638,7 +2040,7 @@ func (m *DropContinuousQueryCommand) Reset()         { *m = DropContinuousQueryC
 func (m *DropContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*DropContinuousQueryCommand) ProtoMessage()    {}
 func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{24}
+	return fileDescriptor_59b0956366e72083, []int{26}
 }
 func (m *DropContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropContinuousQueryCommand.Unmarshal(m, b)
@@ -1694,7 +2096,7 @@ func (m *CreateUserCommand) Reset()         { *m = CreateUserCommand{} }
 func (m *CreateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateUserCommand) ProtoMessage()    {}
 func (*CreateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{25}
+	return fileDescriptor_59b0956366e72083, []int{27}
 }
 func (m *CreateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateUserCommand.Unmarshal(m, b)
@@ -1755,7 +2157,7 @@ func (m *DropUserCommand) Reset()         { *m = DropUserCommand{} }
 func (m *DropUserCommand) String() string { return proto.CompactTextString(m) }
 func (*DropUserCommand) ProtoMessage()    {}
 func (*DropUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{26}
+	return fileDescriptor_59b0956366e72083, []int{28}
 }
 func (m *DropUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropUserCommand.Unmarshal(m, b)
@@ -1803,7 +2205,7 @@ func (m *UpdateUserCommand) Reset()         { *m = UpdateUserCommand{} }
 func (m *UpdateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateUserCommand) ProtoMessage()    {}
 func (*UpdateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{27}
+	return fileDescriptor_59b0956366e72083, []int{29}
 }
 func (m *UpdateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateUserCommand.Unmarshal(m, b)
@@ -1859,7 +2261,7 @@ func (m *SetPrivilegeCommand) Reset()         { *m = SetPrivilegeCommand{} }
 func (m *SetPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetPrivilegeCommand) ProtoMessage()    {}
 func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{28}
+	return fileDescriptor_59b0956366e72083, []int{30}
 }
 func (m *SetPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetPrivilegeCommand.Unmarshal(m, b)
@@ -1920,7 +2322,7 @@ func (m *SetDataCommand) Reset()         { *m = SetDataCommand{} }
 func (m *SetDataCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDataCommand) ProtoMessage()    {}
 func (*SetDataCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{29}
+	return fileDescriptor_59b0956366e72083, []int{31}
 }
 func (m *SetDataCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDataCommand.Unmarshal(m, b)
@@ -1968,7 +2370,7 @@ func (m *SetAdminPrivilegeCommand) Reset()         { *m = SetAdminPrivilegeComma
 func (m *SetAdminPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetAdminPrivilegeCommand) ProtoMessage()    {}
 func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{30}
+	return fileDescriptor_59b0956366e72083, []int{32}
 }
 func (m *SetAdminPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetAdminPrivilegeCommand.Unmarshal(m, b)
@@ -2023,7 +2425,7 @@ func (m *UpdateNodeCommand) Reset()         { *m = UpdateNodeCommand{} }
 func (m *UpdateNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateNodeCommand) ProtoMessage()    {}
 func (*UpdateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{31}
+	return fileDescriptor_59b0956366e72083, []int{33}
 }
 func (m *UpdateNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateNodeCommand.Unmarshal(m, b)
@@ -2072,6 +2474,7 @@ type CreateSubscriptionCommand struct {
 	RetentionPolicy      *string  `protobuf:"bytes,3,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
 	Mode                 *string  `protobuf:"bytes,4,req,name=Mode" json:"Mode,omitempty"`
 	Destinations         []string `protobuf:"bytes,5,rep,name=Destinations" json:"Destinations,omitempty"`
+	Measurements         []string `protobuf:"bytes,6,rep,name=Measurements" json:"Measurements,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2081,7 +2484,7 @@ func (m *CreateSubscriptionCommand) Reset()         { *m = CreateSubscriptionCom
 func (m *CreateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateSubscriptionCommand) ProtoMessage()    {}
 func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{32}
+	return fileDescriptor_59b0956366e72083, []int{34}
 }
 func (m *CreateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateSubscriptionCommand.Unmarshal(m, b)
@@ -2136,6 +2539,13 @@ func (m *CreateSubscriptionCommand) GetDestinations() []string {
 	return nil
 }
 
+func (m *CreateSubscriptionCommand) GetMeasurements() []string {
+	if m != nil {
+		return m.Measurements
+	}
+	return nil
+}
+
 var E_CreateSubscriptionCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateSubscriptionCommand)(nil),
@@ -2158,7 +2568,7 @@ func (m *DropSubscriptionCommand) Reset()         { *m = DropSubscriptionCommand
 func (m *DropSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*DropSubscriptionCommand) ProtoMessage()    {}
 func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{33}
+	return fileDescriptor_59b0956366e72083, []int{35}
 }
 func (m *DropSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropSubscriptionCommand.Unmarshal(m, b)
@@ -2220,7 +2630,7 @@ func (m *RemovePeerCommand) Reset()         { *m = RemovePeerCommand{} }
 func (m *RemovePeerCommand) String() string { return proto.CompactTextString(m) }
 func (*RemovePeerCommand) ProtoMessage()    {}
 func (*RemovePeerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{34}
+	return fileDescriptor_59b0956366e72083, []int{36}
 }
 func (m *RemovePeerCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemovePeerCommand.Unmarshal(m, b)
@@ -2267,6 +2677,7 @@ type CreateMetaNodeCommand struct {
 	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
 	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
 	Rand                 *uint64  `protobuf:"varint,3,req,name=Rand" json:"Rand,omitempty"`
+	Zone                 *string  `protobuf:"bytes,4,opt,name=Zone" json:"Zone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2276,7 +2687,7 @@ func (m *CreateMetaNodeCommand) Reset()         { *m = CreateMetaNodeCommand{} }
 func (m *CreateMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateMetaNodeCommand) ProtoMessage()    {}
 func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{35}
+	return fileDescriptor_59b0956366e72083, []int{37}
 }
 func (m *CreateMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMetaNodeCommand.Unmarshal(m, b)
@@ -2317,6 +2728,13 @@ func (m *CreateMetaNodeCommand) GetRand() uint64 {
 	return 0
 }
 
+func (m *CreateMetaNodeCommand) GetZone() string {
+	if m != nil && m.Zone != nil {
+		return *m.Zone
+	}
+	return ""
+}
+
 var E_CreateMetaNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateMetaNodeCommand)(nil),
@@ -2329,6 +2747,7 @@ var E_CreateMetaNodeCommand_Command = &proto.ExtensionDesc{
 type CreateDataNodeCommand struct {
 	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
 	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+	Zone                 *string  `protobuf:"bytes,3,opt,name=Zone" json:"Zone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2338,7 +2757,7 @@ func (m *CreateDataNodeCommand) Reset()         { *m = CreateDataNodeCommand{} }
 func (m *CreateDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDataNodeCommand) ProtoMessage()    {}
 func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{36}
+	return fileDescriptor_59b0956366e72083, []int{38}
 }
 func (m *CreateDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDataNodeCommand.Unmarshal(m, b)
@@ -2372,6 +2791,13 @@ func (m *CreateDataNodeCommand) GetTCPAddr() string {
 	return ""
 }
 
+func (m *CreateDataNodeCommand) GetZone() string {
+	if m != nil && m.Zone != nil {
+		return *m.Zone
+	}
+	return ""
+}
+
 var E_CreateDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateDataNodeCommand)(nil),
@@ -2394,7 +2820,7 @@ func (m *UpdateDataNodeCommand) Reset()         { *m = UpdateDataNodeCommand{} }
 func (m *UpdateDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateDataNodeCommand) ProtoMessage()    {}
 func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{37}
+	return fileDescriptor_59b0956366e72083, []int{39}
 }
 func (m *UpdateDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateDataNodeCommand.Unmarshal(m, b)
@@ -2455,7 +2881,7 @@ func (m *DeleteMetaNodeCommand) Reset()         { *m = DeleteMetaNodeCommand{} }
 func (m *DeleteMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteMetaNodeCommand) ProtoMessage()    {}
 func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{38}
+	return fileDescriptor_59b0956366e72083, []int{40}
 }
 func (m *DeleteMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteMetaNodeCommand.Unmarshal(m, b)
@@ -2502,7 +2928,7 @@ func (m *DeleteDataNodeCommand) Reset()         { *m = DeleteDataNodeCommand{} }
 func (m *DeleteDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteDataNodeCommand) ProtoMessage()    {}
 func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{39}
+	return fileDescriptor_59b0956366e72083, []int{41}
 }
 func (m *DeleteDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteDataNodeCommand.Unmarshal(m, b)
@@ -2542,6 +2968,7 @@ type Response struct {
 	OK                   *bool    `protobuf:"varint,1,req,name=OK" json:"OK,omitempty"`
 	Error                *string  `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
 	Index                *uint64  `protobuf:"varint,3,opt,name=Index" json:"Index,omitempty"`
+	ID                   *uint64  `protobuf:"varint,4,opt,name=ID" json:"ID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2551,7 +2978,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{40}
+	return fileDescriptor_59b0956366e72083, []int{42}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -2592,6 +3019,13 @@ func (m *Response) GetIndex() uint64 {
 	return 0
 }
 
+func (m *Response) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
 // SetMetaNodeCommand is for the initial metanode in a cluster or
 // if the single host restarts and its hostname changes, this will update it
 type SetMetaNodeCommand struct {
@@ -2607,7 +3041,7 @@ func (m *SetMetaNodeCommand) Reset()         { *m = SetMetaNodeCommand{} }
 func (m *SetMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetMetaNodeCommand) ProtoMessage()    {}
 func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{41}
+	return fileDescriptor_59b0956366e72083, []int{43}
 }
 func (m *SetMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetMetaNodeCommand.Unmarshal(m, b)
@@ -2668,7 +3102,7 @@ func (m *DropShardCommand) Reset()         { *m = DropShardCommand{} }
 func (m *DropShardCommand) String() string { return proto.CompactTextString(m) }
 func (*DropShardCommand) ProtoMessage()    {}
 func (*DropShardCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{42}
+	return fileDescriptor_59b0956366e72083, []int{44}
 }
 func (m *DropShardCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropShardCommand.Unmarshal(m, b)
@@ -2715,7 +3149,7 @@ func (m *TruncateShardGroupsCommand) Reset()         { *m = TruncateShardGroupsC
 func (m *TruncateShardGroupsCommand) String() string { return proto.CompactTextString(m) }
 func (*TruncateShardGroupsCommand) ProtoMessage()    {}
 func (*TruncateShardGroupsCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{43}
+	return fileDescriptor_59b0956366e72083, []int{45}
 }
 func (m *TruncateShardGroupsCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TruncateShardGroupsCommand.Unmarshal(m, b)
@@ -2761,7 +3195,7 @@ func (m *PruneShardGroupsCommand) Reset()         { *m = PruneShardGroupsCommand
 func (m *PruneShardGroupsCommand) String() string { return proto.CompactTextString(m) }
 func (*PruneShardGroupsCommand) ProtoMessage()    {}
 func (*PruneShardGroupsCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{44}
+	return fileDescriptor_59b0956366e72083, []int{46}
 }
 func (m *PruneShardGroupsCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PruneShardGroupsCommand.Unmarshal(m, b)
@@ -2802,7 +3236,7 @@ func (m *CopyShardOwnerCommand) Reset()         { *m = CopyShardOwnerCommand{} }
 func (m *CopyShardOwnerCommand) String() string { return proto.CompactTextString(m) }
 func (*CopyShardOwnerCommand) ProtoMessage()    {}
 func (*CopyShardOwnerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{45}
+	return fileDescriptor_59b0956366e72083, []int{47}
 }
 func (m *CopyShardOwnerCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CopyShardOwnerCommand.Unmarshal(m, b)
@@ -2857,7 +3291,7 @@ func (m *RemoveShardOwnerCommand) Reset()         { *m = RemoveShardOwnerCommand
 func (m *RemoveShardOwnerCommand) String() string { return proto.CompactTextString(m) }
 func (*RemoveShardOwnerCommand) ProtoMessage()    {}
 func (*RemoveShardOwnerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{46}
+	return fileDescriptor_59b0956366e72083, []int{48}
 }
 func (m *RemoveShardOwnerCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoveShardOwnerCommand.Unmarshal(m, b)
@@ -2900,6 +3334,1540 @@ var E_RemoveShardOwnerCommand_Command = &proto.ExtensionDesc{
 	Filename:      "internal/meta.proto",
 }
 
+type TouchShardCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Time                 *int64   `protobuf:"varint,2,req,name=Time" json:"Time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TouchShardCommand) Reset()         { *m = TouchShardCommand{} }
+func (m *TouchShardCommand) String() string { return proto.CompactTextString(m) }
+func (*TouchShardCommand) ProtoMessage()    {}
+func (*TouchShardCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{49}
+}
+func (m *TouchShardCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TouchShardCommand.Unmarshal(m, b)
+}
+func (m *TouchShardCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TouchShardCommand.Marshal(b, m, deterministic)
+}
+func (m *TouchShardCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TouchShardCommand.Merge(m, src)
+}
+func (m *TouchShardCommand) XXX_Size() int {
+	return xxx_messageInfo_TouchShardCommand.Size(m)
+}
+func (m *TouchShardCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_TouchShardCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TouchShardCommand proto.InternalMessageInfo
+
+func (m *TouchShardCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *TouchShardCommand) GetTime() int64 {
+	if m != nil && m.Time != nil {
+		return *m.Time
+	}
+	return 0
+}
+
+var E_TouchShardCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*TouchShardCommand)(nil),
+	Field:         135,
+	Name:          "meta.TouchShardCommand.command",
+	Tag:           "bytes,135,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type RebalanceShardsCommand struct {
+	Strategy             *string  `protobuf:"bytes,1,opt,name=Strategy" json:"Strategy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RebalanceShardsCommand) Reset()         { *m = RebalanceShardsCommand{} }
+func (m *RebalanceShardsCommand) String() string { return proto.CompactTextString(m) }
+func (*RebalanceShardsCommand) ProtoMessage()    {}
+func (*RebalanceShardsCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{50}
+}
+func (m *RebalanceShardsCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebalanceShardsCommand.Unmarshal(m, b)
+}
+func (m *RebalanceShardsCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebalanceShardsCommand.Marshal(b, m, deterministic)
+}
+func (m *RebalanceShardsCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebalanceShardsCommand.Merge(m, src)
+}
+func (m *RebalanceShardsCommand) XXX_Size() int {
+	return xxx_messageInfo_RebalanceShardsCommand.Size(m)
+}
+func (m *RebalanceShardsCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebalanceShardsCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebalanceShardsCommand proto.InternalMessageInfo
+
+func (m *RebalanceShardsCommand) GetStrategy() string {
+	if m != nil && m.Strategy != nil {
+		return *m.Strategy
+	}
+	return ""
+}
+
+var E_RebalanceShardsCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RebalanceShardsCommand)(nil),
+	Field:         136,
+	Name:          "meta.RebalanceShardsCommand.command",
+	Tag:           "bytes,136,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetPlacementStrategyCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPlacementStrategyCommand) Reset()         { *m = SetPlacementStrategyCommand{} }
+func (m *SetPlacementStrategyCommand) String() string { return proto.CompactTextString(m) }
+func (*SetPlacementStrategyCommand) ProtoMessage()    {}
+func (*SetPlacementStrategyCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{51}
+}
+func (m *SetPlacementStrategyCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPlacementStrategyCommand.Unmarshal(m, b)
+}
+func (m *SetPlacementStrategyCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPlacementStrategyCommand.Marshal(b, m, deterministic)
+}
+func (m *SetPlacementStrategyCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPlacementStrategyCommand.Merge(m, src)
+}
+func (m *SetPlacementStrategyCommand) XXX_Size() int {
+	return xxx_messageInfo_SetPlacementStrategyCommand.Size(m)
+}
+func (m *SetPlacementStrategyCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPlacementStrategyCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPlacementStrategyCommand proto.InternalMessageInfo
+
+func (m *SetPlacementStrategyCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_SetPlacementStrategyCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetPlacementStrategyCommand)(nil),
+	Field:         137,
+	Name:          "meta.SetPlacementStrategyCommand.command",
+	Tag:           "bytes,137,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type CreateRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRoleCommand) Reset()         { *m = CreateRoleCommand{} }
+func (m *CreateRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateRoleCommand) ProtoMessage()    {}
+func (*CreateRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{52}
+}
+func (m *CreateRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRoleCommand.Unmarshal(m, b)
+}
+func (m *CreateRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRoleCommand.Merge(m, src)
+}
+func (m *CreateRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateRoleCommand.Size(m)
+}
+func (m *CreateRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRoleCommand proto.InternalMessageInfo
+
+func (m *CreateRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_CreateRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateRoleCommand)(nil),
+	Field:         138,
+	Name:          "meta.CreateRoleCommand.command",
+	Tag:           "bytes,138,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type DropRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropRoleCommand) Reset()         { *m = DropRoleCommand{} }
+func (m *DropRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*DropRoleCommand) ProtoMessage()    {}
+func (*DropRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{53}
+}
+func (m *DropRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropRoleCommand.Unmarshal(m, b)
+}
+func (m *DropRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *DropRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropRoleCommand.Merge(m, src)
+}
+func (m *DropRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_DropRoleCommand.Size(m)
+}
+func (m *DropRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropRoleCommand proto.InternalMessageInfo
+
+func (m *DropRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropRoleCommand)(nil),
+	Field:         139,
+	Name:          "meta.DropRoleCommand.command",
+	Tag:           "bytes,139,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type AddUserToRoleCommand struct {
+	Role                 *string  `protobuf:"bytes,1,req,name=Role" json:"Role,omitempty"`
+	Username             *string  `protobuf:"bytes,2,req,name=Username" json:"Username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddUserToRoleCommand) Reset()         { *m = AddUserToRoleCommand{} }
+func (m *AddUserToRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*AddUserToRoleCommand) ProtoMessage()    {}
+func (*AddUserToRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{54}
+}
+func (m *AddUserToRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddUserToRoleCommand.Unmarshal(m, b)
+}
+func (m *AddUserToRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddUserToRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *AddUserToRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddUserToRoleCommand.Merge(m, src)
+}
+func (m *AddUserToRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_AddUserToRoleCommand.Size(m)
+}
+func (m *AddUserToRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddUserToRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddUserToRoleCommand proto.InternalMessageInfo
+
+func (m *AddUserToRoleCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *AddUserToRoleCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
+	}
+	return ""
+}
+
+var E_AddUserToRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*AddUserToRoleCommand)(nil),
+	Field:         140,
+	Name:          "meta.AddUserToRoleCommand.command",
+	Tag:           "bytes,140,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type RemoveUserFromRoleCommand struct {
+	Role                 *string  `protobuf:"bytes,1,req,name=Role" json:"Role,omitempty"`
+	Username             *string  `protobuf:"bytes,2,req,name=Username" json:"Username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveUserFromRoleCommand) Reset()         { *m = RemoveUserFromRoleCommand{} }
+func (m *RemoveUserFromRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*RemoveUserFromRoleCommand) ProtoMessage()    {}
+func (*RemoveUserFromRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{55}
+}
+func (m *RemoveUserFromRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveUserFromRoleCommand.Unmarshal(m, b)
+}
+func (m *RemoveUserFromRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveUserFromRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *RemoveUserFromRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveUserFromRoleCommand.Merge(m, src)
+}
+func (m *RemoveUserFromRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_RemoveUserFromRoleCommand.Size(m)
+}
+func (m *RemoveUserFromRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveUserFromRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveUserFromRoleCommand proto.InternalMessageInfo
+
+func (m *RemoveUserFromRoleCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *RemoveUserFromRoleCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
+	}
+	return ""
+}
+
+var E_RemoveUserFromRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RemoveUserFromRoleCommand)(nil),
+	Field:         141,
+	Name:          "meta.RemoveUserFromRoleCommand.command",
+	Tag:           "bytes,141,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetRolePrivilegeCommand struct {
+	Role                 *string  `protobuf:"bytes,1,req,name=Role" json:"Role,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Privilege            *int32   `protobuf:"varint,3,req,name=Privilege" json:"Privilege,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRolePrivilegeCommand) Reset()         { *m = SetRolePrivilegeCommand{} }
+func (m *SetRolePrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetRolePrivilegeCommand) ProtoMessage()    {}
+func (*SetRolePrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{56}
+}
+func (m *SetRolePrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Unmarshal(m, b)
+}
+func (m *SetRolePrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Marshal(b, m, deterministic)
+}
+func (m *SetRolePrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRolePrivilegeCommand.Merge(m, src)
+}
+func (m *SetRolePrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Size(m)
+}
+func (m *SetRolePrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRolePrivilegeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRolePrivilegeCommand proto.InternalMessageInfo
+
+func (m *SetRolePrivilegeCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *SetRolePrivilegeCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetRolePrivilegeCommand) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+var E_SetRolePrivilegeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetRolePrivilegeCommand)(nil),
+	Field:         142,
+	Name:          "meta.SetRolePrivilegeCommand.command",
+	Tag:           "bytes,142,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type TouchShardsCommand struct {
+	IDs                  []uint64 `protobuf:"varint,1,rep,name=IDs" json:"IDs,omitempty"`
+	Time                 *int64   `protobuf:"varint,2,req,name=Time" json:"Time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TouchShardsCommand) Reset()         { *m = TouchShardsCommand{} }
+func (m *TouchShardsCommand) String() string { return proto.CompactTextString(m) }
+func (*TouchShardsCommand) ProtoMessage()    {}
+func (*TouchShardsCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{57}
+}
+func (m *TouchShardsCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TouchShardsCommand.Unmarshal(m, b)
+}
+func (m *TouchShardsCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TouchShardsCommand.Marshal(b, m, deterministic)
+}
+func (m *TouchShardsCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TouchShardsCommand.Merge(m, src)
+}
+func (m *TouchShardsCommand) XXX_Size() int {
+	return xxx_messageInfo_TouchShardsCommand.Size(m)
+}
+func (m *TouchShardsCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_TouchShardsCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TouchShardsCommand proto.InternalMessageInfo
+
+func (m *TouchShardsCommand) GetIDs() []uint64 {
+	if m != nil {
+		return m.IDs
+	}
+	return nil
+}
+
+func (m *TouchShardsCommand) GetTime() int64 {
+	if m != nil && m.Time != nil {
+		return *m.Time
+	}
+	return 0
+}
+
+var E_TouchShardsCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*TouchShardsCommand)(nil),
+	Field:         143,
+	Name:          "meta.TouchShardsCommand.command",
+	Tag:           "bytes,143,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type UpdateSubscriptionCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy      *string  `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Name                 *string  `protobuf:"bytes,3,req,name=Name" json:"Name,omitempty"`
+	Mode                 *string  `protobuf:"bytes,4,req,name=Mode" json:"Mode,omitempty"`
+	Destinations         []string `protobuf:"bytes,5,rep,name=Destinations" json:"Destinations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateSubscriptionCommand) Reset()         { *m = UpdateSubscriptionCommand{} }
+func (m *UpdateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateSubscriptionCommand) ProtoMessage()    {}
+func (*UpdateSubscriptionCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{58}
+}
+func (m *UpdateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateSubscriptionCommand.Unmarshal(m, b)
+}
+func (m *UpdateSubscriptionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateSubscriptionCommand.Marshal(b, m, deterministic)
+}
+func (m *UpdateSubscriptionCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateSubscriptionCommand.Merge(m, src)
+}
+func (m *UpdateSubscriptionCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateSubscriptionCommand.Size(m)
+}
+func (m *UpdateSubscriptionCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateSubscriptionCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateSubscriptionCommand proto.InternalMessageInfo
+
+func (m *UpdateSubscriptionCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *UpdateSubscriptionCommand) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *UpdateSubscriptionCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *UpdateSubscriptionCommand) GetMode() string {
+	if m != nil && m.Mode != nil {
+		return *m.Mode
+	}
+	return ""
+}
+
+func (m *UpdateSubscriptionCommand) GetDestinations() []string {
+	if m != nil {
+		return m.Destinations
+	}
+	return nil
+}
+
+var E_UpdateSubscriptionCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UpdateSubscriptionCommand)(nil),
+	Field:         144,
+	Name:          "meta.UpdateSubscriptionCommand.command",
+	Tag:           "bytes,144,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type RenameDatabaseCommand struct {
+	OldName              *string  `protobuf:"bytes,1,req,name=OldName" json:"OldName,omitempty"`
+	NewName              *string  `protobuf:"bytes,2,req,name=NewName" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameDatabaseCommand) Reset()         { *m = RenameDatabaseCommand{} }
+func (m *RenameDatabaseCommand) String() string { return proto.CompactTextString(m) }
+func (*RenameDatabaseCommand) ProtoMessage()    {}
+func (*RenameDatabaseCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{59}
+}
+func (m *RenameDatabaseCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameDatabaseCommand.Unmarshal(m, b)
+}
+func (m *RenameDatabaseCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameDatabaseCommand.Marshal(b, m, deterministic)
+}
+func (m *RenameDatabaseCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameDatabaseCommand.Merge(m, src)
+}
+func (m *RenameDatabaseCommand) XXX_Size() int {
+	return xxx_messageInfo_RenameDatabaseCommand.Size(m)
+}
+func (m *RenameDatabaseCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameDatabaseCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameDatabaseCommand proto.InternalMessageInfo
+
+func (m *RenameDatabaseCommand) GetOldName() string {
+	if m != nil && m.OldName != nil {
+		return *m.OldName
+	}
+	return ""
+}
+
+func (m *RenameDatabaseCommand) GetNewName() string {
+	if m != nil && m.NewName != nil {
+		return *m.NewName
+	}
+	return ""
+}
+
+var E_RenameDatabaseCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RenameDatabaseCommand)(nil),
+	Field:         145,
+	Name:          "meta.RenameDatabaseCommand.command",
+	Tag:           "bytes,145,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type RenameRetentionPolicyCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	OldName              *string  `protobuf:"bytes,2,req,name=OldName" json:"OldName,omitempty"`
+	NewName              *string  `protobuf:"bytes,3,req,name=NewName" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameRetentionPolicyCommand) Reset()         { *m = RenameRetentionPolicyCommand{} }
+func (m *RenameRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
+func (*RenameRetentionPolicyCommand) ProtoMessage()    {}
+func (*RenameRetentionPolicyCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{60}
+}
+func (m *RenameRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameRetentionPolicyCommand.Unmarshal(m, b)
+}
+func (m *RenameRetentionPolicyCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameRetentionPolicyCommand.Marshal(b, m, deterministic)
+}
+func (m *RenameRetentionPolicyCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameRetentionPolicyCommand.Merge(m, src)
+}
+func (m *RenameRetentionPolicyCommand) XXX_Size() int {
+	return xxx_messageInfo_RenameRetentionPolicyCommand.Size(m)
+}
+func (m *RenameRetentionPolicyCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameRetentionPolicyCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameRetentionPolicyCommand proto.InternalMessageInfo
+
+func (m *RenameRetentionPolicyCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *RenameRetentionPolicyCommand) GetOldName() string {
+	if m != nil && m.OldName != nil {
+		return *m.OldName
+	}
+	return ""
+}
+
+func (m *RenameRetentionPolicyCommand) GetNewName() string {
+	if m != nil && m.NewName != nil {
+		return *m.NewName
+	}
+	return ""
+}
+
+var E_RenameRetentionPolicyCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RenameRetentionPolicyCommand)(nil),
+	Field:         146,
+	Name:          "meta.RenameRetentionPolicyCommand.command",
+	Tag:           "bytes,146,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDefaultShardGroupDurationCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Duration             *int64   `protobuf:"varint,2,req,name=Duration" json:"Duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDefaultShardGroupDurationCommand) Reset()         { *m = SetDefaultShardGroupDurationCommand{} }
+func (m *SetDefaultShardGroupDurationCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDefaultShardGroupDurationCommand) ProtoMessage()    {}
+func (*SetDefaultShardGroupDurationCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{61}
+}
+func (m *SetDefaultShardGroupDurationCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDefaultShardGroupDurationCommand.Unmarshal(m, b)
+}
+func (m *SetDefaultShardGroupDurationCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDefaultShardGroupDurationCommand.Marshal(b, m, deterministic)
+}
+func (m *SetDefaultShardGroupDurationCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDefaultShardGroupDurationCommand.Merge(m, src)
+}
+func (m *SetDefaultShardGroupDurationCommand) XXX_Size() int {
+	return xxx_messageInfo_SetDefaultShardGroupDurationCommand.Size(m)
+}
+func (m *SetDefaultShardGroupDurationCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDefaultShardGroupDurationCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDefaultShardGroupDurationCommand proto.InternalMessageInfo
+
+func (m *SetDefaultShardGroupDurationCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetDefaultShardGroupDurationCommand) GetDuration() int64 {
+	if m != nil && m.Duration != nil {
+		return *m.Duration
+	}
+	return 0
+}
+
+var E_SetDefaultShardGroupDurationCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDefaultShardGroupDurationCommand)(nil),
+	Field:         147,
+	Name:          "meta.SetDefaultShardGroupDurationCommand.command",
+	Tag:           "bytes,147,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDatabaseLimitsCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	MaxSeries            *int64   `protobuf:"varint,2,req,name=MaxSeries" json:"MaxSeries,omitempty"`
+	MaxValues            *int64   `protobuf:"varint,3,req,name=MaxValues" json:"MaxValues,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDatabaseLimitsCommand) Reset()         { *m = SetDatabaseLimitsCommand{} }
+func (m *SetDatabaseLimitsCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDatabaseLimitsCommand) ProtoMessage()    {}
+func (*SetDatabaseLimitsCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{62}
+}
+func (m *SetDatabaseLimitsCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDatabaseLimitsCommand.Unmarshal(m, b)
+}
+func (m *SetDatabaseLimitsCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDatabaseLimitsCommand.Marshal(b, m, deterministic)
+}
+func (m *SetDatabaseLimitsCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDatabaseLimitsCommand.Merge(m, src)
+}
+func (m *SetDatabaseLimitsCommand) XXX_Size() int {
+	return xxx_messageInfo_SetDatabaseLimitsCommand.Size(m)
+}
+func (m *SetDatabaseLimitsCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDatabaseLimitsCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDatabaseLimitsCommand proto.InternalMessageInfo
+
+func (m *SetDatabaseLimitsCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SetDatabaseLimitsCommand) GetMaxSeries() int64 {
+	if m != nil && m.MaxSeries != nil {
+		return *m.MaxSeries
+	}
+	return 0
+}
+
+func (m *SetDatabaseLimitsCommand) GetMaxValues() int64 {
+	if m != nil && m.MaxValues != nil {
+		return *m.MaxValues
+	}
+	return 0
+}
+
+var E_SetDatabaseLimitsCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDatabaseLimitsCommand)(nil),
+	Field:         148,
+	Name:          "meta.SetDatabaseLimitsCommand.command",
+	Tag:           "bytes,148,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetShardOwnerStateCommand struct {
+	ShardID              *uint64  `protobuf:"varint,1,req,name=ShardID" json:"ShardID,omitempty"`
+	NodeID               *uint64  `protobuf:"varint,2,req,name=NodeID" json:"NodeID,omitempty"`
+	State                *string  `protobuf:"bytes,3,req,name=State" json:"State,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetShardOwnerStateCommand) Reset()         { *m = SetShardOwnerStateCommand{} }
+func (m *SetShardOwnerStateCommand) String() string { return proto.CompactTextString(m) }
+func (*SetShardOwnerStateCommand) ProtoMessage()    {}
+func (*SetShardOwnerStateCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{63}
+}
+func (m *SetShardOwnerStateCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetShardOwnerStateCommand.Unmarshal(m, b)
+}
+func (m *SetShardOwnerStateCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetShardOwnerStateCommand.Marshal(b, m, deterministic)
+}
+func (m *SetShardOwnerStateCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetShardOwnerStateCommand.Merge(m, src)
+}
+func (m *SetShardOwnerStateCommand) XXX_Size() int {
+	return xxx_messageInfo_SetShardOwnerStateCommand.Size(m)
+}
+func (m *SetShardOwnerStateCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetShardOwnerStateCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetShardOwnerStateCommand proto.InternalMessageInfo
+
+func (m *SetShardOwnerStateCommand) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *SetShardOwnerStateCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *SetShardOwnerStateCommand) GetState() string {
+	if m != nil && m.State != nil {
+		return *m.State
+	}
+	return ""
+}
+
+var E_SetShardOwnerStateCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetShardOwnerStateCommand)(nil),
+	Field:         149,
+	Name:          "meta.SetShardOwnerStateCommand.command",
+	Tag:           "bytes,149,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetContinuousQueryEnabledCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	Enabled              *bool    `protobuf:"varint,3,req,name=Enabled" json:"Enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetContinuousQueryEnabledCommand) Reset()         { *m = SetContinuousQueryEnabledCommand{} }
+func (m *SetContinuousQueryEnabledCommand) String() string { return proto.CompactTextString(m) }
+func (*SetContinuousQueryEnabledCommand) ProtoMessage()    {}
+func (*SetContinuousQueryEnabledCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{64}
+}
+func (m *SetContinuousQueryEnabledCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetContinuousQueryEnabledCommand.Unmarshal(m, b)
+}
+func (m *SetContinuousQueryEnabledCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetContinuousQueryEnabledCommand.Marshal(b, m, deterministic)
+}
+func (m *SetContinuousQueryEnabledCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetContinuousQueryEnabledCommand.Merge(m, src)
+}
+func (m *SetContinuousQueryEnabledCommand) XXX_Size() int {
+	return xxx_messageInfo_SetContinuousQueryEnabledCommand.Size(m)
+}
+func (m *SetContinuousQueryEnabledCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetContinuousQueryEnabledCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetContinuousQueryEnabledCommand proto.InternalMessageInfo
+
+func (m *SetContinuousQueryEnabledCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryEnabledCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryEnabledCommand) GetEnabled() bool {
+	if m != nil && m.Enabled != nil {
+		return *m.Enabled
+	}
+	return false
+}
+
+var E_SetContinuousQueryEnabledCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetContinuousQueryEnabledCommand)(nil),
+	Field:         150,
+	Name:          "meta.SetContinuousQueryEnabledCommand.command",
+	Tag:           "bytes,150,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type UpdateContinuousQueryCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	Query                *string  `protobuf:"bytes,3,req,name=Query" json:"Query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateContinuousQueryCommand) Reset()         { *m = UpdateContinuousQueryCommand{} }
+func (m *UpdateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateContinuousQueryCommand) ProtoMessage()    {}
+func (*UpdateContinuousQueryCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{65}
+}
+func (m *UpdateContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateContinuousQueryCommand.Unmarshal(m, b)
+}
+func (m *UpdateContinuousQueryCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateContinuousQueryCommand.Marshal(b, m, deterministic)
+}
+func (m *UpdateContinuousQueryCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateContinuousQueryCommand.Merge(m, src)
+}
+func (m *UpdateContinuousQueryCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateContinuousQueryCommand.Size(m)
+}
+func (m *UpdateContinuousQueryCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateContinuousQueryCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateContinuousQueryCommand proto.InternalMessageInfo
+
+func (m *UpdateContinuousQueryCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *UpdateContinuousQueryCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *UpdateContinuousQueryCommand) GetQuery() string {
+	if m != nil && m.Query != nil {
+		return *m.Query
+	}
+	return ""
+}
+
+var E_UpdateContinuousQueryCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UpdateContinuousQueryCommand)(nil),
+	Field:         151,
+	Name:          "meta.UpdateContinuousQueryCommand.command",
+	Tag:           "bytes,151,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type PromoteToDataNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	HTTPAddr             *string  `protobuf:"bytes,2,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
+	TCPAddr              *string  `protobuf:"bytes,3,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromoteToDataNodeCommand) Reset()         { *m = PromoteToDataNodeCommand{} }
+func (m *PromoteToDataNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*PromoteToDataNodeCommand) ProtoMessage()    {}
+func (*PromoteToDataNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{66}
+}
+func (m *PromoteToDataNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteToDataNodeCommand.Unmarshal(m, b)
+}
+func (m *PromoteToDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteToDataNodeCommand.Marshal(b, m, deterministic)
+}
+func (m *PromoteToDataNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteToDataNodeCommand.Merge(m, src)
+}
+func (m *PromoteToDataNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_PromoteToDataNodeCommand.Size(m)
+}
+func (m *PromoteToDataNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteToDataNodeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteToDataNodeCommand proto.InternalMessageInfo
+
+func (m *PromoteToDataNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *PromoteToDataNodeCommand) GetHTTPAddr() string {
+	if m != nil && m.HTTPAddr != nil {
+		return *m.HTTPAddr
+	}
+	return ""
+}
+
+func (m *PromoteToDataNodeCommand) GetTCPAddr() string {
+	if m != nil && m.TCPAddr != nil {
+		return *m.TCPAddr
+	}
+	return ""
+}
+
+var E_PromoteToDataNodeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*PromoteToDataNodeCommand)(nil),
+	Field:         152,
+	Name:          "meta.PromoteToDataNodeCommand.command",
+	Tag:           "bytes,152,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetPrivilegesCommand struct {
+	Username             *string          `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Privileges           []*UserPrivilege `protobuf:"bytes,2,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SetPrivilegesCommand) Reset()         { *m = SetPrivilegesCommand{} }
+func (m *SetPrivilegesCommand) String() string { return proto.CompactTextString(m) }
+func (*SetPrivilegesCommand) ProtoMessage()    {}
+func (*SetPrivilegesCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{67}
+}
+func (m *SetPrivilegesCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPrivilegesCommand.Unmarshal(m, b)
+}
+func (m *SetPrivilegesCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPrivilegesCommand.Marshal(b, m, deterministic)
+}
+func (m *SetPrivilegesCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPrivilegesCommand.Merge(m, src)
+}
+func (m *SetPrivilegesCommand) XXX_Size() int {
+	return xxx_messageInfo_SetPrivilegesCommand.Size(m)
+}
+func (m *SetPrivilegesCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPrivilegesCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPrivilegesCommand proto.InternalMessageInfo
+
+func (m *SetPrivilegesCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
+	}
+	return ""
+}
+
+func (m *SetPrivilegesCommand) GetPrivileges() []*UserPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+var E_SetPrivilegesCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetPrivilegesCommand)(nil),
+	Field:         153,
+	Name:          "meta.SetPrivilegesCommand.command",
+	Tag:           "bytes,153,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDataNodeStatusCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Status               *string  `protobuf:"bytes,2,req,name=Status" json:"Status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDataNodeStatusCommand) Reset()         { *m = SetDataNodeStatusCommand{} }
+func (m *SetDataNodeStatusCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDataNodeStatusCommand) ProtoMessage()    {}
+func (*SetDataNodeStatusCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{68}
+}
+func (m *SetDataNodeStatusCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDataNodeStatusCommand.Unmarshal(m, b)
+}
+func (m *SetDataNodeStatusCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDataNodeStatusCommand.Marshal(b, m, deterministic)
+}
+func (m *SetDataNodeStatusCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDataNodeStatusCommand.Merge(m, src)
+}
+func (m *SetDataNodeStatusCommand) XXX_Size() int {
+	return xxx_messageInfo_SetDataNodeStatusCommand.Size(m)
+}
+func (m *SetDataNodeStatusCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDataNodeStatusCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDataNodeStatusCommand proto.InternalMessageInfo
+
+func (m *SetDataNodeStatusCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *SetDataNodeStatusCommand) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+var E_SetDataNodeStatusCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDataNodeStatusCommand)(nil),
+	Field:         154,
+	Name:          "meta.SetDataNodeStatusCommand.command",
+	Tag:           "bytes,154,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type CreateShardGroupWithOwnersCommand struct {
+	Database             *string          `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy               *string          `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Timestamp            *int64           `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
+	Owners               []*ShardOwnerSet `protobuf:"bytes,4,rep,name=Owners" json:"Owners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CreateShardGroupWithOwnersCommand) Reset()         { *m = CreateShardGroupWithOwnersCommand{} }
+func (m *CreateShardGroupWithOwnersCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateShardGroupWithOwnersCommand) ProtoMessage()    {}
+func (*CreateShardGroupWithOwnersCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{69}
+}
+func (m *CreateShardGroupWithOwnersCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateShardGroupWithOwnersCommand.Unmarshal(m, b)
+}
+func (m *CreateShardGroupWithOwnersCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateShardGroupWithOwnersCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateShardGroupWithOwnersCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateShardGroupWithOwnersCommand.Merge(m, src)
+}
+func (m *CreateShardGroupWithOwnersCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateShardGroupWithOwnersCommand.Size(m)
+}
+func (m *CreateShardGroupWithOwnersCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateShardGroupWithOwnersCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateShardGroupWithOwnersCommand proto.InternalMessageInfo
+
+func (m *CreateShardGroupWithOwnersCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *CreateShardGroupWithOwnersCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *CreateShardGroupWithOwnersCommand) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *CreateShardGroupWithOwnersCommand) GetOwners() []*ShardOwnerSet {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
+var E_CreateShardGroupWithOwnersCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateShardGroupWithOwnersCommand)(nil),
+	Field:         155,
+	Name:          "meta.CreateShardGroupWithOwnersCommand.command",
+	Tag:           "bytes,155,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetWriteAffinityTagCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy               *string  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Tag                  *string  `protobuf:"bytes,3,req,name=Tag" json:"Tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetWriteAffinityTagCommand) Reset()         { *m = SetWriteAffinityTagCommand{} }
+func (m *SetWriteAffinityTagCommand) String() string { return proto.CompactTextString(m) }
+func (*SetWriteAffinityTagCommand) ProtoMessage()    {}
+func (*SetWriteAffinityTagCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{70}
+}
+func (m *SetWriteAffinityTagCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetWriteAffinityTagCommand.Unmarshal(m, b)
+}
+func (m *SetWriteAffinityTagCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetWriteAffinityTagCommand.Marshal(b, m, deterministic)
+}
+func (m *SetWriteAffinityTagCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetWriteAffinityTagCommand.Merge(m, src)
+}
+func (m *SetWriteAffinityTagCommand) XXX_Size() int {
+	return xxx_messageInfo_SetWriteAffinityTagCommand.Size(m)
+}
+func (m *SetWriteAffinityTagCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetWriteAffinityTagCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetWriteAffinityTagCommand proto.InternalMessageInfo
+
+func (m *SetWriteAffinityTagCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetWriteAffinityTagCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *SetWriteAffinityTagCommand) GetTag() string {
+	if m != nil && m.Tag != nil {
+		return *m.Tag
+	}
+	return ""
+}
+
+var E_SetWriteAffinityTagCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetWriteAffinityTagCommand)(nil),
+	Field:         156,
+	Name:          "meta.SetWriteAffinityTagCommand.command",
+	Tag:           "bytes,156,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetPendingShardCountCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy               *string  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Count                *uint32  `protobuf:"varint,3,req,name=Count" json:"Count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPendingShardCountCommand) Reset()         { *m = SetPendingShardCountCommand{} }
+func (m *SetPendingShardCountCommand) String() string { return proto.CompactTextString(m) }
+func (*SetPendingShardCountCommand) ProtoMessage()    {}
+func (*SetPendingShardCountCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{71}
+}
+func (m *SetPendingShardCountCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPendingShardCountCommand.Unmarshal(m, b)
+}
+func (m *SetPendingShardCountCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPendingShardCountCommand.Marshal(b, m, deterministic)
+}
+func (m *SetPendingShardCountCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPendingShardCountCommand.Merge(m, src)
+}
+func (m *SetPendingShardCountCommand) XXX_Size() int {
+	return xxx_messageInfo_SetPendingShardCountCommand.Size(m)
+}
+func (m *SetPendingShardCountCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPendingShardCountCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPendingShardCountCommand proto.InternalMessageInfo
+
+func (m *SetPendingShardCountCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetPendingShardCountCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *SetPendingShardCountCommand) GetCount() uint32 {
+	if m != nil && m.Count != nil {
+		return *m.Count
+	}
+	return 0
+}
+
+var E_SetPendingShardCountCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetPendingShardCountCommand)(nil),
+	Field:         157,
+	Name:          "meta.SetPendingShardCountCommand.command",
+	Tag:           "bytes,157,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetPreCreateCountCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy               *string  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Count                *uint32  `protobuf:"varint,3,req,name=Count" json:"Count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPreCreateCountCommand) Reset()         { *m = SetPreCreateCountCommand{} }
+func (m *SetPreCreateCountCommand) String() string { return proto.CompactTextString(m) }
+func (*SetPreCreateCountCommand) ProtoMessage()    {}
+func (*SetPreCreateCountCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{72}
+}
+func (m *SetPreCreateCountCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPreCreateCountCommand.Unmarshal(m, b)
+}
+func (m *SetPreCreateCountCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPreCreateCountCommand.Marshal(b, m, deterministic)
+}
+func (m *SetPreCreateCountCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPreCreateCountCommand.Merge(m, src)
+}
+func (m *SetPreCreateCountCommand) XXX_Size() int {
+	return xxx_messageInfo_SetPreCreateCountCommand.Size(m)
+}
+func (m *SetPreCreateCountCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPreCreateCountCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPreCreateCountCommand proto.InternalMessageInfo
+
+func (m *SetPreCreateCountCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetPreCreateCountCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *SetPreCreateCountCommand) GetCount() uint32 {
+	if m != nil && m.Count != nil {
+		return *m.Count
+	}
+	return 0
+}
+
+var E_SetPreCreateCountCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetPreCreateCountCommand)(nil),
+	Field:         158,
+	Name:          "meta.SetPreCreateCountCommand.command",
+	Tag:           "bytes,158,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type PreCreateShardGroupsCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy               *string  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Timestamp            *int64   `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreCreateShardGroupsCommand) Reset()         { *m = PreCreateShardGroupsCommand{} }
+func (m *PreCreateShardGroupsCommand) String() string { return proto.CompactTextString(m) }
+func (*PreCreateShardGroupsCommand) ProtoMessage()    {}
+func (*PreCreateShardGroupsCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{73}
+}
+func (m *PreCreateShardGroupsCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreCreateShardGroupsCommand.Unmarshal(m, b)
+}
+func (m *PreCreateShardGroupsCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreCreateShardGroupsCommand.Marshal(b, m, deterministic)
+}
+func (m *PreCreateShardGroupsCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreCreateShardGroupsCommand.Merge(m, src)
+}
+func (m *PreCreateShardGroupsCommand) XXX_Size() int {
+	return xxx_messageInfo_PreCreateShardGroupsCommand.Size(m)
+}
+func (m *PreCreateShardGroupsCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreCreateShardGroupsCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreCreateShardGroupsCommand proto.InternalMessageInfo
+
+func (m *PreCreateShardGroupsCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *PreCreateShardGroupsCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *PreCreateShardGroupsCommand) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+var E_PreCreateShardGroupsCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*PreCreateShardGroupsCommand)(nil),
+	Field:         159,
+	Name:          "meta.PreCreateShardGroupsCommand.command",
+	Tag:           "bytes,159,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type MoveShardCommand struct {
+	ShardID              *uint64  `protobuf:"varint,1,req,name=ShardID" json:"ShardID,omitempty"`
+	FromNodeID           *uint64  `protobuf:"varint,2,req,name=FromNodeID" json:"FromNodeID,omitempty"`
+	ToNodeID             *uint64  `protobuf:"varint,3,req,name=ToNodeID" json:"ToNodeID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveShardCommand) Reset()         { *m = MoveShardCommand{} }
+func (m *MoveShardCommand) String() string { return proto.CompactTextString(m) }
+func (*MoveShardCommand) ProtoMessage()    {}
+func (*MoveShardCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{74}
+}
+func (m *MoveShardCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveShardCommand.Unmarshal(m, b)
+}
+func (m *MoveShardCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveShardCommand.Marshal(b, m, deterministic)
+}
+func (m *MoveShardCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveShardCommand.Merge(m, src)
+}
+func (m *MoveShardCommand) XXX_Size() int {
+	return xxx_messageInfo_MoveShardCommand.Size(m)
+}
+func (m *MoveShardCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveShardCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveShardCommand proto.InternalMessageInfo
+
+func (m *MoveShardCommand) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *MoveShardCommand) GetFromNodeID() uint64 {
+	if m != nil && m.FromNodeID != nil {
+		return *m.FromNodeID
+	}
+	return 0
+}
+
+func (m *MoveShardCommand) GetToNodeID() uint64 {
+	if m != nil && m.ToNodeID != nil {
+		return *m.ToNodeID
+	}
+	return 0
+}
+
+var E_MoveShardCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*MoveShardCommand)(nil),
+	Field:         160,
+	Name:          "meta.MoveShardCommand.command",
+	Tag:           "bytes,160,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
 func init() {
 	proto.RegisterEnum("meta.Command_Type", Command_Type_name, Command_Type_value)
 	proto.RegisterType((*Data)(nil), "meta.Data")
@@ -2907,13 +4875,16 @@ func init() {
 	proto.RegisterType((*DatabaseInfo)(nil), "meta.DatabaseInfo")
 	proto.RegisterType((*RetentionPolicySpec)(nil), "meta.RetentionPolicySpec")
 	proto.RegisterType((*RetentionPolicyInfo)(nil), "meta.RetentionPolicyInfo")
+	proto.RegisterMapType((map[string]int64)(nil), "meta.RetentionPolicyInfo.MeasurementRetentionEntry")
 	proto.RegisterType((*ShardGroupInfo)(nil), "meta.ShardGroupInfo")
 	proto.RegisterType((*ShardInfo)(nil), "meta.ShardInfo")
 	proto.RegisterType((*SubscriptionInfo)(nil), "meta.SubscriptionInfo")
 	proto.RegisterType((*ShardOwner)(nil), "meta.ShardOwner")
+	proto.RegisterType((*ShardOwnerSet)(nil), "meta.ShardOwnerSet")
 	proto.RegisterType((*ContinuousQueryInfo)(nil), "meta.ContinuousQueryInfo")
 	proto.RegisterType((*UserInfo)(nil), "meta.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "meta.UserPrivilege")
+	proto.RegisterType((*RoleInfo)(nil), "meta.RoleInfo")
 	proto.RegisterType((*Command)(nil), "meta.Command")
 	proto.RegisterExtension(E_CreateNodeCommand_Command)
 	proto.RegisterType((*CreateNodeCommand)(nil), "meta.CreateNodeCommand")
@@ -2982,132 +4953,271 @@ func init() {
 	proto.RegisterType((*CopyShardOwnerCommand)(nil), "meta.CopyShardOwnerCommand")
 	proto.RegisterExtension(E_RemoveShardOwnerCommand_Command)
 	proto.RegisterType((*RemoveShardOwnerCommand)(nil), "meta.RemoveShardOwnerCommand")
+	proto.RegisterExtension(E_TouchShardCommand_Command)
+	proto.RegisterType((*TouchShardCommand)(nil), "meta.TouchShardCommand")
+	proto.RegisterExtension(E_RebalanceShardsCommand_Command)
+	proto.RegisterType((*RebalanceShardsCommand)(nil), "meta.RebalanceShardsCommand")
+	proto.RegisterExtension(E_SetPlacementStrategyCommand_Command)
+	proto.RegisterType((*SetPlacementStrategyCommand)(nil), "meta.SetPlacementStrategyCommand")
+	proto.RegisterExtension(E_CreateRoleCommand_Command)
+	proto.RegisterType((*CreateRoleCommand)(nil), "meta.CreateRoleCommand")
+	proto.RegisterExtension(E_DropRoleCommand_Command)
+	proto.RegisterType((*DropRoleCommand)(nil), "meta.DropRoleCommand")
+	proto.RegisterExtension(E_AddUserToRoleCommand_Command)
+	proto.RegisterType((*AddUserToRoleCommand)(nil), "meta.AddUserToRoleCommand")
+	proto.RegisterExtension(E_RemoveUserFromRoleCommand_Command)
+	proto.RegisterType((*RemoveUserFromRoleCommand)(nil), "meta.RemoveUserFromRoleCommand")
+	proto.RegisterExtension(E_SetRolePrivilegeCommand_Command)
+	proto.RegisterType((*SetRolePrivilegeCommand)(nil), "meta.SetRolePrivilegeCommand")
+	proto.RegisterExtension(E_TouchShardsCommand_Command)
+	proto.RegisterType((*TouchShardsCommand)(nil), "meta.TouchShardsCommand")
+	proto.RegisterExtension(E_UpdateSubscriptionCommand_Command)
+	proto.RegisterType((*UpdateSubscriptionCommand)(nil), "meta.UpdateSubscriptionCommand")
+	proto.RegisterExtension(E_RenameDatabaseCommand_Command)
+	proto.RegisterType((*RenameDatabaseCommand)(nil), "meta.RenameDatabaseCommand")
+	proto.RegisterExtension(E_RenameRetentionPolicyCommand_Command)
+	proto.RegisterType((*RenameRetentionPolicyCommand)(nil), "meta.RenameRetentionPolicyCommand")
+	proto.RegisterExtension(E_SetDefaultShardGroupDurationCommand_Command)
+	proto.RegisterType((*SetDefaultShardGroupDurationCommand)(nil), "meta.SetDefaultShardGroupDurationCommand")
+	proto.RegisterExtension(E_SetDatabaseLimitsCommand_Command)
+	proto.RegisterType((*SetDatabaseLimitsCommand)(nil), "meta.SetDatabaseLimitsCommand")
+	proto.RegisterExtension(E_SetShardOwnerStateCommand_Command)
+	proto.RegisterType((*SetShardOwnerStateCommand)(nil), "meta.SetShardOwnerStateCommand")
+	proto.RegisterExtension(E_SetContinuousQueryEnabledCommand_Command)
+	proto.RegisterType((*SetContinuousQueryEnabledCommand)(nil), "meta.SetContinuousQueryEnabledCommand")
+	proto.RegisterExtension(E_UpdateContinuousQueryCommand_Command)
+	proto.RegisterType((*UpdateContinuousQueryCommand)(nil), "meta.UpdateContinuousQueryCommand")
+	proto.RegisterExtension(E_PromoteToDataNodeCommand_Command)
+	proto.RegisterType((*PromoteToDataNodeCommand)(nil), "meta.PromoteToDataNodeCommand")
+	proto.RegisterExtension(E_SetPrivilegesCommand_Command)
+	proto.RegisterType((*SetPrivilegesCommand)(nil), "meta.SetPrivilegesCommand")
+	proto.RegisterExtension(E_SetDataNodeStatusCommand_Command)
+	proto.RegisterType((*SetDataNodeStatusCommand)(nil), "meta.SetDataNodeStatusCommand")
+	proto.RegisterExtension(E_CreateShardGroupWithOwnersCommand_Command)
+	proto.RegisterType((*CreateShardGroupWithOwnersCommand)(nil), "meta.CreateShardGroupWithOwnersCommand")
+	proto.RegisterExtension(E_SetWriteAffinityTagCommand_Command)
+	proto.RegisterType((*SetWriteAffinityTagCommand)(nil), "meta.SetWriteAffinityTagCommand")
+	proto.RegisterExtension(E_SetPendingShardCountCommand_Command)
+	proto.RegisterType((*SetPendingShardCountCommand)(nil), "meta.SetPendingShardCountCommand")
+	proto.RegisterExtension(E_SetPreCreateCountCommand_Command)
+	proto.RegisterType((*SetPreCreateCountCommand)(nil), "meta.SetPreCreateCountCommand")
+	proto.RegisterExtension(E_PreCreateShardGroupsCommand_Command)
+	proto.RegisterType((*PreCreateShardGroupsCommand)(nil), "meta.PreCreateShardGroupsCommand")
+	proto.RegisterExtension(E_MoveShardCommand_Command)
+	proto.RegisterType((*MoveShardCommand)(nil), "meta.MoveShardCommand")
 }
 
 func init() { proto.RegisterFile("internal/meta.proto", fileDescriptor_59b0956366e72083) }
 
 var fileDescriptor_59b0956366e72083 = []byte{
-	// 1940 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4b, 0x8f, 0x24, 0x39,
-	0x11, 0x96, 0xb3, 0xde, 0xd1, 0xd3, 0x8f, 0x71, 0xbf, 0xb2, 0x67, 0x7a, 0x7a, 0x8b, 0xd4, 0x68,
-	0x29, 0x21, 0xd4, 0xa0, 0x42, 0xda, 0x13, 0xaf, 0xd9, 0xae, 0x99, 0xe9, 0x62, 0xd4, 0x0f, 0xb2,
-	0x7a, 0xaf, 0x48, 0x39, 0x5d, 0x9e, 0x9d, 0x82, 0xaa, 0xcc, 0x22, 0x33, 0x6b, 0x66, 0x9a, 0xa5,
-	0xa1, 0x79, 0x2d, 0x12, 0xa7, 0x45, 0x08, 0x71, 0x67, 0x0f, 0x1c, 0x11, 0x42, 0x02, 0x21, 0x4e,
-	0xfc, 0x01, 0x7e, 0x06, 0x27, 0xee, 0x5c, 0x91, 0xed, 0x74, 0xda, 0x99, 0xb6, 0xb3, 0xbb, 0x97,
-	0xe1, 0x96, 0x8e, 0x08, 0x3b, 0xbe, 0x08, 0x87, 0xc3, 0x11, 0x4e, 0x58, 0x9f, 0x84, 0x29, 0x89,
-	0xc3, 0x60, 0xfa, 0xa5, 0x19, 0x49, 0x83, 0xfd, 0x79, 0x1c, 0xa5, 0x11, 0xae, 0xd3, 0x6f, 0xef,
-	0x93, 0x1a, 0xd4, 0x07, 0x41, 0x1a, 0x60, 0x0c, 0xf5, 0x33, 0x12, 0xcf, 0x5c, 0xd4, 0x75, 0x7a,
-	0x75, 0x9f, 0x7d, 0xe3, 0x0d, 0x68, 0x0c, 0xc3, 0x31, 0x79, 0xe3, 0x3a, 0x8c, 0xc8, 0x07, 0x78,
-	0x17, 0x3a, 0x07, 0xd3, 0x45, 0x92, 0x92, 0x78, 0x38, 0x70, 0x6b, 0x8c, 0x23, 0x09, 0xf8, 0x21,
-	0x34, 0x8e, 0xa3, 0x31, 0x49, 0xdc, 0x7a, 0xb7, 0xd6, 0x5b, 0xea, 0xaf, 0xec, 0x33, 0x95, 0x94,
-	0x34, 0x0c, 0x5f, 0x44, 0x3e, 0x67, 0xe2, 0x2f, 0x43, 0x87, 0x6a, 0x7d, 0x1e, 0x24, 0x24, 0x71,
-	0x1b, 0x4c, 0x12, 0x73, 0x49, 0x41, 0x66, 0xd2, 0x52, 0x88, 0xae, 0xfb, 0x41, 0x42, 0xe2, 0xc4,
-	0x6d, 0xaa, 0xeb, 0x52, 0x12, 0x5f, 0x97, 0x31, 0x29, 0xb6, 0xa3, 0xe0, 0x0d, 0xd3, 0x36, 0x70,
-	0x5b, 0x1c, 0x5b, 0x4e, 0xc0, 0x3d, 0x58, 0x3d, 0x0a, 0xde, 0x8c, 0x5e, 0x06, 0xf1, 0xf8, 0x69,
-	0x1c, 0x2d, 0xe6, 0xc3, 0x81, 0xdb, 0x66, 0x32, 0x65, 0x32, 0xde, 0x03, 0x10, 0xa4, 0xe1, 0xc0,
-	0xed, 0x30, 0x21, 0x85, 0x82, 0xbf, 0xc8, 0xf1, 0x73, 0x4b, 0xc1, 0x68, 0xa9, 0x14, 0xa0, 0xd2,
-	0x47, 0x44, 0x48, 0x2f, 0x99, 0xa5, 0x73, 0x01, 0xef, 0x10, 0xda, 0x82, 0x8c, 0x57, 0xc0, 0x19,
-	0x0e, 0xb2, 0x3d, 0x71, 0x86, 0x03, 0xba, 0x4b, 0x8f, 0xc6, 0xe3, 0xd8, 0x75, 0xba, 0xa8, 0xd7,
-	0xf1, 0xd9, 0x37, 0x76, 0xa1, 0x75, 0x76, 0x70, 0xca, 0xc8, 0x35, 0x46, 0x16, 0x43, 0xef, 0xdf,
-	0x08, 0xee, 0xa8, 0xfe, 0xa4, 0xd3, 0x8f, 0x83, 0x19, 0x61, 0x0b, 0x76, 0x7c, 0xf6, 0x8d, 0xdf,
-	0x83, 0xad, 0x01, 0x79, 0x11, 0x2c, 0xa6, 0xa9, 0x4f, 0x52, 0x12, 0xa6, 0x93, 0x28, 0x3c, 0x8d,
-	0xa6, 0x93, 0xf3, 0x0b, 0xb6, 0xeb, 0x1d, 0xdf, 0xc2, 0xc5, 0x4f, 0xe1, 0x6e, 0x91, 0x34, 0x21,
-	0x89, 0x5b, 0x63, 0xc6, 0xed, 0x70, 0xe3, 0x4a, 0x33, 0x98, 0x9d, 0xfa, 0x1c, 0xba, 0xd0, 0x41,
-	0x14, 0xa6, 0x93, 0x70, 0x11, 0x2d, 0x92, 0x6f, 0x2f, 0x48, 0x3c, 0xc9, 0xa3, 0x27, 0x5b, 0xa8,
-	0xc8, 0xce, 0x16, 0xd2, 0xe6, 0x78, 0xbf, 0x46, 0xb0, 0x5e, 0xd2, 0x39, 0x9a, 0x93, 0x73, 0xc5,
-	0x6a, 0x94, 0x5b, 0x7d, 0x0f, 0xda, 0x83, 0x45, 0x1c, 0x50, 0x49, 0xe6, 0xcc, 0x9a, 0x9f, 0x8f,
-	0xf1, 0x3e, 0x60, 0x19, 0x0c, 0xb9, 0x54, 0x8d, 0x49, 0x19, 0x38, 0x74, 0x2d, 0x9f, 0xcc, 0xa7,
-	0x93, 0xf3, 0xe0, 0xd8, 0xad, 0x77, 0x51, 0x6f, 0xd9, 0xcf, 0xc7, 0xde, 0x2f, 0x1d, 0x0d, 0x93,
-	0x75, 0x27, 0x8a, 0x98, 0x9c, 0x1b, 0x61, 0x72, 0x6e, 0x84, 0xc9, 0x51, 0x31, 0xe1, 0xf7, 0x60,
-	0x49, 0xce, 0x10, 0xc7, 0x6f, 0x83, 0xbb, 0x5a, 0x39, 0x05, 0xd4, 0xcb, 0xaa, 0x20, 0xfe, 0x2a,
-	0x2c, 0x8f, 0x16, 0xcf, 0x93, 0xf3, 0x78, 0x32, 0xa7, 0x3a, 0xc4, 0x51, 0xdc, 0xca, 0x66, 0x2a,
-	0x2c, 0x36, 0xb7, 0x28, 0xec, 0xfd, 0x03, 0xc1, 0x4a, 0x71, 0x75, 0x2d, 0xba, 0x77, 0xa1, 0x33,
-	0x4a, 0x83, 0x38, 0x3d, 0x9b, 0xcc, 0x48, 0xe6, 0x01, 0x49, 0xa0, 0x71, 0xfe, 0x38, 0x1c, 0x33,
-	0x1e, 0xb7, 0x5b, 0x0c, 0xe9, 0xbc, 0x01, 0x99, 0x92, 0x94, 0x8c, 0x1f, 0xa5, 0xcc, 0xda, 0x9a,
-	0x2f, 0x09, 0xf8, 0xf3, 0xd0, 0x64, 0x7a, 0x85, 0xa5, 0xab, 0x8a, 0xa5, 0x0c, 0x68, 0xc6, 0xc6,
-	0x5d, 0x58, 0x3a, 0x8b, 0x17, 0xe1, 0x79, 0xc0, 0x17, 0x6a, 0xb2, 0x0d, 0x57, 0x49, 0x1e, 0x81,
-	0x4e, 0x3e, 0x4d, 0x43, 0xbf, 0x07, 0xed, 0x93, 0xd7, 0x21, 0x4d, 0x82, 0x89, 0xeb, 0x74, 0x6b,
-	0xbd, 0xfa, 0xfb, 0x8e, 0x8b, 0xfc, 0x9c, 0x86, 0x7b, 0xd0, 0x64, 0xdf, 0xe2, 0x94, 0xac, 0x29,
-	0x38, 0x18, 0xc3, 0xcf, 0xf8, 0xde, 0x77, 0x60, 0xad, 0xec, 0x4d, 0x63, 0xc0, 0x60, 0xa8, 0x1f,
-	0x45, 0x63, 0x92, 0x1d, 0x54, 0xf6, 0x8d, 0x3d, 0xb8, 0x33, 0x20, 0x49, 0x3a, 0x09, 0x03, 0xbe,
-	0x47, 0x54, 0x57, 0xc7, 0x2f, 0xd0, 0xbc, 0x87, 0x00, 0x52, 0x2b, 0xde, 0x82, 0x66, 0x96, 0x30,
-	0xb9, 0x2d, 0xd9, 0xc8, 0xfb, 0x06, 0xac, 0x1b, 0x0e, 0x9e, 0x11, 0xc8, 0x06, 0x34, 0x98, 0x40,
-	0x86, 0x84, 0x0f, 0xbc, 0x4b, 0x68, 0x8b, 0xfc, 0x6c, 0x83, 0x7f, 0x18, 0x24, 0x2f, 0x05, 0x7c,
-	0xfa, 0x4d, 0x57, 0x7a, 0x34, 0x9e, 0x4d, 0x78, 0x68, 0xb7, 0x7d, 0x3e, 0xc0, 0x5f, 0x01, 0x38,
-	0x8d, 0x27, 0xaf, 0x26, 0x53, 0xf2, 0x61, 0x9e, 0x1b, 0xd6, 0xe5, 0x0d, 0x90, 0xf3, 0x7c, 0x45,
-	0xcc, 0x1b, 0xc2, 0x72, 0x81, 0xc9, 0xce, 0x57, 0x96, 0x0d, 0x33, 0x1c, 0xf9, 0x98, 0x86, 0x50,
-	0x2e, 0xc8, 0x00, 0x35, 0x7c, 0x49, 0xf0, 0xfe, 0xd9, 0x82, 0xd6, 0x41, 0x34, 0x9b, 0x05, 0xe1,
-	0x18, 0xbf, 0x0b, 0xf5, 0xf4, 0x62, 0xce, 0x57, 0x58, 0x11, 0xb7, 0x56, 0xc6, 0xdc, 0x3f, 0xbb,
-	0x98, 0x13, 0x9f, 0xf1, 0xbd, 0x4f, 0x5a, 0x50, 0xa7, 0x43, 0xbc, 0x09, 0x77, 0x0f, 0x62, 0x12,
-	0xa4, 0x84, 0xfa, 0x35, 0x13, 0x5c, 0x43, 0x94, 0xcc, 0x63, 0x54, 0x25, 0x3b, 0x78, 0x07, 0x36,
-	0xb9, 0xb4, 0x80, 0x26, 0x58, 0x35, 0xbc, 0x0d, 0xeb, 0x83, 0x38, 0x9a, 0x97, 0x19, 0x75, 0xdc,
-	0x85, 0x5d, 0x3e, 0xa7, 0x94, 0x69, 0x84, 0x44, 0x03, 0xef, 0xc1, 0x3d, 0x3a, 0xd5, 0xc2, 0x6f,
-	0xe2, 0x87, 0xd0, 0x1d, 0x91, 0xd4, 0x9c, 0xe9, 0x85, 0x54, 0x8b, 0xea, 0xf9, 0x60, 0x3e, 0xb6,
-	0xeb, 0x69, 0xe3, 0xfb, 0xb0, 0xcd, 0x91, 0xc8, 0x93, 0x2e, 0x98, 0x1d, 0xca, 0xe4, 0x16, 0xeb,
-	0x4c, 0x90, 0x36, 0x94, 0x62, 0x4e, 0x48, 0x2c, 0x09, 0x1b, 0x2c, 0xfc, 0x3b, 0xd2, 0xcf, 0x74,
-	0xd7, 0x05, 0x79, 0x19, 0xaf, 0xc3, 0x2a, 0x9d, 0xa6, 0x12, 0x57, 0xa8, 0x2c, 0xb7, 0x44, 0x25,
-	0xaf, 0x52, 0x0f, 0x8f, 0x48, 0x9a, 0xef, 0xbb, 0x60, 0xac, 0x61, 0x0c, 0x2b, 0xd4, 0x3f, 0x41,
-	0x1a, 0x08, 0xda, 0x5d, 0xbc, 0x0b, 0xee, 0x88, 0xa4, 0x2c, 0x40, 0xb5, 0x19, 0x58, 0x6a, 0x50,
-	0xb7, 0x77, 0x1d, 0x3f, 0x80, 0x9d, 0xcc, 0x41, 0xca, 0x01, 0x17, 0xec, 0x4d, 0xe6, 0xa2, 0x38,
-	0x9a, 0x9b, 0x98, 0x5b, 0x74, 0x49, 0x9f, 0xcc, 0xa2, 0x57, 0xe4, 0x94, 0x48, 0xd0, 0xdb, 0x32,
-	0x62, 0x44, 0x09, 0x21, 0x58, 0x6e, 0x31, 0x98, 0x54, 0xd6, 0x0e, 0x65, 0x71, 0x7c, 0x65, 0xd6,
-	0x3d, 0xca, 0xe2, 0xfb, 0x54, 0x5e, 0xf0, 0xbe, 0x64, 0x95, 0x67, 0xed, 0xe2, 0x2d, 0xc0, 0x23,
-	0x92, 0x96, 0xa7, 0x3c, 0xc0, 0x1b, 0xb0, 0xc6, 0x4c, 0xa2, 0x7b, 0x2e, 0xa8, 0x7b, 0x74, 0x33,
-	0x45, 0x62, 0x55, 0xae, 0x18, 0xc1, 0x7f, 0x87, 0x3a, 0xe2, 0x34, 0x5e, 0x84, 0x26, 0x66, 0x97,
-	0x99, 0x15, 0xcd, 0x2f, 0x64, 0x0e, 0x13, 0xac, 0xcf, 0xd1, 0x79, 0xdc, 0x47, 0x3a, 0xd3, 0xfb,
-	0x42, 0xbb, 0x3d, 0x5e, 0xbb, 0xba, 0xba, 0xba, 0x72, 0xbc, 0x4b, 0xc3, 0x99, 0x64, 0xf9, 0x28,
-	0x4a, 0x52, 0x91, 0xa3, 0xe8, 0x37, 0xa5, 0xf9, 0x41, 0x38, 0xce, 0x2a, 0x60, 0xf6, 0xdd, 0xff,
-	0x26, 0xb4, 0xce, 0xb3, 0x29, 0xcb, 0x85, 0xe3, 0xef, 0x92, 0x2e, 0xea, 0x2d, 0xf5, 0xb7, 0x33,
-	0x62, 0x59, 0x81, 0x2f, 0xa6, 0x79, 0x1f, 0x19, 0xce, 0xbe, 0x76, 0x9f, 0x6c, 0x40, 0xe3, 0x49,
-	0x14, 0x9f, 0xf3, 0x74, 0xd4, 0xf6, 0xf9, 0xa0, 0x42, 0xf9, 0x0b, 0x55, 0xb9, 0xb6, 0xbc, 0x54,
-	0xfe, 0x17, 0x64, 0x49, 0x31, 0xc6, 0x24, 0x7d, 0x00, 0xab, 0x7a, 0x5d, 0x88, 0xaa, 0x8b, 0xbc,
-	0xf2, 0x8c, 0xfe, 0xc0, 0x0a, 0xfa, 0x43, 0xb6, 0xd6, 0x7d, 0xd5, 0x63, 0x25, 0x54, 0x12, 0xf8,
-	0xcc, 0x98, 0xff, 0x4c, 0xa8, 0xfb, 0xef, 0x5b, 0x15, 0xbe, 0x54, 0xc1, 0x1b, 0x96, 0x93, 0xea,
-	0xfe, 0x85, 0xaa, 0xd3, 0x6a, 0xe5, 0x7d, 0x62, 0x74, 0x9b, 0x73, 0x3b, 0xb7, 0xd1, 0x8a, 0x27,
-	0x4b, 0xc9, 0xac, 0xfa, 0x6c, 0xfb, 0x62, 0xd8, 0x7f, 0x66, 0xb5, 0x6f, 0xc2, 0xec, 0xf3, 0x54,
-	0x87, 0x9a, 0xe1, 0x4b, 0x43, 0x7f, 0x87, 0xaa, 0x6e, 0x87, 0x4a, 0x33, 0x85, 0xef, 0x1d, 0xc5,
-	0xf7, 0x43, 0x2b, 0xb6, 0xef, 0x32, 0x6c, 0x5d, 0xe9, 0xfb, 0xeb, 0x90, 0x7d, 0x8a, 0xae, 0xbf,
-	0x97, 0x6e, 0x8d, 0xef, 0xc4, 0x8a, 0xef, 0x7b, 0x0c, 0xdf, 0xbb, 0x59, 0x5d, 0x76, 0x8d, 0x5e,
-	0x89, 0xf2, 0xaf, 0x4e, 0xf5, 0xbd, 0x78, 0x5b, 0x84, 0x74, 0xdf, 0x8f, 0xc9, 0x6b, 0x46, 0xce,
-	0x3a, 0xba, 0x6c, 0x58, 0x68, 0x11, 0xea, 0xa5, 0xb6, 0x45, 0x2d, 0xf9, 0x1b, 0xc5, 0x36, 0xc4,
-	0xd2, 0x3e, 0x34, 0xad, 0x2d, 0x8d, 0x12, 0x79, 0xad, 0x9b, 0x46, 0xde, 0x54, 0x8d, 0xbc, 0x2a,
-	0x7f, 0x48, 0xcf, 0xfd, 0x19, 0x59, 0xeb, 0x85, 0x4a, 0xa7, 0x6d, 0x41, 0xb3, 0xd0, 0xa3, 0x66,
-	0x23, 0x5a, 0xc5, 0xd1, 0x86, 0x20, 0x49, 0x83, 0xd9, 0x3c, 0x6b, 0x12, 0x24, 0xa1, 0xff, 0xc4,
-	0x0a, 0x7d, 0xc6, 0xa0, 0x3f, 0x50, 0x0f, 0x8d, 0x06, 0x48, 0xa2, 0xfe, 0x1b, 0xb2, 0x16, 0x32,
-	0x9f, 0x09, 0xb5, 0x07, 0x77, 0x0a, 0x6f, 0x12, 0xfc, 0x4d, 0xa5, 0x40, 0xab, 0xc0, 0x1e, 0xaa,
-	0xd8, 0x2d, 0xb0, 0x24, 0xf6, 0x3f, 0xa1, 0xea, 0x3a, 0xeb, 0xd6, 0xb1, 0x9a, 0x97, 0xfe, 0x35,
-	0xa5, 0xf4, 0xaf, 0x88, 0x92, 0x48, 0xcf, 0x4f, 0x66, 0x24, 0x7a, 0x7e, 0x7a, 0x3b, 0x88, 0x2b,
-	0xf2, 0xd3, 0xbc, 0x9c, 0x9f, 0xae, 0x43, 0xf6, 0x1b, 0x64, 0xa8, 0x39, 0xff, 0xb7, 0x5e, 0xa7,
-	0xe2, 0x82, 0xff, 0xbe, 0x5e, 0x5d, 0x28, 0x6a, 0x25, 0x2a, 0xa2, 0x55, 0xbc, 0xc6, 0x3b, 0xf2,
-	0xeb, 0x56, 0x45, 0x31, 0x53, 0xb4, 0x29, 0xfd, 0x60, 0x54, 0x73, 0x69, 0xa8, 0xa1, 0x6f, 0x6a,
-	0x7b, 0x85, 0x95, 0x89, 0x6a, 0xa5, 0xa6, 0x40, 0xaa, 0xff, 0x23, 0x32, 0x16, 0xeb, 0x34, 0x1c,
-	0xa8, 0x7c, 0x28, 0x51, 0xe4, 0xe3, 0x42, 0xa8, 0x38, 0x55, 0x1d, 0x60, 0xad, 0xd4, 0x01, 0x56,
-	0x14, 0x14, 0xa9, 0x5a, 0x50, 0x18, 0x00, 0x49, 0xc4, 0x51, 0xb9, 0x89, 0xc0, 0x7b, 0xfc, 0xf1,
-	0x95, 0xe1, 0x5c, 0xea, 0x83, 0x7c, 0x01, 0xf5, 0x19, 0xbd, 0xff, 0x35, 0xab, 0xd6, 0x05, 0xd3,
-	0xba, 0x21, 0xaf, 0x2a, 0xb9, 0xaa, 0x54, 0xf8, 0x5b, 0x64, 0x6f, 0x51, 0x2a, 0xfd, 0x94, 0x47,
-	0xa6, 0xa3, 0x46, 0xe6, 0x53, 0x2b, 0x9a, 0x57, 0x0c, 0xcd, 0x5e, 0x8e, 0xc6, 0xa8, 0x51, 0xe2,
-	0xba, 0x30, 0xf4, 0x46, 0xa6, 0xa7, 0x4e, 0x56, 0x8d, 0x3b, 0xb2, 0x1a, 0xaf, 0x88, 0x9a, 0xd7,
-	0x7a, 0xd4, 0x18, 0x8b, 0xdf, 0xff, 0xa0, 0x8a, 0x06, 0xcc, 0xfa, 0x2a, 0x67, 0x8b, 0x99, 0x9e,
-	0x5e, 0xe5, 0xf1, 0x34, 0xa8, 0x95, 0x72, 0xe2, 0xa9, 0xa6, 0x5e, 0xf1, 0x54, 0xd3, 0xd0, 0x9f,
-	0x6a, 0xfa, 0x87, 0x56, 0x8b, 0x2f, 0x98, 0xc5, 0xef, 0x14, 0xee, 0x2c, 0xdd, 0x24, 0x69, 0xf9,
-	0xdf, 0x91, 0xb5, 0xb7, 0xfc, 0xff, 0xd9, 0x5d, 0x71, 0x6f, 0xfd, 0xa0, 0x70, 0x6f, 0x99, 0x81,
-	0x15, 0x42, 0x46, 0xeb, 0x7d, 0xf3, 0x90, 0x41, 0xda, 0xeb, 0xb8, 0x23, 0x5e, 0xc7, 0x2b, 0x42,
-	0xe6, 0x23, 0x35, 0x64, 0xb4, 0xc5, 0xa5, 0xea, 0x3f, 0x20, 0x4b, 0x83, 0x4d, 0x5d, 0x74, 0x78,
-	0x76, 0xc6, 0x9f, 0xde, 0xb3, 0x23, 0x24, 0xc6, 0xea, 0xab, 0x3c, 0x87, 0x23, 0x86, 0x79, 0x4b,
-	0x59, 0x53, 0x5a, 0x4a, 0x7b, 0x83, 0xf4, 0x43, 0xbd, 0x41, 0x2a, 0xc1, 0x28, 0x5c, 0x47, 0xe6,
-	0x7e, 0xff, 0xb3, 0x21, 0xad, 0x40, 0x75, 0x69, 0x6e, 0xdb, 0x8c, 0xa8, 0x3e, 0x45, 0x96, 0xa7,
-	0x06, 0xed, 0xc8, 0xab, 0x28, 0x1d, 0x3b, 0xca, 0xda, 0x4d, 0x51, 0xfe, 0x48, 0x45, 0x69, 0x84,
-	0xa0, 0x36, 0x97, 0xe6, 0x47, 0x8f, 0x32, 0xc8, 0x0a, 0x75, 0x3f, 0x56, 0xd5, 0x19, 0x17, 0x93,
-	0xea, 0x42, 0xcb, 0x43, 0x8a, 0xa6, 0xee, 0xb1, 0x55, 0xdd, 0x15, 0xd2, 0xf5, 0x59, 0xcd, 0x7b,
-	0x42, 0x9b, 0x83, 0x64, 0x1e, 0x85, 0x09, 0xa1, 0x2a, 0x4e, 0x9e, 0x31, 0x15, 0x6d, 0xdf, 0x39,
-	0x79, 0x46, 0xb3, 0xfd, 0xe3, 0x38, 0x8e, 0xc4, 0x5f, 0x25, 0x3e, 0x90, 0x3f, 0xff, 0x6a, 0xec,
-	0x7c, 0xf1, 0x81, 0xf7, 0x7b, 0x64, 0x7a, 0xe6, 0x79, 0x8b, 0x27, 0xc1, 0x7e, 0xd1, 0xfe, 0x84,
-	0xdb, 0xeb, 0xe6, 0xb7, 0x8c, 0xd5, 0xb9, 0x63, 0xfd, 0xc9, 0x49, 0xf3, 0xab, 0x3d, 0x2f, 0xfc,
-	0x94, 0xeb, 0xd9, 0x52, 0x32, 0x93, 0xb2, 0x90, 0xd4, 0xf2, 0x31, 0xaa, 0x7a, 0xc3, 0x2a, 0xf6,
-	0x22, 0xa8, 0xdc, 0x8b, 0x7c, 0xcb, 0xaa, 0xfe, 0x67, 0x48, 0xad, 0x42, 0xed, 0x0a, 0x24, 0x90,
-	0xe7, 0xd6, 0xb7, 0xb2, 0x8a, 0x2b, 0xfb, 0xe7, 0x48, 0xcd, 0xbf, 0x96, 0xf9, 0x05, 0x63, 0xcd,
-	0x6f, 0x6e, 0xda, 0x21, 0x96, 0xbf, 0x13, 0x1c, 0xf5, 0x77, 0x42, 0x45, 0x20, 0xff, 0xa2, 0x10,
-	0xc8, 0x46, 0x2d, 0x12, 0xc8, 0xaf, 0x90, 0xf5, 0x85, 0xef, 0xc6, 0x50, 0xec, 0x5e, 0xf9, 0xb8,
-	0xe0, 0x15, 0x8b, 0x9e, 0x1c, 0xcc, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x7d, 0xe7, 0x5c, 0xdb,
-	0x59, 0x1f, 0x00, 0x00,
+	// 3343 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0xdb, 0x93, 0x1c, 0x37,
+	0xd5, 0x2f, 0xf5, 0xcc, 0xee, 0xce, 0x68, 0x3d, 0xeb, 0xb5, 0x76, 0xbd, 0x6e, 0xaf, 0x9d, 0xcd,
+	0x64, 0xe2, 0xd8, 0x9b, 0xcb, 0xe7, 0xe4, 0x9b, 0x40, 0x48, 0x4c, 0xb8, 0x6c, 0x76, 0x1c, 0x67,
+	0x49, 0x6c, 0x6f, 0x7a, 0x36, 0x4e, 0xc1, 0x5b, 0x7b, 0x46, 0xb6, 0x3b, 0x99, 0xe9, 0x1e, 0x7a,
+	0x7a, 0x6c, 0x6f, 0x6e, 0x98, 0x84, 0x84, 0x10, 0xc2, 0x25, 0x09, 0x49, 0x80, 0x10, 0xa0, 0xc8,
+	0x03, 0x55, 0x54, 0x71, 0x29, 0x8a, 0xa2, 0x8a, 0x82, 0xe2, 0x99, 0x17, 0x8a, 0x47, 0x8a, 0x07,
+	0xfe, 0x0c, 0xaa, 0x78, 0xa2, 0x28, 0x49, 0xad, 0x96, 0xd4, 0xba, 0x78, 0x6d, 0x36, 0x6f, 0xad,
+	0x73, 0x24, 0x9d, 0x9f, 0x4e, 0x1f, 0x1d, 0x1d, 0x9d, 0x23, 0xb8, 0x10, 0xc5, 0x19, 0x4e, 0xe3,
+	0x70, 0x70, 0xef, 0x10, 0x67, 0xe1, 0xf1, 0x51, 0x9a, 0x64, 0x09, 0xaa, 0x92, 0xef, 0xd6, 0x7f,
+	0x2a, 0xb0, 0xda, 0x09, 0xb3, 0x10, 0x21, 0x58, 0xdd, 0xc2, 0xe9, 0xd0, 0x07, 0x4d, 0x6f, 0xb5,
+	0x1a, 0xd0, 0x6f, 0xb4, 0x08, 0xa7, 0x36, 0xe2, 0x3e, 0xbe, 0xea, 0x7b, 0x94, 0xc8, 0x1a, 0xe8,
+	0x30, 0xac, 0xaf, 0x0f, 0x26, 0xe3, 0x0c, 0xa7, 0x1b, 0x1d, 0xbf, 0x42, 0x39, 0x82, 0x80, 0x8e,
+	0xc0, 0xa9, 0x33, 0x49, 0x1f, 0x8f, 0xfd, 0x6a, 0xb3, 0xb2, 0x3a, 0xdb, 0x9e, 0x3b, 0x4e, 0x45,
+	0x12, 0xd2, 0x46, 0x7c, 0x21, 0x09, 0x18, 0x13, 0xdd, 0x07, 0xeb, 0x44, 0xea, 0xf9, 0x70, 0x8c,
+	0xc7, 0xfe, 0x14, 0xed, 0x89, 0x58, 0x4f, 0x4e, 0xa6, 0xbd, 0x45, 0x27, 0x32, 0xef, 0x53, 0x63,
+	0x9c, 0x8e, 0xfd, 0x69, 0x79, 0x5e, 0x42, 0x62, 0xf3, 0x52, 0x26, 0xc1, 0x76, 0x3a, 0xbc, 0x4a,
+	0xa5, 0x75, 0xfc, 0x19, 0x86, 0xad, 0x20, 0xa0, 0x55, 0xb8, 0xf7, 0x74, 0x78, 0xb5, 0x7b, 0x29,
+	0x4c, 0xfb, 0xa7, 0xd2, 0x64, 0x32, 0xda, 0xe8, 0xf8, 0x35, 0xda, 0xa7, 0x4c, 0x46, 0x2b, 0x10,
+	0x72, 0xd2, 0x46, 0xc7, 0xaf, 0xd3, 0x4e, 0x12, 0x05, 0xdd, 0xc3, 0xf0, 0xb3, 0x95, 0x42, 0xe3,
+	0x4a, 0x45, 0x07, 0xd2, 0xfb, 0x34, 0xe6, 0xbd, 0x67, 0xcd, 0xbd, 0x8b, 0x0e, 0x64, 0xa5, 0x41,
+	0x32, 0xc0, 0x63, 0x7f, 0x8f, 0xdc, 0x93, 0x90, 0xd8, 0x4a, 0x29, 0x13, 0xf9, 0x70, 0xe6, 0x1c,
+	0x4e, 0xc7, 0x51, 0x12, 0xfb, 0x8d, 0x26, 0x58, 0x6d, 0x04, 0xbc, 0x89, 0xee, 0x81, 0xfb, 0x36,
+	0x07, 0x61, 0x0f, 0x0f, 0x71, 0x9c, 0x75, 0xb3, 0x34, 0xcc, 0xf0, 0xc5, 0x6d, 0x7f, 0xae, 0x09,
+	0x56, 0xeb, 0x81, 0xce, 0x68, 0x65, 0xb0, 0xc6, 0x41, 0xa0, 0x39, 0xe8, 0x6d, 0x74, 0x72, 0x0b,
+	0xf0, 0x36, 0x3a, 0xc4, 0x26, 0xd6, 0xfa, 0xfd, 0xd4, 0xf7, 0xe8, 0x60, 0xfa, 0x4d, 0xe4, 0x6e,
+	0xad, 0x6f, 0x52, 0x72, 0x85, 0x92, 0x79, 0x93, 0xf4, 0xfe, 0x52, 0x12, 0x63, 0xbf, 0xca, 0x7a,
+	0x93, 0x6f, 0xb4, 0x04, 0xa7, 0xbb, 0x59, 0x98, 0x4d, 0xc8, 0x4f, 0x26, 0xd4, 0xbc, 0xd5, 0x7a,
+	0xbd, 0x02, 0xf7, 0xc8, 0x7f, 0x9a, 0x0c, 0x3e, 0x13, 0x0e, 0x31, 0x15, 0x5e, 0x0f, 0xe8, 0x37,
+	0x7a, 0x00, 0x2e, 0x75, 0xf0, 0x85, 0x70, 0x32, 0xc8, 0x02, 0x9c, 0xe1, 0x38, 0x8b, 0x92, 0x78,
+	0x33, 0x19, 0x44, 0xbd, 0x6d, 0x6a, 0x8f, 0xf5, 0xc0, 0xc2, 0x45, 0xa7, 0xe0, 0x3e, 0x95, 0x14,
+	0xe1, 0xb1, 0x5f, 0xa1, 0xca, 0x3c, 0x98, 0x2b, 0x53, 0x1d, 0x41, 0xf5, 0xaa, 0x8f, 0x21, 0x13,
+	0xad, 0x27, 0x71, 0x16, 0xc5, 0x93, 0x64, 0x32, 0x7e, 0x72, 0x82, 0xd3, 0xa8, 0xb0, 0xeb, 0x7c,
+	0x22, 0x95, 0x9d, 0x4f, 0xa4, 0x8d, 0x41, 0x0f, 0xc3, 0x83, 0x39, 0x56, 0x61, 0x65, 0x9d, 0x49,
+	0x1a, 0x12, 0x69, 0x54, 0x33, 0x95, 0xc0, 0xde, 0x01, 0xb5, 0xe1, 0x22, 0x31, 0x3d, 0x3a, 0xd5,
+	0x26, 0x4e, 0xb9, 0xde, 0xfc, 0x69, 0x3a, 0xd0, 0xc8, 0xcb, 0x4d, 0xfd, 0x5c, 0x38, 0x98, 0x50,
+	0xfa, 0x56, 0x78, 0xd1, 0x9f, 0xa1, 0xdd, 0xcb, 0xe4, 0xd6, 0x5b, 0x00, 0x2e, 0x94, 0xf4, 0xd1,
+	0x1d, 0xe1, 0x9e, 0xf4, 0x47, 0x40, 0xf1, 0x47, 0x96, 0x61, 0xad, 0x80, 0xed, 0xd1, 0xe9, 0x8a,
+	0x36, 0x3a, 0x0e, 0x91, 0x61, 0x71, 0x15, 0xda, 0xcb, 0xc0, 0x21, 0x73, 0x05, 0x78, 0x34, 0x88,
+	0x7a, 0xe1, 0x19, 0x6a, 0x32, 0x8d, 0xa0, 0x68, 0xb7, 0xfe, 0x5e, 0xd5, 0x30, 0x59, 0xad, 0x44,
+	0xc5, 0xe4, 0xed, 0x08, 0x93, 0xb7, 0x23, 0x4c, 0x9e, 0x8c, 0x09, 0x3d, 0x00, 0x67, 0xc5, 0x08,
+	0xee, 0xb4, 0x16, 0x99, 0x19, 0x08, 0x06, 0xb5, 0x00, 0xb9, 0x23, 0x7a, 0x18, 0x36, 0xba, 0x93,
+	0xf3, 0xe3, 0x5e, 0x1a, 0x8d, 0x88, 0x0c, 0xee, 0xc0, 0x96, 0xf2, 0x91, 0x12, 0x8b, 0x8e, 0x55,
+	0x3b, 0xa3, 0xbb, 0xe0, 0xfc, 0xd3, 0x69, 0x94, 0xe1, 0xb5, 0x0b, 0x17, 0xa2, 0x38, 0xca, 0xb6,
+	0xf9, 0x8f, 0xac, 0x07, 0x1a, 0x9d, 0x6e, 0x7c, 0x1c, 0xf7, 0xa3, 0xf8, 0x22, 0x95, 0xbf, 0x9e,
+	0x4c, 0xe2, 0xcc, 0xaf, 0x51, 0xd5, 0xea, 0x0c, 0x74, 0x14, 0xce, 0x6d, 0xa6, 0x78, 0x3d, 0xc5,
+	0x61, 0x86, 0x59, 0xd7, 0x3a, 0xed, 0x5a, 0xa2, 0xa2, 0x8b, 0x70, 0xf1, 0x34, 0x0e, 0xc7, 0x93,
+	0x94, 0xfa, 0x8d, 0xe2, 0xaf, 0xe4, 0x5e, 0xef, 0x7e, 0xeb, 0x86, 0x3a, 0x6e, 0x1a, 0x75, 0x32,
+	0xce, 0xd2, 0xed, 0xc0, 0x38, 0x21, 0x53, 0x7e, 0xd8, 0x3f, 0x1b, 0x0f, 0xb6, 0xfd, 0xd9, 0x26,
+	0x58, 0xad, 0x05, 0x45, 0x7b, 0xf9, 0x14, 0x3c, 0x68, 0x9d, 0x0e, 0xcd, 0xc3, 0xca, 0xb3, 0x78,
+	0x3b, 0x37, 0x54, 0xf2, 0x49, 0x0e, 0xae, 0xcb, 0xc4, 0xc6, 0x73, 0x23, 0x65, 0x8d, 0x13, 0xde,
+	0x83, 0xa0, 0xf5, 0x4f, 0x00, 0xe7, 0xd4, 0xbf, 0xa5, 0x79, 0xbd, 0xc3, 0xb0, 0xde, 0xcd, 0xc2,
+	0x34, 0xdb, 0x8a, 0x86, 0x38, 0xb7, 0x28, 0x41, 0x20, 0xfe, 0xef, 0x64, 0xdc, 0xa7, 0x3c, 0x66,
+	0x47, 0xbc, 0x49, 0xc6, 0x75, 0xf0, 0x00, 0x67, 0xb8, 0xbf, 0x96, 0x51, 0xeb, 0xa9, 0x04, 0x82,
+	0x80, 0x8e, 0xc1, 0x69, 0x2a, 0x97, 0x5b, 0xce, 0x5e, 0xc9, 0x72, 0xe8, 0x8f, 0xcf, 0xd9, 0xa8,
+	0x09, 0x67, 0xb7, 0xd2, 0x49, 0xdc, 0x0b, 0xd9, 0x44, 0x6c, 0x93, 0xcb, 0x24, 0xc5, 0x4a, 0x67,
+	0x4a, 0x3b, 0xe7, 0x15, 0x00, 0xeb, 0xc5, 0x9c, 0xda, 0xd2, 0x56, 0x60, 0xed, 0xec, 0x95, 0x98,
+	0x9c, 0xd3, 0x63, 0xdf, 0x6b, 0x56, 0x56, 0xab, 0x8f, 0x78, 0x3e, 0x08, 0x0a, 0x1a, 0x5a, 0x85,
+	0xd3, 0xf4, 0x9b, 0xbb, 0xcb, 0x79, 0x09, 0x24, 0x65, 0x04, 0x39, 0x9f, 0x2c, 0xf6, 0x89, 0x70,
+	0x9c, 0x51, 0x1b, 0xa4, 0xdb, 0xb7, 0x12, 0x08, 0x42, 0xeb, 0x65, 0x00, 0xe7, 0xcb, 0x96, 0x6d,
+	0xdc, 0xbc, 0x08, 0x56, 0x4f, 0x27, 0x7d, 0x9c, 0x3b, 0x74, 0xfa, 0x8d, 0x5a, 0x70, 0x4f, 0x07,
+	0x8f, 0xb3, 0x28, 0x0e, 0xd9, 0x7e, 0x21, 0x50, 0xea, 0x81, 0x42, 0x23, 0x7d, 0x24, 0x7b, 0x60,
+	0x4e, 0xb9, 0x1e, 0x28, 0xb4, 0xd6, 0x09, 0x08, 0x05, 0x70, 0x72, 0x12, 0xe5, 0x61, 0x01, 0x53,
+	0x47, 0xde, 0x22, 0xa6, 0x42, 0xce, 0x24, 0x9c, 0x1f, 0x72, 0xac, 0xd1, 0x7a, 0x08, 0x36, 0xc4,
+	0xd8, 0x2e, 0xce, 0x24, 0xcd, 0x00, 0xb7, 0x66, 0x5a, 0x5f, 0x84, 0x0b, 0x86, 0x53, 0xc1, 0xb8,
+	0xfa, 0x45, 0x38, 0x45, 0x3b, 0xe4, 0xcb, 0x67, 0x0d, 0x66, 0x61, 0xe1, 0xf9, 0x01, 0xee, 0x53,
+	0xef, 0x59, 0x0b, 0x78, 0xb3, 0xf5, 0x21, 0x80, 0x35, 0x1e, 0xf1, 0xd8, 0xd4, 0xf9, 0x58, 0x38,
+	0xbe, 0xc4, 0xd5, 0x49, 0xbe, 0x89, 0x90, 0xb5, 0xfe, 0x30, 0x62, 0x6e, 0xaf, 0x16, 0xb0, 0x06,
+	0xba, 0x1f, 0xc2, 0xcd, 0x34, 0xba, 0x1c, 0x0d, 0xf0, 0xc5, 0xe2, 0x4c, 0x5b, 0x10, 0x31, 0x55,
+	0xc1, 0x0b, 0xa4, 0x6e, 0x24, 0x2a, 0xa2, 0xa3, 0xbb, 0x51, 0xdc, 0xc3, 0xf9, 0xb9, 0x25, 0x51,
+	0x5a, 0x1b, 0xb0, 0xa1, 0x0c, 0xa6, 0xbe, 0x99, 0x9f, 0x56, 0x0c, 0x67, 0xd1, 0x26, 0x16, 0x54,
+	0x74, 0xa4, 0x80, 0xa7, 0x02, 0x41, 0x68, 0x45, 0xb0, 0xc6, 0x23, 0x1e, 0x9b, 0xea, 0x58, 0x38,
+	0xe8, 0xd1, 0x3f, 0xcf, 0x1a, 0xa5, 0x55, 0x55, 0x76, 0xb4, 0xaa, 0xd6, 0x3f, 0x1a, 0x70, 0x66,
+	0x3d, 0x19, 0x0e, 0xc3, 0xb8, 0x8f, 0x8e, 0xc2, 0x6a, 0xb6, 0x3d, 0x62, 0xa2, 0xe6, 0x78, 0x48,
+	0x9a, 0x33, 0x8f, 0x6f, 0x6d, 0x8f, 0x70, 0x40, 0xf9, 0xad, 0x0f, 0x1a, 0xb0, 0x4a, 0x9a, 0x68,
+	0x3f, 0xdc, 0xc7, 0x9c, 0x25, 0x31, 0xa7, 0xbc, 0xe3, 0x3c, 0x20, 0x64, 0xb6, 0xf5, 0x65, 0xb2,
+	0x87, 0x0e, 0xc2, 0xfd, 0xac, 0x37, 0xd7, 0x02, 0x67, 0x55, 0xd0, 0x01, 0xb8, 0xd0, 0x49, 0x93,
+	0x51, 0x99, 0x51, 0x45, 0x4d, 0x78, 0x98, 0x8d, 0x29, 0xf9, 0x58, 0xde, 0x63, 0x0a, 0xad, 0xc0,
+	0x65, 0x32, 0xd4, 0xc2, 0x9f, 0x46, 0x47, 0x60, 0xb3, 0x8b, 0x33, 0x73, 0xb0, 0xc4, 0x7b, 0xcd,
+	0x10, 0x39, 0x4f, 0x8d, 0xfa, 0x76, 0x39, 0x35, 0x74, 0x08, 0x1e, 0x60, 0x48, 0x84, 0x03, 0xe5,
+	0xcc, 0x3a, 0x61, 0xb2, 0x15, 0xeb, 0x4c, 0x28, 0xd6, 0x50, 0xda, 0x19, 0xbc, 0xc7, 0x2c, 0x5f,
+	0x83, 0x85, 0xbf, 0x47, 0xe8, 0x99, 0xfc, 0x47, 0x4e, 0x6e, 0xa0, 0x05, 0xb8, 0x97, 0x0c, 0x93,
+	0x89, 0x73, 0xa4, 0x2f, 0x5b, 0x89, 0x4c, 0xde, 0x4b, 0x34, 0xdc, 0xc5, 0x59, 0xf1, 0xe3, 0x39,
+	0x63, 0x1e, 0x21, 0x38, 0x47, 0xf4, 0x13, 0x66, 0x21, 0xa7, 0xed, 0x43, 0x87, 0xa1, 0xdf, 0xc5,
+	0x19, 0xb5, 0x6d, 0x6d, 0x04, 0x12, 0x12, 0xe4, 0xdf, 0xbb, 0x80, 0x6e, 0x81, 0x07, 0x73, 0x05,
+	0x49, 0xbe, 0x8f, 0xb3, 0xf7, 0x53, 0x15, 0xa5, 0xc9, 0xc8, 0xc4, 0x5c, 0x22, 0x53, 0x06, 0x78,
+	0x98, 0x5c, 0xc6, 0x9b, 0x58, 0x80, 0x3e, 0x20, 0x2c, 0x86, 0xdf, 0x0f, 0x38, 0xcb, 0x57, 0x8d,
+	0x49, 0x66, 0x1d, 0x24, 0x2c, 0x86, 0xaf, 0xcc, 0x5a, 0x26, 0x2c, 0xf6, 0x9f, 0xca, 0x13, 0x1e,
+	0x12, 0xac, 0xf2, 0xa8, 0xc3, 0x68, 0x09, 0xa2, 0x2e, 0xce, 0xca, 0x43, 0x6e, 0x41, 0x8b, 0x70,
+	0x9e, 0x2e, 0x89, 0x85, 0x15, 0x8c, 0xba, 0x42, 0x7e, 0x26, 0x3f, 0xaf, 0xa4, 0x48, 0x88, 0xf3,
+	0x6f, 0x25, 0x8a, 0xd8, 0x4c, 0x27, 0xb1, 0x89, 0xd9, 0xa4, 0xcb, 0x4a, 0x46, 0xdb, 0xc2, 0xb3,
+	0x72, 0xd6, 0x6d, 0x64, 0x1c, 0xd3, 0x91, 0xce, 0x6c, 0x11, 0x05, 0x6e, 0x25, 0x93, 0xde, 0x25,
+	0x05, 0xcb, 0xed, 0x68, 0x19, 0x2e, 0x05, 0xf8, 0x7c, 0x38, 0x08, 0xe3, 0x1e, 0x1b, 0x56, 0x88,
+	0x3a, 0x82, 0x6e, 0x85, 0x87, 0x88, 0x45, 0x94, 0xef, 0x44, 0xbc, 0xc3, 0x1d, 0xc2, 0xea, 0x88,
+	0x2f, 0xe2, 0xe4, 0xa3, 0xdc, 0xea, 0x64, 0xe2, 0x31, 0xe4, 0xc3, 0xc5, 0xb5, 0x7e, 0x9f, 0x98,
+	0xdc, 0x56, 0x22, 0x73, 0x56, 0x89, 0x59, 0x30, 0xd8, 0x84, 0xf9, 0x68, 0x9a, 0x0c, 0x65, 0xf6,
+	0x9d, 0x64, 0x55, 0x5d, 0x9c, 0x11, 0x9a, 0x66, 0x69, 0x77, 0x11, 0xc5, 0x8b, 0x55, 0x15, 0xd0,
+	0xef, 0x26, 0x73, 0xb2, 0x3f, 0x6c, 0xb2, 0xa6, 0x7b, 0x88, 0x12, 0x03, 0x1c, 0x87, 0x43, 0xcd,
+	0xd1, 0xfc, 0x1f, 0xd9, 0x8b, 0x8c, 0x65, 0xd9, 0xe7, 0xc7, 0xd1, 0x31, 0x78, 0xbb, 0xf0, 0x17,
+	0x7a, 0x94, 0xcc, 0x3b, 0xde, 0x9b, 0x6f, 0x12, 0x2e, 0xe2, 0x89, 0x68, 0x18, 0x65, 0x05, 0xc4,
+	0xfb, 0x08, 0xc4, 0x2e, 0xce, 0xa4, 0x63, 0x34, 0xa3, 0x0e, 0x80, 0xb1, 0xff, 0x3f, 0xf7, 0x4a,
+	0xa5, 0x0d, 0x9f, 0x9f, 0x74, 0xbc, 0x57, 0x5b, 0x78, 0x25, 0x8b, 0x67, 0xb8, 0x9f, 0x80, 0xd8,
+	0x4c, 0x93, 0x61, 0x92, 0xe1, 0xad, 0xa4, 0x6c, 0xb8, 0x9f, 0x20, 0x7f, 0x45, 0xde, 0xf4, 0x05,
+	0xbc, 0x4f, 0x4a, 0xe0, 0xc9, 0x08, 0x76, 0x2f, 0xe5, 0xdc, 0x07, 0xd0, 0x1d, 0xf0, 0xb6, 0xb2,
+	0xaf, 0x7b, 0x3a, 0xca, 0x2e, 0xb1, 0x33, 0x9e, 0x77, 0xfb, 0x14, 0xb1, 0xf4, 0x2e, 0xce, 0xca,
+	0x91, 0x38, 0xe7, 0x3f, 0xc8, 0x2d, 0xac, 0x1c, 0x7c, 0xf3, 0x0e, 0x0f, 0xe5, 0x28, 0xd4, 0x90,
+	0x9b, 0x73, 0x4f, 0x90, 0xe1, 0x05, 0xcb, 0xb0, 0x59, 0x3e, 0x4d, 0xf6, 0xdf, 0x69, 0xbe, 0x1f,
+	0x38, 0xf5, 0xe1, 0xbb, 0x6a, 0xb5, 0xfe, 0xfc, 0xb5, 0x6b, 0xd7, 0xae, 0x79, 0xad, 0x17, 0x0d,
+	0xc7, 0x13, 0x8d, 0x12, 0x92, 0x71, 0xc6, 0xcf, 0x53, 0xf2, 0x4d, 0x68, 0x41, 0x18, 0xf7, 0xf3,
+	0x4c, 0x0f, 0xfd, 0x6e, 0x7f, 0x1e, 0xce, 0xf4, 0xf2, 0x21, 0x0d, 0xe5, 0x24, 0xf4, 0x71, 0x13,
+	0xac, 0xce, 0xb6, 0x0f, 0xe4, 0xc4, 0xb2, 0x80, 0x80, 0x0f, 0x6b, 0x3d, 0x6f, 0x38, 0x06, 0xb5,
+	0xa0, 0x74, 0x11, 0x4e, 0x3d, 0x9a, 0xa4, 0x3d, 0x16, 0x04, 0xd4, 0x02, 0xd6, 0x70, 0x08, 0xbf,
+	0x20, 0x0b, 0xd7, 0xa6, 0x17, 0xc2, 0x7f, 0x0f, 0x2c, 0xa7, 0xad, 0x31, 0xa0, 0x58, 0x87, 0x7b,
+	0xf5, 0x2c, 0x03, 0x70, 0xa7, 0x0c, 0xca, 0x23, 0xda, 0x1d, 0x2b, 0xe8, 0x8b, 0x74, 0xae, 0x43,
+	0xb2, 0xc6, 0x4a, 0xa8, 0x04, 0xf0, 0xa1, 0x31, 0x14, 0x30, 0xa1, 0x6e, 0x3f, 0x62, 0x15, 0x78,
+	0x49, 0x06, 0x6f, 0x98, 0x4e, 0x88, 0xfb, 0x8b, 0xe7, 0x8e, 0x30, 0x9c, 0x51, 0x9c, 0x51, 0x6d,
+	0xde, 0x8d, 0xa9, 0x8d, 0x44, 0xbc, 0xb9, 0xb7, 0xe1, 0x11, 0x6f, 0xde, 0x44, 0x47, 0x60, 0x63,
+	0xfd, 0x12, 0xee, 0x3d, 0xab, 0x64, 0x0a, 0x6a, 0x81, 0x4a, 0x44, 0x27, 0xa0, 0xdf, 0xcd, 0xd2,
+	0xa8, 0x67, 0xcb, 0xae, 0xd4, 0x02, 0x2b, 0xbf, 0xfd, 0xb8, 0x55, 0x83, 0x11, 0xd5, 0x60, 0x4b,
+	0xfe, 0x65, 0x66, 0x05, 0x09, 0x55, 0xbe, 0x0f, 0x5c, 0xa1, 0x98, 0x53, 0x91, 0xfc, 0xef, 0x7a,
+	0xd2, 0xdf, 0xdd, 0xb0, 0x62, 0x7b, 0x86, 0x62, 0x6b, 0x8a, 0xbf, 0x7b, 0x3d, 0x64, 0x1f, 0x81,
+	0xeb, 0x07, 0x81, 0x37, 0x8c, 0xef, 0xac, 0x15, 0xdf, 0xb3, 0x14, 0xdf, 0x51, 0x46, 0xbc, 0x9e,
+	0x5c, 0x81, 0xf2, 0xd5, 0xaa, 0x3b, 0x08, 0xbd, 0x51, 0x84, 0xc4, 0xb2, 0xce, 0xe0, 0x2b, 0x94,
+	0x9c, 0x67, 0x2b, 0xf3, 0xa6, 0x92, 0x36, 0xaa, 0x96, 0x52, 0x59, 0xf2, 0x05, 0x7b, 0x4a, 0xbd,
+	0x60, 0x5b, 0x52, 0x4a, 0xd3, 0xd6, 0x34, 0x97, 0x64, 0xdb, 0x33, 0xaa, 0x6d, 0xdf, 0x07, 0x17,
+	0xd6, 0x06, 0x83, 0xe4, 0xca, 0xc9, 0xab, 0x3d, 0x3c, 0x1e, 0x17, 0x02, 0x6b, 0xb4, 0x97, 0x89,
+	0xa5, 0x64, 0x48, 0xea, 0x6a, 0x86, 0x44, 0xdf, 0x29, 0xd0, 0xb4, 0x53, 0x5a, 0x70, 0x0f, 0xdb,
+	0x09, 0x27, 0xaf, 0x8e, 0xa2, 0x94, 0xe7, 0x59, 0x14, 0x1a, 0x49, 0x40, 0x50, 0x17, 0x9c, 0x77,
+	0xd9, 0x43, 0xbb, 0xc8, 0x24, 0x5a, 0x2b, 0x20, 0x09, 0x90, 0x06, 0x5d, 0x35, 0xfd, 0x76, 0xec,
+	0xa3, 0x81, 0xbc, 0x8f, 0x5c, 0x7f, 0x57, 0xd8, 0xc1, 0xdf, 0x80, 0xf5, 0xaa, 0xe1, 0x34, 0x81,
+	0x25, 0x38, 0xad, 0x64, 0x88, 0xf3, 0x16, 0xb9, 0x6b, 0x12, 0x90, 0xe3, 0x2c, 0x1c, 0x8e, 0xf2,
+	0xb4, 0x8d, 0x20, 0xb8, 0x32, 0x91, 0xed, 0x47, 0xad, 0xcb, 0x1a, 0xd2, 0x65, 0xdd, 0x22, 0xbb,
+	0x07, 0x0d, 0xac, 0x58, 0xd1, 0x1f, 0x80, 0xf5, 0x7e, 0x74, 0x53, 0x2b, 0x22, 0x3f, 0x52, 0xae,
+	0x63, 0xb0, 0x3a, 0x8c, 0x42, 0x73, 0x60, 0x8f, 0x65, 0xec, 0x16, 0x58, 0x02, 0xfb, 0x5f, 0x81,
+	0xfb, 0xfa, 0x76, 0xc3, 0xbb, 0xb2, 0xc8, 0x7b, 0x54, 0xe4, 0xbc, 0xc7, 0x32, 0xac, 0x9d, 0x0b,
+	0x07, 0x11, 0xb1, 0x8f, 0xdc, 0xcd, 0x17, 0x6d, 0x87, 0x75, 0x25, 0xba, 0x97, 0x36, 0xa3, 0xd4,
+	0xbd, 0xf4, 0xee, 0xac, 0xc6, 0xe1, 0xa5, 0x47, 0x65, 0x2f, 0x7d, 0x3d, 0x64, 0xef, 0x00, 0xc3,
+	0x35, 0xf7, 0x7f, 0xcb, 0xf4, 0x38, 0x02, 0xa9, 0x2f, 0xeb, 0x51, 0x9c, 0x24, 0x56, 0xa0, 0xc2,
+	0xda, 0x25, 0xdb, 0x18, 0x8b, 0x7c, 0xd6, 0x2a, 0x28, 0xa5, 0x82, 0xf6, 0x0b, 0x3d, 0x18, 0xc5,
+	0xbc, 0x68, 0xb8, 0xb6, 0xef, 0x74, 0xed, 0x8e, 0x55, 0x8e, 0xe5, 0x55, 0x6a, 0x02, 0x84, 0xf8,
+	0x5f, 0x03, 0x63, 0x7e, 0x80, 0x98, 0x03, 0xe9, 0x1f, 0x0b, 0x14, 0x45, 0x5b, 0x31, 0x15, 0xcf,
+	0x95, 0xdf, 0xaa, 0x94, 0xf2, 0x5b, 0x8e, 0xc0, 0x2d, 0x93, 0x03, 0x37, 0x03, 0x20, 0x81, 0x38,
+	0x29, 0xe7, 0x2d, 0xd0, 0x0a, 0x2b, 0xe6, 0x52, 0x9c, 0xb3, 0x6d, 0x28, 0x2a, 0xaa, 0x01, 0xa5,
+	0xb7, 0x3f, 0x63, 0x95, 0x3a, 0x69, 0x02, 0xa9, 0x9c, 0xa1, 0xcc, 0x2a, 0x04, 0xbe, 0x0b, 0xec,
+	0x59, 0x11, 0xa7, 0x9e, 0x0a, 0xcb, 0xf4, 0x64, 0xcb, 0x3c, 0x65, 0x45, 0x73, 0x99, 0xa2, 0x59,
+	0x29, 0xd0, 0x18, 0x25, 0x0a, 0x5c, 0xdb, 0x86, 0x74, 0x8c, 0xa9, 0x98, 0x49, 0x6f, 0x3d, 0x9e,
+	0xb8, 0xf5, 0x38, 0xac, 0xe6, 0x8a, 0x6e, 0x35, 0xc6, 0x4b, 0xc6, 0x07, 0x9e, 0x23, 0xe7, 0x63,
+	0xad, 0x57, 0xd9, 0x6c, 0x66, 0x55, 0x8f, 0xa6, 0x99, 0x8b, 0x2c, 0x93, 0x8b, 0xc4, 0x79, 0xd5,
+	0x91, 0x38, 0x9f, 0xda, 0x41, 0xe2, 0x7c, 0x5a, 0x4f, 0x9c, 0xb7, 0x1f, 0xb3, 0x6a, 0x65, 0x9b,
+	0x6a, 0xe5, 0x56, 0xe5, 0xcc, 0xd3, 0x97, 0x2d, 0xb4, 0xf3, 0x47, 0x60, 0x4d, 0x79, 0x7d, 0x7c,
+	0xba, 0x71, 0x9c, 0x7b, 0xcf, 0x29, 0xe7, 0x9e, 0x19, 0x98, 0x62, 0x56, 0x5a, 0x4a, 0xae, 0x30,
+	0x2b, 0xa0, 0xd5, 0xc8, 0x3d, 0x5e, 0x23, 0x77, 0x98, 0xd5, 0xf3, 0xb2, 0x59, 0x69, 0x93, 0x2b,
+	0x8a, 0x33, 0xe7, 0xfd, 0x88, 0x8a, 0x1e, 0xdb, 0xda, 0x62, 0x05, 0xf8, 0x7c, 0x9b, 0xf1, 0xb6,
+	0x5c, 0x9b, 0x67, 0x70, 0xe4, 0xda, 0x3c, 0xbd, 0xde, 0x57, 0xc4, 0xf5, 0xde, 0x54, 0xaf, 0x77,
+	0x5c, 0x60, 0x5f, 0xd0, 0x2f, 0xb0, 0x25, 0x68, 0x02, 0xfd, 0xcf, 0x81, 0x25, 0x35, 0x79, 0xf3,
+	0xe8, 0x29, 0xd2, 0xca, 0x8e, 0x90, 0xbe, 0x68, 0xbe, 0x6a, 0x1b, 0x91, 0x7e, 0x04, 0x2c, 0x99,
+	0x52, 0xcd, 0x7d, 0xc8, 0xc8, 0x3d, 0x3b, 0xf2, 0x8a, 0x82, 0xdc, 0x81, 0xf2, 0x25, 0x19, 0xa5,
+	0x11, 0x82, 0x9c, 0x10, 0x30, 0xe7, 0x6c, 0xcb, 0x20, 0x1d, 0xe2, 0xbe, 0x22, 0x8b, 0x33, 0x4e,
+	0x26, 0xc4, 0xc5, 0x96, 0x3c, 0xb0, 0x26, 0xee, 0xa4, 0x55, 0xdc, 0x35, 0xa0, 0xcb, 0xb3, 0x2e,
+	0xef, 0x1c, 0x89, 0xbf, 0xc7, 0xa3, 0x24, 0x1e, 0x63, 0x22, 0xe2, 0xec, 0xe3, 0x54, 0x44, 0x2d,
+	0xf0, 0xce, 0x3e, 0x4e, 0x4e, 0x8e, 0x93, 0x69, 0x9a, 0xf0, 0x37, 0x28, 0xac, 0x21, 0x1e, 0x26,
+	0x55, 0xe8, 0x3e, 0x64, 0x8d, 0x1c, 0x5e, 0x95, 0x6f, 0xcd, 0xd6, 0xcf, 0x80, 0x29, 0x6b, 0xbd,
+	0x7b, 0x3b, 0xc8, 0x71, 0x88, 0x7f, 0x95, 0xad, 0xdf, 0x2f, 0x4e, 0x30, 0xab, 0xb2, 0xfb, 0x7a,
+	0x06, 0x5d, 0xd3, 0xb3, 0xdd, 0x9f, 0xbc, 0xcc, 0xe4, 0x2c, 0x49, 0x1e, 0x4d, 0x9a, 0x48, 0x48,
+	0x79, 0x0d, 0xb8, 0x52, 0xf2, 0xea, 0xfd, 0x08, 0x94, 0xee, 0x47, 0xed, 0x2f, 0x58, 0xc5, 0xbf,
+	0x02, 0xe4, 0x08, 0xd7, 0x2e, 0x40, 0x00, 0x39, 0x6f, 0x4d, 0xfd, 0x3b, 0xc2, 0x81, 0xaf, 0x01,
+	0xd9, 0x6f, 0x5b, 0xc6, 0x2b, 0x8b, 0x35, 0x97, 0x10, 0xb4, 0x4d, 0x2d, 0x8a, 0xc2, 0x9e, 0x5c,
+	0x14, 0x76, 0x18, 0xf6, 0xab, 0x8a, 0x61, 0x1b, 0xa5, 0x08, 0x20, 0x6f, 0x00, 0x6b, 0xc1, 0x62,
+	0xc7, 0x50, 0xec, 0x5a, 0x79, 0x4d, 0xd1, 0x8a, 0x45, 0x8e, 0x00, 0xf3, 0x9c, 0xa1, 0x3e, 0x62,
+	0x0a, 0x92, 0xa4, 0x67, 0x0f, 0xf4, 0xbb, 0xbd, 0x66, 0x45, 0xf0, 0x75, 0x20, 0x1f, 0x67, 0xda,
+	0xec, 0x42, 0xf6, 0x0b, 0xb6, 0x22, 0x0c, 0xd9, 0x8c, 0xc5, 0x1b, 0x35, 0xf6, 0x80, 0xa3, 0x68,
+	0x3b, 0xce, 0xf1, 0xd7, 0x99, 0xe0, 0xc3, 0x7c, 0xe9, 0xa6, 0xa9, 0x85, 0xf4, 0x97, 0x9c, 0x65,
+	0x1e, 0xe3, 0x5d, 0xc6, 0x7e, 0xdf, 0xfc, 0x06, 0x13, 0x7d, 0x9b, 0x88, 0xcf, 0x2d, 0xf3, 0x0a,
+	0xf9, 0xcf, 0x18, 0xaa, 0x48, 0x46, 0xa9, 0x76, 0x4d, 0xbf, 0x01, 0xf4, 0xbb, 0x9a, 0x34, 0x9b,
+	0x90, 0x75, 0x41, 0x2b, 0x4d, 0x19, 0x25, 0x7d, 0xce, 0x2a, 0xe9, 0x9b, 0xa0, 0x7c, 0x59, 0x33,
+	0xca, 0x79, 0x13, 0x98, 0xcb, 0x5d, 0xd4, 0x4f, 0x26, 0x83, 0x42, 0x1a, 0xf9, 0x56, 0xae, 0x06,
+	0x9e, 0x7a, 0x35, 0x70, 0x1c, 0x59, 0x6f, 0x32, 0x24, 0xcb, 0x8c, 0x6a, 0x12, 0x26, 0xe0, 0xbc,
+	0x07, 0x1c, 0x35, 0xb6, 0x1b, 0xc6, 0x64, 0xbf, 0xd1, 0x7f, 0x0b, 0xc8, 0x11, 0xb0, 0x55, 0xa2,
+	0x00, 0xf6, 0x1b, 0x60, 0xad, 0xee, 0xd9, 0x60, 0xdd, 0xe4, 0x8d, 0xd2, 0xee, 0x28, 0xbe, 0xad,
+	0x38, 0x0a, 0x0b, 0x1a, 0x79, 0xbb, 0x18, 0x4a, 0x8e, 0xe4, 0x91, 0xd5, 0x46, 0x87, 0xbd, 0x7e,
+	0xa9, 0x06, 0xe4, 0xd3, 0xe8, 0x2b, 0xec, 0x27, 0xe2, 0x77, 0x94, 0x13, 0x51, 0x17, 0x20, 0xe4,
+	0xff, 0x1b, 0x38, 0x6a, 0x9b, 0xce, 0xec, 0xcc, 0xaa, 0xb9, 0x18, 0x61, 0xbe, 0x3e, 0xe5, 0x49,
+	0x61, 0xfd, 0x2d, 0xd2, 0x0d, 0x5e, 0xa9, 0x1c, 0xd6, 0xf2, 0x5d, 0xc5, 0x5a, 0xac, 0x6b, 0x12,
+	0x4b, 0x7f, 0x1b, 0x58, 0xea, 0xb6, 0x24, 0x30, 0x39, 0x3b, 0xe8, 0x4b, 0xfb, 0x98, 0x37, 0xe5,
+	0x14, 0x77, 0x1e, 0xb2, 0xe4, 0x4d, 0xc7, 0x29, 0xf6, 0x96, 0x72, 0x8a, 0x19, 0x25, 0x0a, 0x50,
+	0x7f, 0x02, 0xee, 0x8a, 0xb1, 0xf3, 0x97, 0x48, 0xb8, 0x3d, 0x2b, 0xee, 0x8a, 0x8a, 0xfb, 0x09,
+	0x2b, 0xee, 0xb7, 0x81, 0x9c, 0xed, 0x73, 0x81, 0x12, 0xf0, 0x7f, 0x0b, 0x76, 0x54, 0xce, 0x76,
+	0xae, 0xc2, 0xf1, 0xc6, 0xb4, 0xdd, 0xb5, 0xa2, 0x7d, 0x87, 0xa1, 0xbd, 0xb3, 0x5c, 0x05, 0xb1,
+	0x62, 0x10, 0xa0, 0x7f, 0x07, 0xec, 0xa5, 0x75, 0xe3, 0xcd, 0x99, 0x3d, 0x7c, 0x67, 0xef, 0x80,
+	0xf9, 0xa3, 0xc5, 0x82, 0x90, 0x73, 0xd9, 0xb3, 0x5f, 0x9e, 0xff, 0x2e, 0x08, 0x8e, 0xfb, 0xfe,
+	0xf7, 0x40, 0x29, 0x11, 0x63, 0x04, 0x24, 0x60, 0xff, 0x0a, 0x38, 0x6a, 0xfe, 0xe4, 0x8f, 0xf3,
+	0x17, 0xf5, 0x2c, 0xe2, 0xe0, 0x4d, 0x5b, 0xf0, 0x23, 0x1e, 0xe7, 0xe5, 0x89, 0x62, 0xda, 0x70,
+	0x6c, 0xb8, 0x77, 0x95, 0x0d, 0x67, 0x45, 0x22, 0x00, 0xff, 0x19, 0x5c, 0xff, 0x15, 0xc2, 0xcd,
+	0x14, 0x9d, 0xc4, 0x03, 0x3e, 0x4f, 0x7a, 0xc0, 0xd7, 0xde, 0xb4, 0x22, 0x7f, 0x0f, 0x94, 0x2a,
+	0x66, 0x4e, 0x48, 0x8a, 0x75, 0x3b, 0x1f, 0x48, 0xec, 0x4e, 0x6e, 0xde, 0xb1, 0x25, 0xdf, 0x07,
+	0x7a, 0x79, 0xe7, 0x7a, 0x69, 0xee, 0x5f, 0x00, 0xfb, 0x9b, 0x8d, 0x5d, 0xba, 0x78, 0xdb, 0x6d,
+	0xfa, 0xfb, 0x8a, 0x4d, 0xdb, 0x60, 0x08, 0xb0, 0xbf, 0x04, 0xe6, 0x27, 0x24, 0xce, 0x84, 0xa7,
+	0xfa, 0x10, 0xd1, 0xdb, 0xd1, 0x43, 0x44, 0x47, 0x28, 0xf4, 0x03, 0x25, 0x14, 0x32, 0xa1, 0x51,
+	0x22, 0x33, 0xeb, 0xc3, 0x16, 0xd3, 0xad, 0x83, 0x75, 0xe0, 0xe5, 0x25, 0xd6, 0x72, 0xa8, 0xef,
+	0x87, 0x26, 0x97, 0xa0, 0x09, 0x12, 0x70, 0xfe, 0x05, 0x76, 0xf0, 0x92, 0xe6, 0x63, 0x28, 0xea,
+	0xdd, 0x5d, 0x3c, 0xd8, 0x55, 0x1e, 0xb7, 0x2a, 0xaf, 0x7a, 0xf9, 0x9b, 0xdd, 0xf6, 0x93, 0xd6,
+	0xe5, 0x7e, 0xc0, 0x96, 0x7b, 0xcc, 0x5c, 0xe6, 0xd3, 0x16, 0xa2, 0xb8, 0x42, 0xc7, 0xd3, 0xa0,
+	0x9b, 0x5a, 0xf0, 0x3c, 0xac, 0x90, 0xe7, 0xff, 0xcc, 0xd2, 0xc9, 0xa7, 0xe3, 0x66, 0xfe, 0x23,
+	0xe5, 0x66, 0x6e, 0x07, 0xa2, 0x1c, 0x39, 0xae, 0xb7, 0x4a, 0x37, 0x85, 0x78, 0x11, 0x4e, 0xd1,
+	0x39, 0x28, 0xe6, 0x46, 0xc0, 0x1a, 0x8e, 0xcb, 0xd5, 0x87, 0xda, 0xe5, 0xca, 0x82, 0x46, 0xd1,
+	0xb3, 0xf5, 0x05, 0xd5, 0x2e, 0x62, 0xb6, 0x6f, 0x88, 0x1f, 0x97, 0x37, 0x84, 0x11, 0x8a, 0x12,
+	0x4e, 0xb9, 0x1e, 0x75, 0xed, 0xfe, 0x56, 0x70, 0xe8, 0xfb, 0x27, 0x8a, 0xbe, 0x1d, 0xa8, 0x14,
+	0xdf, 0xad, 0x3d, 0x39, 0x73, 0x9c, 0xec, 0x2b, 0x10, 0x92, 0xbb, 0x91, 0x72, 0xba, 0x4b, 0x14,
+	0xb2, 0xda, 0xad, 0x24, 0xe7, 0xb2, 0xd4, 0x5a, 0xd1, 0x76, 0xa4, 0xbd, 0x7e, 0xaa, 0xa4, 0xbd,
+	0xca, 0x70, 0x0a, 0xb0, 0xff, 0x1d, 0x00, 0x0c, 0xa5, 0x09, 0xcd, 0xf9, 0x38, 0x00, 0x00,
 }
@@ -182,25 +182,27 @@ func (r *raftState) close() error {
 	return nil
 }
 
-// apply applies a serialized command to the raft log.
-func (r *raftState) apply(b []byte) error {
+// apply applies a serialized command to the raft log. It returns the node ID
+// assigned by the command, if any.
+func (r *raftState) apply(b []byte) (uint64, error) {
 	// Apply to raft log.
 	f := r.raft.Apply(b, 0)
 	if err := f.Error(); err != nil {
-		return err
+		return 0, err
 	}
 
-	// Return response if it's an error.
+	// Return response if it's an error or a node ID.
 	// No other non-nil objects should be returned.
-	resp := f.Response()
-	if err, ok := resp.(error); ok {
-		return err
-	}
-	if resp != nil {
+	switch resp := f.Response().(type) {
+	case nil:
+		return 0, nil
+	case error:
+		return 0, resp
+	case nodeIDResponse:
+		return uint64(resp), nil
+	default:
 		panic(fmt.Sprintf("unexpected response: %#v", resp))
 	}
-
-	return nil
 }
 
 func (r *raftState) lastIndex() uint64 {
@@ -389,6 +389,7 @@ func TestMetaService_CreateDataNode(t *testing.T) {
 		ID:      2,
 		Addr:    "foo:8380",
 		TCPAddr: "bar:8381",
+		Status:  meta.NodeStatusJoined,
 	}
 
 	n, err := c.CreateDataNode(exp.Addr, exp.TCPAddr)
@@ -457,8 +458,8 @@ func TestMetaService_DropDataNode(t *testing.T) {
 
 	// The first data node should be removed as an owner of the shard on
 	// the shard group
-	if !reflect.DeepEqual(sg.Shards[0].Owners, []meta.ShardOwner{{n2.ID}}) {
-		t.Errorf("owners for shard are %v, expected %v", sg.Shards[0].Owners, []meta.ShardOwner{{2}})
+	if !reflect.DeepEqual(sg.Shards[0].Owners, []meta.ShardOwner{{NodeID: n2.ID}}) {
+		t.Errorf("owners for shard are %v, expected %v", sg.Shards[0].Owners, []meta.ShardOwner{{NodeID: 2}})
 	}
 
 	// The shard group should still be marked as active because it still
@@ -538,8 +539,8 @@ func TestMetaService_DropDataNode_Reassign(t *testing.T) {
 
 	// The second data node should be the owner of both shards.
 	for _, s := range sg.Shards {
-		if !reflect.DeepEqual(s.Owners, []meta.ShardOwner{{n2.ID}}) {
-			t.Errorf("owners for shard are %v, expected %v", s.Owners, []meta.ShardOwner{{2}})
+		if !reflect.DeepEqual(s.Owners, []meta.ShardOwner{{NodeID: n2.ID}}) {
+			t.Errorf("owners for shard are %v, expected %v", s.Owners, []meta.ShardOwner{{NodeID: 2}})
 		}
 	}
 
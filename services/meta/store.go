@@ -321,8 +321,15 @@ func (s *store) index() uint64 {
 
 // apply applies a command to raft.
 func (s *store) apply(b []byte) error {
+	_, err := s.applyWithID(b)
+	return err
+}
+
+// applyWithID applies a command to raft like apply, and returns the node ID
+// assigned by the command, if any.
+func (s *store) applyWithID(b []byte) (uint64, error) {
 	if s.raftState == nil {
-		return fmt.Errorf("store not open")
+		return 0, fmt.Errorf("store not open")
 	}
 	return s.raftState.apply(b)
 }
@@ -915,7 +922,7 @@ func (s *store) cluster() *ClusterInfo {
 				ID:       n.ID,
 				TCPAddr:  n.TCPAddr,
 				HTTPAddr: n.Addr,
-				Status:   NodeStatusJoined,
+				Status:   n.Status,
 			}
 		}
 		ci.Data = data
@@ -977,28 +984,8 @@ func (s *store) shard(id uint64) *ClusterShardInfo {
 }
 
 func (s *store) shardInfo(di DatabaseInfo, rpi RetentionPolicyInfo, sgi ShardGroupInfo, si ShardInfo) *ClusterShardInfo {
-	var expire time.Time
-	if rpi.Duration != 0 {
-		expire = sgi.EndTime.Add(rpi.Duration)
-	}
-	owners := make([]*ShardOwnerInfo, len(si.Owners))
-	for i, owner := range si.Owners {
-		n, _ := s.dataNode(owner.NodeID)
-		owners[i] = &ShardOwnerInfo{
-			ID:      owner.NodeID,
-			TCPAddr: n.TCPAddr,
-		}
-	}
-	return &ClusterShardInfo{
-		ID:              si.ID,
-		Database:        di.Name,
-		RetentionPolicy: rpi.Name,
-		ReplicaN:        rpi.ReplicaN,
-		ShardGroupID:    sgi.ID,
-		StartTime:       sgi.StartTime,
-		EndTime:         sgi.EndTime,
-		ExpireTime:      expire,
-		TruncatedAt:     sgi.TruncatedAt,
-		Owners:          owners,
-	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info := s.data.clusterShardInfo(di.Name, rpi, sgi, si)
+	return &info
 }
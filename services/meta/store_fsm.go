@@ -15,6 +15,9 @@ import (
 // storeFSM represents the finite state machine used by Store to interact with Raft.
 type storeFSM store
 
+// nodeIDResponse is the response of a command that assigns a node ID.
+type nodeIDResponse uint64
+
 func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 	var cmd internal.Command
 	if err := proto.Unmarshal(l.Data, &cmd); err != nil {
@@ -98,6 +101,58 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 			return fsm.applyCopyShardOwnerCommand(&cmd)
 		case internal.Command_RemoveShardOwnerCommand:
 			return fsm.applyRemoveShardOwnerCommand(&cmd)
+		case internal.Command_TouchShardCommand:
+			return fsm.applyTouchShardCommand(&cmd)
+		case internal.Command_RebalanceShardsCommand:
+			return fsm.applyRebalanceShardsCommand(&cmd)
+		case internal.Command_SetPlacementStrategyCommand:
+			return fsm.applySetPlacementStrategyCommand(&cmd)
+		case internal.Command_CreateRoleCommand:
+			return fsm.applyCreateRoleCommand(&cmd)
+		case internal.Command_DropRoleCommand:
+			return fsm.applyDropRoleCommand(&cmd)
+		case internal.Command_AddUserToRoleCommand:
+			return fsm.applyAddUserToRoleCommand(&cmd)
+		case internal.Command_RemoveUserFromRoleCommand:
+			return fsm.applyRemoveUserFromRoleCommand(&cmd)
+		case internal.Command_SetRolePrivilegeCommand:
+			return fsm.applySetRolePrivilegeCommand(&cmd)
+		case internal.Command_TouchShardsCommand:
+			return fsm.applyTouchShardsCommand(&cmd)
+		case internal.Command_UpdateSubscriptionCommand:
+			return fsm.applyUpdateSubscriptionCommand(&cmd)
+		case internal.Command_RenameDatabaseCommand:
+			return fsm.applyRenameDatabaseCommand(&cmd)
+		case internal.Command_RenameRetentionPolicyCommand:
+			return fsm.applyRenameRetentionPolicyCommand(&cmd)
+		case internal.Command_SetDefaultShardGroupDurationCommand:
+			return fsm.applySetDefaultShardGroupDurationCommand(&cmd)
+		case internal.Command_SetDatabaseLimitsCommand:
+			return fsm.applySetDatabaseLimitsCommand(&cmd)
+		case internal.Command_SetShardOwnerStateCommand:
+			return fsm.applySetShardOwnerStateCommand(&cmd)
+		case internal.Command_SetContinuousQueryEnabledCommand:
+			return fsm.applySetContinuousQueryEnabledCommand(&cmd)
+		case internal.Command_UpdateContinuousQueryCommand:
+			return fsm.applyUpdateContinuousQueryCommand(&cmd)
+		case internal.Command_PromoteToDataNodeCommand:
+			return fsm.applyPromoteToDataNodeCommand(&cmd)
+		case internal.Command_SetPrivilegesCommand:
+			return fsm.applySetPrivilegesCommand(&cmd)
+		case internal.Command_SetDataNodeStatusCommand:
+			return fsm.applySetDataNodeStatusCommand(&cmd)
+		case internal.Command_CreateShardGroupWithOwnersCommand:
+			return fsm.applyCreateShardGroupWithOwnersCommand(&cmd)
+		case internal.Command_SetWriteAffinityTagCommand:
+			return fsm.applySetWriteAffinityTagCommand(&cmd)
+		case internal.Command_SetPendingShardCountCommand:
+			return fsm.applySetPendingShardCountCommand(&cmd)
+		case internal.Command_SetPreCreateCountCommand:
+			return fsm.applySetPreCreateCountCommand(&cmd)
+		case internal.Command_PreCreateShardGroupsCommand:
+			return fsm.applyPreCreateShardGroupsCommand(&cmd)
+		case internal.Command_MoveShardCommand:
+			return fsm.applyMoveShardCommand(&cmd)
 		default:
 			panic(fmt.Errorf("cannot apply command: %x", l.Data))
 		}
@@ -189,15 +244,9 @@ func (fsm *storeFSM) applyUpdateDataNodeCommand(cmd *internal.Command) interface
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-
-	node := other.DataNode(v.GetID())
-	if node == nil {
-		return ErrNodeNotFound
+	if err := other.UpdateDataNode(v.GetID(), v.GetHTTPAddr(), v.GetTCPAddr()); err != nil {
+		return err
 	}
-
-	node.Addr = v.GetHTTPAddr()
-	node.TCPAddr = v.GetTCPAddr()
-
 	fsm.data = other
 	return nil
 }
@@ -213,23 +262,19 @@ func (fsm *storeFSM) applyCreateDatabaseCommand(cmd *internal.Command) interface
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateDatabase(v.GetName()); err != nil {
-		return err
-	}
 
 	s := (*store)(fsm)
 	if rpi := v.GetRetentionPolicy(); rpi != nil {
-		if err := other.CreateRetentionPolicy(v.GetName(), &RetentionPolicyInfo{
+		if err := other.CreateDatabaseWithRetentionPolicy(v.GetName(), &RetentionPolicyInfo{
 			Name:               rpi.GetName(),
 			ReplicaN:           int(rpi.GetReplicaN()),
 			Duration:           time.Duration(rpi.GetDuration()),
 			ShardGroupDuration: time.Duration(rpi.GetShardGroupDuration()),
-		}, true); err != nil {
-			if err == ErrRetentionPolicyExists {
-				return ErrRetentionPolicyConflict
-			}
+		}); err != nil {
 			return err
 		}
+	} else if err := other.CreateDatabase(v.GetName()); err != nil {
+		return err
 	} else if s.config.RetentionAutoCreate {
 		// Read node count.
 		// Retention policies must be fully replicated.
@@ -273,15 +318,25 @@ func (fsm *storeFSM) applyCreateRetentionPolicyCommand(cmd *internal.Command) in
 	v := ext.(*internal.CreateRetentionPolicyCommand)
 	pb := v.GetRetentionPolicy()
 
+	// Commands written before the replication factor was checked don't set
+	// CheckReplicaN, and replay unchecked.
+	opts := CreateRetentionPolicyOpts{
+		StrictShardGroupDuration: v.GetStrictShardGroupDuration(),
+		CheckReplicaN:            v.GetCheckReplicaN(),
+	}
+
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateRetentionPolicy(v.GetDatabase(),
+	if err := other.CreateRetentionPolicyWithOpts(v.GetDatabase(),
 		&RetentionPolicyInfo{
 			Name:               pb.GetName(),
 			ReplicaN:           int(pb.GetReplicaN()),
 			Duration:           time.Duration(pb.GetDuration()),
 			ShardGroupDuration: time.Duration(pb.GetShardGroupDuration()),
-		}, v.GetDefault()); err != nil {
+			WriteAffinityTag:   pb.GetWriteAffinityTag(),
+			PendingShardCount:  int(pb.GetPendingShardCount()),
+			PreCreateCount:     int(pb.GetPreCreateCount()),
+		}, v.GetDefault(), opts); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -307,8 +362,14 @@ func (fsm *storeFSM) applyUpdateRetentionPolicyCommand(cmd *internal.Command) in
 	ext, _ := proto.GetExtension(cmd, internal.E_UpdateRetentionPolicyCommand_Command)
 	v := ext.(*internal.UpdateRetentionPolicyCommand)
 
-	// Create update object.
-	rpu := RetentionPolicyUpdate{Name: v.NewName}
+	// Create update object. Commands written before the replication factor
+	// was checked don't set CheckReplicaN, and replay unchecked.
+	rpu := RetentionPolicyUpdate{
+		Name:                v.NewName,
+		AllowExcessReplicaN: v.GetAllowExcessReplicaN() || !v.GetCheckReplicaN(),
+		StrictExpiry:        v.GetStrictExpiry(),
+		ForceExpiry:         v.GetForceExpiry(),
+	}
 	if v.Duration != nil {
 		value := time.Duration(v.GetDuration())
 		rpu.Duration = &value
@@ -321,10 +382,14 @@ func (fsm *storeFSM) applyUpdateRetentionPolicyCommand(cmd *internal.Command) in
 		value := time.Duration(v.GetShardGroupDuration())
 		rpu.ShardGroupDuration = &value
 	}
+	if v.ReadOnly != nil {
+		value := v.GetReadOnly()
+		rpu.ReadOnly = &value
+	}
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.UpdateRetentionPolicy(v.GetDatabase(), v.GetName(), &rpu, v.GetDefault()); err != nil {
+	if _, err := other.UpdateRetentionPolicyWithResult(v.GetDatabase(), v.GetName(), &rpu, v.GetDefault(), UnmarshalTime(v.GetTime())); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -338,7 +403,7 @@ func (fsm *storeFSM) applyCreateShardGroupCommand(cmd *internal.Command) interfa
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateShardGroup(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetTimestamp())); err != nil {
+	if err := other.CreateShardGroupWithReplicaN(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetTimestamp()), int(v.GetReplicaN())); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -420,13 +485,54 @@ func (fsm *storeFSM) applyRemoveShardOwnerCommand(cmd *internal.Command) interfa
 	return nil
 }
 
+func (fsm *storeFSM) applyTouchShardCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_TouchShardCommand_Command)
+	v := ext.(*internal.TouchShardCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	other.TouchShard(v.GetID(), UnmarshalTime(v.GetTime()))
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyRebalanceShardsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RebalanceShardsCommand_Command)
+	v := ext.(*internal.RebalanceShardsCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if _, err := other.RebalanceShards(v.GetStrategy(), false); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetPlacementStrategyCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetPlacementStrategyCommand_Command)
+	v := ext.(*internal.SetPlacementStrategyCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetPlacementStrategy(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
 func (fsm *storeFSM) applyCreateContinuousQueryCommand(cmd *internal.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, internal.E_CreateContinuousQueryCommand_Command)
 	v := ext.(*internal.CreateContinuousQueryCommand)
 
-	// Copy data and update.
+	// Commands written before continuous queries were validated don't set
+	// Validate, and replay unvalidated.
 	other := fsm.data.Clone()
-	if err := other.CreateContinuousQuery(v.GetDatabase(), v.GetName(), v.GetQuery()); err != nil {
+	if err := other.createContinuousQuery(v.GetDatabase(), v.GetName(), v.GetQuery(), v.GetValidate()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -457,6 +563,11 @@ func (fsm *storeFSM) applyCreateSubscriptionCommand(cmd *internal.Command) inter
 	if err := other.CreateSubscription(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName(), v.GetMode(), v.GetDestinations()); err != nil {
 		return err
 	}
+	if len(v.GetMeasurements()) > 0 {
+		if err := other.SetSubscriptionMeasurements(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName(), v.GetMeasurements()); err != nil {
+			return err
+		}
+	}
 	fsm.data = other
 
 	return nil
@@ -546,6 +657,10 @@ func (fsm *storeFSM) applySetDataCommand(cmd *internal.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, internal.E_SetDataCommand_Command)
 	v := ext.(*internal.SetDataCommand)
 
+	if v.GetData().GetVersion() > DataVersion {
+		return ErrMetadataVersion
+	}
+
 	// Overwrite data.
 	fsm.data = &Data{}
 	fsm.data.unmarshal(v.GetData())
@@ -558,7 +673,7 @@ func (fsm *storeFSM) applyCreateMetaNodeCommand(cmd *internal.Command) interface
 	v := ext.(*internal.CreateMetaNodeCommand)
 
 	other := fsm.data.Clone()
-	other.CreateMetaNode(v.GetHTTPAddr(), v.GetTCPAddr())
+	other.CreateMetaNodeWithZone(v.GetHTTPAddr(), v.GetTCPAddr(), v.GetZone())
 
 	// If the cluster ID hasn't been set then use the command's random number.
 	if other.ClusterID == 0 {
@@ -607,12 +722,13 @@ func (fsm *storeFSM) applyCreateDataNodeCommand(cmd *internal.Command) interface
 	v := ext.(*internal.CreateDataNodeCommand)
 
 	other := fsm.data.Clone()
-	if err := other.CreateDataNode(v.GetHTTPAddr(), v.GetTCPAddr()); err != nil {
+	id, err := other.CreateDataNodeWithResult(v.GetHTTPAddr(), v.GetTCPAddr(), v.GetZone())
+	if err != nil {
 		return err
 	}
 
 	fsm.data = other
-	return nil
+	return nodeIDResponse(id)
 }
 
 func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface{} {
@@ -627,6 +743,337 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface
 	return nil
 }
 
+func (fsm *storeFSM) applyCreateRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateRoleCommand_Command)
+	v := ext.(*internal.CreateRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.CreateRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyDropRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_DropRoleCommand_Command)
+	v := ext.(*internal.DropRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.DropRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyAddUserToRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddUserToRoleCommand_Command)
+	v := ext.(*internal.AddUserToRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.AddUserToRole(v.GetRole(), v.GetUsername()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveUserFromRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveUserFromRoleCommand_Command)
+	v := ext.(*internal.RemoveUserFromRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.RemoveUserFromRole(v.GetRole(), v.GetUsername()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetRolePrivilegeCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetRolePrivilegeCommand_Command)
+	v := ext.(*internal.SetRolePrivilegeCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetRolePrivilege(v.GetRole(), v.GetDatabase(), influxql.Privilege(v.GetPrivilege())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyTouchShardsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_TouchShardsCommand_Command)
+	v := ext.(*internal.TouchShardsCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	other.TouchShards(v.GetIDs(), UnmarshalTime(v.GetTime()))
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyUpdateSubscriptionCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UpdateSubscriptionCommand_Command)
+	v := ext.(*internal.UpdateSubscriptionCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.UpdateSubscription(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName(), v.GetMode(), v.GetDestinations()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyRenameDatabaseCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RenameDatabaseCommand_Command)
+	v := ext.(*internal.RenameDatabaseCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.RenameDatabase(v.GetOldName(), v.GetNewName()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyRenameRetentionPolicyCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RenameRetentionPolicyCommand_Command)
+	v := ext.(*internal.RenameRetentionPolicyCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.RenameRetentionPolicy(v.GetDatabase(), v.GetOldName(), v.GetNewName()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetDefaultShardGroupDurationCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDefaultShardGroupDurationCommand_Command)
+	v := ext.(*internal.SetDefaultShardGroupDurationCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetDefaultShardGroupDuration(v.GetDatabase(), time.Duration(v.GetDuration())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetDatabaseLimitsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDatabaseLimitsCommand_Command)
+	v := ext.(*internal.SetDatabaseLimitsCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetDatabaseLimits(v.GetName(), int(v.GetMaxSeries()), int(v.GetMaxValues())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetShardOwnerStateCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetShardOwnerStateCommand_Command)
+	v := ext.(*internal.SetShardOwnerStateCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetShardOwnerState(v.GetShardID(), v.GetNodeID(), v.GetState()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetContinuousQueryEnabledCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetContinuousQueryEnabledCommand_Command)
+	v := ext.(*internal.SetContinuousQueryEnabledCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetContinuousQueryEnabled(v.GetDatabase(), v.GetName(), v.GetEnabled()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyUpdateContinuousQueryCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UpdateContinuousQueryCommand_Command)
+	v := ext.(*internal.UpdateContinuousQueryCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.UpdateContinuousQuery(v.GetDatabase(), v.GetName(), v.GetQuery()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyPromoteToDataNodeCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_PromoteToDataNodeCommand_Command)
+	v := ext.(*internal.PromoteToDataNodeCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.PromoteToDataNode(v.GetID(), v.GetHTTPAddr(), v.GetTCPAddr()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetPrivilegesCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetPrivilegesCommand_Command)
+	v := ext.(*internal.SetPrivilegesCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	grants := make(map[string]influxql.Privilege, len(v.GetPrivileges()))
+	for _, p := range v.GetPrivileges() {
+		grants[p.GetDatabase()] = influxql.Privilege(p.GetPrivilege())
+	}
+	if err := other.SetPrivileges(v.GetUsername(), grants); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetDataNodeStatusCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDataNodeStatusCommand_Command)
+	v := ext.(*internal.SetDataNodeStatusCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetDataNodeStatus(v.GetID(), v.GetStatus()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyCreateShardGroupWithOwnersCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateShardGroupWithOwnersCommand_Command)
+	v := ext.(*internal.CreateShardGroupWithOwnersCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	owners := make([][]ShardOwner, len(v.GetOwners()))
+	for i, set := range v.GetOwners() {
+		owners[i] = make([]ShardOwner, len(set.GetOwners()))
+		for j, owner := range set.GetOwners() {
+			owners[i][j].unmarshal(owner)
+		}
+	}
+	if err := other.CreateShardGroupWithOwners(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetTimestamp()), owners); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetWriteAffinityTagCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetWriteAffinityTagCommand_Command)
+	v := ext.(*internal.SetWriteAffinityTagCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetWriteAffinityTag(v.GetDatabase(), v.GetPolicy(), v.GetTag()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetPendingShardCountCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetPendingShardCountCommand_Command)
+	v := ext.(*internal.SetPendingShardCountCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetPendingShardCount(v.GetDatabase(), v.GetPolicy(), int(v.GetCount())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applySetPreCreateCountCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetPreCreateCountCommand_Command)
+	v := ext.(*internal.SetPreCreateCountCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetPreCreateCount(v.GetDatabase(), v.GetPolicy(), int(v.GetCount())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyPreCreateShardGroupsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_PreCreateShardGroupsCommand_Command)
+	v := ext.(*internal.PreCreateShardGroupsCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if _, err := other.PreCreateShardGroups(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetTimestamp())); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
+func (fsm *storeFSM) applyMoveShardCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_MoveShardCommand_Command)
+	v := ext.(*internal.MoveShardCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.MoveShard(v.GetShardID(), v.GetFromNodeID(), v.GetToNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
 func (fsm *storeFSM) Snapshot() (raft.FSMSnapshot, error) {
 	s := (*store)(fsm)
 	s.mu.Lock()
@@ -198,7 +198,7 @@ func (s *Service) updateMetaStore(conn net.Conn, bits []byte, backupDBName, rest
 
 	data := ossClient.Data()
 
-	IDMap, newDBs, err := data.ImportData(md, backupDBName, restoreDBName, backupRPName, restoreRPName)
+	IDMap, newDBs, err := data.ImportDataMerge(md, backupDBName, restoreDBName, backupRPName, restoreRPName)
 	if err != nil {
 		if err := s.respondIDMap(conn, map[uint64]uint64{}); err != nil {
 			return err
@@ -211,7 +211,7 @@ func (s *Service) updateMetaStore(conn net.Conn, bits []byte, backupDBName, rest
 		return err
 	}
 
-	err = s.createNewDBShards(data, newDBs)
+	err = s.createNewDBShards(&data, newDBs)
 	if err != nil {
 		return err
 	}
@@ -223,7 +223,7 @@ func (s *Service) updateMetaStore(conn net.Conn, bits []byte, backupDBName, rest
 // iterate over a list of newDB's that should have just been added to the metadata
 // If the db was not created in the metadata return an error.
 // None of the shards should exist on a new DB, and CreateShard protects against double-creation.
-func (s *Service) createNewDBShards(data meta.Data, newDBs []string) error {
+func (s *Service) createNewDBShards(data meta.DataView, newDBs []string) error {
 	for _, restoreDBName := range newDBs {
 		dbi := data.Database(restoreDBName)
 		if dbi == nil {
@@ -270,6 +270,7 @@ func (s *Service) writeMetaStore(conn net.Conn) error {
 	if err != nil {
 		return fmt.Errorf("marshal meta: %s", err)
 	}
+	metaBlob = meta.ChecksumMetadata(metaBlob)
 
 	var nodeBytes bytes.Buffer
 	if err := json.NewEncoder(&nodeBytes).Encode(s.Node); err != nil {
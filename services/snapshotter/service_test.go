@@ -22,6 +22,7 @@ import (
 )
 
 var data = meta.Data{
+	Version:   meta.DataVersion,
 	MetaNodes: []meta.NodeInfo{},
 	DataNodes: []meta.NodeInfo{},
 	Databases: []meta.DatabaseInfo{
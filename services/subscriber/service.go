@@ -249,8 +249,12 @@ func (s *Service) run() {
 			p = s.removeBadPoints(p)
 			for se, cw := range s.subs {
 				if p.Database == se.db && p.RetentionPolicy == se.rp {
+					req := p.FilterMeasurements(cw.measurements)
+					if req != p && len(req.Points) == 0 {
+						continue
+					}
 					select {
-					case cw.writeRequests <- p:
+					case cw.writeRequests <- req:
 					default:
 						atomic.AddInt64(&s.stats.WriteFailures, 1)
 					}
@@ -331,8 +335,21 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 					name: si.Name,
 				}
 				allEntries[se] = true
-				if _, ok := s.subs[se]; ok {
-					continue
+				if cw, ok := s.subs[se]; ok {
+					if cw.mode == si.Mode && equalStrings(cw.destinations, si.Destinations) {
+						// Points are filtered as they're sent to the writer,
+						// so a new filter takes effect without replacing it.
+						cw.measurements = si.Measurements
+						s.subs[se] = cw
+						continue
+					}
+
+					// The subscription was updated, so replace its writer.
+					cw.Close()
+					delete(s.subs, se)
+					s.Logger.Info("Updating subscription",
+						logger.Database(se.db),
+						logger.RetentionPolicy(se.rp))
 				}
 				sub, err := s.createSubscription(se, si.Mode, si.Destinations)
 				if err != nil {
@@ -342,6 +359,9 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 				}
 				cw := chanWriter{
 					writeRequests: make(chan *coordinator.WritePointsRequest, s.conf.WriteBufferSize),
+					mode:          si.Mode,
+					destinations:  si.Destinations,
+					measurements:  si.Measurements,
 					pw:            sub,
 					pointsWritten: &s.stats.PointsWritten,
 					failures:      &s.stats.WriteFailures,
@@ -377,6 +397,19 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	}
 }
 
+// equalStrings returns true if a and b hold the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // newPointsWriter returns a new PointsWriter from the given URL.
 func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
 	switch u.Scheme {
@@ -397,6 +430,9 @@ func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
 // chanWriter sends WritePointsRequest to a PointsWriter received over a channel.
 type chanWriter struct {
 	writeRequests chan *coordinator.WritePointsRequest
+	mode          string
+	destinations  []string
+	measurements  []string
 	pw            PointsWriter
 	pointsWritten *int64
 	failures      *int64
@@ -388,6 +388,192 @@ func TestService_Multiple(t *testing.T) {
 	close(dataChanged)
 }
 
+func TestService_UpdatedMeasurements(t *testing.T) {
+	dataChanged := make(chan struct{})
+	ms := MetaClient{}
+	ms.WaitForDataChangedFn = func() chan struct{} {
+		return dataChanged
+	}
+	var measurements []string
+	calls := make(chan bool, 2)
+	ms.DatabasesFn = func() []meta.DatabaseInfo {
+		defer func() { calls <- true }()
+		return []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						Subscriptions: []meta.SubscriptionInfo{
+							{Name: "s0", Mode: "ALL", Destinations: []string{"udp://h0:9093"}, Measurements: measurements},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	prs := make(chan *coordinator.WritePointsRequest, 2)
+	urls := make(chan url.URL, 2)
+	newPointsWriter := func(u url.URL) (subscriber.PointsWriter, error) {
+		sub := Subscription{}
+		sub.WritePointsFn = func(p *coordinator.WritePointsRequest) error {
+			prs <- p
+			return nil
+		}
+		urls <- u
+		return sub, nil
+	}
+
+	s := subscriber.NewService(subscriber.NewConfig())
+	s.MetaClient = ms
+	s.NewPointsWriter = newPointsWriter
+	s.Open()
+	defer s.Close()
+
+	select {
+	case <-calls:
+	case <-time.After(testTimeout):
+		t.Fatal("expected call")
+	}
+
+	// Limit the subscription to cpu points.
+	measurements = []string{"cpu"}
+	dataChanged <- struct{}{}
+	select {
+	case <-calls:
+	case <-time.After(testTimeout):
+		t.Fatal("expected call")
+	}
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "db0",
+		RetentionPolicy: "rp0",
+	}
+	pr.AddPoint("mem", 1.0, time.Unix(0, 0), nil)
+	pr.AddPoint("cpu", 2.0, time.Unix(0, 0), nil)
+	s.Points() <- pr
+
+	select {
+	case got := <-prs:
+		if len(got.Points) != 1 || string(got.Points[0].Name()) != "cpu" {
+			t.Fatalf("unexpected points: %v", got.Points)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("expected points request")
+	}
+
+	// The subscription's writer is kept.
+	select {
+	case u := <-urls:
+		if u.String() != "udp://h0:9093" {
+			t.Fatalf("unexpected url: %s", u.String())
+		}
+	default:
+		t.Fatal("expected url")
+	}
+	select {
+	case u := <-urls:
+		t.Fatalf("unexpected writer created for %s", u.String())
+	default:
+	}
+	close(dataChanged)
+}
+
+func TestService_UpdatedDestinations(t *testing.T) {
+	dataChanged := make(chan struct{})
+	ms := MetaClient{}
+	ms.WaitForDataChangedFn = func() chan struct{} {
+		return dataChanged
+	}
+	destination := "udp://h0:9093"
+	calls := make(chan bool, 2)
+	ms.DatabasesFn = func() []meta.DatabaseInfo {
+		defer func() { calls <- true }()
+		return []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						Subscriptions: []meta.SubscriptionInfo{
+							{Name: "s0", Mode: "ALL", Destinations: []string{destination}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	prs := make(chan string, 2)
+	urls := make(chan url.URL, 2)
+	newPointsWriter := func(u url.URL) (subscriber.PointsWriter, error) {
+		sub := Subscription{}
+		sub.WritePointsFn = func(p *coordinator.WritePointsRequest) error {
+			prs <- u.String()
+			return nil
+		}
+		urls <- u
+		return sub, nil
+	}
+
+	s := subscriber.NewService(subscriber.NewConfig())
+	s.MetaClient = ms
+	s.NewPointsWriter = newPointsWriter
+	s.Open()
+	defer s.Close()
+
+	select {
+	case <-calls:
+	case <-time.After(testTimeout):
+		t.Fatal("expected call")
+	}
+	select {
+	case u := <-urls:
+		if u.String() != "udp://h0:9093" {
+			t.Fatalf("unexpected url: %s", u.String())
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("expected url")
+	}
+
+	// Point the subscription at another destination.
+	destination = "udp://h1:9093"
+	dataChanged <- struct{}{}
+	select {
+	case <-calls:
+	case <-time.After(testTimeout):
+		t.Fatal("expected call")
+	}
+
+	// A writer is created for the new destination and sent the points.
+	select {
+	case u := <-urls:
+		if u.String() != "udp://h1:9093" {
+			t.Fatalf("unexpected url: %s", u.String())
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("expected url")
+	}
+
+	pr := &coordinator.WritePointsRequest{
+		Database:        "db0",
+		RetentionPolicy: "rp0",
+	}
+	pr.AddPoint("cpu", 1.0, time.Unix(0, 0), nil)
+	s.Points() <- pr
+
+	select {
+	case got := <-prs:
+		if got != "udp://h1:9093" {
+			t.Fatalf("points written to %s, expected udp://h1:9093", got)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("expected points request")
+	}
+	close(dataChanged)
+}
+
 func TestService_WaitForDataChanged(t *testing.T) {
 	dataChanged := make(chan struct{}, 1)
 	ms := MetaClient{}